@@ -2,4 +2,9 @@
 
 rm -f bin/*.exec
 
-go build -o bin/server.exec cmd/server/main.go
\ No newline at end of file
+VERSION=${VERSION:-$(git describe --tags --always 2>/dev/null || echo dev)}
+COMMIT=$(git rev-parse --short HEAD 2>/dev/null || echo unknown)
+BUILD_TIME=$(date -u +'%Y-%m-%dT%H:%M:%SZ')
+LDFLAGS="-X system-collector/pkg/version.Version=$VERSION -X system-collector/pkg/version.Commit=$COMMIT -X system-collector/pkg/version.BuildTime=$BUILD_TIME"
+
+go build -ldflags "$LDFLAGS" -o bin/server.exec ./cmd/server
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	config "system-collector/configs"
+	"system-collector/internal/tlsutil"
+)
+
+const healthcheckUsage = `사용법: collector [플래그] healthcheck
+  실행 중인 서버의 /healthz를 확인하고 정상이면 0, 아니면 1로 종료`
+
+// healthcheckTimeout은 healthcheck 하위 명령의 요청 제한 시간입니다
+const healthcheckTimeout = 3 * time.Second
+
+// healthcheckCommand는 같은 설정으로 실행 중인 서버의 /healthz를 확인하고 종료 코드를 반환합니다.
+// 컨테이너 HEALTHCHECK용이며, DB 장애로 컨테이너가 재시작되지 않도록 readiness가 아닌 liveness를 확인합니다.
+// TLS가 켜져 있으면 https로 접속하며, 루프백 접속이므로 서버 인증서는 검증하지 않습니다.
+// 클라이언트 인증서가 필수(client_auth: require)이면 요청할 수 없으므로 포트가 열려 있는지만 확인합니다.
+func healthcheckCommand() int {
+	cfg := config.Get()
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(cfg.Server.Port))
+
+	if cfg.Server.TLS.Enabled && cfg.Server.TLS.ClientAuth == tlsutil.ClientAuthRequire {
+		conn, err := net.DialTimeout("tcp", addr, healthcheckTimeout)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "헬스 체크 실패: %v\n", err)
+			return 1
+		}
+		conn.Close()
+		return 0
+	}
+
+	scheme := "http"
+	client := &http.Client{Timeout: healthcheckTimeout}
+	if cfg.Server.TLS.Enabled {
+		scheme = "https"
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	}
+
+	resp, err := client.Get(scheme + "://" + addr + "/healthz")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "헬스 체크 실패: %v\n", err)
+		return 1
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		fmt.Fprintf(os.Stderr, "헬스 체크 실패: 상태 코드 %d\n", resp.StatusCode)
+		return 1
+	}
+	return 0
+}
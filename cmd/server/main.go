@@ -2,35 +2,121 @@ package main
 
 import (
 	config "system-collector/configs"
+	"system-collector/internal/admin"
+	"system-collector/internal/alerting"
+	"system-collector/internal/anomaly"
+	"system-collector/internal/cluster"
+	"system-collector/internal/containers"
+	"system-collector/internal/events"
+	"system-collector/internal/exposure"
+	"system-collector/internal/forecast"
+	"system-collector/internal/health"
+	"system-collector/internal/ingest"
+	"system-collector/internal/inventory"
+	"system-collector/internal/iphistory"
+	"system-collector/internal/liveness"
+	"system-collector/internal/notify"
+	"system-collector/internal/processes"
+	"system-collector/internal/registry"
 	"system-collector/internal/repository"
+	"system-collector/internal/security"
+	"system-collector/internal/services"
 	"system-collector/internal/storage"
+	"system-collector/internal/telemetry"
 	"system-collector/internal/websocket"
 	"system-collector/pkg/models"
 
+	"context"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
+	"system-collector/pkg/geoip"
 	"system-collector/pkg/logger"
+	"system-collector/pkg/version"
 )
 
+// overrideFlags는 반복해서 지정할 수 있는 -set key=value 플래그입니다
+type overrideFlags []string
+
+func (o *overrideFlags) String() string {
+	return strings.Join(*o, ",")
+}
+
+func (o *overrideFlags) Set(value string) error {
+	*o = append(*o, value)
+	return nil
+}
+
 func main() {
-	// 로거 초기화
-	if err := logger.InitCustomLogger(); err != nil {
+	configFile := flag.String("config", envOr("SC_CONFIG", config.DefaultFile), "설정 파일 경로 (환경 변수 SC_CONFIG)")
+	printConfig := flag.Bool("print-config", false, "최종 설정을 비밀 값을 가려 출력하고 종료")
+	var overrides overrideFlags
+	flag.Var(&overrides, "set", "설정 항목 오버라이드 (예: -set connection.rate_limit.burst=20, 반복 가능)")
+	flag.Parse()
+
+	// 설정 로드 (기본값 -> 설정 파일 -> 환경 변수 -> 명령줄 순서)
+	// 경로를 명시하지 않았으면 기본 설정 파일이 없어도 환경 변수만으로 시작
+	load := config.LoadOptional
+	if os.Getenv("SC_CONFIG") != "" || flagSet("config") {
+		load = config.Load
+	}
+	if err := load(*configFile, overrides...); err != nil {
+		fmt.Fprintf(os.Stderr, "설정 로드 실패: %v\n", err)
+		os.Exit(1)
+	}
+	if *printConfig {
+		out, err := config.Redacted(config.Get())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "설정 출력 실패: %v\n", err)
+			os.Exit(1)
+		}
+		os.Stdout.Write(out)
+		return
+	}
+	// healthcheck는 주기적으로 실행되므로 로거를 초기화하지 않음 (로그 파일을 만들지 않도록)
+	if args := flag.Args(); len(args) > 0 && args[0] == "healthcheck" {
+		os.Exit(healthcheckCommand())
+	}
+
+	// 로거 초기화 (비밀 값은 첫 로그 전에 등록)
+	logger.RegisterSecret(config.Secrets(config.Get())...)
+	logCfg := config.Get().Log
+	if err := logger.InitCustomLogger(logger.Options{
+		Level:            logCfg.Level,
+		Packages:         logCfg.Packages,
+		Encoding:         logCfg.Encoding,
+		Dir:              logCfg.Dir,
+		MaxSizeMB:        logCfg.MaxSizeMB,
+		MaxBackups:       logCfg.MaxBackups,
+		MaxAgeDays:       logCfg.MaxAgeDays,
+		RotateDaily:      logCfg.RotateDaily,
+		SampleInitial:    logCfg.Sampling.Initial,
+		SampleThereafter: logCfg.Sampling.Thereafter,
+	}); err != nil {
 		panic(fmt.Sprintf("로거 초기화 실패: %v", err))
 	}
 
 	sugar := logger.GetCustomLogger()
 	defer sugar.Close()
 
-	sugar.Infow("Starting System Collector")
-
-	// 설정 로드
-	if err := config.Load("configs/config.yaml"); err != nil {
-		sugar.Errorw("설정 로드 실패", "error", err)
+	// 하위 명령 (migrate)은 실행 후 종료
+	if args := flag.Args(); len(args) > 0 {
+		if args[0] != "migrate" {
+			fmt.Fprintf(os.Stderr, "알 수 없는 명령: %s\n%s\n%s\n", args[0], migrateUsage, healthcheckUsage)
+			os.Exit(2)
+		}
+		code := migrateCommand(args[1:])
+		sugar.Close()
+		os.Exit(code)
 	}
 
+	sugar.Infow("Starting System Collector", "version", version.Version, "commit", version.Commit, "config", *configFile)
+
 	// 스토리지 초기화
 	store, err := storage.NewInfluxDBClient()
 	if err != nil {
@@ -45,48 +131,297 @@ func main() {
 	}
 	// defer pgClient.Close()
 
+	// 스키마 마이그레이션 (여러 인스턴스가 동시에 시작해도 잠금으로 한 인스턴스만 적용)
+	if config.Get().Postgres.AutoMigrate {
+		if err := runMigrations(pgClient.GetDB(), []string{"up"}); err != nil {
+			sugar.Errorw("스키마 마이그레이션 실패", "error", err)
+			os.Exit(1)
+		}
+	}
+
 	// 커맨드 저장소 초기화
 	cmdRepo := repository.NewCommandRepository(pgClient.GetDB())
 	userRepo := repository.NewUserRepository(pgClient.GetDB())
 	nodeRepo := repository.NewNodeRepository(pgClient.GetDB())
 	logRepo := repository.NewLogRepository(pgClient.GetDB())
-	// 버퍼가 있는 채널 생성
-	metricsChan := make(chan *models.SystemMetrics, 1000)
-
-	// 메트릭스 처리를 위한 워커 풀 생성
-	for range [50]struct{}{} { // 워커 수는 필요에 따라 조정
-		go func() {
-			for metrics := range metricsChan {
-				if err := store.StoreMetrics(metrics); err != nil {
-					sugar.Errorw("메트릭스 저장 실패", "error", err)
-				}
-			}
-		}()
+	inventoryRepo := repository.NewInventoryRepository(pgClient.GetDB())
+	eventRepo := repository.NewEventRepository(pgClient.GetDB())
+	ipHistoryRepo := repository.NewIPHistoryRepository(pgClient.GetDB())
+	leaseRepo := repository.NewLeaseRepository(pgClient.GetDB())
+	alertRepo := repository.NewAlertRepository(pgClient.GetDB())
+	baselineRepo := repository.NewBaselineRepository(pgClient.GetDB())
+	containerEventRepo := repository.NewContainerEventRepository(pgClient.GetDB())
+	serviceRepo := repository.NewServiceRepository(pgClient.GetDB())
+	securityRepo := repository.NewSecurityRepository(pgClient.GetDB())
+
+	// 노드 이벤트 버스 (저장 및 구독자 전달)
+	eventBus := events.NewBus(eventRepo, 1000)
+	eventBus.Start()
+
+	// 알림/이벤트를 webhook, Slack, Discord, 메일로 전달
+	notifier, err := notify.NewDispatcher()
+	if err != nil {
+		sugar.Errorw("일부 알림 채널 설정 오류, 해당 채널 없이 계속 진행", "error", err)
+	}
+	eventBus.Subscribe(notifier.Handle)
+	notifier.Start()
+
+	// 노드 연결 소유권 관리 (클러스터 모드에서는 다른 인스턴스와 리스로 조정)
+	coordinator := cluster.NewCoordinator(leaseRepo, nodeRepo, eventBus)
+
+	// 노드 생존 상태 추적 (이전 실행에서 남은 온라인 상태 정리, 다른 인스턴스가 소유한 노드 제외)
+	livenessTracker := liveness.NewTracker(nodeRepo, eventBus, coordinator)
+	if err := livenessTracker.Reconcile(); err != nil {
+		sugar.Errorw("노드 상태 정리 실패", "error", err)
+	}
+	livenessTracker.Start()
+
+	// 외부 IP 위치/ASN 조회용 GeoIP DB (선택)
+	geoResolver, err := geoip.NewResolver(config.Get().GeoIP.CountryDB, config.Get().GeoIP.ASNDB)
+	if err != nil {
+		sugar.Errorw("GeoIP DB 로드 실패, 위치 정보 없이 계속 진행", "error", err)
+	}
+
+	// 노드 레지스트리 초기화 및 변경 알림 구독
+	nodeRegistry := registry.NewNodeRegistry(nodeRepo)
+	if err := nodeRegistry.Listen(pgClient.NewListener); err != nil {
+		sugar.Errorw("노드 변경 알림 구독 실패, 캐시 무효화 없이 계속 진행", "error", err)
+	}
+
+	// 메트릭스 처리를 위한 수집 큐와 워커 풀 생성 (워커 수는 필요에 따라 조정)
+	inventoryTracker := inventory.NewTracker(inventoryRepo)
+	ipTracker := iphistory.NewTracker(ipHistoryRepo, nodeRepo, geoResolver, eventBus, nodeRegistry.All())
+	alertEngine := alerting.NewEngine(alertRepo, eventBus, nodeRegistry)
+	alertEngine.Start()
+	anomalyDetector := anomaly.NewDetector(baselineRepo, eventBus, nodeRegistry)
+	diskForecaster := forecast.NewForecaster(eventBus, nodeRegistry)
+	containerTracker := containers.NewTracker(containerEventRepo, eventBus, nodeRegistry)
+	serviceTracker := services.NewTracker(serviceRepo, eventBus, nodeRegistry)
+	processTable := processes.NewTable()
+	securityTracker := security.NewTracker(securityRepo, eventBus, nodeRegistry)
+	exposureTracker := exposure.NewTracker(eventBus, nodeRegistry)
+	// InfluxDB를 먼저 쓰고, PostgreSQL에 기록하는 트래커는 다음 단계 큐에서 처리하여
+	// DB 지연이 메트릭스 저장을 막거나 수집 큐를 채우지 않도록 함
+	ingestCfg := config.Get().Ingest
+	queue := ingest.NewQueue(ingestCfg.QueueSize, ingestCfg.Workers, store, alertEngine, anomalyDetector, diskForecaster, processTable, exposureTracker)
+	trackerQueue := ingest.NewQueue(ingestCfg.QueueSize, ingestCfg.Workers, inventoryTracker, ipTracker, containerTracker, serviceTracker, securityTracker)
+	queue.Forward(trackerQueue)
+	queue.Start()
+
+	telemetry.NewGaugeFunc("collector_ingest_queue_length", "수집 큐에 대기 중인 메트릭스 수", func() float64 {
+		return float64(queue.Len())
+	})
+	telemetry.NewGaugeFunc("collector_ingest_queue_capacity", "수집 큐 전체 버퍼 크기", func() float64 {
+		return float64(queue.Cap())
+	})
+	telemetry.NewCounterFunc("collector_ingest_dropped_total", "수집 큐가 가득 차 버려진 메트릭스 수", func() float64 {
+		return float64(queue.Dropped())
+	})
+	telemetry.NewGaugeFunc("collector_ingest_tracker_queue_length", "트래커 큐에 대기 중인 메트릭스 수", func() float64 {
+		return float64(trackerQueue.Len())
+	})
+	telemetry.NewCounterFunc("collector_ingest_tracker_dropped_total", "트래커 큐가 가득 차 트래커에 전달하지 못한 메트릭스 수", func() float64 {
+		return float64(trackerQueue.Dropped())
+	})
+
+	// PostgreSQL 연결 풀 통계
+	telemetry.NewGaugeFunc("collector_postgres_pool_max_open", "PostgreSQL 연결 풀의 최대 연결 수 (0은 제한 없음)", func() float64 {
+		return float64(pgClient.Stats().MaxOpenConnections)
+	})
+	telemetry.NewGaugeFunc("collector_postgres_pool_open", "PostgreSQL 연결 풀의 열린 연결 수", func() float64 {
+		return float64(pgClient.Stats().OpenConnections)
+	})
+	telemetry.NewGaugeFunc("collector_postgres_pool_in_use", "PostgreSQL 연결 풀에서 사용 중인 연결 수", func() float64 {
+		return float64(pgClient.Stats().InUse)
+	})
+	telemetry.NewGaugeFunc("collector_postgres_pool_idle", "PostgreSQL 연결 풀의 유휴 연결 수", func() float64 {
+		return float64(pgClient.Stats().Idle)
+	})
+	telemetry.NewCounterFunc("collector_postgres_pool_wait_total", "연결 풀이 가득 차 연결을 기다린 횟수", func() float64 {
+		return float64(pgClient.Stats().WaitCount)
+	})
+	telemetry.NewCounterFunc("collector_postgres_pool_wait_seconds_total", "연결 풀에서 연결을 기다린 총 시간", func() float64 {
+		return pgClient.Stats().WaitDuration.Seconds()
+	})
+	telemetry.NewCounterFunc("collector_postgres_pool_closed_total", "유휴/수명 제한으로 닫힌 연결 수", func() float64 {
+		st := pgClient.Stats()
+		return float64(st.MaxIdleClosed + st.MaxIdleTimeClosed + st.MaxLifetimeClosed)
+	})
+
+	// 내부 메트릭을 InfluxDB에도 기록 (선택)
+	var selfReporter *telemetry.InfluxReporter
+	if cfg := config.Get().SelfMetrics; cfg.InfluxDBEnabled {
+		interval := time.Duration(cfg.Interval) * time.Second
+		if interval <= 0 {
+			interval = 15 * time.Second
+		}
+		selfReporter = telemetry.NewInfluxReporter(store, interval)
+		selfReporter.Start()
 	}
 
-	// WebSocket 서버 초기화 (메트릭스 채널 전달)
-	wsServer := websocket.NewServer(func(m *models.SystemMetrics) error {
-		metricsChan <- m
-		return nil
-	}, cmdRepo, userRepo, nodeRepo, logRepo)
+	// WebSocket 서버 초기화 (수집 큐 전달)
+	wsServer := websocket.NewServer(func(ctx context.Context, m *models.SystemMetrics) error {
+		return queue.Enqueue(ctx, m)
+	}, cmdRepo, userRepo, nodeRepo, logRepo, nodeRegistry, livenessTracker, coordinator)
+
+	// 다른 인스턴스가 노드를 가져가면 로컬 연결을 끊고, 노드를 놓으면 노드별 캐시를 버림
+	coordinator.OnTakeover(wsServer.CloseNode)
+	coordinator.OnRelease(inventoryTracker.Forget)
+	coordinator.OnRelease(ipTracker.Forget)
+	coordinator.OnRelease(alertEngine.Forget)
+	coordinator.OnRelease(anomalyDetector.Forget)
+	coordinator.OnRelease(diskForecaster.Forget)
+	coordinator.OnRelease(containerTracker.Forget)
+	coordinator.OnRelease(serviceTracker.Forget)
+	coordinator.OnRelease(processTable.Forget)
+	coordinator.OnRelease(securityTracker.Forget)
+	coordinator.OnRelease(exposureTracker.Forget)
+	coordinator.OnCommands(wsServer.DeliverCommands)
+	if err := coordinator.Start(pgClient.NewListener); err != nil {
+		sugar.Errorw("클러스터 코디네이터 시작 실패, 명령어 알림 없이 계속 진행", "error", err)
+	}
+
+	// 헬스 체크 및 진단 엔드포인트 등록
+	healthHandler := health.NewHandler(wsServer, queue,
+		health.Check{Name: "postgres", Fn: pgClient.Ping},
+		health.Check{Name: "influxdb", Fn: store.Ping},
+	)
+	healthHandler.RegisterRoutes(wsServer.Mux())
+	wsServer.Mux().Handle("GET /metrics", telemetry.Handler())
+
+	// 노드 인벤토리 조회 API 등록
+	inventory.NewHandler(inventoryRepo).RegisterRoutes(wsServer.Mux())
+	iphistory.NewHandler(ipHistoryRepo).RegisterRoutes(wsServer.Mux())
+	events.NewAPIHandler(eventRepo).RegisterRoutes(wsServer.Mux())
+	alerting.NewAPIHandler(alertEngine, alertRepo).RegisterRoutes(wsServer.Mux())
+	anomaly.NewHandler(anomalyDetector, baselineRepo).RegisterRoutes(wsServer.Mux())
+	forecast.NewHandler(diskForecaster).RegisterRoutes(wsServer.Mux())
+	containers.NewHandler(containerEventRepo).RegisterRoutes(wsServer.Mux())
+	services.NewHandler(serviceTracker, serviceRepo).RegisterRoutes(wsServer.Mux())
+	processes.NewHandler(processTable).RegisterRoutes(wsServer.Mux())
+	security.NewHandler(securityTracker, securityRepo).RegisterRoutes(wsServer.Mux())
+	exposure.NewHandler(exposureTracker).RegisterRoutes(wsServer.Mux())
+	cluster.NewHandler(coordinator).RegisterRoutes(wsServer.Mux())
+	admin.NewLogHandler().RegisterRoutes(wsServer.Mux())
+	notify.NewHandler(notifier).RegisterRoutes(wsServer.Mux())
 
 	// 시그널 처리를 위한 채널 생성
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
+	// SIGHUP을 받으면 설정을 다시 로드 (재시작이 필요한 항목은 적용하지 않음)
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+	go func() {
+		for range hupChan {
+			reloadConfig()
+			if err := alertEngine.Refresh(context.Background()); err != nil {
+				sugar.Errorw("알림 규칙 갱신 실패", "error", err)
+			}
+		}
+	}()
+
 	// 별도의 고루틴에서 WebSocket 서버 시작
-	go wsServer.Start()
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- wsServer.Start()
+	}()
 
-	// 종료 시그널 대기
-	sig := <-sigChan
-	sugar.Infow("시스템 종료 신호 수신", "signal", sig.String())
+	// 종료 시그널 또는 서버 오류 대기
+	select {
+	case sig := <-sigChan:
+		sugar.Infow("시스템 종료 신호 수신", "signal", sig.String())
+	case err := <-serverErr:
+		sugar.Errorw("WebSocket 서버 비정상 종료", "error", err)
+	}
 	sugar.Infow("System Collector 종료 시작...")
 
-	// 여기에서 명시적으로 종료 작업 수행
+	shutdownTimeout := time.Duration(config.Get().Server.ShutdownTimeout) * time.Second
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	// 1. 새 연결을 막고 클라이언트에 close frame 전송 후 처리 중인 메시지 대기
+	sugar.Infow("WebSocket 연결 정리 중...")
+	if err := wsServer.Shutdown(ctx); err != nil {
+		sugar.Errorw("WebSocket 서버 종료 오류", "error", err)
+	}
+	// 소유한 노드 리스를 반납하여 다른 인스턴스가 바로 이어받을 수 있도록 함
+	coordinator.Stop()
+
+	// 2. 수집 큐에 남은 메트릭스를 저장하고 Sink 플러시
+	sugar.Infow("수집 큐 비우는 중...")
+	if err := queue.Drain(ctx); err != nil {
+		sugar.Errorw("수집 큐 비우기 실패", "error", err)
+	}
+
+	// 남은 노드 상태 변경과 이벤트를 반영
+	alertEngine.Stop()
+	livenessTracker.Stop()
+	if err := eventBus.Close(ctx); err != nil {
+		sugar.Errorw("이벤트 버스 종료 실패", "error", err)
+	}
+	if err := notifier.Stop(ctx); err != nil {
+		sugar.Errorw("알림 디스패처 종료 실패", "error", err)
+	}
+
+	if selfReporter != nil {
+		selfReporter.Stop()
+	}
+
+	// 3. 마지막으로 데이터베이스 연결 종료
 	sugar.Infow("데이터베이스 연결 종료 중...")
+	nodeRegistry.Close()
 	pgClient.Close()
 	sugar.Infow("스토리지 연결 종료 중...")
 	store.Close()
 
 	sugar.Infow("System Collector가 정상적으로 종료되었습니다")
 }
+
+// reloadConfig는 설정을 다시 읽어 로그 레벨, 연결 정책, 속도 제한, 생존 판단 기준, Sink 사용 여부를 반영합니다.
+// 속도 제한과 메시지 크기 제한은 기존 연결에도 다음 메시지부터 적용됩니다.
+func reloadConfig() {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("설정 다시 로드 시작")
+
+	ignored, err := config.Reload()
+	if err != nil {
+		sugar.Errorw("설정 다시 로드 실패, 기존 설정 유지", "error", err)
+		return
+	}
+	// 바뀐 비밀 값도 이후 로그에서 가림
+	logger.RegisterSecret(config.Secrets(config.Get())...)
+	if len(ignored) > 0 {
+		sugar.Warnw("재시작이 필요한 설정 변경은 적용하지 않음", "keys", ignored)
+	}
+	// 관리 API로 바꾼 로그 레벨은 설정 파일의 값으로 돌아감
+	if err := logger.SetLevel(config.Get().Log.Level); err != nil {
+		sugar.Errorw("로그 레벨 변경 실패", "error", err)
+	}
+	if err := logger.SetPackageLevels(config.Get().Log.Packages); err != nil {
+		sugar.Errorw("패키지별 로그 레벨 변경 실패", "error", err)
+	}
+	sugar.Infow("설정 다시 로드 완료", "logLevel", config.Get().Log.Level)
+}
+
+// flagSet은 명령줄에서 해당 플래그를 지정했는지 반환합니다
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// envOr는 환경 변수 값이 있으면 그 값을, 없으면 def를 반환합니다
+func envOr(name, def string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return def
+}
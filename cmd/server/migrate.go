@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"system-collector/internal/migrate"
+	"system-collector/internal/storage"
+)
+
+const migrateUsage = `사용법: collector [플래그] migrate <명령>
+  up [N]    적용하지 않은 마이그레이션을 모두(또는 N개) 적용
+  down [N]  최신 마이그레이션부터 1개(또는 N개) 되돌리기
+  status    마이그레이션별 적용 상태 출력`
+
+// migrateCommand는 migrate 하위 명령을 실행하고 종료 코드를 반환합니다
+func migrateCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	pgClient, err := storage.NewPostgresClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "PostgreSQL 연결 실패: %v\n", err)
+		return 1
+	}
+	defer pgClient.Close()
+
+	if err := runMigrations(pgClient.GetDB(), args); err != nil {
+		fmt.Fprintf(os.Stderr, "마이그레이션 실패: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runMigrations는 up/down/status 명령을 실행합니다
+func runMigrations(db *sql.DB, args []string) error {
+	migrator, err := migrate.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	steps := 0
+	if len(args) > 1 {
+		steps, err = strconv.Atoi(args[1])
+		if err != nil || steps <= 0 {
+			return fmt.Errorf("단계 수는 1 이상의 정수여야 합니다: %s", args[1])
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	switch args[0] {
+	case "up":
+		_, err = migrator.Up(ctx, steps)
+		return err
+	case "down":
+		_, err = migrator.Down(ctx, steps)
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, st := range statuses {
+			state, appliedAt := "pending", ""
+			if st.Applied {
+				state = "applied"
+				appliedAt = st.AppliedAt.Local().Format(time.RFC3339)
+			}
+			if st.Modified {
+				state += " (modified)"
+			}
+			if st.Unknown {
+				state += " (unknown)"
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", st.Version, st.Name, state, appliedAt)
+		}
+		return w.Flush()
+	}
+	return fmt.Errorf("알 수 없는 migrate 명령: %s\n%s", args[0], migrateUsage)
+}
@@ -1,63 +1,372 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"gopkg.in/yaml.v2"
 )
 
+// Config는 수집기 설정입니다. 값은 기본값(Default), 설정 파일, 환경 변수(SC_*),
+// 명령줄 오버라이드 순서로 적용되며, secret 태그가 붙은 필드는 출력 시 가려집니다.
 type Config struct {
 	Server struct {
 		Host string `yaml:"host"`
 		Port int    `yaml:"port"`
+		// ShutdownTimeout은 종료 시 연결과 큐를 정리하는 최대 시간(초)입니다
+		ShutdownTimeout int `yaml:"shutdown_timeout"`
+		// ReconnectDelay는 종료 시 클라이언트에게 안내하는 재접속 대기 시간(초)입니다
+		ReconnectDelay int `yaml:"reconnect_delay"`
+		TLS            struct {
+			Enabled  bool   `yaml:"enabled"`
+			CertFile string `yaml:"cert_file"`
+			KeyFile  string `yaml:"key_file"`
+			// ClientCAFile은 클라이언트 인증서(mTLS) 검증에 사용할 CA 번들입니다
+			ClientCAFile string `yaml:"client_ca_file"`
+			// ClientAuth는 none, request, require 중 하나입니다
+			ClientAuth string `yaml:"client_auth"`
+			// ReloadInterval은 인증서 파일 변경 확인 주기(초)입니다. 0이면 다시 로드하지 않습니다.
+			ReloadInterval int `yaml:"reload_interval"`
+			// SkipKeyCheck가 true이면 인증서로 확인된 노드는 obscura key 조회를 생략합니다 (처음 등록하는 노드는 제외)
+			SkipKeyCheck bool `yaml:"skip_key_check"`
+		} `yaml:"tls"`
 	} `yaml:"server"`
+	Connection struct {
+		// AllowedOrigins는 허용할 Origin 목록입니다. 비어 있거나 "*"가 있으면 모두 허용합니다.
+		AllowedOrigins []string `yaml:"allowed_origins"`
+		// MaxClients는 전체 메트릭스 연결 수 상한입니다
+		MaxClients int `yaml:"max_clients"`
+		// MaxPerIP, MaxPerKey, MaxPerNode는 원격 IP, obscura key, 노드 ID별 연결 수 상한입니다 (0이면 무제한)
+		MaxPerIP   int `yaml:"max_per_ip"`
+		MaxPerKey  int `yaml:"max_per_key"`
+		MaxPerNode int `yaml:"max_per_node"`
+		// DuplicateNodePolicy는 MaxPerNode를 넘었을 때의 처리 방식입니다 (kick_old | reject_new)
+		DuplicateNodePolicy string `yaml:"duplicate_node_policy"`
+		// MaxMessageSize는 메시지 하나의 최대 크기(바이트)입니다 (0이면 무제한)
+		MaxMessageSize int64 `yaml:"max_message_size"`
+		RateLimit      struct {
+			// MessagesPerSecond는 연결당 초당 허용 메시지 수입니다 (0이면 무제한)
+			MessagesPerSecond float64 `yaml:"messages_per_second"`
+			Burst             int     `yaml:"burst"`
+			// MaxViolations번 연속으로 제한을 넘으면 연결을 끊습니다
+			MaxViolations int `yaml:"max_violations"`
+		} `yaml:"rate_limit"`
+	} `yaml:"connection"`
 	InfluxDB struct {
 		URL    string `yaml:"url"`
-		Token  string `yaml:"token"`
+		Token  string `yaml:"token" secret:"true"`
 		Org    string `yaml:"org"`
 		Bucket string `yaml:"bucket"`
+		// TokenFile이 있으면 파일 내용을 토큰으로 사용합니다 (Docker/Kubernetes secret)
+		TokenFile string `yaml:"token_file"`
 	} `yaml:"influxdb"`
 	Postgres struct {
 		Host     string `yaml:"host"`
 		Port     int    `yaml:"port"`
 		User     string `yaml:"user"`
-		Password string `yaml:"password"`
+		Password string `yaml:"password" secret:"true"`
 		DBName   string `yaml:"dbname"`
 		SSLMode  string `yaml:"sslmode"`
+		// PasswordFile이 있으면 파일 내용을 비밀번호로 사용합니다 (Docker/Kubernetes secret)
+		PasswordFile string `yaml:"password_file"`
+
+		// QueryTimeout은 저장소 쿼리 하나에 허용하는 최대 시간(초)입니다
+		QueryTimeout int `yaml:"query_timeout"`
+		// AutoMigrate가 켜져 있으면 시작할 때 적용하지 않은 스키마 마이그레이션을 적용합니다
+		AutoMigrate bool `yaml:"auto_migrate"`
+		// Pool은 연결 풀 설정입니다. 0이면 database/sql 기본값(제한 없음)을 사용합니다.
+		Pool struct {
+			MaxOpenConns int `yaml:"max_open_conns"`
+			MaxIdleConns int `yaml:"max_idle_conns"`
+			// ConnMaxLifetime, ConnMaxIdleTime은 초 단위입니다
+			ConnMaxLifetime int `yaml:"conn_max_lifetime"`
+			ConnMaxIdleTime int `yaml:"conn_max_idle_time"`
+		} `yaml:"pool"`
 	} `yaml:"postgres"`
 	WebServer struct {
 		URL string `yaml:"url"`
 	} `yaml:"webServer"`
+	Liveness struct {
+		// ExpectedInterval은 에이전트의 기본 메트릭스 전송 주기(초)입니다.
+		// 노드가 실제로 더 느린 주기로 보내면 관측한 주기를 기준으로 판단합니다.
+		ExpectedInterval int `yaml:"expected_interval"`
+		// StaleAfterMissed번 연속으로 메트릭스가 오지 않으면 지연(stale) 상태가 됩니다
+		StaleAfterMissed int `yaml:"stale_after_missed"`
+		// OfflineAfterMissed번 연속으로 메트릭스가 오지 않으면 연결이 남아 있어도 오프라인이 됩니다
+		OfflineAfterMissed int `yaml:"offline_after_missed"`
+		// CheckInterval은 상태 점검 주기(초)입니다
+		CheckInterval int `yaml:"check_interval"`
+	} `yaml:"liveness"`
+	GeoIP struct {
+		// CountryDB는 MaxMind 형식(.mmdb) 국가 또는 도시 DB 파일 경로입니다 (비어 있으면 사용 안 함)
+		CountryDB string `yaml:"country_db"`
+		// ASNDB는 MaxMind 형식(.mmdb) ASN DB 파일 경로입니다 (비어 있으면 사용 안 함)
+		ASNDB string `yaml:"asn_db"`
+	} `yaml:"geoip"`
+	Cluster struct {
+		// Enabled가 true이면 여러 수집기 인스턴스가 PostgreSQL 리스로 노드 연결 소유권을 나눠 갖습니다
+		Enabled bool `yaml:"enabled"`
+		// InstanceID는 이 인스턴스의 ID입니다 (비어 있으면 호스트명과 임의 접미사로 생성)
+		InstanceID string `yaml:"instance_id"`
+		// LeaseTTL은 노드 리스 유효 시간(초)이며, 이 시간 동안 연장되지 않으면 노드를 오프라인으로 정리합니다
+		LeaseTTL int `yaml:"lease_ttl"`
+		// RenewInterval은 리스 연장 주기(초)입니다
+		RenewInterval int `yaml:"renew_interval"`
+	} `yaml:"cluster"`
+	Log struct {
+		// Level은 출력할 최소 로그 레벨입니다 (debug | info | warn | error)
+		Level string `yaml:"level"`
+		// Packages는 패키지별 로그 레벨입니다 (예: websocket: debug, internal/repository: warn)
+		Packages map[string]string `yaml:"packages"`
+		// Encoding은 console 또는 json입니다
+		Encoding string `yaml:"encoding"`
+		// Dir은 로그 파일 디렉토리입니다 (비어 있으면 표준 출력에만 기록)
+		Dir string `yaml:"dir"`
+		// MaxSizeMB를 넘거나 RotateDaily이고 날짜가 바뀌면 파일을 교체하고,
+		// 교체된 파일은 MaxBackups개, MaxAgeDays일까지 보관합니다 (0이면 무제한)
+		MaxSizeMB   int  `yaml:"max_size_mb"`
+		MaxBackups  int  `yaml:"max_backups"`
+		MaxAgeDays  int  `yaml:"max_age_days"`
+		RotateDaily bool `yaml:"rotate_daily"`
+		Sampling    struct {
+			// 같은 메시지가 1초에 Initial번을 넘으면 이후에는 Thereafter번마다 한 번만 기록합니다 (Initial이 0이면 사용 안 함)
+			Initial    int `yaml:"initial"`
+			Thereafter int `yaml:"thereafter"`
+		} `yaml:"sampling"`
+	} `yaml:"log"`
+	Admin struct {
+		// Token이 있으면 관리 API(/admin/*)에 Authorization: Bearer 토큰이 필요하고,
+		// 없으면 로컬(loopback) 요청만 허용합니다
+		Token string `yaml:"token" secret:"true"`
+	} `yaml:"admin"`
+	Ingest struct {
+		// QueueSize는 수집 큐 전체 버퍼 크기, Workers는 워커 수입니다.
+		// PostgreSQL에 기록하는 트래커(inventory, ip_history, containers, services, security)는
+		// 같은 크기의 별도 큐에서 처리하므로 느려져도 InfluxDB 저장을 막지 않습니다.
+		QueueSize int `yaml:"queue_size"`
+		Workers   int `yaml:"workers"`
+		// SinkTimeout은 Sink 하나가 메트릭스 하나를 처리하는 최대 시간(초)입니다 (0은 제한 없음)
+		SinkTimeout int `yaml:"sink_timeout"`
+		// DisabledSinks에 있는 Sink는 메트릭스를 받지 않습니다 (influxdb, inventory, ip_history, alerting, anomaly, forecast, containers, services, processes, security, exposure)
+		DisabledSinks []string `yaml:"disabled_sinks"`
+	} `yaml:"ingest"`
+	SelfMetrics struct {
+		// InfluxDBEnabled가 true이면 내부 메트릭을 collector_self measurement로 기록합니다
+		InfluxDBEnabled bool `yaml:"influxdb_enabled"`
+		// Interval은 InfluxDB 기록 주기(초)입니다
+		Interval int `yaml:"interval"`
+	} `yaml:"self_metrics"`
+	Alerting struct {
+		// Enabled가 false이면 알림 규칙을 평가하지 않습니다
+		Enabled bool `yaml:"enabled"`
+		// Rules는 설정 파일에 정의한 알림 규칙입니다. API로 추가한 규칙은 DB에 저장됩니다.
+		Rules []AlertRule `yaml:"rules"`
+		// RefreshInterval은 DB의 규칙과 사일런스를 다시 읽는 주기(초)입니다
+		RefreshInterval int `yaml:"refresh_interval"`
+	} `yaml:"alerting"`
+	Anomaly struct {
+		// Enabled가 false이면 기준값을 학습하지 않고 이상을 판단하지 않습니다
+		Enabled bool `yaml:"enabled"`
+		// ZThreshold는 기준값에서 벗어난 정도(표준편차의 배수)가 이 값 이상이면 이상으로 판단합니다
+		ZThreshold float64 `yaml:"z_threshold"`
+		// Window는 지수 가중 이동 평균의 기간(샘플 수)입니다. 클수록 천천히 학습합니다.
+		Window int `yaml:"window"`
+		// MinSamples는 이상을 판단하기 전에 학습할 최소 샘플 수입니다
+		MinSamples int `yaml:"min_samples"`
+		// Seasonal이 true이면 시간대(0~23시)별 기준값을 함께 학습하고, 충분히 학습된 시간대는 그 기준값으로 판단합니다
+		Seasonal bool `yaml:"seasonal"`
+		// PersistInterval은 기준값을 PostgreSQL에 저장하는 주기(초)입니다
+		PersistInterval int `yaml:"persist_interval"`
+	} `yaml:"anomaly"`
+	Forecast struct {
+		// Enabled가 false이면 디스크 사용량 추세를 계산하지 않습니다
+		Enabled bool `yaml:"enabled"`
+		// Window는 추세를 계산할 최근 기간(초), Resolution은 보관하는 샘플 간격(초)입니다
+		Window     int `yaml:"window"`
+		Resolution int `yaml:"resolution"`
+		// MinSamples는 예측하기 전에 필요한 최소 샘플 수입니다
+		MinSamples int `yaml:"min_samples"`
+		// WarnBefore는 가득 찰 때까지 남은 예상 시간(초)이 이 값보다 짧으면 경고 이벤트를 발행합니다
+		WarnBefore int `yaml:"warn_before"`
+	} `yaml:"forecast"`
+	Security struct {
+		// Enabled가 false이면 프로세스 목록에서 보안 이벤트를 감지하지 않습니다
+		Enabled bool `yaml:"enabled"`
+		// SuspiciousPaths는 root 프로세스가 실행되면 안 되는 경로 접두사입니다
+		SuspiciousPaths []string `yaml:"suspicious_paths"`
+		// MinerPatterns는 알려진 채굴 프로그램의 프로세스 이름 또는 명령어 정규식입니다 (대소문자 구분 없음)
+		MinerPatterns []string `yaml:"miner_patterns"`
+		// SpikeRatio, SpikeMinIncrease는 프로세스/스레드 수가 평소 값의 SpikeRatio배 이상이고
+		// SpikeMinIncrease개 이상 늘었을 때 급증으로 판단하는 기준입니다
+		SpikeRatio       float64 `yaml:"spike_ratio"`
+		SpikeMinIncrease int     `yaml:"spike_min_increase"`
+		// SpikeWindow는 평소 값(지수 가중 이동 평균)의 기간(샘플 수)입니다
+		SpikeWindow int `yaml:"spike_window"`
+	} `yaml:"security"`
+	Notify struct {
+		// Events는 알림 채널로 보낼 이벤트 유형입니다 (기본 alert_firing, alert_resolved)
+		Events []string `yaml:"events"`
+		// GroupBy는 한 메시지로 묶을 기준입니다 (node_id, rule, severity, type 중 선택, 기본 node_id).
+		// GroupWait(초) 동안 같은 그룹의 이벤트를 모아 한 번에 보내며, 0이면 바로 보냅니다.
+		GroupBy   []string `yaml:"group_by"`
+		GroupWait int      `yaml:"group_wait"`
+		// QueueSize는 채널별 전송 대기 메시지 수입니다. 가득 차면 메시지를 버립니다.
+		QueueSize int             `yaml:"queue_size"`
+		Channels  []NotifyChannel `yaml:"channels"`
+	} `yaml:"notify"`
 }
 
-var cfg *Config
+// NotifyChannel은 알림을 보낼 채널입니다.
+// Type이 webhook이면 JSON을, slack과 discord는 incoming webhook 형식을 URL로 보내고, email은 SMTP로 보냅니다.
+type NotifyChannel struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+	// URL은 webhook, slack, discord 채널의 주소입니다. Slack/Discord 주소에는 토큰이 들어 있어 비밀 값으로 취급합니다.
+	URL string `yaml:"url" secret:"true"`
+	// Headers는 webhook 요청에 붙일 HTTP 헤더입니다. 인증 토큰이 들어가므로 값은 비밀 값으로 취급합니다.
+	Headers map[string]string `yaml:"headers" secret:"true"`
+	// MinSeverity보다 낮은 심각도의 알림은 보내지 않습니다 (info, warning, critical)
+	MinSeverity string `yaml:"min_severity"`
+	// RateLimit은 분당 최대 메시지 수입니다 (0은 제한 없음)
+	RateLimit int `yaml:"rate_limit"`
+	// MaxRetries는 전송 실패 시 재시도 횟수, Timeout은 전송 한 번의 제한 시간(초)입니다
+	MaxRetries int `yaml:"max_retries"`
+	Timeout    int `yaml:"timeout"`
+	// TitleTemplate, Template은 제목과 본문의 Go text/template입니다. 비어 있으면 기본 형식을 사용합니다.
+	TitleTemplate string `yaml:"title_template"`
+	Template      string `yaml:"template"`
+	SMTP          struct {
+		Host     string   `yaml:"host"`
+		Port     int      `yaml:"port"`
+		Username string   `yaml:"username"`
+		Password string   `yaml:"password" secret:"true"`
+		From     string   `yaml:"from"`
+		To       []string `yaml:"to"`
+		// StartTLS가 true이면 서버가 지원하지 않을 때 보내지 않습니다. false이면 지원할 때만 사용합니다.
+		StartTLS bool `yaml:"starttls"`
+	} `yaml:"smtp"`
+}
 
-// Load 함수는 설정 파일을 로드하고 전역 설정 객체를 초기화합니다
-func Load(filename string) error {
-	buf, err := os.ReadFile(filename)
+// AlertRule은 설정 파일에 정의하는 알림 규칙입니다.
+// Expr 예: "cpu.usage > 90 for 5m", "disk.usage_percent > 85 on mount /", "container.restarts increased"
+type AlertRule struct {
+	Name     string            `yaml:"name"`
+	Expr     string            `yaml:"expr"`
+	For      string            `yaml:"for"`
+	Severity string            `yaml:"severity"`
+	Match    map[string]string `yaml:"match"`
+	// UserKey, NodeID, ServerType은 규칙을 적용할 대상입니다. 비어 있으면 모든 노드에 적용합니다.
+	UserKey    string `yaml:"user_key"`
+	NodeID     string `yaml:"node_id"`
+	ServerType string `yaml:"server_type"`
+}
+
+// DefaultFile은 경로를 지정하지 않았을 때 사용하는 설정 파일입니다
+const DefaultFile = "configs/config.yaml"
+
+var (
+	current atomic.Pointer[Config]
+
+	// 다시 로드할 때 같은 입력을 사용하기 위해 보관
+	loadMu        sync.Mutex
+	loadFile      string
+	loadOptional  bool
+	loadOverrides []string
+)
+
+// Load 함수는 기본값, 설정 파일, 환경 변수, 명령줄 오버라이드(key=value) 순서로 설정을 구성하고
+// 검증한 뒤 전역 설정 객체를 초기화합니다
+func Load(filename string, overrides ...string) error {
+	return load(filename, false, overrides)
+}
+
+// LoadOptional은 Load와 같지만 설정 파일이 없으면 파일 단계를 건너뜁니다.
+// 경로를 명시하지 않고 DefaultFile을 사용할 때 환경 변수만으로 설정할 수 있게 합니다.
+func LoadOptional(filename string, overrides ...string) error {
+	return load(filename, true, overrides)
+}
+
+func load(filename string, optional bool, overrides []string) error {
+	loadMu.Lock()
+	defer loadMu.Unlock()
+
+	next, err := build(filename, optional, overrides)
 	if err != nil {
 		return err
 	}
 
-	cfg = &Config{}
-	err = yaml.Unmarshal(buf, cfg)
+	loadFile = filename
+	loadOptional = optional
+	loadOverrides = overrides
+	current.Store(next)
+	return nil
+}
+
+// Reload는 Load와 같은 입력으로 설정을 다시 읽어 재시작 없이 반영할 수 있는 값만 적용합니다.
+// 리스너, DB 연결처럼 재시작이 필요한 설정의 변경은 적용하지 않고 해당 경로를 반환합니다.
+// 새 설정이 유효하지 않으면 기존 설정을 그대로 유지합니다.
+func Reload() ([]string, error) {
+	loadMu.Lock()
+	defer loadMu.Unlock()
+
+	prev := current.Load()
+	if prev == nil {
+		return nil, fmt.Errorf("설정이 로드되지 않았습니다")
+	}
+	next, err := build(loadFile, loadOptional, loadOverrides)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	ignored := keepStructural(next, prev)
+	current.Store(next)
+	return ignored, nil
+}
+
+// build는 설정을 단계별로 구성하고 검증합니다.
+// optional이면 설정 파일이 없을 때 기본값에 환경 변수와 오버라이드만 적용합니다.
+func build(filename string, optional bool, overrides []string) (*Config, error) {
+	next := Default()
+
+	buf, err := os.ReadFile(filename)
+	switch {
+	case err == nil:
+		if err := yaml.UnmarshalStrict(buf, next); err != nil {
+			return nil, fmt.Errorf("설정 파일 %s 파싱 실패: %v", filename, err)
+		}
+	case optional && os.IsNotExist(err):
+	default:
+		return nil, fmt.Errorf("설정 파일 읽기 실패: %v", err)
 	}
 
-	// 환경 변수로 설정 오버라이드
-	if dbHost := os.Getenv("DB_HOST"); dbHost != "" {
-		cfg.Postgres.Host = dbHost
+	if err := applyEnv(next); err != nil {
+		return nil, err
+	}
+	for _, override := range overrides {
+		key, value, ok := strings.Cut(override, "=")
+		if !ok {
+			return nil, fmt.Errorf("잘못된 설정 오버라이드 %q (key=value 형식이어야 합니다)", override)
+		}
+		if err := Set(next, key, value); err != nil {
+			return nil, err
+		}
 	}
 
-	if influxURL := os.Getenv("INFLUXDB_URL"); influxURL != "" {
-		cfg.InfluxDB.URL = influxURL
+	if err := resolveSecrets(next); err != nil {
+		return nil, err
 	}
 
-	return nil
+	if err := Validate(next); err != nil {
+		return nil, fmt.Errorf("설정 검증 실패:\n%v", err)
+	}
+	return next, nil
 }
 
-// Get 함수는 현재 로드된 설정을 반환합니다
+// Get 함수는 현재 로드된 설정을 반환합니다.
+// 다시 로드되면 새 객체로 교체되므로 값이 필요할 때마다 호출해야 합니다.
 func Get() *Config {
-	return cfg
+	return current.Load()
 }
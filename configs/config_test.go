@@ -0,0 +1,165 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const validYAML = "influxdb: {token: test, org: test, bucket: test}\npostgres: {user: test, dbname: test}\n"
+
+func TestLoadMissingFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		optional bool
+		env      bool
+		wantErr  bool
+	}{
+		{name: "기본 경로, 환경 변수로 필수 항목 지정", optional: true, env: true},
+		{name: "기본 경로, 필수 항목 없음", optional: true, wantErr: true},
+		{name: "명시한 경로", optional: false, env: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env {
+				t.Setenv("SC_INFLUXDB_TOKEN", "env-token")
+				t.Setenv("SC_INFLUXDB_ORG", "org")
+				t.Setenv("SC_INFLUXDB_BUCKET", "bucket")
+				t.Setenv("SC_POSTGRES_USER", "user")
+				t.Setenv("SC_POSTGRES_DBNAME", "db")
+			}
+			missing := filepath.Join(t.TempDir(), "config.yaml")
+
+			load := Load
+			if tt.optional {
+				load = LoadOptional
+			}
+			err := load(missing, "log.level=debug")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("오류 = %v, 오류 기대 %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			cfg := Get()
+			if cfg.InfluxDB.Token != "env-token" || cfg.Log.Level != "debug" {
+				t.Errorf("환경 변수와 오버라이드가 적용되지 않음: token %q, log.level %q", cfg.InfluxDB.Token, cfg.Log.Level)
+			}
+		})
+	}
+}
+
+func TestLoadOptionalReadsExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(validYAML+"log: {level: warn}\n"), 0o600); err != nil {
+		t.Fatalf("설정 파일 쓰기 실패: %v", err)
+	}
+	if err := LoadOptional(path); err != nil {
+		t.Fatalf("LoadOptional: %v", err)
+	}
+	if got := Get().Log.Level; got != "warn" {
+		t.Errorf("log.level = %q, 기대 warn", got)
+	}
+
+	// 파일 단계를 건너뛰는 것은 없는 파일뿐이며, 잘못된 파일은 그대로 오류
+	if err := os.WriteFile(path, []byte("unknown_key: 1\n"), 0o600); err != nil {
+		t.Fatalf("설정 파일 쓰기 실패: %v", err)
+	}
+	if err := LoadOptional(path); err == nil {
+		t.Error("잘못된 설정 파일이 로드됨")
+	}
+}
+
+func TestReloadKeepsOptionalFile(t *testing.T) {
+	t.Setenv("SC_INFLUXDB_TOKEN", "env-token")
+	t.Setenv("SC_INFLUXDB_ORG", "org")
+	t.Setenv("SC_INFLUXDB_BUCKET", "bucket")
+	t.Setenv("SC_POSTGRES_USER", "user")
+	t.Setenv("SC_POSTGRES_DBNAME", "db")
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := LoadOptional(path); err != nil {
+		t.Fatalf("LoadOptional: %v", err)
+	}
+	if _, err := Reload(); err != nil {
+		t.Fatalf("파일 없이 Reload: %v", err)
+	}
+
+	// 나중에 생긴 파일은 다시 로드할 때 반영
+	if err := os.WriteFile(path, []byte("log: {level: error}\n"), 0o600); err != nil {
+		t.Fatalf("설정 파일 쓰기 실패: %v", err)
+	}
+	if _, err := Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if got := Get().Log.Level; got != "error" {
+		t.Errorf("log.level = %q, 기대 error", got)
+	}
+}
+
+func TestShippedConfigSecrets(t *testing.T) {
+	// 배포용 설정 파일은 비밀 값을 평문으로 담지 않고 환경 변수를 참조
+	t.Setenv("INFLUXDB_TOKEN", "token-from-env")
+	t.Setenv("POSTGRES_PASSWORD", "password-from-env")
+
+	if err := Load("../bin/configs/config.yaml"); err != nil {
+		t.Fatalf("배포용 설정 로드 실패: %v", err)
+	}
+	cfg := Get()
+	if cfg.InfluxDB.Token != "token-from-env" || cfg.Postgres.Password != "password-from-env" {
+		t.Errorf("비밀 값이 환경 변수에서 오지 않음: token %q, password %q", cfg.InfluxDB.Token, cfg.Postgres.Password)
+	}
+}
+
+func TestNotifyHeaderSecrets(t *testing.T) {
+	t.Setenv("HOOK_TOKEN", "token-from-env")
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	yaml := validYAML + `notify:
+  channels:
+    - name: hook
+      type: webhook
+      url: https://hooks.example.com/a
+      headers: {Authorization: "${HOOK_TOKEN}", X-Api-Key: plain-key}
+`
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatalf("설정 파일 쓰기 실패: %v", err)
+	}
+	if err := Load(path); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	cfg := Get()
+
+	// 헤더 값도 환경 변수 참조를 풂
+	headers := cfg.Notify.Channels[0].Headers
+	if headers["Authorization"] != "token-from-env" || headers["X-Api-Key"] != "plain-key" {
+		t.Errorf("헤더 = %v", headers)
+	}
+
+	// 로그에서 가릴 비밀 값으로 등록
+	secrets := Secrets(cfg)
+	for _, want := range []string{"token-from-env", "plain-key", "https://hooks.example.com/a"} {
+		if !slices.Contains(secrets, want) {
+			t.Errorf("비밀 값 %q가 Secrets에 없음 (%q)", want, secrets)
+		}
+	}
+
+	// --print-config 출력에는 헤더 이름만 남고 값은 가려짐
+	out, err := Redacted(cfg)
+	if err != nil {
+		t.Fatalf("Redacted: %v", err)
+	}
+	for _, leaked := range []string{"token-from-env", "plain-key"} {
+		if strings.Contains(string(out), leaked) {
+			t.Errorf("가린 설정에 %q가 남음:\n%s", leaked, out)
+		}
+	}
+	if !strings.Contains(string(out), "Authorization: '"+redactedValue+"'") {
+		t.Errorf("가린 설정에 헤더 이름이 없음:\n%s", out)
+	}
+	// 원본 설정은 바뀌지 않음
+	if headers["Authorization"] != "token-from-env" {
+		t.Errorf("Redacted가 원본 헤더를 바꿈: %v", headers)
+	}
+}
@@ -0,0 +1,89 @@
+package config
+
+// Default는 설정 파일에 없는 항목에 사용할 기본값을 반환합니다
+func Default() *Config {
+	c := &Config{}
+
+	c.Server.Host = "0.0.0.0"
+	c.Server.Port = 8087
+	c.Server.ShutdownTimeout = 30
+	c.Server.ReconnectDelay = 5
+	c.Server.TLS.ClientAuth = "none"
+	c.Server.TLS.ReloadInterval = 30
+
+	c.Connection.MaxClients = 1000
+	c.Connection.MaxPerNode = 1
+	c.Connection.DuplicateNodePolicy = "kick_old"
+	c.Connection.MaxMessageSize = 16 << 20
+	c.Connection.RateLimit.MessagesPerSecond = 2
+	c.Connection.RateLimit.Burst = 10
+	c.Connection.RateLimit.MaxViolations = 20
+
+	c.InfluxDB.URL = "http://localhost:8086"
+
+	c.Postgres.Host = "localhost"
+	c.Postgres.Port = 5432
+	c.Postgres.SSLMode = "disable"
+	c.Postgres.QueryTimeout = 5
+	c.Postgres.AutoMigrate = true
+	c.Postgres.Pool.MaxOpenConns = 25
+	c.Postgres.Pool.MaxIdleConns = 10
+	c.Postgres.Pool.ConnMaxLifetime = 1800
+	c.Postgres.Pool.ConnMaxIdleTime = 300
+
+	c.WebServer.URL = "http://localhost:8000"
+
+	c.Liveness.ExpectedInterval = 5
+	c.Liveness.StaleAfterMissed = 3
+	c.Liveness.OfflineAfterMissed = 12
+	c.Liveness.CheckInterval = 5
+
+	c.Cluster.LeaseTTL = 30
+	c.Cluster.RenewInterval = 10
+
+	c.Log.Level = "info"
+	c.Log.Encoding = "console"
+	c.Log.Dir = "logs"
+	c.Log.MaxSizeMB = 100
+	c.Log.MaxBackups = 14
+	c.Log.MaxAgeDays = 30
+	c.Log.RotateDaily = true
+	c.Log.Sampling.Initial = 100
+	c.Log.Sampling.Thereafter = 100
+
+	c.Ingest.QueueSize = 1000
+	c.Ingest.Workers = 50
+	c.Ingest.SinkTimeout = 10
+
+	c.SelfMetrics.Interval = 15
+
+	c.Alerting.Enabled = true
+	c.Alerting.RefreshInterval = 60
+
+	c.Anomaly.Enabled = true
+	c.Anomaly.ZThreshold = 4
+	c.Anomaly.Window = 720
+	c.Anomaly.MinSamples = 120
+	c.Anomaly.Seasonal = true
+	c.Anomaly.PersistInterval = 300
+
+	c.Forecast.Enabled = true
+	c.Forecast.Window = 6 * 60 * 60
+	c.Forecast.Resolution = 60
+	c.Forecast.MinSamples = 10
+	c.Forecast.WarnBefore = 24 * 60 * 60
+
+	c.Security.Enabled = true
+	c.Security.SuspiciousPaths = []string{"/tmp/", "/var/tmp/", "/dev/shm/"}
+	c.Security.MinerPatterns = []string{"xmrig", "kdevtmpfsi", "kinsing", "minerd", "cpuminer", "ethminer", `stratum\+(tcp|ssl)://`}
+	c.Security.SpikeRatio = 2
+	c.Security.SpikeMinIncrease = 200
+	c.Security.SpikeWindow = 60
+
+	c.Notify.Events = []string{"alert_firing", "alert_resolved"}
+	c.Notify.GroupBy = []string{"node_id"}
+	c.Notify.GroupWait = 30
+	c.Notify.QueueSize = 100
+
+	return c
+}
@@ -0,0 +1,271 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// envPrefix는 설정 항목을 덮어쓰는 환경 변수의 접두사입니다.
+// 예: postgres.host -> SC_POSTGRES_HOST, connection.rate_limit.burst -> SC_CONNECTION_RATE_LIMIT_BURST
+const envPrefix = "SC_"
+
+// legacyEnv는 이전 버전과 docker-compose에서 사용하던 환경 변수입니다. SC_* 변수가 우선합니다.
+var legacyEnv = []struct {
+	name string
+	key  string
+}{
+	{"DB_HOST", "postgres.host"},
+	{"POSTGRES_HOST", "postgres.host"},
+	{"INFLUXDB_URL", "influxdb.url"},
+}
+
+// redactedValue는 출력 시 secret 필드 대신 표시하는 값입니다
+const redactedValue = "******"
+
+// structuralKeys는 재시작해야 반영되는 설정입니다. 다시 로드할 때 이 경로(하위 항목 포함)는 바뀌지 않습니다.
+var structuralKeys = []string{
+	"server.host",
+	"server.port",
+	"server.tls",
+	"influxdb",
+	"postgres",
+	"geoip",
+	"cluster",
+	"log.encoding",
+	"log.dir",
+	"log.max_size_mb",
+	"log.max_backups",
+	"log.max_age_days",
+	"log.rotate_daily",
+	"log.sampling",
+	"ingest.queue_size",
+	"ingest.workers",
+	"self_metrics",
+	"notify",
+}
+
+// field는 설정의 말단 항목 하나입니다
+type field struct {
+	key    string // 점으로 구분한 yaml 경로 (예: server.tls.cert_file)
+	value  reflect.Value
+	secret bool
+}
+
+// fields는 설정의 모든 말단 항목을 선언 순서대로 반환합니다
+func fields(c *Config) []field {
+	return walkFields(reflect.ValueOf(c).Elem(), "", nil)
+}
+
+// listFields는 구조체 목록(예: notify.channels) 항목의 말단 필드를 key[i].name 경로로 반환합니다.
+// 목록 항목은 환경 변수나 -set으로 설정할 수 없어 fields에 포함하지 않으며, 비밀 값 처리에만 사용합니다.
+func listFields(c *Config) []field {
+	var result []field
+	for _, f := range fields(c) {
+		if !isStructList(f.value) {
+			continue
+		}
+		for i := 0; i < f.value.Len(); i++ {
+			result = walkFields(f.value.Index(i), fmt.Sprintf("%s[%d]", f.key, i), result)
+		}
+	}
+	return result
+}
+
+func walkFields(v reflect.Value, prefix string, result []field) []field {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, _, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		key := strings.ToLower(name)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Struct {
+			result = walkFields(fv, key, result)
+			continue
+		}
+		result = append(result, field{key: key, value: fv, secret: sf.Tag.Get("secret") == "true"})
+	}
+	return result
+}
+
+func isStructList(v reflect.Value) bool {
+	return v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Struct
+}
+
+// Keys는 설정할 수 있는 모든 항목의 경로를 반환합니다
+func Keys() []string {
+	all := fields(Default())
+	keys := make([]string, len(all))
+	for i, f := range all {
+		keys[i] = f.key
+	}
+	return keys
+}
+
+// EnvName은 설정 경로에 해당하는 환경 변수 이름을 반환합니다
+func EnvName(key string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// Set은 경로(예: connection.rate_limit.burst)로 지정한 항목에 문자열 값을 적용합니다.
+// 목록 항목은 쉼표로 구분하고, 맵 항목은 key=value를 쉼표로 구분합니다.
+func Set(c *Config, key, raw string) error {
+	key = strings.ToLower(strings.TrimSpace(key))
+	for _, f := range fields(c) {
+		if f.key == key {
+			if err := setValue(f.value, raw); err != nil {
+				return fmt.Errorf("%s: %v", key, err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("알 수 없는 설정 항목 %q", key)
+}
+
+func setValue(v reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("true 또는 false여야 합니다 (%q)", raw)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("정수여야 합니다 (%q)", raw)
+		}
+		v.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("숫자여야 합니다 (%q)", raw)
+		}
+		v.SetFloat(f)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String || v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("지원하지 않는 맵 형식 %s", v.Type())
+		}
+		items := make(map[string]string)
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			k, value, ok := strings.Cut(item, "=")
+			if !ok {
+				return fmt.Errorf("key=value 목록이어야 합니다 (%q)", item)
+			}
+			items[strings.TrimSpace(k)] = strings.TrimSpace(value)
+		}
+		v.Set(reflect.ValueOf(items))
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("지원하지 않는 목록 형식 %s", v.Type())
+		}
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("지원하지 않는 형식 %s", v.Type())
+	}
+	return nil
+}
+
+// applyEnv는 이전 환경 변수와 SC_* 환경 변수를 설정에 적용합니다
+func applyEnv(c *Config) error {
+	for _, legacy := range legacyEnv {
+		if value, ok := os.LookupEnv(legacy.name); ok && value != "" {
+			if err := Set(c, legacy.key, value); err != nil {
+				return fmt.Errorf("환경 변수 %s: %v", legacy.name, err)
+			}
+		}
+	}
+	for _, f := range fields(c) {
+		name := EnvName(f.key)
+		if value, ok := os.LookupEnv(name); ok {
+			if err := setValue(f.value, value); err != nil {
+				return fmt.Errorf("환경 변수 %s: %v", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// keepStructural은 next의 재시작이 필요한 항목을 prev 값으로 되돌리고, 바뀌었던 항목의 경로를 반환합니다
+func keepStructural(next, prev *Config) []string {
+	prevFields := fields(prev)
+	var ignored []string
+	for i, f := range fields(next) {
+		if !isStructural(f.key) {
+			continue
+		}
+		old := prevFields[i].value
+		if reflect.DeepEqual(f.value.Interface(), old.Interface()) {
+			continue
+		}
+		f.value.Set(old)
+		ignored = append(ignored, f.key)
+	}
+	return ignored
+}
+
+func isStructural(key string) bool {
+	for _, prefix := range structuralKeys {
+		if key == prefix || strings.HasPrefix(key, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Redacted는 secret 항목을 가린 설정을 YAML로 반환합니다
+func Redacted(c *Config) ([]byte, error) {
+	copied := *c
+	// 구조체 목록은 원본과 배열을 공유하므로 항목을 가리기 전에 복사
+	for _, f := range fields(&copied) {
+		if isStructList(f.value) && !f.value.IsNil() {
+			list := reflect.MakeSlice(f.value.Type(), f.value.Len(), f.value.Len())
+			reflect.Copy(list, f.value)
+			f.value.Set(list)
+		}
+	}
+	for _, f := range append(fields(&copied), listFields(&copied)...) {
+		if !f.secret {
+			continue
+		}
+		// 맵 항목은 키는 남기고 값만 가리며, 원본 맵을 바꾸지 않도록 새 맵으로 교체
+		if f.value.Kind() == reflect.Map {
+			if f.value.Len() == 0 {
+				continue
+			}
+			redacted := reflect.MakeMapWithSize(f.value.Type(), f.value.Len())
+			iter := f.value.MapRange()
+			for iter.Next() {
+				redacted.SetMapIndex(iter.Key(), reflect.ValueOf(redactedValue))
+			}
+			f.value.Set(redacted)
+			continue
+		}
+		if f.value.String() != "" {
+			f.value.SetString(redactedValue)
+		}
+	}
+	return yaml.Marshal(&copied)
+}
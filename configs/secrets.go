@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// secretFiles는 비밀 값과 그 값을 읽을 파일 경로 항목의 쌍입니다
+var secretFiles = []struct {
+	secret string
+	file   string
+}{
+	{"influxdb.token", "influxdb.token_file"},
+	{"postgres.password", "postgres.password_file"},
+}
+
+// envRef는 비밀 값에 쓸 수 있는 환경 변수 참조 형식입니다 (예: ${INFLUX_TOKEN})
+var envRef = regexp.MustCompile(`^\$\{([A-Za-z_][A-Za-z0-9_]*)\}$`)
+
+// resolveSecrets는 비밀 항목의 환경 변수 참조를 풀고, *_file 항목이 있으면 파일 내용으로 값을 채웁니다.
+// 파일이 지정되면 설정 파일이나 환경 변수의 값보다 우선합니다.
+func resolveSecrets(c *Config) error {
+	all := fields(c)
+	byKey := make(map[string]field, len(all))
+	for _, f := range all {
+		byKey[f.key] = f
+	}
+
+	for _, f := range append(all, listFields(c)...) {
+		if !f.secret {
+			continue
+		}
+		// 맵 항목(예: 알림 채널 헤더)은 값마다 참조를 풂
+		if f.value.Kind() == reflect.Map {
+			iter := f.value.MapRange()
+			for iter.Next() {
+				value, err := resolveEnvRef(f.key+"."+iter.Key().String(), iter.Value().String())
+				if err != nil {
+					return err
+				}
+				f.value.SetMapIndex(iter.Key(), reflect.ValueOf(value))
+			}
+			continue
+		}
+		value, err := resolveEnvRef(f.key, f.value.String())
+		if err != nil {
+			return err
+		}
+		f.value.SetString(value)
+	}
+
+	for _, pair := range secretFiles {
+		path := byKey[pair.file].value.String()
+		if path == "" {
+			continue
+		}
+		buf, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("%s: 비밀 파일 읽기 실패: %v", pair.file, err)
+		}
+		// 편집기나 echo가 붙이는 마지막 줄바꿈 제거
+		byKey[pair.secret].value.SetString(strings.TrimRight(string(buf), "\r\n"))
+	}
+	return nil
+}
+
+// resolveEnvRef는 value가 ${NAME} 형식이면 환경 변수 값을, 아니면 value를 그대로 반환합니다
+func resolveEnvRef(key, value string) (string, error) {
+	m := envRef.FindStringSubmatch(value)
+	if m == nil {
+		return value, nil
+	}
+	resolved, ok := os.LookupEnv(m[1])
+	if !ok {
+		return "", fmt.Errorf("%s: 참조한 환경 변수 %s가 없습니다", key, m[1])
+	}
+	return resolved, nil
+}
+
+// Secrets는 설정에 들어 있는 비밀 값을 반환합니다. 로그에서 가리는 데 사용합니다.
+func Secrets(c *Config) []string {
+	var secrets []string
+	for _, f := range append(fields(c), listFields(c)...) {
+		if !f.secret {
+			continue
+		}
+		if f.value.Kind() == reflect.Map {
+			iter := f.value.MapRange()
+			for iter.Next() {
+				if value := iter.Value().String(); value != "" {
+					secrets = append(secrets, value)
+				}
+			}
+			continue
+		}
+		if f.value.String() != "" {
+			secrets = append(secrets, f.value.String())
+		}
+	}
+	return secrets
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+)
+
+// 선택지가 정해진 설정 항목의 허용 값
+var (
+	duplicatePolicies = []string{"kick_old", "reject_new"}
+	clientAuthModes   = []string{"none", "request", "require"}
+	sslModes          = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+	logLevels         = []string{"debug", "info", "warn", "error"}
+	logEncodings      = []string{"console", "json"}
+	sinkNames         = []string{"influxdb", "inventory", "ip_history", "alerting", "anomaly", "forecast", "containers", "services", "processes", "security", "exposure"}
+	severities        = []string{"info", "warning", "critical"}
+	notifyTypes       = []string{"webhook", "slack", "discord", "email"}
+	notifyGroupBy     = []string{"node_id", "rule", "severity", "type"}
+)
+
+// Validate는 설정 값의 범위와 항목 간 관계를 검사하여 잘못된 항목을 모두 모아 반환합니다
+func Validate(c *Config) error {
+	var errs []error
+	check := func(ok bool, key, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+		}
+	}
+	nonNegative := func(key string, n int64) {
+		check(n >= 0, key, "0 이상이어야 합니다 (현재 %d)", n)
+	}
+
+	// server
+	check(c.Server.Port > 0 && c.Server.Port <= 65535, "server.port", "1~65535 범위여야 합니다 (현재 %d)", c.Server.Port)
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout", "1 이상이어야 합니다 (현재 %d)", c.Server.ShutdownTimeout)
+	nonNegative("server.reconnect_delay", int64(c.Server.ReconnectDelay))
+	tls := c.Server.TLS
+	check(slices.Contains(clientAuthModes, tls.ClientAuth), "server.tls.client_auth", "%v 중 하나여야 합니다 (현재 %q)", clientAuthModes, tls.ClientAuth)
+	if tls.Enabled {
+		check(tls.CertFile != "", "server.tls.cert_file", "TLS를 사용하려면 필요합니다")
+		check(tls.KeyFile != "", "server.tls.key_file", "TLS를 사용하려면 필요합니다")
+		check(tls.ClientAuth == "none" || tls.ClientCAFile != "", "server.tls.client_ca_file", "client_auth가 %q이면 필요합니다", tls.ClientAuth)
+	}
+	nonNegative("server.tls.reload_interval", int64(tls.ReloadInterval))
+
+	// connection
+	conn := c.Connection
+	nonNegative("connection.max_clients", int64(conn.MaxClients))
+	nonNegative("connection.max_per_ip", int64(conn.MaxPerIP))
+	nonNegative("connection.max_per_key", int64(conn.MaxPerKey))
+	nonNegative("connection.max_per_node", int64(conn.MaxPerNode))
+	nonNegative("connection.max_message_size", conn.MaxMessageSize)
+	check(slices.Contains(duplicatePolicies, conn.DuplicateNodePolicy), "connection.duplicate_node_policy", "%v 중 하나여야 합니다 (현재 %q)", duplicatePolicies, conn.DuplicateNodePolicy)
+	check(conn.RateLimit.MessagesPerSecond >= 0, "connection.rate_limit.messages_per_second", "0 이상이어야 합니다 (현재 %v)", conn.RateLimit.MessagesPerSecond)
+	check(conn.RateLimit.MessagesPerSecond == 0 || conn.RateLimit.Burst >= 1, "connection.rate_limit.burst", "속도 제한을 사용하려면 1 이상이어야 합니다 (현재 %d)", conn.RateLimit.Burst)
+	nonNegative("connection.rate_limit.max_violations", int64(conn.RateLimit.MaxViolations))
+
+	// influxdb, postgres
+	checkURL := func(key, raw string) {
+		u, err := url.Parse(raw)
+		check(err == nil && u.Scheme != "" && u.Host != "", key, "올바른 URL이어야 합니다 (현재 %q)", raw)
+	}
+	checkURL("influxdb.url", c.InfluxDB.URL)
+	check(c.InfluxDB.Token != "", "influxdb.token", "필요합니다 (token, token_file 또는 SC_INFLUXDB_TOKEN)")
+	check(c.InfluxDB.Org != "", "influxdb.org", "필요합니다")
+	check(c.InfluxDB.Bucket != "", "influxdb.bucket", "필요합니다")
+	check(c.Postgres.Host != "", "postgres.host", "필요합니다")
+	check(c.Postgres.Port > 0 && c.Postgres.Port <= 65535, "postgres.port", "1~65535 범위여야 합니다 (현재 %d)", c.Postgres.Port)
+	check(c.Postgres.User != "", "postgres.user", "필요합니다")
+	check(c.Postgres.DBName != "", "postgres.dbname", "필요합니다")
+	check(slices.Contains(sslModes, c.Postgres.SSLMode), "postgres.sslmode", "%v 중 하나여야 합니다 (현재 %q)", sslModes, c.Postgres.SSLMode)
+	check(c.Postgres.QueryTimeout > 0, "postgres.query_timeout", "1 이상이어야 합니다 (현재 %d)", c.Postgres.QueryTimeout)
+	pool := c.Postgres.Pool
+	nonNegative("postgres.pool.max_open_conns", int64(pool.MaxOpenConns))
+	nonNegative("postgres.pool.max_idle_conns", int64(pool.MaxIdleConns))
+	check(pool.MaxOpenConns == 0 || pool.MaxIdleConns <= pool.MaxOpenConns, "postgres.pool.max_idle_conns", "max_open_conns(%d) 이하여야 합니다 (현재 %d)", pool.MaxOpenConns, pool.MaxIdleConns)
+	nonNegative("postgres.pool.conn_max_lifetime", int64(pool.ConnMaxLifetime))
+	nonNegative("postgres.pool.conn_max_idle_time", int64(pool.ConnMaxIdleTime))
+	if c.WebServer.URL != "" {
+		checkURL("webServer.url", c.WebServer.URL)
+	}
+
+	// liveness
+	live := c.Liveness
+	check(live.ExpectedInterval > 0, "liveness.expected_interval", "1 이상이어야 합니다 (현재 %d)", live.ExpectedInterval)
+	check(live.StaleAfterMissed > 0, "liveness.stale_after_missed", "1 이상이어야 합니다 (현재 %d)", live.StaleAfterMissed)
+	check(live.OfflineAfterMissed > live.StaleAfterMissed, "liveness.offline_after_missed", "stale_after_missed(%d)보다 커야 합니다 (현재 %d)", live.StaleAfterMissed, live.OfflineAfterMissed)
+	check(live.CheckInterval > 0, "liveness.check_interval", "1 이상이어야 합니다 (현재 %d)", live.CheckInterval)
+
+	// cluster
+	if c.Cluster.Enabled {
+		check(c.Cluster.LeaseTTL > 0, "cluster.lease_ttl", "1 이상이어야 합니다 (현재 %d)", c.Cluster.LeaseTTL)
+		check(c.Cluster.RenewInterval > 0 && c.Cluster.RenewInterval < c.Cluster.LeaseTTL, "cluster.renew_interval", "1 이상이고 lease_ttl(%d)보다 작아야 합니다 (현재 %d)", c.Cluster.LeaseTTL, c.Cluster.RenewInterval)
+	}
+
+	// log, ingest, self_metrics
+	check(slices.Contains(logLevels, c.Log.Level), "log.level", "%v 중 하나여야 합니다 (현재 %q)", logLevels, c.Log.Level)
+	for pkg, level := range c.Log.Packages {
+		check(slices.Contains(logLevels, level), "log.packages."+pkg, "%v 중 하나여야 합니다 (현재 %q)", logLevels, level)
+	}
+	check(slices.Contains(logEncodings, c.Log.Encoding), "log.encoding", "%v 중 하나여야 합니다 (현재 %q)", logEncodings, c.Log.Encoding)
+	nonNegative("log.max_size_mb", int64(c.Log.MaxSizeMB))
+	nonNegative("log.max_backups", int64(c.Log.MaxBackups))
+	nonNegative("log.max_age_days", int64(c.Log.MaxAgeDays))
+	nonNegative("log.sampling.initial", int64(c.Log.Sampling.Initial))
+	nonNegative("log.sampling.thereafter", int64(c.Log.Sampling.Thereafter))
+	check(c.Ingest.QueueSize > 0, "ingest.queue_size", "1 이상이어야 합니다 (현재 %d)", c.Ingest.QueueSize)
+	check(c.Ingest.Workers > 0, "ingest.workers", "1 이상이어야 합니다 (현재 %d)", c.Ingest.Workers)
+	nonNegative("ingest.sink_timeout", int64(c.Ingest.SinkTimeout))
+	for _, name := range c.Ingest.DisabledSinks {
+		check(slices.Contains(sinkNames, name), "ingest.disabled_sinks", "%v 중 하나여야 합니다 (현재 %q)", sinkNames, name)
+	}
+	if c.SelfMetrics.InfluxDBEnabled {
+		check(c.SelfMetrics.Interval > 0, "self_metrics.interval", "1 이상이어야 합니다 (현재 %d)", c.SelfMetrics.Interval)
+	}
+
+	// alerting
+	check(c.Alerting.RefreshInterval > 0, "alerting.refresh_interval", "1 이상이어야 합니다 (현재 %d)", c.Alerting.RefreshInterval)
+	ruleNames := make(map[string]bool)
+	for i, rule := range c.Alerting.Rules {
+		key := fmt.Sprintf("alerting.rules[%d]", i)
+		check(rule.Name != "", key+".name", "비어 있을 수 없습니다")
+		check(!ruleNames[rule.Name], key+".name", "이름이 중복됩니다 (%q)", rule.Name)
+		check(rule.Expr != "", key+".expr", "비어 있을 수 없습니다")
+		ruleNames[rule.Name] = true
+	}
+
+	// anomaly
+	check(c.Anomaly.ZThreshold > 0, "anomaly.z_threshold", "0보다 커야 합니다 (현재 %g)", c.Anomaly.ZThreshold)
+	check(c.Anomaly.Window > 1, "anomaly.window", "2 이상이어야 합니다 (현재 %d)", c.Anomaly.Window)
+	nonNegative("anomaly.min_samples", int64(c.Anomaly.MinSamples))
+	check(c.Anomaly.PersistInterval > 0, "anomaly.persist_interval", "1 이상이어야 합니다 (현재 %d)", c.Anomaly.PersistInterval)
+
+	// forecast
+	check(c.Forecast.Resolution > 0, "forecast.resolution", "1 이상이어야 합니다 (현재 %d)", c.Forecast.Resolution)
+	check(c.Forecast.Window >= c.Forecast.Resolution, "forecast.window", "resolution(%d) 이상이어야 합니다 (현재 %d)", c.Forecast.Resolution, c.Forecast.Window)
+	check(c.Forecast.MinSamples >= 2, "forecast.min_samples", "2 이상이어야 합니다 (현재 %d)", c.Forecast.MinSamples)
+	nonNegative("forecast.warn_before", int64(c.Forecast.WarnBefore))
+
+	// security
+	for i, pattern := range c.Security.MinerPatterns {
+		_, err := regexp.Compile(pattern)
+		check(err == nil, fmt.Sprintf("security.miner_patterns[%d]", i), "올바른 정규식이 아닙니다 (%v)", err)
+	}
+	check(c.Security.SpikeRatio > 1, "security.spike_ratio", "1보다 커야 합니다 (현재 %g)", c.Security.SpikeRatio)
+	nonNegative("security.spike_min_increase", int64(c.Security.SpikeMinIncrease))
+	check(c.Security.SpikeWindow > 1, "security.spike_window", "2 이상이어야 합니다 (현재 %d)", c.Security.SpikeWindow)
+
+	// notify
+	nonNegative("notify.group_wait", int64(c.Notify.GroupWait))
+	check(c.Notify.QueueSize > 0, "notify.queue_size", "1 이상이어야 합니다 (현재 %d)", c.Notify.QueueSize)
+	for _, key := range c.Notify.GroupBy {
+		check(slices.Contains(notifyGroupBy, key), "notify.group_by", "%v 중 하나여야 합니다 (현재 %q)", notifyGroupBy, key)
+	}
+	channelNames := make(map[string]bool)
+	for i, ch := range c.Notify.Channels {
+		key := fmt.Sprintf("notify.channels[%d]", i)
+		check(ch.Name != "", key+".name", "비어 있을 수 없습니다")
+		check(!channelNames[ch.Name], key+".name", "이름이 중복됩니다 (%q)", ch.Name)
+		channelNames[ch.Name] = true
+		check(slices.Contains(notifyTypes, ch.Type), key+".type", "%v 중 하나여야 합니다 (현재 %q)", notifyTypes, ch.Type)
+		if ch.MinSeverity != "" {
+			check(slices.Contains(severities, ch.MinSeverity), key+".min_severity", "%v 중 하나여야 합니다 (현재 %q)", severities, ch.MinSeverity)
+		}
+		nonNegative(key+".rate_limit", int64(ch.RateLimit))
+		nonNegative(key+".max_retries", int64(ch.MaxRetries))
+		nonNegative(key+".timeout", int64(ch.Timeout))
+		if ch.Type == "email" {
+			check(ch.SMTP.Host != "", key+".smtp.host", "email 채널에 필요합니다")
+			check(ch.SMTP.Port > 0 && ch.SMTP.Port <= 65535, key+".smtp.port", "1~65535 범위여야 합니다 (현재 %d)", ch.SMTP.Port)
+			check(ch.SMTP.From != "", key+".smtp.from", "email 채널에 필요합니다")
+			check(len(ch.SMTP.To) > 0, key+".smtp.to", "email 채널에 필요합니다")
+		} else if ch.Type != "" {
+			u, err := url.Parse(ch.URL)
+			check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", key+".url", "http(s) 주소여야 합니다")
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,37 @@
+package admin
+
+import (
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"strings"
+
+	config "system-collector/configs"
+	"system-collector/internal/httpapi"
+)
+
+// RequireAdmin은 관리 API 요청을 인증합니다. 노드 정보를 담은 다른 패키지의 조회/쓰기 API에도 사용합니다
+// (에이전트 WebSocket과 같은 포트에서 제공되므로 인증 없이 열어 두지 않음).
+// admin.token이 설정되어 있으면 Bearer 토큰을 확인하고, 없으면 로컬(loopback) 요청만 허용합니다.
+func RequireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := config.Get().Admin.Token
+		if token == "" {
+			host, _, err := net.SplitHostPort(r.RemoteAddr)
+			if ip := net.ParseIP(host); err != nil || ip == nil || !ip.IsLoopback() {
+				httpapi.WriteError(w, http.StatusForbidden, "admin.token이 없으면 로컬 요청만 허용됩니다")
+				return
+			}
+			next(w, r)
+			return
+		}
+
+		given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			httpapi.WriteError(w, http.StatusUnauthorized, "인증이 필요합니다")
+			return
+		}
+		next(w, r)
+	}
+}
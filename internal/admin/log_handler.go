@@ -0,0 +1,71 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"system-collector/internal/httpapi"
+	"system-collector/pkg/logger"
+)
+
+// LogHandler는 실행 중 로그 레벨을 조회하고 바꾸는 관리 API를 제공합니다
+type LogHandler struct{}
+
+// NewLogHandler는 로그 레벨 관리 핸들러를 생성합니다
+func NewLogHandler() *LogHandler {
+	return &LogHandler{}
+}
+
+// RegisterRoutes는 핸들러를 mux에 등록합니다
+func (h *LogHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /admin/log/level", RequireAdmin(h.handleGet))
+	mux.HandleFunc("PUT /admin/log/level", RequireAdmin(h.handlePut))
+}
+
+// logLevelResponse는 현재 로그 레벨입니다
+type logLevelResponse struct {
+	Level    string            `json:"level"`
+	Packages map[string]string `json:"packages"`
+}
+
+// logLevelRequest는 로그 레벨 변경 요청입니다.
+// Package가 비어 있으면 전역 레벨을, 있으면 해당 패키지 레벨을 바꾸며 Level이 비어 있으면 패키지 레벨을 해제합니다.
+type logLevelRequest struct {
+	Level   string `json:"level"`
+	Package string `json:"package"`
+}
+
+func (h *LogHandler) handleGet(w http.ResponseWriter, r *http.Request) {
+	level, packages := logger.Levels()
+	httpapi.WriteJSON(w, http.StatusOK, logLevelResponse{Level: level, Packages: packages})
+}
+
+// handlePut은 로그 레벨을 바꿉니다. 설정을 다시 로드하면 설정 파일의 값으로 돌아갑니다.
+func (h *LogHandler) handlePut(w http.ResponseWriter, r *http.Request) {
+	sugar := logger.GetCustomLogger()
+
+	var req logLevelRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+		httpapi.WriteError(w, http.StatusBadRequest, "요청 본문이 올바른 JSON이 아닙니다")
+		return
+	}
+
+	var err error
+	if req.Package == "" {
+		if req.Level == "" {
+			httpapi.WriteError(w, http.StatusBadRequest, "level이 필요합니다")
+			return
+		}
+		err = logger.SetLevel(req.Level)
+	} else {
+		err = logger.SetPackageLevel(req.Package, req.Level)
+	}
+	if err != nil {
+		httpapi.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	sugar.Infow("로그 레벨 변경", "level", req.Level, "package", req.Package, "remoteAddr", r.RemoteAddr)
+
+	level, packages := logger.Levels()
+	httpapi.WriteJSON(w, http.StatusOK, logLevelResponse{Level: level, Packages: packages})
+}
@@ -0,0 +1,590 @@
+package alerting
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	config "system-collector/configs"
+	"system-collector/internal/events"
+	"system-collector/internal/registry"
+	"system-collector/internal/repository"
+	"system-collector/pkg/logger"
+	"system-collector/pkg/models"
+)
+
+// defaultIncreaseHold는 for가 없는 increased 규칙의 알림을 유지하는 기간입니다
+const defaultIncreaseHold = 5 * time.Minute
+
+// series는 시리즈 하나의 평가 상태입니다
+type series struct {
+	value       float64
+	hasValue    bool
+	increasedAt time.Time
+}
+
+// nodeState는 노드 하나의 알림과 시리즈 상태입니다
+type nodeState struct {
+	alerts map[string]*models.Alert // fingerprint -> 대기 중이거나 발생한 알림
+	series map[string]*series       // fingerprint -> 이전 값 (increased 규칙)
+}
+
+// Engine은 수집 큐의 Sink로 동작하며 수신한 메트릭스에 알림 규칙을 적용합니다.
+// 조건이 for 기간 이상 계속되면 알림을 발생시키고, 조건이 끝나면 해소합니다.
+// 상태 변화는 PostgreSQL에 저장되고 이벤트 버스로 발행됩니다.
+type Engine struct {
+	repo     *repository.AlertRepository
+	bus      *events.Bus
+	registry *registry.NodeRegistry
+
+	rulesMu  sync.RWMutex
+	rules    []*rule
+	silences []models.Silence
+
+	mu    sync.Mutex
+	nodes map[string]*nodeState
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewEngine은 알림 엔진을 생성합니다. 규칙은 Start 또는 Refresh에서 읽습니다.
+func NewEngine(repo *repository.AlertRepository, bus *events.Bus, registry *registry.NodeRegistry) *Engine {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("알림 엔진 초기화 중")
+
+	return &Engine{
+		repo:     repo,
+		bus:      bus,
+		registry: registry,
+		nodes:    make(map[string]*nodeState),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Name은 Sink 이름을 반환합니다
+func (e *Engine) Name() string {
+	return "alerting"
+}
+
+// Start는 규칙을 읽고 주기적으로 DB의 규칙과 사일런스를 다시 읽습니다
+func (e *Engine) Start() {
+	sugar := logger.GetCustomLogger()
+	if err := e.Refresh(context.Background()); err != nil {
+		sugar.Errorw("알림 규칙 로드 실패", "error", err)
+	}
+
+	go func() {
+		defer close(e.done)
+		ticker := time.NewTicker(time.Duration(config.Get().Alerting.RefreshInterval) * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-e.stop:
+				return
+			case <-ticker.C:
+				if err := e.Refresh(context.Background()); err != nil {
+					sugar.Errorw("알림 규칙 갱신 실패", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop은 주기적인 규칙 갱신을 멈춥니다
+func (e *Engine) Stop() {
+	close(e.stop)
+	<-e.done
+}
+
+// Refresh는 설정 파일과 DB의 규칙, 유효한 사일런스를 다시 읽습니다.
+// 잘못된 규칙은 건너뛰고 나머지 규칙은 적용하며, 건너뛴 규칙의 오류를 모아 반환합니다.
+func (e *Engine) Refresh(ctx context.Context) error {
+	sugar := logger.FromContext(ctx)
+
+	dbRules, err := e.repo.GetRules(ctx)
+	if err != nil {
+		return fmt.Errorf("알림 규칙 조회 실패: %v", err)
+	}
+	silences, err := e.repo.GetSilences(ctx)
+	if err != nil {
+		return fmt.Errorf("사일런스 조회 실패: %v", err)
+	}
+
+	var defs []models.AlertRule
+	for _, r := range config.Get().Alerting.Rules {
+		defs = append(defs, models.AlertRule{
+			Name:       r.Name,
+			Expr:       r.Expr,
+			For:        r.For,
+			Severity:   r.Severity,
+			Match:      r.Match,
+			UserKey:    r.UserKey,
+			NodeID:     r.NodeID,
+			ServerType: r.ServerType,
+			Enabled:    true,
+			Source:     models.RuleSourceConfig,
+		})
+	}
+	defs = append(defs, dbRules...)
+
+	var (
+		rules []*rule
+		errs  []error
+	)
+	for _, def := range defs {
+		r, err := compile(def)
+		if err != nil {
+			sugar.Errorw("잘못된 알림 규칙을 건너뜀", "rule", def.Name, "source", def.Source, "error", err)
+			errs = append(errs, err)
+			continue
+		}
+		rules = append(rules, r)
+	}
+
+	e.rulesMu.Lock()
+	e.rules = rules
+	e.silences = silences
+	e.rulesMu.Unlock()
+
+	sugar.Debugw("알림 규칙 갱신 완료", "rules", len(rules), "silences", len(silences))
+	return errors.Join(errs...)
+}
+
+// Write는 메트릭스에 노드에 적용되는 규칙을 평가하고 알림 상태를 바꿉니다.
+// alerting.enabled가 false이면 아무 것도 하지 않습니다 (설정을 다시 로드하면 바로 반영됩니다).
+// 같은 노드의 메트릭스는 수집 큐의 같은 워커가 순서대로 처리합니다.
+func (e *Engine) Write(ctx context.Context, metrics *models.SystemMetrics) error {
+	if !config.Get().Alerting.Enabled {
+		return nil
+	}
+
+	nodeID := metrics.Key
+	serverType := registry.InferServerType(metrics)
+	node := eventNode{name: metrics.System.Hostname, hostname: metrics.System.Hostname}
+	if registered, ok := e.registry.Get(nodeID); ok {
+		if registered.ServerType != "" {
+			serverType = registered.ServerType
+		}
+		if registered.Name != "" {
+			node.name = registered.Name
+		}
+	}
+
+	e.rulesMu.RLock()
+	rules := e.rules
+	e.rulesMu.RUnlock()
+
+	st, err := e.state(ctx, nodeID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var changes []*models.Alert
+	metricOf := make(map[string]string) // 규칙 키 -> 지표 이름 (알림 메시지용)
+
+	e.mu.Lock()
+	seen := make(map[string]bool)
+	for _, r := range rules {
+		metricOf[r.key] = r.metric
+		if !r.appliesTo(nodeID, metrics.USER_ID, serverType) {
+			continue
+		}
+		for _, s := range r.source.extract(metrics) {
+			if !r.matches(s) {
+				continue
+			}
+			fp := fingerprint(r.key, nodeID, s.labels)
+			seen[fp] = true
+			if a := e.evaluate(st, r, fp, nodeID, s, now); a != nil {
+				changes = append(changes, a)
+			}
+		}
+	}
+	// 규칙이 바뀌었거나 시리즈(디스크, 컨테이너 등)가 사라진 알림은 조건이 끝난 것으로 봅니다
+	for fp, a := range st.alerts {
+		if !seen[fp] {
+			if resolved := e.clear(st, fp, a, a.Value, now); resolved != nil {
+				changes = append(changes, resolved)
+			}
+		}
+	}
+	for fp := range st.series {
+		if !seen[fp] {
+			delete(st.series, fp)
+		}
+	}
+	e.mu.Unlock()
+
+	for _, a := range changes {
+		e.persist(ctx, st, a)
+		e.notify(a, metricOf[a.RuleKey], node)
+	}
+	return nil
+}
+
+// state는 노드의 알림 상태를 반환합니다. 처음 보는 노드면 DB에서 해소되지 않은 알림을 읽어
+// 재시작하거나 노드가 다른 인스턴스에서 옮겨 온 뒤에도 같은 알림을 다시 발생시키지 않습니다.
+func (e *Engine) state(ctx context.Context, nodeID string) (*nodeState, error) {
+	e.mu.Lock()
+	st, ok := e.nodes[nodeID]
+	e.mu.Unlock()
+	if ok {
+		return st, nil
+	}
+
+	firing, err := e.repo.GetFiringAlerts(ctx, nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("발생 중인 알림 조회 실패: %v", err)
+	}
+	st = &nodeState{
+		alerts: make(map[string]*models.Alert, len(firing)),
+		series: make(map[string]*series),
+	}
+	for i := range firing {
+		firing[i].State = models.AlertFiring
+		st.alerts[firing[i].Fingerprint] = &firing[i]
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if cur, ok := e.nodes[nodeID]; ok {
+		return cur, nil
+	}
+	e.nodes[nodeID] = st
+	return st, nil
+}
+
+// evaluate는 시리즈 하나에 규칙을 적용하고, 알림이 발생하거나 해소되었으면 그 알림의 복사본을 반환합니다.
+// e.mu를 잡은 상태에서 호출해야 합니다.
+func (e *Engine) evaluate(st *nodeState, r *rule, fp, nodeID string, s sample, now time.Time) *models.Alert {
+	wait := r.forPeriod
+	var active bool
+	if r.op == opIncreased {
+		// increased는 값이 커지면 바로 발생하고, for 기간(기본 5분) 동안 더 커지지 않으면 해소합니다
+		prev, ok := st.series[fp]
+		if !ok {
+			prev = &series{}
+			// DB에서 읽은 발생 중인 알림은 발생 시각부터 유지 기간을 셉니다
+			if a, exists := st.alerts[fp]; exists && a.FiredAt != nil {
+				prev.increasedAt = *a.FiredAt
+			}
+			st.series[fp] = prev
+		}
+		if r.check(s.value, prev.value, prev.hasValue) {
+			prev.increasedAt = now
+		}
+		prev.value, prev.hasValue = s.value, true
+
+		hold := wait
+		if hold == 0 {
+			hold = defaultIncreaseHold
+		}
+		active = !prev.increasedAt.IsZero() && now.Sub(prev.increasedAt) < hold
+		wait = 0
+	} else {
+		active = r.check(s.value, 0, false)
+	}
+
+	a, exists := st.alerts[fp]
+	if !active {
+		if exists {
+			return e.clear(st, fp, a, s.value, now)
+		}
+		return nil
+	}
+
+	if !exists {
+		a = &models.Alert{
+			Fingerprint: fp,
+			RuleKey:     r.key,
+			RuleName:    r.Name,
+			NodeID:      nodeID,
+			Labels:      s.labels,
+			Severity:    r.Severity,
+			State:       models.AlertPending,
+			StartedAt:   now,
+		}
+		st.alerts[fp] = a
+	}
+	a.Value = s.value
+	a.Message = r.describe(s.labels, s.value)
+
+	// 이미 발생한 알림은 값만 갱신하고 다시 알리지 않습니다
+	if a.State == models.AlertPending && now.Sub(a.StartedAt) >= wait {
+		firedAt := now
+		a.State = models.AlertFiring
+		a.FiredAt = &firedAt
+		a.Silenced = e.silenced(a, now)
+		fired := *a
+		return &fired
+	}
+	return nil
+}
+
+// clear는 조건이 끝난 알림을 정리합니다. 발생한 알림이었으면 해소된 복사본을 반환합니다.
+// e.mu를 잡은 상태에서 호출해야 합니다.
+func (e *Engine) clear(st *nodeState, fp string, a *models.Alert, value float64, now time.Time) *models.Alert {
+	delete(st.alerts, fp)
+	if a.State != models.AlertFiring {
+		return nil
+	}
+	resolvedAt := now
+	resolved := *a
+	resolved.State = models.AlertResolved
+	resolved.Value = value
+	resolved.ResolvedAt = &resolvedAt
+	return &resolved
+}
+
+// persist는 알림의 상태 변화를 DB에 저장합니다
+func (e *Engine) persist(ctx context.Context, st *nodeState, a *models.Alert) {
+	sugar := logger.FromContext(ctx)
+
+	switch a.State {
+	case models.AlertFiring:
+		if err := e.repo.SaveFiring(ctx, a); err != nil {
+			sugar.Errorw("알림 저장 실패", "rule", a.RuleName, "fingerprint", a.Fingerprint, "error", err)
+			return
+		}
+		e.mu.Lock()
+		if cur, ok := st.alerts[a.Fingerprint]; ok && cur.State == models.AlertFiring {
+			cur.ID = a.ID
+		}
+		e.mu.Unlock()
+	case models.AlertResolved:
+		if a.ID == 0 {
+			sugar.Warnw("저장되지 않은 알림이 해소됨", "rule", a.RuleName, "fingerprint", a.Fingerprint)
+			return
+		}
+		if err := e.repo.Resolve(ctx, a.ID, *a.ResolvedAt, a.Value); err != nil {
+			sugar.Errorw("알림 해소 저장 실패", "rule", a.RuleName, "alertID", a.ID, "error", err)
+		}
+	}
+}
+
+// eventNode는 알림 이벤트에 넣는 노드 표시 정보입니다
+type eventNode struct {
+	name     string
+	hostname string
+}
+
+// notify는 알림의 발생과 해소를 이벤트로 발행합니다.
+// 사일런스에 해당해 발생을 알리지 않은 알림은 해소도 알리지 않습니다.
+func (e *Engine) notify(a *models.Alert, metric string, node eventNode) {
+	sugar := logger.GetCustomLogger()
+	if a.Silenced {
+		sugar.Infow("사일런스에 해당하는 알림", "nodeID", a.NodeID, "rule", a.RuleName, "state", a.State)
+		return
+	}
+
+	event := models.Event{
+		NodeID: a.NodeID,
+		Data: map[string]interface{}{
+			"alert_id":    a.ID,
+			"rule":        a.RuleName,
+			"fingerprint": a.Fingerprint,
+			"labels":      a.Labels,
+			"value":       a.Value,
+			"severity":    a.Severity,
+			"metric":      metric,
+			"node_name":   node.name,
+			"hostname":    node.hostname,
+		},
+	}
+	if a.State == models.AlertFiring {
+		event.Type = models.EventAlertFiring
+		event.Severity = a.Severity
+		event.Message = a.Message
+	} else {
+		event.Type = models.EventAlertResolved
+		event.Severity = models.SeverityInfo
+		event.Message = fmt.Sprintf("해소됨 - %s", a.Message)
+	}
+	e.bus.Publish(event)
+}
+
+// silenced는 알림이 유효한 사일런스에 해당하는지 반환합니다
+func (e *Engine) silenced(a *models.Alert, now time.Time) bool {
+	e.rulesMu.RLock()
+	defer e.rulesMu.RUnlock()
+
+	for _, s := range e.silences {
+		if s.Active(now) && silenceMatches(s, a) {
+			return true
+		}
+	}
+	return false
+}
+
+// silenceMatches는 사일런스의 조건이 모두 알림에 맞는지 반환합니다
+func silenceMatches(s models.Silence, a *models.Alert) bool {
+	for key, value := range s.Matchers {
+		var actual string
+		switch key {
+		case "rule":
+			actual = a.RuleName
+		case "node_id":
+			actual = a.NodeID
+		case "severity":
+			actual = a.Severity
+		default:
+			actual = a.Labels[key]
+		}
+		if actual != value {
+			return false
+		}
+	}
+	return true
+}
+
+// fingerprint는 규칙, 노드, 시리즈 레이블로 알림 식별자를 만듭니다
+func fingerprint(ruleKey, nodeID string, labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s", ruleKey, nodeID)
+	for _, k := range keys {
+		fmt.Fprintf(h, "\x00%s=%s", k, labels[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// Forget은 노드의 알림 상태를 버립니다. 노드가 다른 인스턴스로 옮겨 간 뒤 다시 돌아오면
+// DB에서 해소되지 않은 알림을 다시 읽습니다.
+func (e *Engine) Forget(nodeID string) {
+	e.mu.Lock()
+	delete(e.nodes, nodeID)
+	e.mu.Unlock()
+}
+
+// Active는 대기 중이거나 발생한 알림을 노드, 규칙 이름 순으로 반환합니다
+func (e *Engine) Active() []models.Alert {
+	e.mu.Lock()
+	alerts := []models.Alert{}
+	for _, st := range e.nodes {
+		for _, a := range st.alerts {
+			alerts = append(alerts, *a)
+		}
+	}
+	e.mu.Unlock()
+
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].NodeID != alerts[j].NodeID {
+			return alerts[i].NodeID < alerts[j].NodeID
+		}
+		if alerts[i].RuleName != alerts[j].RuleName {
+			return alerts[i].RuleName < alerts[j].RuleName
+		}
+		return alerts[i].Fingerprint < alerts[j].Fingerprint
+	})
+	return alerts
+}
+
+// Rules는 적용 중인 규칙을 반환합니다
+func (e *Engine) Rules() []models.AlertRule {
+	e.rulesMu.RLock()
+	defer e.rulesMu.RUnlock()
+
+	rules := make([]models.AlertRule, len(e.rules))
+	for i, r := range e.rules {
+		rules[i] = r.AlertRule
+	}
+	return rules
+}
+
+// Silences는 유효한 사일런스를 반환합니다
+func (e *Engine) Silences() []models.Silence {
+	e.rulesMu.RLock()
+	defer e.rulesMu.RUnlock()
+
+	now := time.Now()
+	silences := []models.Silence{}
+	for _, s := range e.silences {
+		if now.Before(s.EndsAt) {
+			silences = append(silences, s)
+		}
+	}
+	return silences
+}
+
+// AddRule은 규칙을 검증해 DB에 저장하고 바로 적용합니다
+func (e *Engine) AddRule(ctx context.Context, ar *models.AlertRule) error {
+	ar.Source = models.RuleSourceDB
+	r, err := compile(*ar)
+	if err != nil {
+		return err
+	}
+	ar.Severity = r.Severity
+	ar.For = r.For
+	for _, existing := range e.Rules() {
+		if existing.Name == ar.Name {
+			return fmt.Errorf("규칙 %q: 같은 이름의 규칙이 이미 있습니다", ar.Name)
+		}
+	}
+
+	if err := e.repo.CreateRule(ctx, ar); err != nil {
+		return fmt.Errorf("알림 규칙 저장 실패: %v", err)
+	}
+	e.refresh(ctx)
+	return nil
+}
+
+// DeleteRule은 DB의 규칙을 삭제합니다. 규칙의 알림은 다음 메트릭스에서 해소됩니다.
+func (e *Engine) DeleteRule(ctx context.Context, id int64) (bool, error) {
+	deleted, err := e.repo.DeleteRule(ctx, id)
+	if err != nil || !deleted {
+		return deleted, err
+	}
+	e.refresh(ctx)
+	return true, nil
+}
+
+// AddSilence는 사일런스를 저장하고 바로 적용합니다. 이미 발생한 알림에는 적용되지 않습니다.
+func (e *Engine) AddSilence(ctx context.Context, s *models.Silence) error {
+	if len(s.Matchers) == 0 {
+		return fmt.Errorf("사일런스 조건(matchers)이 필요합니다")
+	}
+	if s.StartsAt.IsZero() {
+		s.StartsAt = time.Now()
+	}
+	if !s.EndsAt.After(s.StartsAt) {
+		return fmt.Errorf("ends_at은 starts_at 이후여야 합니다")
+	}
+
+	if err := e.repo.CreateSilence(ctx, s); err != nil {
+		return fmt.Errorf("사일런스 저장 실패: %v", err)
+	}
+	e.refresh(ctx)
+	return nil
+}
+
+// ExpireSilence는 사일런스를 바로 만료시킵니다
+func (e *Engine) ExpireSilence(ctx context.Context, id int64) (bool, error) {
+	expired, err := e.repo.ExpireSilence(ctx, id)
+	if err != nil || !expired {
+		return expired, err
+	}
+	e.refresh(ctx)
+	return true, nil
+}
+
+// refresh는 API로 규칙이나 사일런스를 바꾼 뒤 다시 읽습니다. 실패해도 다음 주기에 다시 읽습니다.
+func (e *Engine) refresh(ctx context.Context) {
+	sugar := logger.FromContext(ctx)
+	if err := e.Refresh(ctx); err != nil {
+		sugar.Warnw("알림 규칙 갱신 중 오류", "error", err)
+	}
+}
@@ -0,0 +1,161 @@
+package alerting
+
+import (
+	"testing"
+	"time"
+
+	"system-collector/pkg/models"
+)
+
+// step은 평가 한 번의 입력(경과 시간과 값)과 기대하는 결과 상태입니다 (빈 문자열이면 변화 없음)
+type step struct {
+	at    time.Duration
+	value float64
+	want  string
+}
+
+func runSteps(t *testing.T, e *Engine, expr string, steps []step) *nodeState {
+	t.Helper()
+	r, err := compile(models.AlertRule{Name: "test", Expr: expr, Enabled: true})
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	st := &nodeState{alerts: make(map[string]*models.Alert), series: make(map[string]*series)}
+	fp := fingerprint(r.key, "node-1", nil)
+	origin := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	for i, s := range steps {
+		a := e.evaluate(st, r, fp, "node-1", sample{value: s.value}, origin.Add(s.at))
+		got := ""
+		if a != nil {
+			got = a.State
+		}
+		if got != s.want {
+			t.Fatalf("%d단계 (%v, 값 %g): 결과 %q, 기대 %q", i, s.at, s.value, got, s.want)
+		}
+	}
+	return st
+}
+
+func TestEvaluateForDuration(t *testing.T) {
+	runSteps(t, &Engine{}, "cpu.usage > 90 for 5m", []step{
+		{at: 0, value: 95},               // 대기 시작
+		{at: 2 * time.Minute, value: 96}, // 아직 5분 전
+		{at: 5 * time.Minute, value: 97, want: models.AlertFiring},
+		{at: 6 * time.Minute, value: 98}, // 이미 발생한 알림은 다시 알리지 않음
+		{at: 7 * time.Minute, value: 50, want: models.AlertResolved},
+		{at: 8 * time.Minute, value: 95},  // 다시 대기
+		{at: 9 * time.Minute, value: 50},  // 발생 전에 끝나면 해소도 없음
+		{at: 10 * time.Minute, value: 95}, // 대기 시간은 처음부터 다시 셈
+		{at: 14 * time.Minute, value: 95},
+		{at: 15 * time.Minute, value: 95, want: models.AlertFiring},
+	})
+}
+
+func TestEvaluateWithoutFor(t *testing.T) {
+	st := runSteps(t, &Engine{}, "memory.usage_percent >= 80", []step{
+		{at: 0, value: 80, want: models.AlertFiring},
+		{at: time.Minute, value: 79, want: models.AlertResolved},
+	})
+	if len(st.alerts) != 0 {
+		t.Errorf("해소 후 남은 알림 %d개", len(st.alerts))
+	}
+}
+
+func TestEvaluateIncreased(t *testing.T) {
+	runSteps(t, &Engine{}, "container.restarts increased", []step{
+		{at: 0, value: 1}, // 이전 값이 없으면 비교하지 않음
+		{at: time.Minute, value: 2, want: models.AlertFiring}, // 증가하면 바로 발생
+		{at: 3 * time.Minute, value: 2},                       // 기본 유지 기간 5분
+		{at: 6 * time.Minute, value: 2, want: models.AlertResolved},
+		{at: 7 * time.Minute, value: 3, want: models.AlertFiring},
+		{at: 10 * time.Minute, value: 4}, // 다시 증가하면 유지 기간을 새로 셈
+		{at: 14 * time.Minute, value: 4},
+		{at: 15 * time.Minute, value: 4, want: models.AlertResolved},
+	})
+
+	runSteps(t, &Engine{}, "container.restarts increased for 1m", []step{
+		{at: 0, value: 1},
+		{at: time.Minute, value: 2, want: models.AlertFiring},
+		{at: 2 * time.Minute, value: 2, want: models.AlertResolved},
+	})
+}
+
+func TestEvaluateRestoredIncrease(t *testing.T) {
+	// DB에서 읽은 발생 중인 알림은 발생 시각부터 유지 기간을 셈
+	r, _ := compile(models.AlertRule{Name: "restarts", Expr: "container.restarts increased", Enabled: true})
+	fp := fingerprint(r.key, "node-1", nil)
+	firedAt := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	st := &nodeState{
+		alerts: map[string]*models.Alert{fp: {Fingerprint: fp, ID: 3, State: models.AlertFiring, FiredAt: &firedAt}},
+		series: make(map[string]*series),
+	}
+
+	e := &Engine{}
+	if a := e.evaluate(st, r, fp, "node-1", sample{value: 5}, firedAt.Add(time.Minute)); a != nil {
+		t.Fatalf("재시작 직후 결과 %q, 변화 없음 기대", a.State)
+	}
+	a := e.evaluate(st, r, fp, "node-1", sample{value: 5}, firedAt.Add(defaultIncreaseHold))
+	if a == nil || a.State != models.AlertResolved || a.ID != 3 {
+		t.Fatalf("유지 기간 후 결과 %+v, 저장된 알림의 해소 기대", a)
+	}
+}
+
+func TestEvaluateSilenced(t *testing.T) {
+	now := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	e := &Engine{silences: []models.Silence{{
+		Matchers: map[string]string{"rule": "test", "node_id": "node-1"},
+		StartsAt: now.Add(-time.Hour),
+		EndsAt:   now.Add(time.Hour),
+	}}}
+	r, _ := compile(models.AlertRule{Name: "test", Expr: "cpu.usage > 90", Enabled: true})
+	st := &nodeState{alerts: make(map[string]*models.Alert), series: make(map[string]*series)}
+	fp := fingerprint(r.key, "node-1", nil)
+
+	a := e.evaluate(st, r, fp, "node-1", sample{value: 95}, now)
+	if a == nil || a.State != models.AlertFiring || !a.Silenced {
+		t.Fatalf("결과 %+v, 사일런스된 발생 기대", a)
+	}
+	// 해소도 사일런스 여부를 이어받음
+	if resolved := e.evaluate(st, r, fp, "node-1", sample{value: 10}, now.Add(time.Minute)); resolved == nil || !resolved.Silenced {
+		t.Errorf("해소 결과 %+v, 사일런스 유지 기대", resolved)
+	}
+}
+
+func TestSilenceMatches(t *testing.T) {
+	alert := &models.Alert{RuleName: "root-disk", NodeID: "node-1", Severity: models.SeverityCritical, Labels: map[string]string{"mount": "/"}}
+	tests := []struct {
+		name     string
+		matchers map[string]string
+		want     bool
+	}{
+		{name: "조건 없음", matchers: nil, want: true},
+		{name: "규칙과 노드", matchers: map[string]string{"rule": "root-disk", "node_id": "node-1"}, want: true},
+		{name: "레이블", matchers: map[string]string{"mount": "/", "severity": models.SeverityCritical}, want: true},
+		{name: "다른 노드", matchers: map[string]string{"rule": "root-disk", "node_id": "node-2"}},
+		{name: "없는 레이블", matchers: map[string]string{"container": "web"}},
+	}
+	for _, tt := range tests {
+		if got := silenceMatches(models.Silence{Matchers: tt.matchers}, alert); got != tt.want {
+			t.Errorf("%s: silenceMatches = %v, 기대 %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a := fingerprint("config:disk", "node-1", map[string]string{"mount": "/", "device": "/dev/sda1"})
+	b := fingerprint("config:disk", "node-1", map[string]string{"device": "/dev/sda1", "mount": "/"})
+	if a != b || len(a) != 16 {
+		t.Errorf("레이블 순서에 따라 다른 식별자: %s, %s", a, b)
+	}
+	others := []string{
+		fingerprint("config:disk", "node-2", map[string]string{"mount": "/", "device": "/dev/sda1"}),
+		fingerprint("config:cpu", "node-1", map[string]string{"mount": "/", "device": "/dev/sda1"}),
+		fingerprint("config:disk", "node-1", map[string]string{"mount": "/data", "device": "/dev/sda1"}),
+	}
+	for _, other := range others {
+		if other == a {
+			t.Errorf("다른 시리즈가 같은 식별자 %s", a)
+		}
+	}
+}
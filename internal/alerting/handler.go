@@ -0,0 +1,233 @@
+package alerting
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"system-collector/internal/admin"
+	"system-collector/internal/httpapi"
+	"system-collector/internal/repository"
+	"system-collector/pkg/logger"
+	"system-collector/pkg/models"
+)
+
+// 알림 이력 조회 시 limit 기본값과 최대값
+const (
+	defaultAlertLimit = 100
+	maxAlertLimit     = 1000
+)
+
+// APIHandler는 알림, 알림 규칙, 사일런스 API를 제공합니다.
+// 조회와 변경 API 모두 관리 API와 같은 인증을 사용합니다.
+type APIHandler struct {
+	engine *Engine
+	repo   *repository.AlertRepository
+}
+
+// NewAPIHandler는 알림 API 핸들러를 생성합니다
+func NewAPIHandler(engine *Engine, repo *repository.AlertRepository) *APIHandler {
+	return &APIHandler{engine: engine, repo: repo}
+}
+
+// RegisterRoutes는 핸들러를 mux에 등록합니다
+func (h *APIHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/alerts", admin.RequireAdmin(h.handleActive))
+	mux.HandleFunc("GET /api/alerts/history", admin.RequireAdmin(h.handleHistory))
+	mux.HandleFunc("GET /api/nodes/{nodeID}/alerts", admin.RequireAdmin(h.handleNodeAlerts))
+	mux.HandleFunc("GET /api/alerts/rules", admin.RequireAdmin(h.handleRules))
+	mux.HandleFunc("POST /api/alerts/rules", admin.RequireAdmin(h.handleCreateRule))
+	mux.HandleFunc("DELETE /api/alerts/rules/{id}", admin.RequireAdmin(h.handleDeleteRule))
+	mux.HandleFunc("GET /api/alerts/silences", admin.RequireAdmin(h.handleSilences))
+	mux.HandleFunc("POST /api/alerts/silences", admin.RequireAdmin(h.handleCreateSilence))
+	mux.HandleFunc("DELETE /api/alerts/silences/{id}", admin.RequireAdmin(h.handleExpireSilence))
+}
+
+// handleActive는 대기 중이거나 발생한 알림을 반환합니다. state 쿼리 파라미터(pending, firing)로 거를 수 있습니다.
+func (h *APIHandler) handleActive(w http.ResponseWriter, r *http.Request) {
+	state := r.URL.Query().Get("state")
+	if state != "" && state != models.AlertPending && state != models.AlertFiring {
+		httpapi.WriteError(w, http.StatusBadRequest, "state는 pending 또는 firing이어야 합니다")
+		return
+	}
+
+	alerts := []models.Alert{}
+	for _, a := range h.engine.Active() {
+		if state == "" || a.State == state {
+			alerts = append(alerts, a)
+		}
+	}
+	httpapi.WriteJSON(w, http.StatusOK, alerts)
+}
+
+// handleHistory는 저장된 알림 이력을 최신순으로 반환합니다. node_id, state(firing, resolved), limit 쿼리 파라미터를 지원합니다.
+func (h *APIHandler) handleHistory(w http.ResponseWriter, r *http.Request) {
+	h.writeHistory(w, r, r.URL.Query().Get("node_id"))
+}
+
+// handleNodeAlerts는 노드의 알림 이력을 최신순으로 반환합니다
+func (h *APIHandler) handleNodeAlerts(w http.ResponseWriter, r *http.Request) {
+	h.writeHistory(w, r, r.PathValue("nodeID"))
+}
+
+func (h *APIHandler) writeHistory(w http.ResponseWriter, r *http.Request, nodeID string) {
+	state := r.URL.Query().Get("state")
+	if state != "" && state != models.AlertFiring && state != models.AlertResolved {
+		httpapi.WriteError(w, http.StatusBadRequest, "state는 firing 또는 resolved여야 합니다")
+		return
+	}
+	limit := defaultAlertLimit
+	if s := r.URL.Query().Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			httpapi.WriteError(w, http.StatusBadRequest, "limit는 양의 정수여야 합니다")
+			return
+		}
+		limit = min(n, maxAlertLimit)
+	}
+
+	alerts, err := h.repo.GetAlerts(r.Context(), nodeID, state, limit)
+	if err != nil {
+		httpapi.WriteError(w, http.StatusInternalServerError, "알림 조회 실패")
+		return
+	}
+	httpapi.WriteJSON(w, http.StatusOK, alerts)
+}
+
+// handleRules는 적용 중인 규칙을 반환합니다. 사용자 키는 가려서 반환합니다.
+func (h *APIHandler) handleRules(w http.ResponseWriter, r *http.Request) {
+	rules := h.engine.Rules()
+	for i := range rules {
+		if rules[i].UserKey != "" {
+			rules[i].UserKey = logger.MaskKey(rules[i].UserKey)
+		}
+	}
+	httpapi.WriteJSON(w, http.StatusOK, rules)
+}
+
+// ruleRequest는 규칙 생성 요청입니다. Enabled가 없으면 활성화된 규칙으로 만듭니다.
+type ruleRequest struct {
+	Name       string            `json:"name"`
+	Expr       string            `json:"expr"`
+	For        string            `json:"for"`
+	Severity   string            `json:"severity"`
+	Match      map[string]string `json:"match"`
+	UserKey    string            `json:"user_key"`
+	NodeID     string            `json:"node_id"`
+	ServerType string            `json:"server_type"`
+	Enabled    *bool             `json:"enabled"`
+}
+
+func (h *APIHandler) handleCreateRule(w http.ResponseWriter, r *http.Request) {
+	var req ruleRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64*1024)).Decode(&req); err != nil {
+		httpapi.WriteError(w, http.StatusBadRequest, "요청 본문이 올바른 JSON이 아닙니다")
+		return
+	}
+
+	rule := models.AlertRule{
+		Name:       req.Name,
+		Expr:       req.Expr,
+		For:        req.For,
+		Severity:   req.Severity,
+		Match:      req.Match,
+		UserKey:    req.UserKey,
+		NodeID:     req.NodeID,
+		ServerType: req.ServerType,
+		Enabled:    req.Enabled == nil || *req.Enabled,
+	}
+	if err := h.engine.AddRule(r.Context(), &rule); err != nil {
+		httpapi.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if rule.UserKey != "" {
+		rule.UserKey = logger.MaskKey(rule.UserKey)
+	}
+	httpapi.WriteJSON(w, http.StatusCreated, rule)
+}
+
+func (h *APIHandler) handleDeleteRule(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		httpapi.WriteError(w, http.StatusBadRequest, "규칙 ID는 정수여야 합니다")
+		return
+	}
+
+	deleted, err := h.engine.DeleteRule(r.Context(), id)
+	if err != nil {
+		httpapi.WriteError(w, http.StatusInternalServerError, "알림 규칙 삭제 실패")
+		return
+	}
+	if !deleted {
+		httpapi.WriteError(w, http.StatusNotFound, "알림 규칙이 없습니다 (설정 파일의 규칙은 API로 삭제할 수 없습니다)")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *APIHandler) handleSilences(w http.ResponseWriter, r *http.Request) {
+	httpapi.WriteJSON(w, http.StatusOK, h.engine.Silences())
+}
+
+// silenceRequest는 사일런스 생성 요청입니다. EndsAt 대신 Duration(예: "2h")을 줄 수 있습니다.
+type silenceRequest struct {
+	Matchers  map[string]string `json:"matchers"`
+	Comment   string            `json:"comment"`
+	CreatedBy string            `json:"created_by"`
+	StartsAt  time.Time         `json:"starts_at"`
+	EndsAt    time.Time         `json:"ends_at"`
+	Duration  string            `json:"duration"`
+}
+
+func (h *APIHandler) handleCreateSilence(w http.ResponseWriter, r *http.Request) {
+	var req silenceRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64*1024)).Decode(&req); err != nil {
+		httpapi.WriteError(w, http.StatusBadRequest, "요청 본문이 올바른 JSON이 아닙니다")
+		return
+	}
+
+	silence := models.Silence{
+		Matchers:  req.Matchers,
+		Comment:   req.Comment,
+		CreatedBy: req.CreatedBy,
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+	}
+	if silence.StartsAt.IsZero() {
+		silence.StartsAt = time.Now()
+	}
+	if req.Duration != "" {
+		d, err := time.ParseDuration(req.Duration)
+		if err != nil || d <= 0 {
+			httpapi.WriteError(w, http.StatusBadRequest, "duration이 올바르지 않습니다")
+			return
+		}
+		silence.EndsAt = silence.StartsAt.Add(d)
+	}
+
+	if err := h.engine.AddSilence(r.Context(), &silence); err != nil {
+		httpapi.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	httpapi.WriteJSON(w, http.StatusCreated, silence)
+}
+
+func (h *APIHandler) handleExpireSilence(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		httpapi.WriteError(w, http.StatusBadRequest, "사일런스 ID는 정수여야 합니다")
+		return
+	}
+
+	expired, err := h.engine.ExpireSilence(r.Context(), id)
+	if err != nil {
+		httpapi.WriteError(w, http.StatusInternalServerError, "사일런스 종료 실패")
+		return
+	}
+	if !expired {
+		httpapi.WriteError(w, http.StatusNotFound, "유효한 사일런스가 없습니다")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
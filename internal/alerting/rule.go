@@ -0,0 +1,314 @@
+package alerting
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"system-collector/pkg/models"
+)
+
+// 규칙 조건 연산자
+const (
+	opGreater      = ">"
+	opGreaterEqual = ">="
+	opLess         = "<"
+	opLessEqual    = "<="
+	opEqual        = "=="
+	opNotEqual     = "!="
+	// opIncreased는 같은 시리즈의 값이 이전 메트릭스보다 커졌을 때 만족합니다 (예: 컨테이너 재시작 횟수)
+	opIncreased = "increased"
+)
+
+// sample은 메트릭스에서 꺼낸 시리즈 하나의 값입니다
+type sample struct {
+	labels map[string]string
+	value  float64
+}
+
+// metricSource는 메트릭스에서 지표의 시리즈를 꺼냅니다
+type metricSource struct {
+	// labels는 시리즈를 구분하는 레이블 이름입니다 (on/match에 사용할 수 있는 이름)
+	labels  []string
+	extract func(m *models.SystemMetrics) []sample
+}
+
+func scalar(fn func(m *models.SystemMetrics) float64) metricSource {
+	return metricSource{extract: func(m *models.SystemMetrics) []sample {
+		return []sample{{value: fn(m)}}
+	}}
+}
+
+func perDisk(fn func(d models.DiskMetrics) float64) metricSource {
+	return metricSource{labels: []string{"mount", "device"}, extract: func(m *models.SystemMetrics) []sample {
+		samples := make([]sample, 0, len(m.Disk))
+		for _, d := range m.Disk {
+			if d.ErrorFlag {
+				continue
+			}
+			samples = append(samples, sample{labels: map[string]string{"mount": d.MountPoint, "device": d.Device}, value: fn(d)})
+		}
+		return samples
+	}}
+}
+
+func perInterface(fn func(n models.NetworkMetrics) float64) metricSource {
+	return metricSource{labels: []string{"interface"}, extract: func(m *models.SystemMetrics) []sample {
+		samples := make([]sample, 0, len(m.Network))
+		for _, n := range m.Network {
+			samples = append(samples, sample{labels: map[string]string{"interface": n.Interface}, value: fn(n)})
+		}
+		return samples
+	}}
+}
+
+func perContainer(fn func(c models.DockerContainer) float64) metricSource {
+	return metricSource{labels: []string{"container", "image"}, extract: func(m *models.SystemMetrics) []sample {
+		samples := make([]sample, 0, len(m.Containers))
+		for _, c := range m.Containers {
+			samples = append(samples, sample{labels: map[string]string{"container": c.Name, "image": c.Image}, value: fn(c)})
+		}
+		return samples
+	}}
+}
+
+func percent(part, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
+
+// boolValue는 참이면 1, 거짓이면 0을 반환합니다
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// metricSources는 규칙에서 사용할 수 있는 지표입니다.
+// 이름은 대소문자와 밑줄을 구분하지 않습니다 (CPU.Usage, disk.UsagePercent, disk.usage_percent 모두 가능).
+var metricSources = map[string]metricSource{
+	"cpu.usage":              scalar(func(m *models.SystemMetrics) float64 { return m.CPU.Usage }),
+	"cpu.temperature":        scalar(func(m *models.SystemMetrics) float64 { return m.CPU.Temperature }),
+	"memory.usage_percent":   scalar(func(m *models.SystemMetrics) float64 { return m.Memory.UsagePercent }),
+	"memory.available":       scalar(func(m *models.SystemMetrics) float64 { return float64(m.Memory.Available) }),
+	"memory.swap_percent":    scalar(func(m *models.SystemMetrics) float64 { return percent(m.Memory.SwapUsed, m.Memory.SwapTotal) }),
+	"system.total_processes": scalar(func(m *models.SystemMetrics) float64 { return float64(m.System.TotalProcesses) }),
+	"system.uptime":          scalar(func(m *models.SystemMetrics) float64 { return float64(m.System.Uptime) }),
+
+	"disk.usage_percent":  perDisk(func(d models.DiskMetrics) float64 { return d.UsagePercent }),
+	"disk.free":           perDisk(func(d models.DiskMetrics) float64 { return float64(d.Free) }),
+	"disk.inodes_percent": perDisk(func(d models.DiskMetrics) float64 { return percent(d.InodesUsed, d.InodesTotal) }),
+
+	"network.rx_bytes_per_sec": perInterface(func(n models.NetworkMetrics) float64 { return n.RxBytesPerSec }),
+	"network.tx_bytes_per_sec": perInterface(func(n models.NetworkMetrics) float64 { return n.TxBytesPerSec }),
+	"network.rx_errors":        perInterface(func(n models.NetworkMetrics) float64 { return float64(n.RxErrors) }),
+	"network.tx_errors":        perInterface(func(n models.NetworkMetrics) float64 { return float64(n.TxErrors) }),
+
+	"container.cpu_usage":      perContainer(func(c models.DockerContainer) float64 { return c.CPUUsage }),
+	"container.memory_percent": perContainer(func(c models.DockerContainer) float64 { return c.MemoryPercent }),
+	"container.restarts":       perContainer(func(c models.DockerContainer) float64 { return float64(c.Restarts) }),
+
+	// 컨테이너 생명주기 상태 (실행 중, 헬스 체크 실패는 1, 아니면 0)
+	"container.running":               perContainer(func(c models.DockerContainer) float64 { return boolValue(c.Running()) }),
+	"container.unhealthy":             perContainer(func(c models.DockerContainer) float64 { return boolValue(c.Unhealthy()) }),
+	"container.health_failing_streak": perContainer(func(c models.DockerContainer) float64 { return float64(c.Health.FailingStreak) }),
+}
+
+// normalizeMetric은 지표 이름을 비교용으로 바꿉니다 (소문자, 밑줄 제거)
+func normalizeMetric(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), "_", "")
+}
+
+var normalizedSources = func() map[string]string {
+	result := make(map[string]string, len(metricSources))
+	for name := range metricSources {
+		result[normalizeMetric(name)] = name
+	}
+	return result
+}()
+
+// Metrics는 규칙에서 사용할 수 있는 지표 이름을 반환합니다
+func Metrics() []string {
+	names := make([]string, 0, len(metricSources))
+	for name := range metricSources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// rule은 검증과 파싱을 마친 규칙입니다
+type rule struct {
+	models.AlertRule
+	// key는 규칙 식별자입니다 (config:<이름> 또는 db:<ID>)
+	key       string
+	metric    string
+	source    metricSource
+	op        string
+	threshold float64
+	match     map[string]string
+	forPeriod time.Duration
+}
+
+// compile은 규칙의 식을 파싱합니다.
+// 식의 형식은 "<지표> <연산자> <값> [on <레이블> <값>]... [for <기간>]" 또는 "<지표> increased [...]"이며,
+// 지표는 "cpu.usage", "CPU.Usage", "container Restarts"처럼 쓸 수 있습니다.
+// 식의 on/for는 규칙의 Match/For보다 우선합니다.
+func compile(ar models.AlertRule) (*rule, error) {
+	if ar.Name == "" {
+		return nil, fmt.Errorf("규칙 이름이 필요합니다")
+	}
+	tokens := strings.Fields(ar.Expr)
+	if len(tokens) < 2 {
+		return nil, fmt.Errorf("규칙 %q: 식이 올바르지 않습니다 (%q)", ar.Name, ar.Expr)
+	}
+
+	r := &rule{AlertRule: ar, match: make(map[string]string)}
+	if ar.Source == models.RuleSourceDB {
+		r.key = fmt.Sprintf("db:%d", ar.ID)
+	} else {
+		r.key = "config:" + ar.Name
+	}
+	if r.Severity == "" {
+		r.Severity = models.SeverityWarning
+	}
+	switch r.Severity {
+	case models.SeverityInfo, models.SeverityWarning, models.SeverityCritical:
+	default:
+		return nil, fmt.Errorf("규칙 %q: 심각도는 info, warning, critical 중 하나여야 합니다 (%q)", ar.Name, ar.Severity)
+	}
+
+	name, ok := normalizedSources[normalizeMetric(tokens[0])]
+	if !ok && len(tokens) > 2 {
+		// "container Restarts increased"처럼 구성 요소와 지표를 띄어 쓴 형식
+		if name, ok = normalizedSources[normalizeMetric(tokens[0]+"."+tokens[1])]; ok {
+			tokens = tokens[1:]
+		}
+	}
+	if !ok {
+		return nil, fmt.Errorf("규칙 %q: 알 수 없는 지표 %q", ar.Name, tokens[0])
+	}
+	r.metric = name
+	r.source = metricSources[name]
+
+	r.op = strings.ToLower(tokens[1])
+	rest := tokens[2:]
+	switch r.op {
+	case opIncreased:
+	case opGreater, opGreaterEqual, opLess, opLessEqual, opEqual, opNotEqual:
+		if len(rest) == 0 {
+			return nil, fmt.Errorf("규칙 %q: %s 뒤에 비교할 값이 필요합니다", ar.Name, r.op)
+		}
+		threshold, err := strconv.ParseFloat(rest[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("규칙 %q: 비교할 값이 숫자가 아닙니다 (%q)", ar.Name, rest[0])
+		}
+		r.threshold = threshold
+		rest = rest[1:]
+	default:
+		return nil, fmt.Errorf("규칙 %q: 알 수 없는 연산자 %q", ar.Name, tokens[1])
+	}
+
+	for label, value := range ar.Match {
+		r.match[label] = value
+	}
+	forPeriod := ar.For
+	for len(rest) > 0 {
+		switch strings.ToLower(rest[0]) {
+		case "on":
+			if len(rest) < 3 {
+				return nil, fmt.Errorf("규칙 %q: on 뒤에 레이블 이름과 값이 필요합니다", ar.Name)
+			}
+			r.match[strings.ToLower(rest[1])] = rest[2]
+			rest = rest[3:]
+		case "for":
+			if len(rest) < 2 {
+				return nil, fmt.Errorf("규칙 %q: for 뒤에 기간이 필요합니다", ar.Name)
+			}
+			forPeriod = rest[1]
+			rest = rest[2:]
+		default:
+			return nil, fmt.Errorf("규칙 %q: 알 수 없는 식 %q", ar.Name, rest[0])
+		}
+	}
+	for label := range r.match {
+		if !slices.Contains(r.source.labels, label) {
+			return nil, fmt.Errorf("규칙 %q: 지표 %s에는 %q 레이블이 없습니다 (사용 가능: %v)", ar.Name, r.metric, label, r.source.labels)
+		}
+	}
+	if forPeriod != "" {
+		d, err := time.ParseDuration(forPeriod)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("규칙 %q: 기간이 올바르지 않습니다 (%q)", ar.Name, forPeriod)
+		}
+		r.forPeriod = d
+		r.For = forPeriod
+	}
+	return r, nil
+}
+
+// appliesTo는 규칙이 노드에 적용되는지 반환합니다
+func (r *rule) appliesTo(nodeID, userKey, serverType string) bool {
+	return r.Enabled &&
+		(r.NodeID == "" || r.NodeID == nodeID) &&
+		(r.UserKey == "" || r.UserKey == userKey) &&
+		(r.ServerType == "" || r.ServerType == serverType)
+}
+
+// matches는 시리즈가 규칙의 레이블 조건에 맞는지 반환합니다
+func (r *rule) matches(s sample) bool {
+	for label, value := range r.match {
+		if s.labels[label] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// check는 값이 조건을 만족하는지 반환합니다. increased는 이전 값(hasPrev가 false면 만족하지 않음)과 비교합니다.
+func (r *rule) check(value, prev float64, hasPrev bool) bool {
+	switch r.op {
+	case opGreater:
+		return value > r.threshold
+	case opGreaterEqual:
+		return value >= r.threshold
+	case opLess:
+		return value < r.threshold
+	case opLessEqual:
+		return value <= r.threshold
+	case opEqual:
+		return value == r.threshold
+	case opNotEqual:
+		return value != r.threshold
+	case opIncreased:
+		return hasPrev && value > prev
+	}
+	return false
+}
+
+// describe는 알림 메시지를 만듭니다
+func (r *rule) describe(labels map[string]string, value float64) string {
+	target := r.metric
+	if len(labels) > 0 {
+		keys := make([]string, 0, len(labels))
+		for k := range labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, len(keys))
+		for i, k := range keys {
+			parts[i] = k + "=" + labels[k]
+		}
+		target += "{" + strings.Join(parts, ",") + "}"
+	}
+	if r.op == opIncreased {
+		return fmt.Sprintf("%s: %s 값 증가 (현재 %g)", r.Name, target, value)
+	}
+	return fmt.Sprintf("%s: %s = %g (조건 %s %g)", r.Name, target, value, r.op, r.threshold)
+}
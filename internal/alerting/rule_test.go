@@ -0,0 +1,164 @@
+package alerting
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"system-collector/pkg/models"
+)
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      models.AlertRule
+		metric    string
+		op        string
+		threshold float64
+		match     map[string]string
+		forPeriod time.Duration
+		key       string
+	}{
+		{
+			name:   "기본 형식",
+			rule:   models.AlertRule{Name: "cpu", Expr: "cpu.usage > 90"},
+			metric: "cpu.usage", op: opGreater, threshold: 90, match: map[string]string{}, key: "config:cpu",
+		},
+		{
+			name:   "대소문자와 밑줄을 구분하지 않는 지표와 for",
+			rule:   models.AlertRule{Name: "disk", Expr: "Disk.UsagePercent >= 85.5 for 10m"},
+			metric: "disk.usage_percent", op: opGreaterEqual, threshold: 85.5, match: map[string]string{}, forPeriod: 10 * time.Minute, key: "config:disk",
+		},
+		{
+			name:   "on 레이블",
+			rule:   models.AlertRule{Name: "root", Expr: "disk.usage_percent > 90 on mount / on device /dev/sda1"},
+			metric: "disk.usage_percent", op: opGreater, threshold: 90, match: map[string]string{"mount": "/", "device": "/dev/sda1"}, key: "config:root",
+		},
+		{
+			name:   "띄어 쓴 지표와 increased",
+			rule:   models.AlertRule{Name: "restarts", Expr: "container Restarts increased"},
+			metric: "container.restarts", op: opIncreased, match: map[string]string{}, key: "config:restarts",
+		},
+		{
+			name: "식의 on/for가 규칙 필드보다 우선",
+			rule: models.AlertRule{
+				Name: "nginx", Expr: "container.running == 0 on container nginx for 1m",
+				Match: map[string]string{"container": "web", "image": "nginx:1.25"}, For: "5m",
+				Source: models.RuleSourceDB, ID: 7,
+			},
+			metric: "container.running", op: opEqual, match: map[string]string{"container": "nginx", "image": "nginx:1.25"}, forPeriod: time.Minute, key: "db:7",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := compile(tt.rule)
+			if err != nil {
+				t.Fatalf("compile: %v", err)
+			}
+			if r.metric != tt.metric || r.op != tt.op || r.threshold != tt.threshold || r.forPeriod != tt.forPeriod || r.key != tt.key {
+				t.Errorf("규칙 = %s %s %g for %v (%s), 기대 %s %s %g for %v (%s)",
+					r.metric, r.op, r.threshold, r.forPeriod, r.key, tt.metric, tt.op, tt.threshold, tt.forPeriod, tt.key)
+			}
+			if !reflect.DeepEqual(r.match, tt.match) {
+				t.Errorf("match = %v, 기대 %v", r.match, tt.match)
+			}
+			if r.Severity != models.SeverityWarning {
+				t.Errorf("기본 심각도 = %s, 기대 warning", r.Severity)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		rule models.AlertRule
+	}{
+		{name: "이름 없음", rule: models.AlertRule{Expr: "cpu.usage > 90"}},
+		{name: "식이 짧음", rule: models.AlertRule{Name: "x", Expr: "cpu.usage"}},
+		{name: "알 수 없는 지표", rule: models.AlertRule{Name: "x", Expr: "gpu.usage > 90"}},
+		{name: "알 수 없는 연산자", rule: models.AlertRule{Name: "x", Expr: "cpu.usage => 90"}},
+		{name: "값 없음", rule: models.AlertRule{Name: "x", Expr: "cpu.usage >"}},
+		{name: "숫자가 아닌 값", rule: models.AlertRule{Name: "x", Expr: "cpu.usage > high"}},
+		{name: "on 인자 부족", rule: models.AlertRule{Name: "x", Expr: "disk.free < 1 on mount"}},
+		{name: "for 인자 부족", rule: models.AlertRule{Name: "x", Expr: "cpu.usage > 90 for"}},
+		{name: "잘못된 기간", rule: models.AlertRule{Name: "x", Expr: "cpu.usage > 90 for 5 minutes"}},
+		{name: "음수 기간", rule: models.AlertRule{Name: "x", Expr: "cpu.usage > 90", For: "-1m"}},
+		{name: "알 수 없는 절", rule: models.AlertRule{Name: "x", Expr: "cpu.usage > 90 unless 1"}},
+		{name: "지표에 없는 레이블", rule: models.AlertRule{Name: "x", Expr: "cpu.usage > 90 on mount /"}},
+		{name: "Match에 없는 레이블", rule: models.AlertRule{Name: "x", Expr: "disk.free < 1", Match: map[string]string{"container": "a"}}},
+		{name: "잘못된 심각도", rule: models.AlertRule{Name: "x", Expr: "cpu.usage > 90", Severity: "fatal"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := compile(tt.rule); err == nil {
+				t.Errorf("compile(%q) 오류 없음", tt.rule.Expr)
+			}
+		})
+	}
+}
+
+func TestRuleCheck(t *testing.T) {
+	tests := []struct {
+		expr    string
+		value   float64
+		prev    float64
+		hasPrev bool
+		want    bool
+	}{
+		{expr: "cpu.usage > 90", value: 90.1, want: true},
+		{expr: "cpu.usage > 90", value: 90},
+		{expr: "cpu.usage >= 90", value: 90, want: true},
+		{expr: "cpu.usage < 10", value: 9, want: true},
+		{expr: "cpu.usage <= 10", value: 10.5},
+		{expr: "cpu.usage == 0", value: 0, want: true},
+		{expr: "cpu.usage != 0", value: 0},
+		{expr: "container.restarts increased", value: 3, prev: 2, hasPrev: true, want: true},
+		{expr: "container.restarts increased", value: 2, prev: 2, hasPrev: true},
+		{expr: "container.restarts increased", value: 3},
+	}
+	for _, tt := range tests {
+		r, err := compile(models.AlertRule{Name: "x", Expr: tt.expr})
+		if err != nil {
+			t.Fatalf("compile(%q): %v", tt.expr, err)
+		}
+		if got := r.check(tt.value, tt.prev, tt.hasPrev); got != tt.want {
+			t.Errorf("%q check(%g, %g, %v) = %v, 기대 %v", tt.expr, tt.value, tt.prev, tt.hasPrev, got, tt.want)
+		}
+	}
+}
+
+func TestAppliesTo(t *testing.T) {
+	r, err := compile(models.AlertRule{Name: "x", Expr: "cpu.usage > 90", NodeID: "node-1", ServerType: models.ServerTypeVM, Enabled: true})
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	tests := []struct {
+		nodeID, serverType string
+		want               bool
+	}{
+		{nodeID: "node-1", serverType: models.ServerTypeVM, want: true},
+		{nodeID: "node-2", serverType: models.ServerTypeVM},
+		{nodeID: "node-1", serverType: models.ServerTypeBareMetal},
+	}
+	for _, tt := range tests {
+		if got := r.appliesTo(tt.nodeID, "key", tt.serverType); got != tt.want {
+			t.Errorf("appliesTo(%s, %s) = %v, 기대 %v", tt.nodeID, tt.serverType, got, tt.want)
+		}
+	}
+	r.Enabled = false
+	if r.appliesTo("node-1", "key", models.ServerTypeVM) {
+		t.Error("비활성화된 규칙이 적용됨")
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	r, _ := compile(models.AlertRule{Name: "root-disk", Expr: "disk.usage_percent > 90"})
+	if got, want := r.describe(map[string]string{"mount": "/", "device": "/dev/sda1"}, 95), "root-disk: disk.usage_percent{device=/dev/sda1,mount=/} = 95 (조건 > 90)"; got != want {
+		t.Errorf("describe = %q, 기대 %q", got, want)
+	}
+	r, _ = compile(models.AlertRule{Name: "restarts", Expr: "container.restarts increased"})
+	if got, want := r.describe(nil, 4), "restarts: container.restarts 값 증가 (현재 4)"; got != want {
+		t.Errorf("describe = %q, 기대 %q", got, want)
+	}
+}
@@ -0,0 +1,273 @@
+package anomaly
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	config "system-collector/configs"
+	"system-collector/internal/events"
+	"system-collector/internal/registry"
+	"system-collector/pkg/logger"
+	"system-collector/pkg/models"
+)
+
+// hoursPerDay는 시간대별 기준값의 개수입니다
+const hoursPerDay = 24
+
+// nodeState는 노드 하나의 기준값과 이상 상태입니다
+type nodeState struct {
+	baselines map[string]*models.MetricBaseline // 시리즈 -> 기준값
+	anomalous map[string]bool                   // 시리즈 -> 현재 이상 상태
+	dirty     bool
+	savedAt   time.Time
+}
+
+// BaselineStore는 노드별 지표 기준값 저장소입니다 (repository.BaselineRepository)
+type BaselineStore interface {
+	GetBaselines(ctx context.Context, nodeID string) ([]models.MetricBaseline, error)
+	SaveBaselines(ctx context.Context, baselines []models.MetricBaseline) error
+}
+
+// Detector는 수집 큐의 Sink로 동작하며 노드별 지표 기준값(지수 가중 이동 평균과 표준편차)을 학습하고,
+// 기준값에서 z_threshold 이상 벗어난 샘플을 metric_anomaly 이벤트로 발행합니다.
+// 기준값은 주기적으로 PostgreSQL에 저장되어 재시작 후에도 학습이 이어집니다.
+type Detector struct {
+	repo     BaselineStore
+	bus      *events.Bus
+	registry *registry.NodeRegistry
+
+	mu    sync.Mutex
+	nodes map[string]*nodeState
+}
+
+// NewDetector는 이상 탐지기를 생성합니다
+func NewDetector(repo BaselineStore, bus *events.Bus, registry *registry.NodeRegistry) *Detector {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("이상 탐지기 초기화 중")
+
+	return &Detector{
+		repo:     repo,
+		bus:      bus,
+		registry: registry,
+		nodes:    make(map[string]*nodeState),
+	}
+}
+
+// Name은 Sink 이름을 반환합니다
+func (d *Detector) Name() string {
+	return "anomaly"
+}
+
+// Write는 메트릭스의 각 시리즈를 기준값과 비교한 뒤 기준값에 반영합니다.
+// 시리즈가 이상 상태로 바뀔 때 한 번만 이벤트를 발행합니다.
+func (d *Detector) Write(ctx context.Context, metrics *models.SystemMetrics) error {
+	cfg := config.Get().Anomaly
+	if !cfg.Enabled {
+		return nil
+	}
+
+	nodeID := metrics.Key
+	st, err := d.state(ctx, nodeID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	sampledAt := metrics.Timestamp
+	if sampledAt.IsZero() {
+		sampledAt = now
+	}
+	hour := sampledAt.Local().Hour()
+	alpha := 2 / float64(cfg.Window+1)
+
+	var detected []models.Event
+	d.mu.Lock()
+	for _, p := range extract(metrics) {
+		b, ok := st.baselines[p.series]
+		if !ok {
+			b = &models.MetricBaseline{NodeID: nodeID, Series: p.series}
+			st.baselines[p.series] = b
+		}
+		if cfg.Seasonal && len(b.Hourly) != hoursPerDay {
+			b.Hourly = make([]models.BaselineStats, hoursPerDay)
+		}
+
+		// 충분히 학습된 시간대는 그 시간대의 기준값으로 판단
+		ref := b.BaselineStats
+		if cfg.Seasonal && b.Hourly[hour].Count >= int64(cfg.MinSamples) {
+			ref = b.Hourly[hour]
+		}
+		z := zscore(ref, p.value)
+		anomalous := ref.Count >= int64(cfg.MinSamples) &&
+			math.Abs(z) >= cfg.ZThreshold &&
+			math.Abs(p.value-ref.Mean) >= p.minDelta
+		if anomalous && !st.anomalous[p.series] {
+			detected = append(detected, d.event(nodeID, p, ref, z))
+		}
+		if anomalous {
+			st.anomalous[p.series] = true
+		} else {
+			delete(st.anomalous, p.series)
+		}
+
+		update(&b.BaselineStats, p.value, alpha)
+		if cfg.Seasonal {
+			update(&b.Hourly[hour], p.value, alpha)
+		}
+		b.UpdatedAt = now
+		st.dirty = true
+	}
+
+	var pending []models.MetricBaseline
+	if now.Sub(st.savedAt) >= time.Duration(cfg.PersistInterval)*time.Second {
+		pending = st.snapshot()
+		st.dirty = false
+		st.savedAt = now
+	}
+	d.mu.Unlock()
+
+	name := d.registry.DisplayName(nodeID, metrics.System.Hostname)
+	for _, event := range detected {
+		event.Data["node_name"] = name
+		event.Data["hostname"] = metrics.System.Hostname
+		d.bus.Publish(event)
+	}
+
+	if len(pending) > 0 {
+		if err := d.repo.SaveBaselines(ctx, pending); err != nil {
+			d.markDirty(nodeID)
+			return fmt.Errorf("지표 기준값 저장 실패: %v", err)
+		}
+	}
+	return nil
+}
+
+// state는 노드의 상태를 반환합니다. 처음 보는 노드는 저장된 기준값을 읽어 학습을 이어갑니다.
+func (d *Detector) state(ctx context.Context, nodeID string) (*nodeState, error) {
+	d.mu.Lock()
+	st, ok := d.nodes[nodeID]
+	d.mu.Unlock()
+	if ok {
+		return st, nil
+	}
+
+	saved, err := d.repo.GetBaselines(ctx, nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("지표 기준값 조회 실패: %v", err)
+	}
+	st = &nodeState{
+		baselines: make(map[string]*models.MetricBaseline, len(saved)),
+		anomalous: make(map[string]bool),
+		savedAt:   time.Now(),
+	}
+	for i := range saved {
+		st.baselines[saved[i].Series] = &saved[i]
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if cur, ok := d.nodes[nodeID]; ok {
+		return cur, nil
+	}
+	d.nodes[nodeID] = st
+	return st, nil
+}
+
+// snapshot은 노드 기준값의 복사본을 반환합니다. d.mu를 잡은 상태에서 호출해야 합니다.
+func (st *nodeState) snapshot() []models.MetricBaseline {
+	baselines := make([]models.MetricBaseline, 0, len(st.baselines))
+	for _, b := range st.baselines {
+		c := *b
+		c.Hourly = append([]models.BaselineStats(nil), b.Hourly...)
+		baselines = append(baselines, c)
+	}
+	sort.Slice(baselines, func(i, j int) bool { return baselines[i].Series < baselines[j].Series })
+	return baselines
+}
+
+// markDirty는 저장에 실패한 노드를 다음 저장 대상으로 남깁니다
+func (d *Detector) markDirty(nodeID string) {
+	d.mu.Lock()
+	if st, ok := d.nodes[nodeID]; ok {
+		st.dirty = true
+	}
+	d.mu.Unlock()
+}
+
+// event는 이상 이벤트를 만듭니다. 노드 이름과 호스트명은 호출자가 채웁니다.
+func (d *Detector) event(nodeID string, p point, ref models.BaselineStats, z float64) models.Event {
+	stddev := math.Sqrt(ref.Variance)
+	return models.Event{
+		NodeID:   nodeID,
+		Type:     models.EventMetricAnomaly,
+		Severity: models.SeverityWarning,
+		Message:  fmt.Sprintf("%s 값 %.2f이(가) 기준값 %.2f(표준편차 %.2f)에서 벗어남 (z=%.1f)", p.series, p.value, ref.Mean, stddev, z),
+		Data: map[string]interface{}{
+			"series": p.series,
+			"metric": p.metric,
+			"labels": p.labels,
+			"value":  p.value,
+			"mean":   ref.Mean,
+			"stddev": stddev,
+			"z":      z,
+		},
+	}
+}
+
+// Flush는 저장하지 않은 기준값을 모두 저장합니다. 수집 큐가 종료할 때 호출합니다.
+func (d *Detector) Flush() {
+	sugar := logger.GetCustomLogger()
+
+	d.mu.Lock()
+	pending := make(map[string][]models.MetricBaseline)
+	for nodeID, st := range d.nodes {
+		if st.dirty {
+			pending[nodeID] = st.snapshot()
+			st.dirty = false
+			st.savedAt = time.Now()
+		}
+	}
+	d.mu.Unlock()
+
+	for nodeID, baselines := range pending {
+		if err := d.repo.SaveBaselines(context.Background(), baselines); err != nil {
+			sugar.Errorw("종료 전 지표 기준값 저장 실패", "nodeID", nodeID, "error", err)
+		}
+	}
+	sugar.Infow("지표 기준값 저장 완료", "nodes", len(pending))
+}
+
+// Forget은 노드의 기준값을 메모리에서 지웁니다. 저장된 기준값은 남아 다음 연결에서 다시 읽습니다.
+func (d *Detector) Forget(nodeID string) {
+	d.mu.Lock()
+	st, ok := d.nodes[nodeID]
+	delete(d.nodes, nodeID)
+	var pending []models.MetricBaseline
+	if ok && st.dirty {
+		pending = st.snapshot()
+	}
+	d.mu.Unlock()
+
+	if len(pending) > 0 {
+		if err := d.repo.SaveBaselines(context.Background(), pending); err != nil {
+			sugar := logger.GetCustomLogger()
+			sugar.Errorw("노드 기준값 저장 실패", "nodeID", nodeID, "error", err)
+		}
+	}
+}
+
+// Baselines는 노드의 기준값을 시리즈 이름 순으로 반환합니다. 메모리에 없으면 false를 반환합니다.
+func (d *Detector) Baselines(nodeID string) ([]models.MetricBaseline, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	st, ok := d.nodes[nodeID]
+	if !ok {
+		return nil, false
+	}
+	return st.snapshot(), true
+}
@@ -0,0 +1,269 @@
+package anomaly
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	config "system-collector/configs"
+	"system-collector/internal/events"
+	"system-collector/internal/registry"
+	"system-collector/pkg/models"
+)
+
+// fakeStore는 메모리에 기준값을 저장하는 BaselineStore입니다
+type fakeStore struct {
+	mu      sync.Mutex
+	saved   map[string][]models.MetricBaseline
+	saveErr error
+	saves   int
+}
+
+func (s *fakeStore) GetBaselines(_ context.Context, nodeID string) ([]models.MetricBaseline, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]models.MetricBaseline(nil), s.saved[nodeID]...), nil
+}
+
+func (s *fakeStore) SaveBaselines(_ context.Context, baselines []models.MetricBaseline) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.saves++
+	if s.saveErr != nil {
+		return s.saveErr
+	}
+	if s.saved == nil {
+		s.saved = make(map[string][]models.MetricBaseline)
+	}
+	for _, b := range baselines {
+		s.saved[b.NodeID] = append(s.saved[b.NodeID], b)
+	}
+	return nil
+}
+
+// loadTestConfig는 검증을 통과하는 최소 설정에 anomaly 설정을 덧붙여 로드합니다
+func loadTestConfig(t *testing.T, anomaly string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	yaml := "influxdb: {token: test, org: test, bucket: test}\npostgres: {user: test, dbname: test}\nanomaly: " + anomaly + "\n"
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatalf("설정 파일 쓰기 실패: %v", err)
+	}
+	if err := config.Load(path); err != nil {
+		t.Fatalf("설정 로드 실패: %v", err)
+	}
+}
+
+// newTestDetector는 발행된 이벤트를 모으는 버스와 함께 탐지기를 만듭니다.
+// 이벤트는 반환된 함수를 호출하면 버스를 닫은 뒤 돌려줍니다.
+func newTestDetector(t *testing.T, store BaselineStore) (*Detector, func() []models.Event) {
+	t.Helper()
+	bus := events.NewBus(nil, 100)
+	var mu sync.Mutex
+	var published []models.Event
+	bus.Subscribe(func(e models.Event) {
+		mu.Lock()
+		published = append(published, e)
+		mu.Unlock()
+	})
+	bus.Start()
+
+	d := NewDetector(store, bus, &registry.NodeRegistry{})
+	return d, func() []models.Event {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := bus.Close(ctx); err != nil {
+			t.Fatalf("이벤트 버스 종료: %v", err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		return published
+	}
+}
+
+func cpuMetrics(nodeID string, at time.Time, usage float64) *models.SystemMetrics {
+	return &models.SystemMetrics{
+		Key:       nodeID,
+		Timestamp: at,
+		System:    models.SystemInfo{Hostname: "host-1"},
+		CPU:       models.CPUMetrics{Usage: usage},
+	}
+}
+
+func TestDetectorWrite(t *testing.T) {
+	day := time.Date(2026, 1, 2, 0, 0, 0, 0, time.Local)
+	at := func(hour int) time.Time { return day.Add(time.Duration(hour) * time.Hour) }
+
+	// step은 CPU 사용률 샘플 하나와 반영 후 기대하는 cpu.usage 이상 상태입니다
+	type step struct {
+		hour      int
+		value     float64
+		anomalous bool
+	}
+	repeat := func(n, hour int, value float64) []step {
+		steps := make([]step, n)
+		for i := range steps {
+			steps[i] = step{hour: hour, value: value}
+		}
+		return steps
+	}
+
+	// window 9 (alpha 0.2), z_threshold 3, min_samples 5
+	tests := []struct {
+		name       string
+		seasonal   bool
+		steps      []step
+		wantEvents []float64
+	}{
+		{
+			name:  "학습이 끝나기 전에는 판단하지 않음",
+			steps: append(repeat(4, 0, 10), step{value: 100}),
+		},
+		{
+			name:  "최소 차이보다 작은 변화는 무시",
+			steps: append(repeat(5, 0, 10), step{value: 12}),
+		},
+		{
+			name: "이상 구간마다 한 번만 발행",
+			steps: append(repeat(5, 0, 10),
+				step{value: 12},
+				step{value: 50, anomalous: true},
+				step{value: 200, anomalous: true}, // 이미 이상 상태
+				step{value: 54},                   // 기준값 근처로 돌아와 해제
+				step{value: 1000, anomalous: true},
+			),
+			wantEvents: []float64{50, 1000},
+		},
+		{
+			name:     "학습되지 않은 시간대는 전체 기준값 사용",
+			seasonal: true,
+			steps: append(append(repeat(5, 3, 10),
+				step{hour: 15, value: 80, anomalous: true}), // 15시는 학습 전이므로 전체 기준값(10)과 비교
+				repeat(4, 15, 80)...),
+			wantEvents: []float64{80},
+		},
+		{
+			name:     "학습된 시간대는 그 시간대의 기준값 사용",
+			seasonal: true,
+			steps: append(append(append(repeat(5, 3, 10),
+				step{hour: 15, value: 80, anomalous: true}),
+				repeat(4, 15, 80)...),
+				step{hour: 15, value: 90, anomalous: true}, // 전체 기준값으로는 z≈1이지만 15시 기준값(80, 분산 0)에서는 이상
+				step{hour: 3, value: 80, anomalous: true},  // 3시 기준값(10)에서 벗어남
+			),
+			wantEvents: []float64{80, 90},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seasonal := "false"
+			if tt.seasonal {
+				seasonal = "true"
+			}
+			loadTestConfig(t, "{enabled: true, z_threshold: 3, window: 9, min_samples: 5, persist_interval: 3600, seasonal: "+seasonal+"}")
+			d, published := newTestDetector(t, &fakeStore{})
+
+			for i, s := range tt.steps {
+				if err := d.Write(context.Background(), cpuMetrics("node-1", at(s.hour), s.value)); err != nil {
+					t.Fatalf("%d단계 Write: %v", i, err)
+				}
+				d.mu.Lock()
+				got := d.nodes["node-1"].anomalous["cpu.usage"]
+				d.mu.Unlock()
+				if got != s.anomalous {
+					t.Fatalf("%d단계 (%d시, %g) 이상 상태 = %v, 기대 %v", i, s.hour, s.value, got, s.anomalous)
+				}
+			}
+
+			var values []float64
+			for _, e := range published() {
+				if e.Type != models.EventMetricAnomaly || e.Data["series"] != "cpu.usage" || e.Data["node_name"] != "host-1" {
+					t.Errorf("이벤트 = %+v", e)
+				}
+				values = append(values, e.Data["value"].(float64))
+			}
+			if !reflect.DeepEqual(values, tt.wantEvents) {
+				t.Errorf("이벤트 값 %v, 기대 %v", values, tt.wantEvents)
+			}
+		})
+	}
+}
+
+func TestDetectorRestoresBaselines(t *testing.T) {
+	loadTestConfig(t, "{enabled: true, z_threshold: 3, window: 9, min_samples: 5, persist_interval: 3600, seasonal: false}")
+	store := &fakeStore{saved: map[string][]models.MetricBaseline{
+		"node-1": {{NodeID: "node-1", Series: "cpu.usage", BaselineStats: models.BaselineStats{Mean: 10, Variance: 1, Count: 100}}},
+	}}
+	d, published := newTestDetector(t, store)
+
+	// 저장된 기준값으로 학습을 이어가므로 첫 샘플부터 판단
+	if err := d.Write(context.Background(), cpuMetrics("node-1", time.Time{}, 50)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if got := len(published()); got != 1 {
+		t.Errorf("이벤트 %d개, 1개 기대", got)
+	}
+	baselines, ok := d.Baselines("node-1")
+	if !ok || baselines[0].Series != "cpu.usage" || baselines[0].Count != 101 {
+		t.Errorf("기준값 = %+v, 저장된 기준값에 이어서 학습 기대", baselines)
+	}
+}
+
+func TestDetectorSaveFailure(t *testing.T) {
+	loadTestConfig(t, "{enabled: true, z_threshold: 3, window: 9, min_samples: 5, persist_interval: 1, seasonal: false}")
+	store := &fakeStore{saveErr: errors.New("connection refused")}
+	d, _ := newTestDetector(t, store)
+	ctx := context.Background()
+
+	if err := d.Write(ctx, cpuMetrics("node-1", time.Time{}, 10)); err != nil {
+		t.Fatalf("저장 주기 전 Write: %v", err)
+	}
+	if store.saves != 0 {
+		t.Fatalf("저장 주기 전에 %d번 저장", store.saves)
+	}
+
+	// 저장 주기가 지나면 저장하고, 실패하면 다음 저장 대상으로 남김
+	d.nodes["node-1"].savedAt = time.Now().Add(-time.Hour)
+	if err := d.Write(ctx, cpuMetrics("node-1", time.Time{}, 10)); err == nil {
+		t.Fatal("저장 실패가 반환되지 않음")
+	}
+	if store.saves != 1 || !d.nodes["node-1"].dirty {
+		t.Fatalf("저장 %d번, dirty = %v, 1번과 true 기대", store.saves, d.nodes["node-1"].dirty)
+	}
+
+	// 종료 시 Flush가 남은 기준값을 저장
+	store.saveErr = nil
+	d.Flush()
+	if d.nodes["node-1"].dirty || len(store.saved["node-1"]) == 0 || store.saved["node-1"][0].Count != 2 {
+		t.Errorf("Flush 후 dirty = %v, 저장된 기준값 %+v", d.nodes["node-1"].dirty, store.saved["node-1"])
+	}
+
+	// 저장할 것이 없으면 Flush와 Forget은 저장하지 않음
+	saves := store.saves
+	d.Flush()
+	d.Forget("node-1")
+	if store.saves != saves {
+		t.Errorf("변경 없이 %d번 더 저장", store.saves-saves)
+	}
+	if _, ok := d.Baselines("node-1"); ok {
+		t.Error("Forget 후에도 기준값이 메모리에 남음")
+	}
+}
+
+func TestDetectorDisabled(t *testing.T) {
+	loadTestConfig(t, "{enabled: false}")
+	d, published := newTestDetector(t, &fakeStore{})
+	for i := 0; i < 10; i++ {
+		if err := d.Write(context.Background(), cpuMetrics("node-1", time.Time{}, float64(i*100))); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if _, ok := d.Baselines("node-1"); ok || len(published()) != 0 {
+		t.Error("비활성화 상태에서 학습하거나 이벤트를 발행함")
+	}
+}
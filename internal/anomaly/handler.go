@@ -0,0 +1,83 @@
+package anomaly
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"system-collector/internal/admin"
+	"system-collector/internal/httpapi"
+	"system-collector/internal/repository"
+	"system-collector/pkg/models"
+)
+
+// baselineResponse는 기준값 조회 응답의 시리즈 하나입니다
+type baselineResponse struct {
+	Series    string           `json:"series"`
+	Mean      float64          `json:"mean"`
+	StdDev    float64          `json:"stddev"`
+	Count     int64            `json:"count"`
+	Hourly    []hourlyResponse `json:"hourly,omitempty"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// hourlyResponse는 시간대 하나의 기준값입니다
+type hourlyResponse struct {
+	Hour   int     `json:"hour"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stddev"`
+	Count  int64   `json:"count"`
+}
+
+// Handler는 지표 기준값 조회 API를 제공합니다. 관리 API와 같은 인증을 사용합니다.
+type Handler struct {
+	detector *Detector
+	repo     *repository.BaselineRepository
+}
+
+// NewHandler는 지표 기준값 핸들러를 생성합니다
+func NewHandler(detector *Detector, repo *repository.BaselineRepository) *Handler {
+	return &Handler{detector: detector, repo: repo}
+}
+
+// RegisterRoutes는 핸들러를 mux에 등록합니다
+func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/nodes/{nodeID}/baselines", admin.RequireAdmin(h.handleBaselines))
+}
+
+// handleBaselines는 노드의 지표 기준값을 반환합니다.
+// 이 인스턴스가 학습 중인 노드는 메모리의 값을, 아니면 마지막으로 저장된 값을 반환합니다.
+func (h *Handler) handleBaselines(w http.ResponseWriter, r *http.Request) {
+	nodeID := r.PathValue("nodeID")
+	baselines, ok := h.detector.Baselines(nodeID)
+	if !ok {
+		var err error
+		if baselines, err = h.repo.GetBaselines(r.Context(), nodeID); err != nil {
+			httpapi.WriteError(w, http.StatusInternalServerError, "지표 기준값 조회 실패")
+			return
+		}
+	}
+
+	resp := make([]baselineResponse, 0, len(baselines))
+	for _, b := range baselines {
+		resp = append(resp, newBaselineResponse(b))
+	}
+	httpapi.WriteJSON(w, http.StatusOK, resp)
+}
+
+func newBaselineResponse(b models.MetricBaseline) baselineResponse {
+	resp := baselineResponse{
+		Series:    b.Series,
+		Mean:      b.Mean,
+		StdDev:    math.Sqrt(b.Variance),
+		Count:     b.Count,
+		UpdatedAt: b.UpdatedAt,
+	}
+	for hour, s := range b.Hourly {
+		if s.Count == 0 {
+			continue
+		}
+		resp.Hourly = append(resp.Hourly, hourlyResponse{Hour: hour, Mean: s.Mean, StdDev: math.Sqrt(s.Variance), Count: s.Count})
+	}
+	return resp
+}
@@ -0,0 +1,105 @@
+package anomaly
+
+import (
+	"math"
+
+	"system-collector/pkg/models"
+)
+
+// point는 메트릭스에서 꺼낸 시리즈 하나의 값입니다
+type point struct {
+	// series는 지표 이름과 레이블로 만든 시리즈 키입니다 (예: disk.read_bytes_per_sec{device=sda})
+	series string
+	metric string
+	labels map[string]string
+	value  float64
+	// minDelta는 이상으로 판단할 최소 절대 차이입니다. 거의 변하지 않던 시리즈의
+	// 작은 흔들림이 큰 z 값으로 잡히지 않도록 합니다.
+	minDelta float64
+}
+
+// newPoint는 지표 이름과 레이블(키 하나)로 시리즈 값을 만듭니다
+func newPoint(metric, labelKey, labelValue string, value, minDelta float64) point {
+	p := point{series: metric, metric: metric, value: value, minDelta: minDelta}
+	if labelKey != "" {
+		p.series = metric + "{" + labelKey + "=" + labelValue + "}"
+		p.labels = map[string]string{labelKey: labelValue}
+	}
+	return p
+}
+
+// 지표별 최소 절대 차이
+const (
+	percentMinDelta = 5.0     // 사용률 5%p
+	rateMinDelta    = 1 << 20 // 초당 1MiB
+)
+
+// extract는 이상 탐지 대상 시리즈를 꺼냅니다.
+// CPU/메모리 사용률, 장치별 디스크 읽기/쓰기 속도, 인터페이스별 수신/송신 속도를 사용합니다.
+func extract(m *models.SystemMetrics) []point {
+	points := []point{
+		newPoint("cpu.usage", "", "", m.CPU.Usage, percentMinDelta),
+		newPoint("memory.usage_percent", "", "", m.Memory.UsagePercent, percentMinDelta),
+	}
+
+	// 같은 장치가 여러 마운트에 나타날 수 있으므로 장치별로 한 번만 사용
+	devices := make(map[string]bool)
+	for _, d := range m.Disk {
+		if d.Device == "" || d.IOStats.ErrorFlag || devices[d.Device] {
+			continue
+		}
+		devices[d.Device] = true
+		points = append(points,
+			newPoint("disk.read_bytes_per_sec", "device", d.Device, d.IOStats.ReadBytesPerSec, rateMinDelta),
+			newPoint("disk.write_bytes_per_sec", "device", d.Device, d.IOStats.WriteBytesPerSec, rateMinDelta),
+		)
+	}
+
+	for _, n := range m.Network {
+		if n.Interface == "" || n.Interface == "lo" {
+			continue
+		}
+		points = append(points,
+			newPoint("network.rx_bytes_per_sec", "interface", n.Interface, n.RxBytesPerSec, rateMinDelta),
+			newPoint("network.tx_bytes_per_sec", "interface", n.Interface, n.TxBytesPerSec, rateMinDelta),
+		)
+	}
+
+	// 에이전트가 계산하지 못한 값(NaN, Inf)은 학습하지 않음
+	valid := points[:0]
+	for _, p := range points {
+		if !math.IsNaN(p.value) && !math.IsInf(p.value, 0) {
+			valid = append(valid, p)
+		}
+	}
+	return valid
+}
+
+// update는 지수 가중 이동 평균과 분산에 값을 반영합니다 (alpha는 새 값의 가중치)
+func update(s *models.BaselineStats, value, alpha float64) {
+	if s.Count == 0 {
+		s.Mean = value
+		s.Variance = 0
+		s.Count = 1
+		return
+	}
+	diff := value - s.Mean
+	incr := alpha * diff
+	s.Mean += incr
+	s.Variance = (1 - alpha) * (s.Variance + diff*incr)
+	s.Count++
+}
+
+// zscore는 값이 기준값에서 표준편차의 몇 배만큼 벗어났는지 반환합니다.
+// 분산이 0이면 차이가 있을 때 무한대를 반환합니다.
+func zscore(s models.BaselineStats, value float64) float64 {
+	diff := value - s.Mean
+	std := math.Sqrt(s.Variance)
+	if std == 0 {
+		if diff == 0 {
+			return 0
+		}
+		return math.Copysign(math.Inf(1), diff)
+	}
+	return diff / std
+}
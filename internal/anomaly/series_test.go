@@ -0,0 +1,98 @@
+package anomaly
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"system-collector/pkg/models"
+)
+
+func TestUpdate(t *testing.T) {
+	// alpha 0.5에서 값을 차례로 반영한 뒤의 평균과 분산
+	tests := []struct {
+		value    float64
+		mean     float64
+		variance float64
+	}{
+		{value: 10, mean: 10, variance: 0}, // 첫 값은 그대로 기준값
+		{value: 20, mean: 15, variance: 25},
+		{value: 10, mean: 12.5, variance: 18.75},
+		{value: 12.5, mean: 12.5, variance: 9.375},
+	}
+	var s models.BaselineStats
+	for i, tt := range tests {
+		update(&s, tt.value, 0.5)
+		if math.Abs(s.Mean-tt.mean) > 1e-9 || math.Abs(s.Variance-tt.variance) > 1e-9 || s.Count != int64(i+1) {
+			t.Errorf("%d번째 값 %g 반영 후 평균 %v, 분산 %v, 개수 %d, 기대 %v, %v, %d",
+				i, tt.value, s.Mean, s.Variance, s.Count, tt.mean, tt.variance, i+1)
+		}
+	}
+}
+
+func TestZscore(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats models.BaselineStats
+		value float64
+		want  float64
+	}{
+		{name: "표준편차 2, 3배 위", stats: models.BaselineStats{Mean: 10, Variance: 4}, value: 16, want: 3},
+		{name: "표준편차 2, 1.5배 아래", stats: models.BaselineStats{Mean: 10, Variance: 4}, value: 7, want: -1.5},
+		{name: "분산 0, 같은 값", stats: models.BaselineStats{Mean: 10}, value: 10, want: 0},
+		{name: "분산 0, 큰 값", stats: models.BaselineStats{Mean: 10}, value: 11, want: math.Inf(1)},
+		{name: "분산 0, 작은 값", stats: models.BaselineStats{Mean: 10}, value: 9, want: math.Inf(-1)},
+	}
+	for _, tt := range tests {
+		if got := zscore(tt.stats, tt.value); got != tt.want {
+			t.Errorf("%s: zscore = %v, 기대 %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExtract(t *testing.T) {
+	m := &models.SystemMetrics{
+		CPU:    models.CPUMetrics{Usage: 12.5},
+		Memory: models.MemoryMetrics{UsagePercent: math.NaN()},
+		Disk: []models.DiskMetrics{
+			{Device: "/dev/sda1", IOStats: models.DiskIOStats{ReadBytesPerSec: 100, WriteBytesPerSec: 200}},
+			{Device: "/dev/sda1", IOStats: models.DiskIOStats{ReadBytesPerSec: 999}}, // 같은 장치의 다른 마운트
+			{Device: "/dev/sdb1", IOStats: models.DiskIOStats{ErrorFlag: true}},
+			{Device: ""},
+		},
+		Network: []models.NetworkMetrics{
+			{Interface: "lo", RxBytesPerSec: 5},
+			{Interface: "eth0", RxBytesPerSec: 300, TxBytesPerSec: math.Inf(1)},
+		},
+	}
+
+	var got []string
+	values := map[string]float64{}
+	for _, p := range extract(m) {
+		got = append(got, p.series)
+		values[p.series] = p.value
+	}
+	want := []string{
+		"cpu.usage",
+		"disk.read_bytes_per_sec{device=/dev/sda1}",
+		"disk.write_bytes_per_sec{device=/dev/sda1}",
+		"network.rx_bytes_per_sec{interface=eth0}",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("시리즈 =\n%q\n기대\n%q", got, want)
+	}
+	if values["disk.read_bytes_per_sec{device=/dev/sda1}"] != 100 {
+		t.Errorf("장치의 첫 항목 값 대신 %v 사용", values["disk.read_bytes_per_sec{device=/dev/sda1}"])
+	}
+}
+
+func TestNewPoint(t *testing.T) {
+	p := newPoint("network.rx_bytes_per_sec", "interface", "eth0", 1, rateMinDelta)
+	if p.series != "network.rx_bytes_per_sec{interface=eth0}" || p.metric != "network.rx_bytes_per_sec" ||
+		!reflect.DeepEqual(p.labels, map[string]string{"interface": "eth0"}) {
+		t.Errorf("newPoint = %+v", p)
+	}
+	if p := newPoint("cpu.usage", "", "", 1, percentMinDelta); p.series != "cpu.usage" || p.labels != nil {
+		t.Errorf("레이블 없는 newPoint = %+v", p)
+	}
+}
@@ -0,0 +1,468 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	config "system-collector/configs"
+	"system-collector/internal/events"
+	"system-collector/internal/repository"
+	"system-collector/internal/storage"
+	"system-collector/pkg/logger"
+	"system-collector/pkg/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ControlChannel은 인스턴스 간 메시지를 주고받는 PostgreSQL LISTEN/NOTIFY 채널입니다
+const ControlChannel = "collector_cluster"
+
+// 설정이 없을 때의 기본값
+const (
+	defaultLeaseTTL      = 30 * time.Second
+	defaultRenewInterval = 10 * time.Second
+)
+
+// listenerPingInterval은 LISTEN 연결이 살아 있는지 확인하는 주기입니다
+const listenerPingInterval = 90 * time.Second
+
+// 인스턴스 간 메시지 유형
+const (
+	// messageTakeover는 노드가 다른 인스턴스에 다시 연결되어 이전 연결을 끊어야 함을 알립니다
+	messageTakeover = "takeover"
+)
+
+// controlMessage는 ControlChannel로 주고받는 메시지입니다
+type controlMessage struct {
+	Type   string `json:"type"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	NodeID string `json:"node_id"`
+}
+
+// LeaseStore는 노드 리스와 인스턴스 등록 저장소입니다 (repository.LeaseRepository)
+type LeaseStore interface {
+	AcquireLease(ctx context.Context, nodeID, instanceID string, ttl time.Duration) (string, error)
+	RenewLeases(ctx context.Context, instanceID string, nodeIDs []string, ttl time.Duration) ([]string, error)
+	ReleaseLease(ctx context.Context, nodeID, instanceID string) error
+	ReleaseAll(ctx context.Context, instanceID string) ([]string, error)
+	ExpireLeases(ctx context.Context) ([]models.NodeLease, error)
+	Heartbeat(ctx context.Context, instanceID string, startedAt time.Time) error
+	RemoveInstance(ctx context.Context, instanceID string) error
+	RemoveDeadInstances(ctx context.Context, maxAge time.Duration) ([]string, error)
+	GetInstances(ctx context.Context) ([]models.ClusterInstance, error)
+	Notify(ctx context.Context, channel, payload string) error
+}
+
+// NodeStatusStore는 리스가 만료된 노드의 상태를 기록하는 저장소입니다 (repository.NodeRepository)
+type NodeStatusStore interface {
+	UpdateNodeStatus(ctx context.Context, nodeID string, status int) error
+}
+
+// Coordinator는 여러 수집기 인스턴스가 같은 DB를 사용할 때 노드 연결 소유권을 관리합니다.
+// 노드가 연결된 인스턴스가 PostgreSQL에 리스를 기록하고 주기적으로 연장하며,
+// 노드 상태 갱신과 명령어 전달은 리스를 가진 인스턴스만 수행합니다.
+// 클러스터가 꺼져 있으면 리스를 기록하지 않고 모든 노드를 이 인스턴스가 소유한 것으로 봅니다.
+type Coordinator struct {
+	instanceID    string
+	enabled       bool
+	leaseTTL      time.Duration
+	renewInterval time.Duration
+	startedAt     time.Time
+
+	repo     LeaseStore
+	nodeRepo NodeStatusStore
+	bus      *events.Bus
+
+	mu    sync.Mutex
+	owned map[string]bool // 이 인스턴스가 리스를 가진 노드
+
+	// onTakeover는 다른 인스턴스가 노드를 가져갔을 때, onRelease는 이 인스턴스가 노드를 놓았을 때,
+	// onCommands는 이 인스턴스가 소유한 노드에 새 명령어가 등록되었을 때 호출됩니다
+	onTakeover []func(nodeID string)
+	onRelease  []func(nodeID string)
+	onCommands []func(nodeID string)
+
+	// ctx는 DB 호출과 로그에 인스턴스 ID를 붙이는 백그라운드 컨텍스트입니다
+	ctx context.Context
+
+	listener *pq.Listener
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewCoordinator는 설정에 따라 Coordinator를 생성합니다. bus가 nil이면 이벤트를 발행하지 않습니다.
+func NewCoordinator(repo LeaseStore, nodeRepo NodeStatusStore, bus *events.Bus) *Coordinator {
+	sugar := logger.GetCustomLogger()
+	cfg := config.Get().Cluster
+
+	instanceID := cfg.InstanceID
+	if instanceID == "" {
+		instanceID = defaultInstanceID()
+	}
+	leaseTTL := time.Duration(cfg.LeaseTTL) * time.Second
+	if leaseTTL <= 0 {
+		leaseTTL = defaultLeaseTTL
+	}
+	renewInterval := time.Duration(cfg.RenewInterval) * time.Second
+	if renewInterval <= 0 || renewInterval >= leaseTTL {
+		renewInterval = min(defaultRenewInterval, leaseTTL/3)
+	}
+
+	sugar.Infow("클러스터 코디네이터 초기화 중",
+		"enabled", cfg.Enabled,
+		"instanceID", instanceID,
+		"leaseTTL", leaseTTL,
+		"renewInterval", renewInterval)
+
+	return &Coordinator{
+		instanceID:    instanceID,
+		enabled:       cfg.Enabled,
+		leaseTTL:      leaseTTL,
+		renewInterval: renewInterval,
+		startedAt:     time.Now(),
+		repo:          repo,
+		nodeRepo:      nodeRepo,
+		bus:           bus,
+		owned:         make(map[string]bool),
+		ctx:           logger.WithContext(context.Background(), "instanceID", instanceID),
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+// defaultInstanceID는 호스트명과 임의 접미사로 인스턴스 ID를 만듭니다
+func defaultInstanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "collector"
+	}
+	return fmt.Sprintf("%s-%s", hostname, uuid.NewString()[:8])
+}
+
+// InstanceID는 이 인스턴스의 ID를 반환합니다
+func (c *Coordinator) InstanceID() string {
+	return c.instanceID
+}
+
+// Enabled는 클러스터 모드 여부를 반환합니다
+func (c *Coordinator) Enabled() bool {
+	return c.enabled
+}
+
+// OnTakeover는 다른 인스턴스가 노드를 가져갔을 때 호출할 함수를 등록합니다 (Start 전에 호출)
+func (c *Coordinator) OnTakeover(fn func(nodeID string)) {
+	c.onTakeover = append(c.onTakeover, fn)
+}
+
+// OnRelease는 이 인스턴스가 노드를 놓았을 때 호출할 함수를 등록합니다 (Start 전에 호출).
+// 노드별 메모리 상태를 버려 다른 인스턴스를 거쳐 돌아왔을 때 오래된 값을 쓰지 않도록 합니다.
+func (c *Coordinator) OnRelease(fn func(nodeID string)) {
+	c.onRelease = append(c.onRelease, fn)
+}
+
+// OnCommands는 소유한 노드에 새 명령어가 등록되었을 때 호출할 함수를 등록합니다 (Start 전에 호출)
+func (c *Coordinator) OnCommands(fn func(nodeID string)) {
+	c.onCommands = append(c.onCommands, fn)
+}
+
+// Start는 LISTEN 연결을 열고, 클러스터 모드이면 리스 연장과 만료 정리를 시작합니다
+func (c *Coordinator) Start(newListener storage.ListenerFactory) error {
+	sugar := logger.GetCustomLogger()
+
+	if c.enabled {
+		// 같은 인스턴스 ID로 재시작한 경우 이전 실행의 리스를 정리
+		if nodeIDs, err := c.repo.ReleaseAll(c.ctx, c.instanceID); err != nil {
+			return fmt.Errorf("이전 리스 정리 실패: %v", err)
+		} else if len(nodeIDs) > 0 {
+			sugar.Infow("이전 실행의 노드 리스 정리", "count", len(nodeIDs))
+		}
+		if err := c.repo.Heartbeat(c.ctx, c.instanceID, c.startedAt); err != nil {
+			return fmt.Errorf("인스턴스 등록 실패: %v", err)
+		}
+	}
+
+	listener := newListener(func(ev pq.ListenerEventType, err error) {
+		switch ev {
+		case pq.ListenerEventDisconnected:
+			sugar.Errorw("클러스터 알림 연결 끊김", "error", err)
+		case pq.ListenerEventReconnected:
+			sugar.Infow("클러스터 알림 연결 복구")
+		case pq.ListenerEventConnectionAttemptFailed:
+			sugar.Errorw("클러스터 알림 연결 시도 실패", "error", err)
+		}
+	})
+	channels := []string{repository.CommandNotifyChannel}
+	if c.enabled {
+		channels = append(channels, ControlChannel)
+	}
+	for _, ch := range channels {
+		if err := listener.Listen(ch); err != nil {
+			listener.Close()
+			return fmt.Errorf("%s 채널 구독 실패: %v", ch, err)
+		}
+	}
+	c.listener = listener
+
+	go c.run()
+	sugar.Infow("클러스터 코디네이터 시작", "instanceID", c.instanceID, "channels", channels)
+	return nil
+}
+
+func (c *Coordinator) run() {
+	defer close(c.done)
+	sugar := logger.GetCustomLogger()
+
+	renew := time.NewTicker(c.renewInterval)
+	defer renew.Stop()
+	ping := time.NewTicker(listenerPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case n := <-c.listener.Notify:
+			// nil 알림은 재연결을 뜻하며, 그 사이의 명령어 알림을 놓쳤을 수 있음
+			if n == nil {
+				for _, nodeID := range c.ownedNodes() {
+					c.notifyCommands(nodeID)
+				}
+				continue
+			}
+			c.handleNotification(n)
+		case <-renew.C:
+			if c.enabled {
+				c.maintain()
+			}
+		case <-ping.C:
+			go func() {
+				if err := c.listener.Ping(); err != nil {
+					sugar.Errorw("클러스터 알림 연결 확인 실패", "error", err)
+				}
+			}()
+		}
+	}
+}
+
+func (c *Coordinator) handleNotification(n *pq.Notification) {
+	sugar := logger.GetCustomLogger()
+
+	switch n.Channel {
+	case repository.CommandNotifyChannel:
+		if c.Owns(n.Extra) {
+			c.notifyCommands(n.Extra)
+		}
+	case ControlChannel:
+		var msg controlMessage
+		if err := json.Unmarshal([]byte(n.Extra), &msg); err != nil {
+			sugar.Errorw("클러스터 메시지 파싱 실패", "payload", n.Extra, "error", err)
+			return
+		}
+		if msg.To != c.instanceID {
+			return
+		}
+		switch msg.Type {
+		case messageTakeover:
+			sugar.Infow("다른 인스턴스가 노드를 가져감", "nodeID", msg.NodeID, "newOwner", msg.From)
+			c.mu.Lock()
+			delete(c.owned, msg.NodeID)
+			c.mu.Unlock()
+			for _, fn := range c.onTakeover {
+				fn(msg.NodeID)
+			}
+		}
+	}
+}
+
+func (c *Coordinator) notifyCommands(nodeID string) {
+	for _, fn := range c.onCommands {
+		go fn(nodeID)
+	}
+}
+
+// maintain은 인스턴스 heartbeat를 기록하고, 소유한 리스를 연장하며, 만료된 리스를 정리합니다
+func (c *Coordinator) maintain() {
+	sugar := logger.GetCustomLogger()
+
+	if err := c.repo.Heartbeat(c.ctx, c.instanceID, c.startedAt); err != nil {
+		sugar.Errorw("인스턴스 heartbeat 실패", "error", err)
+	}
+
+	if owned := c.ownedNodes(); len(owned) > 0 {
+		renewed, err := c.repo.RenewLeases(c.ctx, c.instanceID, owned, c.leaseTTL)
+		if err != nil {
+			sugar.Errorw("노드 리스 연장 실패", "error", err)
+		} else if len(renewed) < len(owned) {
+			// 다른 인스턴스가 가져갔거나 연장이 늦어 만료된 리스
+			still := make(map[string]bool, len(renewed))
+			for _, nodeID := range renewed {
+				still[nodeID] = true
+			}
+			for _, nodeID := range owned {
+				if still[nodeID] {
+					continue
+				}
+				sugar.Infow("노드 리스를 잃음", "nodeID", nodeID)
+				c.mu.Lock()
+				delete(c.owned, nodeID)
+				c.mu.Unlock()
+				for _, fn := range c.onTakeover {
+					fn(nodeID)
+				}
+			}
+		}
+	}
+
+	expired, err := c.repo.ExpireLeases(c.ctx)
+	if err != nil {
+		sugar.Errorw("만료 리스 정리 실패", "error", err)
+	}
+	for _, lease := range expired {
+		c.expire(lease)
+	}
+
+	if dead, err := c.repo.RemoveDeadInstances(c.ctx, 3*c.leaseTTL); err != nil {
+		sugar.Errorw("중단된 인스턴스 정리 실패", "error", err)
+	} else if len(dead) > 0 {
+		sugar.Infow("중단된 인스턴스 정리", "instances", dead)
+	}
+}
+
+// expire는 소유 인스턴스가 연장하지 못한 리스의 노드를 오프라인으로 표시합니다
+func (c *Coordinator) expire(lease models.NodeLease) {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("만료된 노드 리스 정리", "nodeID", lease.NodeID, "instanceID", lease.InstanceID)
+
+	if err := c.nodeRepo.UpdateNodeStatus(c.ctx, lease.NodeID, models.NodeStatusOffline); err != nil {
+		sugar.Errorw("노드 상태 업데이트 실패", "nodeID", lease.NodeID, "error", err)
+		return
+	}
+	if c.bus != nil {
+		c.bus.Publish(models.Event{
+			NodeID:   lease.NodeID,
+			Type:     models.EventNodeOffline,
+			Severity: models.SeverityCritical,
+			Message:  fmt.Sprintf("노드 오프라인 (인스턴스 %s의 리스 만료)", lease.InstanceID),
+			Data: map[string]interface{}{
+				"reason":      "lease_expired",
+				"instance_id": lease.InstanceID,
+				"expired_at":  lease.ExpiresAt,
+			},
+		})
+	}
+}
+
+// Acquire는 노드가 이 인스턴스에 연결되었음을 기록합니다.
+// 다른 인스턴스가 유효한 리스를 갖고 있었다면 그 인스턴스에 이전 연결을 끊도록 알립니다.
+func (c *Coordinator) Acquire(nodeID string) error {
+	sugar := logger.GetCustomLogger()
+
+	if !c.enabled {
+		c.mu.Lock()
+		c.owned[nodeID] = true
+		c.mu.Unlock()
+		return nil
+	}
+
+	previous, err := c.repo.AcquireLease(c.ctx, nodeID, c.instanceID, c.leaseTTL)
+	if err != nil {
+		return fmt.Errorf("노드 리스 획득 실패: %v", err)
+	}
+	c.mu.Lock()
+	c.owned[nodeID] = true
+	c.mu.Unlock()
+
+	if previous != "" && previous != c.instanceID {
+		sugar.Infow("다른 인스턴스에서 노드 연결을 가져옴", "nodeID", nodeID, "previousOwner", previous)
+		c.send(controlMessage{Type: messageTakeover, To: previous, NodeID: nodeID})
+	}
+	return nil
+}
+
+// Release는 노드 연결이 끊겼을 때 리스를 반납합니다
+func (c *Coordinator) Release(nodeID string) {
+	sugar := logger.GetCustomLogger()
+
+	c.mu.Lock()
+	owned := c.owned[nodeID]
+	delete(c.owned, nodeID)
+	c.mu.Unlock()
+
+	if owned && c.enabled {
+		if err := c.repo.ReleaseLease(c.ctx, nodeID, c.instanceID); err != nil {
+			sugar.Errorw("노드 리스 반납 실패, 만료 시 정리됨", "nodeID", nodeID, "error", err)
+		}
+	}
+	for _, fn := range c.onRelease {
+		fn(nodeID)
+	}
+}
+
+// Owns는 이 인스턴스가 노드의 리스를 갖고 있는지 반환합니다
+func (c *Coordinator) Owns(nodeID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.owned[nodeID]
+}
+
+func (c *Coordinator) ownedNodes() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	nodeIDs := make([]string, 0, len(c.owned))
+	for nodeID := range c.owned {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	return nodeIDs
+}
+
+func (c *Coordinator) send(msg controlMessage) {
+	sugar := logger.GetCustomLogger()
+
+	msg.From = c.instanceID
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		sugar.Errorw("클러스터 메시지 직렬화 실패", "error", err)
+		return
+	}
+	if err := c.repo.Notify(c.ctx, ControlChannel, string(payload)); err != nil {
+		sugar.Errorw("클러스터 메시지 전송 실패", "type", msg.Type, "to", msg.To, "error", err)
+	}
+}
+
+// Instances는 클러스터에 등록된 인스턴스 목록을 반환합니다
+func (c *Coordinator) Instances() ([]models.ClusterInstance, error) {
+	if !c.enabled {
+		return []models.ClusterInstance{}, nil
+	}
+	return c.repo.GetInstances(c.ctx)
+}
+
+// Stop은 알림 구독을 멈추고, 클러스터 모드이면 소유한 리스를 반납하고 인스턴스 등록을 삭제합니다
+func (c *Coordinator) Stop() {
+	sugar := logger.GetCustomLogger()
+
+	if c.listener != nil {
+		close(c.stop)
+		<-c.done
+		c.listener.Close()
+	}
+
+	if !c.enabled {
+		return
+	}
+	if nodeIDs, err := c.repo.ReleaseAll(c.ctx, c.instanceID); err != nil {
+		sugar.Errorw("노드 리스 반납 실패", "error", err)
+	} else {
+		sugar.Infow("노드 리스 반납 완료", "count", len(nodeIDs))
+	}
+	if err := c.repo.RemoveInstance(c.ctx, c.instanceID); err != nil {
+		sugar.Errorw("인스턴스 등록 삭제 실패", "error", err)
+	}
+}
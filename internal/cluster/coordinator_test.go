@@ -0,0 +1,404 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	config "system-collector/configs"
+	"system-collector/internal/events"
+	"system-collector/internal/repository"
+	"system-collector/pkg/models"
+
+	"github.com/lib/pq"
+)
+
+// fakeLeaseStore는 호출을 기록하고 정해진 결과를 돌려주는 LeaseStore입니다
+type fakeLeaseStore struct {
+	mu sync.Mutex
+
+	previous  string             // AcquireLease가 반환할 이전 소유 인스턴스
+	renewed   []string           // RenewLeases가 반환할 연장된 노드 (nil이면 요청한 노드 전부)
+	renewErr  error              // RenewLeases 오류
+	expired   []models.NodeLease // ExpireLeases가 반환할 만료 리스
+	released  []string           // ReleaseLease로 반납된 노드
+	notified  []controlMessage   // Notify로 보낸 메시지
+	heartbeat int                // Heartbeat 호출 수
+	renewReq  []string           // RenewLeases로 연장을 요청한 노드
+}
+
+func (s *fakeLeaseStore) AcquireLease(_ context.Context, _, _ string, _ time.Duration) (string, error) {
+	return s.previous, nil
+}
+
+func (s *fakeLeaseStore) RenewLeases(_ context.Context, _ string, nodeIDs []string, _ time.Duration) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.renewReq = append(s.renewReq, nodeIDs...)
+	if s.renewErr != nil {
+		return nil, s.renewErr
+	}
+	if s.renewed == nil {
+		return nodeIDs, nil
+	}
+	return s.renewed, nil
+}
+
+func (s *fakeLeaseStore) ReleaseLease(_ context.Context, nodeID, _ string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.released = append(s.released, nodeID)
+	return nil
+}
+
+func (s *fakeLeaseStore) ReleaseAll(context.Context, string) ([]string, error) { return nil, nil }
+
+func (s *fakeLeaseStore) ExpireLeases(context.Context) ([]models.NodeLease, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expired := s.expired
+	s.expired = nil
+	return expired, nil
+}
+
+func (s *fakeLeaseStore) Heartbeat(context.Context, string, time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heartbeat++
+	return nil
+}
+
+func (s *fakeLeaseStore) RemoveInstance(context.Context, string) error { return nil }
+
+func (s *fakeLeaseStore) RemoveDeadInstances(context.Context, time.Duration) ([]string, error) {
+	return nil, nil
+}
+
+func (s *fakeLeaseStore) GetInstances(context.Context) ([]models.ClusterInstance, error) {
+	return nil, nil
+}
+
+func (s *fakeLeaseStore) Notify(_ context.Context, channel, payload string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if channel != ControlChannel {
+		return errors.New("알 수 없는 채널 " + channel)
+	}
+	var msg controlMessage
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		return err
+	}
+	s.notified = append(s.notified, msg)
+	return nil
+}
+
+// fakeNodeStore는 UpdateNodeStatus로 바뀐 노드 상태를 기록합니다
+type fakeNodeStore struct {
+	mu     sync.Mutex
+	status map[string]int
+	err    error
+}
+
+func (s *fakeNodeStore) UpdateNodeStatus(_ context.Context, nodeID string, status int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	if s.status == nil {
+		s.status = make(map[string]int)
+	}
+	s.status[nodeID] = status
+	return nil
+}
+
+// recorder는 Coordinator 콜백으로 전달된 노드 ID를 모읍니다
+type recorder struct {
+	mu    sync.Mutex
+	nodes []string
+}
+
+func (r *recorder) add(nodeID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nodes = append(r.nodes, nodeID)
+}
+
+func (r *recorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.nodes...)
+}
+
+// newTestCoordinator는 클러스터 모드 설정으로 "instance-a" Coordinator를 만들고
+// takeover와 release 콜백 기록을 함께 반환합니다
+func newTestCoordinator(t *testing.T, store LeaseStore, nodes NodeStatusStore, bus *events.Bus) (*Coordinator, *recorder, *recorder) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	yaml := "influxdb: {token: test, org: test, bucket: test}\npostgres: {user: test, dbname: test}\n" +
+		"cluster: {enabled: true, instance_id: instance-a, lease_ttl: 30, renew_interval: 10}\n"
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatalf("설정 파일 쓰기 실패: %v", err)
+	}
+	if err := config.Load(path); err != nil {
+		t.Fatalf("설정 로드 실패: %v", err)
+	}
+
+	c := NewCoordinator(store, nodes, bus)
+	takeovers, releases := &recorder{}, &recorder{}
+	c.OnTakeover(takeovers.add)
+	c.OnRelease(releases.add)
+	return c, takeovers, releases
+}
+
+func takeover(t *testing.T, from, to, nodeID string) *pq.Notification {
+	t.Helper()
+	payload, err := json.Marshal(controlMessage{Type: messageTakeover, From: from, To: to, NodeID: nodeID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &pq.Notification{Channel: ControlChannel, Extra: string(payload)}
+}
+
+func TestHandleNotificationTakeover(t *testing.T) {
+	tests := []struct {
+		name         string
+		notification func(t *testing.T) *pq.Notification
+		wantOwned    []string
+		wantTakeover []string
+	}{
+		{
+			name:         "이 인스턴스로 온 takeover",
+			notification: func(t *testing.T) *pq.Notification { return takeover(t, "instance-b", "instance-a", "node-1") },
+			wantOwned:    []string{"node-2"},
+			wantTakeover: []string{"node-1"},
+		},
+		{
+			name:         "다른 인스턴스로 온 takeover는 무시",
+			notification: func(t *testing.T) *pq.Notification { return takeover(t, "instance-b", "instance-c", "node-1") },
+			wantOwned:    []string{"node-1", "node-2"},
+		},
+		{
+			name: "알 수 없는 메시지 유형은 무시",
+			notification: func(t *testing.T) *pq.Notification {
+				return &pq.Notification{Channel: ControlChannel, Extra: `{"type":"hello","to":"instance-a","node_id":"node-1"}`}
+			},
+			wantOwned: []string{"node-1", "node-2"},
+		},
+		{
+			name:         "파싱할 수 없는 메시지는 무시",
+			notification: func(t *testing.T) *pq.Notification { return &pq.Notification{Channel: ControlChannel, Extra: "{"} },
+			wantOwned:    []string{"node-1", "node-2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, takeovers, _ := newTestCoordinator(t, &fakeLeaseStore{}, &fakeNodeStore{}, nil)
+			for _, nodeID := range []string{"node-1", "node-2"} {
+				if err := c.Acquire(nodeID); err != nil {
+					t.Fatalf("Acquire(%s): %v", nodeID, err)
+				}
+			}
+
+			c.handleNotification(tt.notification(t))
+
+			if got := sortedNodes(c.ownedNodes()); !reflect.DeepEqual(got, tt.wantOwned) {
+				t.Errorf("소유한 노드 %v, 기대 %v", got, tt.wantOwned)
+			}
+			if got := takeovers.get(); !reflect.DeepEqual(got, tt.wantTakeover) {
+				t.Errorf("takeover 콜백 %v, 기대 %v", got, tt.wantTakeover)
+			}
+		})
+	}
+}
+
+func TestHandleNotificationCommands(t *testing.T) {
+	c, _, _ := newTestCoordinator(t, &fakeLeaseStore{}, &fakeNodeStore{}, nil)
+	commands := make(chan string, 2)
+	c.OnCommands(func(nodeID string) { commands <- nodeID })
+	if err := c.Acquire("node-1"); err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+
+	// 소유하지 않은 노드의 알림은 다른 인스턴스가 처리
+	c.handleNotification(&pq.Notification{Channel: repository.CommandNotifyChannel, Extra: "node-2"})
+	c.handleNotification(&pq.Notification{Channel: repository.CommandNotifyChannel, Extra: "node-1"})
+
+	select {
+	case nodeID := <-commands:
+		if nodeID != "node-1" {
+			t.Errorf("명령어 콜백 노드 %s, 기대 node-1", nodeID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("소유한 노드의 명령어 콜백이 호출되지 않음")
+	}
+	select {
+	case nodeID := <-commands:
+		t.Errorf("소유하지 않은 노드 %s의 명령어 콜백이 호출됨", nodeID)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestMaintain(t *testing.T) {
+	expiredAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	store := &fakeLeaseStore{
+		renewed: []string{"node-1"},
+		expired: []models.NodeLease{{NodeID: "node-9", InstanceID: "instance-dead", ExpiresAt: expiredAt}},
+	}
+	nodes := &fakeNodeStore{}
+
+	bus := events.NewBus(nil, 10)
+	var mu sync.Mutex
+	var published []models.Event
+	bus.Subscribe(func(e models.Event) {
+		mu.Lock()
+		published = append(published, e)
+		mu.Unlock()
+	})
+	bus.Start()
+
+	c, takeovers, _ := newTestCoordinator(t, store, nodes, bus)
+	for _, nodeID := range []string{"node-1", "node-2"} {
+		if err := c.Acquire(nodeID); err != nil {
+			t.Fatalf("Acquire(%s): %v", nodeID, err)
+		}
+	}
+
+	c.maintain()
+
+	if store.heartbeat != 1 {
+		t.Errorf("heartbeat %d번, 기대 1번", store.heartbeat)
+	}
+	if got := sortedNodes(store.renewReq); !reflect.DeepEqual(got, []string{"node-1", "node-2"}) {
+		t.Errorf("연장 요청한 노드 %v", got)
+	}
+	// 연장되지 않은 리스는 잃은 것으로 보고 takeover와 같이 처리
+	if got := c.ownedNodes(); !reflect.DeepEqual(got, []string{"node-1"}) {
+		t.Errorf("소유한 노드 %v, 기대 [node-1]", got)
+	}
+	if got := takeovers.get(); !reflect.DeepEqual(got, []string{"node-2"}) {
+		t.Errorf("takeover 콜백 %v, 기대 [node-2]", got)
+	}
+
+	// 만료된 리스의 노드는 오프라인으로 표시하고 이벤트 발행
+	if status, ok := nodes.status["node-9"]; !ok || status != models.NodeStatusOffline {
+		t.Errorf("만료된 노드 상태 = %v (%v), 오프라인 기대", status, ok)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := bus.Close(ctx); err != nil {
+		t.Fatalf("이벤트 버스 종료: %v", err)
+	}
+	if len(published) != 1 {
+		t.Fatalf("이벤트 %d개, 1개 기대", len(published))
+	}
+	e := published[0]
+	if e.NodeID != "node-9" || e.Type != models.EventNodeOffline || e.Data["reason"] != "lease_expired" ||
+		e.Data["instance_id"] != "instance-dead" || e.Data["expired_at"] != expiredAt {
+		t.Errorf("이벤트 = %+v", e)
+	}
+}
+
+func TestMaintainRenewFailure(t *testing.T) {
+	store := &fakeLeaseStore{renewErr: errors.New("connection refused")}
+	c, takeovers, _ := newTestCoordinator(t, store, &fakeNodeStore{}, nil)
+	if err := c.Acquire("node-1"); err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+
+	// 연장 요청이 실패하면 리스를 잃었는지 알 수 없으므로 소유권을 유지
+	c.maintain()
+	if !c.Owns("node-1") || len(takeovers.get()) != 0 {
+		t.Errorf("연장 실패 후 소유 = %v, takeover 콜백 %v", c.Owns("node-1"), takeovers.get())
+	}
+}
+
+func TestExpireUpdateFailure(t *testing.T) {
+	bus := events.NewBus(nil, 10)
+	published := 0
+	bus.Subscribe(func(models.Event) { published++ })
+	bus.Start()
+
+	c, _, _ := newTestCoordinator(t, &fakeLeaseStore{}, &fakeNodeStore{err: errors.New("connection refused")}, bus)
+	c.expire(models.NodeLease{NodeID: "node-9", InstanceID: "instance-dead"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := bus.Close(ctx); err != nil {
+		t.Fatalf("이벤트 버스 종료: %v", err)
+	}
+	if published != 0 {
+		t.Errorf("상태 업데이트 실패 후 이벤트 %d개 발행", published)
+	}
+}
+
+func TestAcquireTakeover(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous string
+		want     []controlMessage
+	}{
+		{name: "이전 소유자 없음"},
+		{name: "같은 인스턴스", previous: "instance-a"},
+		{
+			name:     "다른 인스턴스",
+			previous: "instance-b",
+			want:     []controlMessage{{Type: messageTakeover, From: "instance-a", To: "instance-b", NodeID: "node-1"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeLeaseStore{previous: tt.previous}
+			c, _, _ := newTestCoordinator(t, store, &fakeNodeStore{}, nil)
+			if err := c.Acquire("node-1"); err != nil {
+				t.Fatalf("Acquire: %v", err)
+			}
+			if !c.Owns("node-1") {
+				t.Error("Acquire 후 노드를 소유하지 않음")
+			}
+			if !reflect.DeepEqual(store.notified, tt.want) {
+				t.Errorf("보낸 메시지 %+v, 기대 %+v", store.notified, tt.want)
+			}
+		})
+	}
+}
+
+func TestReleaseAfterTakeover(t *testing.T) {
+	store := &fakeLeaseStore{}
+	c, _, releases := newTestCoordinator(t, store, &fakeNodeStore{}, nil)
+	for _, nodeID := range []string{"node-1", "node-2"} {
+		if err := c.Acquire(nodeID); err != nil {
+			t.Fatalf("Acquire(%s): %v", nodeID, err)
+		}
+	}
+
+	// node-1은 다른 인스턴스가 가져간 뒤 이전 연결이 끊김
+	c.handleNotification(takeover(t, "instance-b", "instance-a", "node-1"))
+	c.Release("node-1")
+	c.Release("node-2")
+
+	// 가져간 인스턴스의 리스를 지우지 않도록 소유한 노드만 반납
+	if !reflect.DeepEqual(store.released, []string{"node-2"}) {
+		t.Errorf("반납한 리스 %v, 기대 [node-2]", store.released)
+	}
+	// 메모리 상태는 두 노드 모두 정리
+	if got := releases.get(); !reflect.DeepEqual(got, []string{"node-1", "node-2"}) {
+		t.Errorf("release 콜백 %v, 기대 [node-1 node-2]", got)
+	}
+	if len(c.ownedNodes()) != 0 {
+		t.Errorf("Release 후 소유한 노드 %v", c.ownedNodes())
+	}
+}
+
+func sortedNodes(nodeIDs []string) []string {
+	out := append([]string(nil), nodeIDs...)
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,38 @@
+package cluster
+
+import (
+	"net/http"
+
+	"system-collector/internal/admin"
+	"system-collector/internal/httpapi"
+)
+
+// Handler는 클러스터 상태 조회 API를 제공합니다. 관리 API와 같은 인증을 사용합니다.
+type Handler struct {
+	coordinator *Coordinator
+}
+
+// NewHandler는 클러스터 상태 핸들러를 생성합니다
+func NewHandler(coordinator *Coordinator) *Handler {
+	return &Handler{coordinator: coordinator}
+}
+
+// RegisterRoutes는 핸들러를 mux에 등록합니다
+func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /debug/cluster", admin.RequireAdmin(h.handleCluster))
+}
+
+// handleCluster는 이 인스턴스의 ID, 소유한 노드 수, 클러스터에 등록된 인스턴스 목록을 반환합니다
+func (h *Handler) handleCluster(w http.ResponseWriter, r *http.Request) {
+	instances, err := h.coordinator.Instances()
+	if err != nil {
+		httpapi.WriteError(w, http.StatusInternalServerError, "인스턴스 조회 실패")
+		return
+	}
+	httpapi.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"enabled":     h.coordinator.Enabled(),
+		"instance_id": h.coordinator.InstanceID(),
+		"owned_nodes": len(h.coordinator.ownedNodes()),
+		"instances":   instances,
+	})
+}
@@ -0,0 +1,149 @@
+package containers
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"system-collector/pkg/models"
+)
+
+// maxOutputLen은 이벤트 메시지에 넣는 헬스 체크 출력의 최대 길이입니다
+const maxOutputLen = 200
+
+// state는 비교에 사용하는 컨테이너 하나의 상태입니다
+type state struct {
+	id        string
+	name      string
+	image     string
+	status    string
+	running   bool
+	restarts  int
+	health    string
+	unhealthy bool
+	streak    int
+	output    string
+}
+
+// snapshot은 컨테이너 목록을 이름별 상태로 바꿉니다. 이름이 없는 컨테이너는 ID를 사용합니다.
+func snapshot(containers []models.DockerContainer) map[string]state {
+	states := make(map[string]state, len(containers))
+	for _, c := range containers {
+		name := c.Name
+		if name == "" {
+			name = c.ID
+		}
+		if name == "" {
+			continue
+		}
+		states[name] = state{
+			id:        c.ID,
+			name:      name,
+			image:     c.Image,
+			status:    c.Status,
+			running:   c.Running(),
+			restarts:  c.Restarts,
+			health:    c.Health.Status,
+			unhealthy: c.Unhealthy(),
+			streak:    c.Health.FailingStreak,
+			output:    c.Health.LastCheckOutput,
+		}
+	}
+	return states
+}
+
+// diff는 이전 목록과 현재 목록을 비교해 컨테이너 이벤트를 컨테이너 이름 순으로 반환합니다.
+// 같은 이름의 컨테이너가 다른 ID로 바뀐 경우 이미지가 다르면 재배포(image_changed), 같으면 새로 생성된 것으로 봅니다.
+func diff(nodeID string, prev, cur map[string]state, now time.Time) []models.ContainerEvent {
+	names := make([]string, 0, len(prev)+len(cur))
+	for name := range cur {
+		names = append(names, name)
+	}
+	for name := range prev {
+		if _, ok := cur[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var events []models.ContainerEvent
+	add := func(s state, eventType, severity, oldValue, newValue, message string) {
+		events = append(events, models.ContainerEvent{
+			NodeID:        nodeID,
+			ContainerName: s.name,
+			ContainerID:   s.id,
+			Image:         s.image,
+			Type:          eventType,
+			Severity:      severity,
+			OldValue:      oldValue,
+			NewValue:      newValue,
+			Message:       message,
+			CreatedAt:     now,
+		})
+	}
+
+	for _, name := range names {
+		p, hadPrev := prev[name]
+		c, hasCur := cur[name]
+
+		switch {
+		case !hasCur:
+			add(p, models.EventContainerDisappeared, models.SeverityWarning, p.status, "",
+				fmt.Sprintf("컨테이너 %s 사라짐 (마지막 상태 %s)", name, p.status))
+			continue
+		case !hadPrev:
+			add(c, models.EventContainerCreated, models.SeverityInfo, "", c.status,
+				fmt.Sprintf("컨테이너 %s 생성됨 (이미지 %s, 상태 %s)", name, c.image, c.status))
+			if c.running {
+				add(c, models.EventContainerStarted, models.SeverityInfo, "", c.status, fmt.Sprintf("컨테이너 %s 시작됨", name))
+			}
+			checkHealth(add, state{}, c)
+			continue
+		}
+
+		recreated := p.id != "" && c.id != "" && p.id != c.id
+		if p.image != c.image {
+			add(c, models.EventContainerImageChanged, models.SeverityInfo, p.image, c.image,
+				fmt.Sprintf("컨테이너 %s 이미지 변경 %s -> %s", name, p.image, c.image))
+		} else if recreated {
+			add(c, models.EventContainerCreated, models.SeverityInfo, p.id, c.id,
+				fmt.Sprintf("컨테이너 %s 다시 생성됨 (이미지 %s, 상태 %s)", name, c.image, c.status))
+		}
+
+		switch {
+		case !p.running && c.running:
+			add(c, models.EventContainerStarted, models.SeverityInfo, p.status, c.status, fmt.Sprintf("컨테이너 %s 시작됨", name))
+		case p.running && !c.running:
+			add(c, models.EventContainerStopped, models.SeverityWarning, p.status, c.status,
+				fmt.Sprintf("컨테이너 %s 중지됨 (상태 %s)", name, c.status))
+		}
+
+		// 다시 생성된 컨테이너는 재시작 횟수가 새로 시작하므로 비교하지 않음
+		if !recreated && c.restarts > p.restarts {
+			delta := c.restarts - p.restarts
+			add(c, models.EventContainerRestarted, models.SeverityWarning, strconv.Itoa(p.restarts), strconv.Itoa(c.restarts),
+				fmt.Sprintf("컨테이너 %s %d회 재시작됨 (총 %d회)", name, delta, c.restarts))
+			events[len(events)-1].RestartDelta = delta
+		}
+
+		checkHealth(add, p, c)
+	}
+	return events
+}
+
+// checkHealth는 헬스 상태가 unhealthy로 바뀌었으면 이벤트를 추가합니다
+func checkHealth(add func(s state, eventType, severity, oldValue, newValue, message string), p, c state) {
+	if !c.unhealthy || p.unhealthy {
+		return
+	}
+	message := fmt.Sprintf("컨테이너 %s 헬스 체크 실패 (연속 %d회)", c.name, c.streak)
+	if output := strings.TrimSpace(c.output); output != "" {
+		if runes := []rune(output); len(runes) > maxOutputLen {
+			output = string(runes[:maxOutputLen]) + "..."
+		}
+		message += ": " + output
+	}
+	add(c, models.EventContainerUnhealthy, models.SeverityWarning, p.health, c.health, message)
+}
@@ -0,0 +1,147 @@
+package containers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"system-collector/pkg/models"
+)
+
+func container(id, name, image, status string, restarts int) models.DockerContainer {
+	return models.DockerContainer{ID: id, Name: name, Image: image, Status: status, Restarts: restarts}
+}
+
+func unhealthy(c models.DockerContainer, streak int, output string) models.DockerContainer {
+	c.Health = models.ContainerHealth{Status: "unhealthy", FailingStreak: streak, LastCheckOutput: output}
+	return c
+}
+
+// brief는 비교하기 쉽게 이벤트를 "유형 이름 이전값->새값" 형식으로 줄입니다
+func brief(events []models.ContainerEvent) []string {
+	var out []string
+	for _, e := range events {
+		out = append(out, fmt.Sprintf("%s %s %s->%s", e.Type, e.ContainerName, e.OldValue, e.NewValue))
+	}
+	return out
+}
+
+func TestDiff(t *testing.T) {
+	web := container("a1", "web", "nginx:1.25", "running", 0)
+
+	tests := []struct {
+		name string
+		prev []models.DockerContainer
+		cur  []models.DockerContainer
+		want []string
+	}{
+		{
+			name: "변화 없음",
+			prev: []models.DockerContainer{web},
+			cur:  []models.DockerContainer{web},
+		},
+		{
+			name: "새 컨테이너 실행",
+			cur:  []models.DockerContainer{web},
+			want: []string{"container_created web ->running", "container_started web ->running"},
+		},
+		{
+			name: "중지된 채 생성",
+			cur:  []models.DockerContainer{container("b1", "job", "busybox", "exited", 0)},
+			want: []string{"container_created job ->exited"},
+		},
+		{
+			name: "사라짐",
+			prev: []models.DockerContainer{web},
+			want: []string{"container_disappeared web running->"},
+		},
+		{
+			name: "중지 후 시작",
+			prev: []models.DockerContainer{container("a1", "web", "nginx:1.25", "exited", 0)},
+			cur:  []models.DockerContainer{container("a1", "web", "nginx:1.25", "Up 2 seconds", 0)},
+			want: []string{"container_started web exited->Up 2 seconds"},
+		},
+		{
+			name: "중지",
+			prev: []models.DockerContainer{web},
+			cur:  []models.DockerContainer{container("a1", "web", "nginx:1.25", "exited", 0)},
+			want: []string{"container_stopped web running->exited"},
+		},
+		{
+			name: "재시작 횟수 증가",
+			prev: []models.DockerContainer{web},
+			cur:  []models.DockerContainer{container("a1", "web", "nginx:1.25", "running", 3)},
+			want: []string{"container_restarted web 0->3"},
+		},
+		{
+			name: "이미지 변경 재배포",
+			prev: []models.DockerContainer{container("a1", "web", "nginx:1.25", "running", 5)},
+			cur:  []models.DockerContainer{container("a2", "web", "nginx:1.26", "running", 0)},
+			want: []string{"container_image_changed web nginx:1.25->nginx:1.26"},
+		},
+		{
+			name: "같은 이미지로 다시 생성 (재시작 횟수 초기화는 무시)",
+			prev: []models.DockerContainer{container("a1", "web", "nginx:1.25", "running", 5)},
+			cur:  []models.DockerContainer{container("a2", "web", "nginx:1.25", "running", 6)},
+			want: []string{"container_created web a1->a2"},
+		},
+		{
+			name: "헬스 체크 실패",
+			prev: []models.DockerContainer{web},
+			cur:  []models.DockerContainer{unhealthy(web, 3, "connection refused")},
+			want: []string{"container_unhealthy web ->unhealthy"},
+		},
+		{
+			name: "계속 unhealthy면 다시 알리지 않음",
+			prev: []models.DockerContainer{unhealthy(web, 3, "")},
+			cur:  []models.DockerContainer{unhealthy(web, 4, "")},
+		},
+		{
+			name: "이름 없는 컨테이너는 ID로 구분",
+			cur:  []models.DockerContainer{container("c1", "", "redis", "running", 0), {}},
+			want: []string{"container_created c1 ->running", "container_started c1 ->running"},
+		},
+		{
+			name: "이름 순 정렬",
+			prev: []models.DockerContainer{container("z1", "zeta", "x", "running", 0)},
+			cur:  []models.DockerContainer{container("a9", "alpha", "x", "running", 0)},
+			want: []string{"container_created alpha ->running", "container_started alpha ->running", "container_disappeared zeta running->"},
+		},
+	}
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := diff("node-1", snapshot(tt.prev), snapshot(tt.cur), now)
+			if got := brief(events); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diff =\n%q\n기대\n%q", got, tt.want)
+			}
+			for _, e := range events {
+				if e.NodeID != "node-1" || !e.CreatedAt.Equal(now) {
+					t.Errorf("이벤트 노드/시간 = %s, %v", e.NodeID, e.CreatedAt)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffDetails(t *testing.T) {
+	prev := snapshot([]models.DockerContainer{container("a1", "web", "nginx", "running", 1)})
+	cur := snapshot([]models.DockerContainer{container("a1", "web", "nginx", "running", 4)})
+	events := diff("node-1", prev, cur, time.Now())
+	if len(events) != 1 || events[0].RestartDelta != 3 || events[0].Severity != models.SeverityWarning {
+		t.Errorf("재시작 이벤트 = %+v, RestartDelta 3 기대", events)
+	}
+
+	long := strings.Repeat("가", maxOutputLen+50)
+	web := container("a1", "web", "nginx", "running", 0)
+	events = diff("node-1", snapshot([]models.DockerContainer{web}), snapshot([]models.DockerContainer{unhealthy(web, 2, "  "+long+"\n")}), time.Now())
+	if len(events) != 1 {
+		t.Fatalf("헬스 체크 이벤트 %d개, 1개 기대", len(events))
+	}
+	want := "컨테이너 web 헬스 체크 실패 (연속 2회): " + strings.Repeat("가", maxOutputLen) + "..."
+	if events[0].Message != want {
+		t.Errorf("메시지 = %q, 출력은 %d자로 잘려야 함", events[0].Message, maxOutputLen)
+	}
+}
@@ -0,0 +1,76 @@
+package containers
+
+import (
+	"net/http"
+	"strconv"
+
+	"system-collector/internal/admin"
+	"system-collector/internal/httpapi"
+	"system-collector/internal/repository"
+)
+
+// 컨테이너 이벤트 조회 시 limit 기본값과 최대값
+const (
+	defaultEventLimit = 100
+	maxEventLimit     = 1000
+)
+
+// Handler는 컨테이너 이벤트 조회 API를 제공합니다. 관리 API와 같은 인증을 사용합니다.
+type Handler struct {
+	repo *repository.ContainerEventRepository
+}
+
+// NewHandler는 컨테이너 이벤트 핸들러를 생성합니다
+func NewHandler(repo *repository.ContainerEventRepository) *Handler {
+	return &Handler{repo: repo}
+}
+
+// RegisterRoutes는 핸들러를 mux에 등록합니다
+func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/containers/events", admin.RequireAdmin(h.handleEvents))
+	mux.HandleFunc("GET /api/nodes/{nodeID}/containers/events", admin.RequireAdmin(h.handleNodeEvents))
+}
+
+// handleEvents는 컨테이너 이벤트를 최신순으로 반환합니다.
+// node_id, container, type, since(RFC3339 또는 24h 같은 기간), limit 쿼리 파라미터를 지원합니다.
+func (h *Handler) handleEvents(w http.ResponseWriter, r *http.Request) {
+	h.writeEvents(w, r, r.URL.Query().Get("node_id"))
+}
+
+// handleNodeEvents는 노드의 컨테이너 이벤트를 최신순으로 반환합니다
+func (h *Handler) handleNodeEvents(w http.ResponseWriter, r *http.Request) {
+	h.writeEvents(w, r, r.PathValue("nodeID"))
+}
+
+func (h *Handler) writeEvents(w http.ResponseWriter, r *http.Request, nodeID string) {
+	q := r.URL.Query()
+	filter := repository.ContainerEventFilter{
+		NodeID:        nodeID,
+		ContainerName: q.Get("container"),
+		Type:          q.Get("type"),
+		Limit:         defaultEventLimit,
+	}
+	if s := q.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			httpapi.WriteError(w, http.StatusBadRequest, "limit는 양의 정수여야 합니다")
+			return
+		}
+		filter.Limit = min(n, maxEventLimit)
+	}
+	if s := q.Get("since"); s != "" {
+		since, ok := httpapi.ParseSince(s)
+		if !ok {
+			httpapi.WriteError(w, http.StatusBadRequest, "since는 RFC3339 시각 또는 양의 기간이어야 합니다 (예: 24h)")
+			return
+		}
+		filter.Since = since
+	}
+
+	events, err := h.repo.GetContainerEvents(r.Context(), filter)
+	if err != nil {
+		httpapi.WriteError(w, http.StatusInternalServerError, "컨테이너 이벤트 조회 실패")
+		return
+	}
+	httpapi.WriteJSON(w, http.StatusOK, events)
+}
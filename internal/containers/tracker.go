@@ -0,0 +1,100 @@
+package containers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"system-collector/internal/events"
+	"system-collector/internal/registry"
+	"system-collector/internal/repository"
+	"system-collector/pkg/logger"
+	"system-collector/pkg/models"
+)
+
+// Tracker는 수집 큐의 Sink로 동작하며 노드의 연속된 컨테이너 목록을 비교해
+// 생성, 시작, 중지, 사라짐, 재시작, 헬스 체크 실패, 이미지 변경 이벤트를 감지합니다.
+// 감지한 이벤트는 container_events 테이블에 저장하고 이벤트 버스로 발행합니다.
+type Tracker struct {
+	repo     *repository.ContainerEventRepository
+	bus      *events.Bus
+	registry *registry.NodeRegistry
+
+	mu   sync.Mutex
+	last map[string]map[string]state // nodeID -> 이름 -> 마지막 컨테이너 상태
+}
+
+// NewTracker는 컨테이너 이벤트 Tracker를 생성합니다
+func NewTracker(repo *repository.ContainerEventRepository, bus *events.Bus, registry *registry.NodeRegistry) *Tracker {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("컨테이너 이벤트 트래커 초기화 중")
+
+	return &Tracker{
+		repo:     repo,
+		bus:      bus,
+		registry: registry,
+		last:     make(map[string]map[string]state),
+	}
+}
+
+// Name은 Sink 이름을 반환합니다
+func (t *Tracker) Name() string {
+	return "containers"
+}
+
+// Write는 컨테이너 목록을 이전 목록과 비교해 이벤트를 저장하고 발행합니다.
+// 노드의 첫 메트릭스(재시작 또는 다른 인스턴스에서 옮겨 온 직후)는 비교 기준으로만 사용합니다.
+func (t *Tracker) Write(ctx context.Context, metrics *models.SystemMetrics) error {
+	sugar := logger.FromContext(ctx)
+
+	nodeID := metrics.Key
+	cur := snapshot(metrics.Containers)
+
+	t.mu.Lock()
+	prev, ok := t.last[nodeID]
+	t.last[nodeID] = cur
+	t.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	changes := diff(nodeID, prev, cur, time.Now())
+	if len(changes) == 0 {
+		return nil
+	}
+	if err := t.repo.SaveContainerEvents(ctx, changes); err != nil {
+		return fmt.Errorf("컨테이너 이벤트 저장 실패: %v", err)
+	}
+
+	name := t.registry.DisplayName(nodeID, metrics.System.Hostname)
+	for _, c := range changes {
+		sugar.Infow("컨테이너 이벤트 감지", "container", c.ContainerName, "type", c.Type, "old", c.OldValue, "new", c.NewValue)
+		t.bus.Publish(models.Event{
+			NodeID:   nodeID,
+			Type:     c.Type,
+			Severity: c.Severity,
+			Message:  c.Message,
+			Data: map[string]interface{}{
+				"container_event_id": c.ID,
+				"container":          c.ContainerName,
+				"container_id":       c.ContainerID,
+				"image":              c.Image,
+				"old_value":          c.OldValue,
+				"new_value":          c.NewValue,
+				"restart_delta":      c.RestartDelta,
+				"labels":             map[string]string{"container": c.ContainerName, "image": c.Image},
+				"node_name":          name,
+				"hostname":           metrics.System.Hostname,
+			},
+		})
+	}
+	return nil
+}
+
+// Forget은 노드의 마지막 컨테이너 목록을 버립니다
+func (t *Tracker) Forget(nodeID string) {
+	t.mu.Lock()
+	delete(t.last, nodeID)
+	t.mu.Unlock()
+}
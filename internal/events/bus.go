@@ -0,0 +1,132 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"system-collector/internal/repository"
+	"system-collector/internal/telemetry"
+	"system-collector/pkg/logger"
+	"system-collector/pkg/models"
+)
+
+// Handler는 이벤트 구독 함수입니다
+type Handler func(event models.Event)
+
+// Bus는 노드 이벤트를 저장하고 구독자에게 전달하는 비동기 이벤트 버스입니다.
+// Publish는 호출자를 막지 않으며, 버퍼가 가득 차면 이벤트를 버립니다.
+type Bus struct {
+	repo   *repository.EventRepository
+	events chan models.Event
+
+	mu       sync.RWMutex
+	handlers []Handler
+	closed   bool
+
+	done chan struct{}
+}
+
+// NewBus는 버퍼 크기 size의 이벤트 버스를 생성합니다. repo가 nil이면 이벤트를 저장하지 않습니다.
+func NewBus(repo *repository.EventRepository, size int) *Bus {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("이벤트 버스 초기화 중", "size", size)
+
+	return &Bus{
+		repo:   repo,
+		events: make(chan models.Event, size),
+		done:   make(chan struct{}),
+	}
+}
+
+// Subscribe는 이벤트 구독자를 등록합니다. 구독자는 하나의 고루틴에서 순서대로 호출됩니다.
+func (b *Bus) Subscribe(h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, h)
+}
+
+// Start는 이벤트 전달 고루틴을 시작합니다
+func (b *Bus) Start() {
+	go func() {
+		defer close(b.done)
+		for event := range b.events {
+			b.dispatch(event)
+		}
+	}()
+}
+
+func (b *Bus) dispatch(event models.Event) {
+	ctx := logger.WithContext(context.Background(), "nodeID", event.NodeID, "eventType", event.Type)
+	sugar := logger.FromContext(ctx)
+
+	if b.repo != nil {
+		if err := b.repo.SaveEvent(ctx, &event); err != nil {
+			sugar.Errorw("이벤트 저장 실패", "nodeID", event.NodeID, "type", event.Type, "error", err)
+		}
+	}
+
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					sugar.Errorw("이벤트 구독자 패닉", "type", event.Type, "panic", fmt.Sprint(r))
+				}
+			}()
+			h(event)
+		}()
+	}
+}
+
+// Publish는 이벤트를 버스에 넣습니다. CreatedAt이 비어 있으면 현재 시간으로 설정합니다.
+func (b *Bus) Publish(event models.Event) {
+	sugar := logger.GetCustomLogger()
+
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	if event.Severity == "" {
+		event.Severity = models.SeverityInfo
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return
+	}
+
+	select {
+	case b.events <- event:
+		telemetry.EventsPublished.WithLabelValues(event.Type).Inc()
+		sugar.Infow("이벤트 발생",
+			"nodeID", event.NodeID,
+			"type", event.Type,
+			"severity", event.Severity,
+			"message", event.Message)
+	default:
+		telemetry.EventsDropped.Inc()
+		sugar.Errorw("이벤트 버퍼가 가득 차 이벤트를 버림", "nodeID", event.NodeID, "type", event.Type)
+	}
+}
+
+// Close는 새 이벤트를 막고 남은 이벤트를 모두 전달할 때까지 기다립니다
+func (b *Bus) Close(ctx context.Context) error {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.events)
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-b.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("이벤트 전달 대기 시간 초과: %d개 남음", len(b.events))
+	}
+}
@@ -0,0 +1,51 @@
+package events
+
+import (
+	"net/http"
+	"strconv"
+
+	"system-collector/internal/admin"
+	"system-collector/internal/httpapi"
+	"system-collector/internal/repository"
+)
+
+// 이벤트 조회 시 limit 기본값과 최대값
+const (
+	defaultEventLimit = 100
+	maxEventLimit     = 1000
+)
+
+// APIHandler는 노드 이벤트 조회 API를 제공합니다. 관리 API와 같은 인증을 사용합니다.
+type APIHandler struct {
+	repo *repository.EventRepository
+}
+
+// NewAPIHandler는 이벤트 API 핸들러를 생성합니다
+func NewAPIHandler(repo *repository.EventRepository) *APIHandler {
+	return &APIHandler{repo: repo}
+}
+
+// RegisterRoutes는 핸들러를 mux에 등록합니다
+func (h *APIHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/nodes/{nodeID}/events", admin.RequireAdmin(h.handleEvents))
+}
+
+// handleEvents는 노드의 이벤트를 최신순으로 반환합니다. type과 limit 쿼리 파라미터를 지원합니다.
+func (h *APIHandler) handleEvents(w http.ResponseWriter, r *http.Request) {
+	limit := defaultEventLimit
+	if s := r.URL.Query().Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			httpapi.WriteError(w, http.StatusBadRequest, "limit는 양의 정수여야 합니다")
+			return
+		}
+		limit = min(n, maxEventLimit)
+	}
+
+	events, err := h.repo.GetEvents(r.Context(), r.PathValue("nodeID"), r.URL.Query().Get("type"), limit)
+	if err != nil {
+		httpapi.WriteError(w, http.StatusInternalServerError, "이벤트 조회 실패")
+		return
+	}
+	httpapi.WriteJSON(w, http.StatusOK, events)
+}
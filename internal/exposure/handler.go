@@ -0,0 +1,91 @@
+package exposure
+
+import (
+	"net/http"
+	"strconv"
+
+	"system-collector/internal/admin"
+	"system-collector/internal/httpapi"
+	"system-collector/pkg/models"
+)
+
+// Handler는 포트 공개 현황 API를 제공합니다. 관리 API와 같은 인증을 사용합니다.
+// 현황은 노드를 처리하는 인스턴스의 메모리에만 있으므로 클러스터 모드에서는 해당 인스턴스에 조회해야 합니다.
+type Handler struct {
+	tracker *Tracker
+}
+
+// NewHandler는 포트 공개 현황 핸들러를 생성합니다
+func NewHandler(tracker *Tracker) *Handler {
+	return &Handler{tracker: tracker}
+}
+
+// RegisterRoutes는 핸들러를 mux에 등록합니다
+func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/exposure", admin.RequireAdmin(h.handleAll))
+	mux.HandleFunc("GET /api/nodes/{nodeID}/exposure", admin.RequireAdmin(h.handleNode))
+}
+
+// handleAll은 모든 노드의 공개 포트 현황을 반환합니다.
+// public=true이면 공인 IP에 공개된 포트만, 그런 포트가 있는 노드만 반환합니다.
+func (h *Handler) handleAll(w http.ResponseWriter, r *http.Request) {
+	publicOnly, ok := parsePublic(w, r)
+	if !ok {
+		return
+	}
+
+	result := []models.NodeExposure{}
+	for _, e := range h.tracker.All() {
+		if publicOnly {
+			e = onlyPublic(e)
+			if len(e.Ports) == 0 {
+				continue
+			}
+		}
+		result = append(result, e)
+	}
+	httpapi.WriteJSON(w, http.StatusOK, result)
+}
+
+// handleNode는 노드의 공개 포트 현황을 반환합니다. public=true이면 공인 IP에 공개된 포트만 반환합니다.
+func (h *Handler) handleNode(w http.ResponseWriter, r *http.Request) {
+	publicOnly, ok := parsePublic(w, r)
+	if !ok {
+		return
+	}
+
+	e, found := h.tracker.Node(r.PathValue("nodeID"))
+	if !found {
+		httpapi.WriteError(w, http.StatusNotFound, "포트 공개 현황이 없습니다")
+		return
+	}
+	if publicOnly {
+		e = onlyPublic(e)
+	}
+	httpapi.WriteJSON(w, http.StatusOK, e)
+}
+
+func parsePublic(w http.ResponseWriter, r *http.Request) (bool, bool) {
+	s := r.URL.Query().Get("public")
+	if s == "" {
+		return false, true
+	}
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		httpapi.WriteError(w, http.StatusBadRequest, "public은 true 또는 false여야 합니다")
+		return false, false
+	}
+	return v, true
+}
+
+// onlyPublic은 공인 IP에 공개된 포트만 남긴 복사본을 반환합니다
+func onlyPublic(e models.NodeExposure) models.NodeExposure {
+	ports := []models.ExposedPort{}
+	for _, p := range e.Ports {
+		if p.Public {
+			ports = append(ports, p)
+		}
+	}
+	e.Ports = ports
+	return e
+}
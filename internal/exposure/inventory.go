@@ -0,0 +1,174 @@
+package exposure
+
+import (
+	"net"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+
+	"system-collector/pkg/models"
+)
+
+// address는 인터페이스 주소 하나입니다
+type address struct {
+	iface string
+	addr  netip.Addr
+}
+
+// interfaceAddrs는 인터페이스 목록의 IPv4, IPv6 주소를 반환합니다.
+// 주소는 CIDR 표기(192.168.0.2/24)나 쉼표로 구분된 여러 주소여도 됩니다.
+func interfaceAddrs(network []models.NetworkMetrics) []address {
+	var addrs []address
+	for _, n := range network {
+		for _, field := range []string{n.IPv4, n.IPv6} {
+			for _, s := range strings.FieldsFunc(field, func(r rune) bool { return r == ',' || r == ' ' }) {
+				s, _, _ = strings.Cut(s, "/")
+				addr, err := netip.ParseAddr(s)
+				if err != nil {
+					continue
+				}
+				addrs = append(addrs, address{iface: n.Interface, addr: addr.WithZone("")})
+			}
+		}
+	}
+	return addrs
+}
+
+// parseHostPort는 도커가 보고한 호스트 포트("8080", "0.0.0.0:8080", "[::]:8080")를 바인딩 주소와 포트로 나눕니다.
+// 호스트에 공개되지 않은 포트이면 false를 반환합니다.
+func parseHostPort(s string) (string, string, bool) {
+	s = strings.TrimSpace(s)
+	host, port := "", s
+	if i := strings.LastIndex(s, ":"); i >= 0 {
+		if h, p, err := net.SplitHostPort(s); err == nil {
+			host, port = h, p
+		} else {
+			host, port = s[:i], s[i+1:]
+		}
+	}
+	if port == "" || port == "0" {
+		return "", "", false
+	}
+	return host, port, true
+}
+
+// isPublic은 주소가 공인 IP이거나 노드의 외부 IP와 같은지 반환합니다
+func isPublic(addr netip.Addr, externalIP netip.Addr) bool {
+	if externalIP.IsValid() && addr == externalIP {
+		return true
+	}
+	return addr.IsGlobalUnicast() && !addr.IsPrivate()
+}
+
+// bound는 바인딩 주소에 해당하는 인터페이스 주소를 반환합니다.
+// 모든 인터페이스에 바인딩된 포트는 루프백 주소를 제외합니다.
+func bound(bind string, addrs []address) []address {
+	switch bind {
+	case "", "0.0.0.0", "::", "*":
+		var result []address
+		for _, a := range addrs {
+			if a.addr.IsLoopback() || (bind == "0.0.0.0" && !a.addr.Is4()) || (bind == "::" && !a.addr.Is6()) {
+				continue
+			}
+			result = append(result, a)
+		}
+		return result
+	}
+
+	addr, err := netip.ParseAddr(strings.Trim(bind, "[]"))
+	if err != nil {
+		return []address{{addr: netip.Addr{}}}
+	}
+	addr = addr.WithZone("")
+	for _, a := range addrs {
+		if a.addr == addr {
+			return []address{a}
+		}
+	}
+	return []address{{addr: addr}}
+}
+
+// inventory는 실행 중인 컨테이너가 공개한 포트를 인터페이스 주소별로 펼쳐 반환합니다.
+// FirstSeen은 비어 있으며 호출하는 쪽에서 채웁니다.
+func inventory(containers []models.DockerContainer, network []models.NetworkMetrics, externalIP string) []models.ExposedPort {
+	addrs := interfaceAddrs(network)
+	external, _ := netip.ParseAddr(externalIP)
+
+	ports := []models.ExposedPort{}
+	for _, c := range containers {
+		if !c.Running() {
+			continue
+		}
+		for _, p := range c.Ports {
+			bind, hostPort, ok := parseHostPort(p.HostPort)
+			if !ok {
+				continue
+			}
+			protocol := strings.ToLower(p.Protocol)
+			if protocol == "" {
+				protocol = "tcp"
+			}
+			for _, a := range bound(bind, addrs) {
+				port := models.ExposedPort{
+					Interface:     a.iface,
+					BindAddress:   bind,
+					HostPort:      hostPort,
+					Protocol:      protocol,
+					ContainerPort: p.ContainerPort,
+					ContainerName: c.Name,
+					ContainerID:   c.ID,
+					Image:         c.Image,
+				}
+				if a.addr.IsValid() {
+					port.Address = a.addr.String()
+					port.Public = isPublic(a.addr, external)
+				} else {
+					port.Address = bind
+				}
+				ports = append(ports, port)
+			}
+		}
+	}
+
+	sort.Slice(ports, func(i, j int) bool {
+		a, b := ports[i], ports[j]
+		if a.Address != b.Address {
+			return a.Address < b.Address
+		}
+		if pa, pb := portNumber(a.HostPort), portNumber(b.HostPort); pa != pb {
+			return pa < pb
+		}
+		if a.Protocol != b.Protocol {
+			return a.Protocol < b.Protocol
+		}
+		return a.ContainerName < b.ContainerName
+	})
+	return ports
+}
+
+// portNumber는 정렬에 사용할 포트 번호입니다. 범위("8000-8010")는 시작 포트를 사용합니다.
+func portNumber(s string) int {
+	s, _, _ = strings.Cut(s, "-")
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// behindNAT는 외부 IP가 있고 어느 인터페이스에도 없는지 반환합니다
+func behindNAT(network []models.NetworkMetrics, externalIP string) bool {
+	external, err := netip.ParseAddr(externalIP)
+	if err != nil {
+		return false
+	}
+	for _, a := range interfaceAddrs(network) {
+		if a.addr == external {
+			return false
+		}
+	}
+	return true
+}
+
+// key는 공개 포트를 구분하는 값입니다 (같은 주소, 포트, 프로토콜은 같은 노출로 봄)
+func key(p models.ExposedPort) string {
+	return p.Address + "|" + p.HostPort + "/" + p.Protocol
+}
@@ -0,0 +1,143 @@
+package exposure
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"system-collector/pkg/models"
+)
+
+func TestParseHostPort(t *testing.T) {
+	tests := []struct {
+		in         string
+		host, port string
+		ok         bool
+	}{
+		{in: "8080", port: "8080", ok: true},
+		{in: "0.0.0.0:8080", host: "0.0.0.0", port: "8080", ok: true},
+		{in: "[::]:8080", host: "::", port: "8080", ok: true},
+		{in: " 127.0.0.1:9000 ", host: "127.0.0.1", port: "9000", ok: true},
+		{in: "0.0.0.0:8000-8010", host: "0.0.0.0", port: "8000-8010", ok: true},
+		{in: ""},
+		{in: "0"},
+		{in: "0.0.0.0:"},
+	}
+	for _, tt := range tests {
+		host, port, ok := parseHostPort(tt.in)
+		if host != tt.host || port != tt.port || ok != tt.ok {
+			t.Errorf("parseHostPort(%q) = %q, %q, %v, 기대 %q, %q, %v", tt.in, host, port, ok, tt.host, tt.port, tt.ok)
+		}
+	}
+}
+
+// brief는 비교하기 쉽게 공개 포트를 "인터페이스 주소 포트/프로토콜 컨테이너 [public]" 형식으로 줄입니다
+func brief(ports []models.ExposedPort) []string {
+	var out []string
+	for _, p := range ports {
+		s := fmt.Sprintf("%s %s %s/%s %s", p.Interface, p.Address, p.HostPort, p.Protocol, p.ContainerName)
+		if p.Public {
+			s += " public"
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+func TestInventory(t *testing.T) {
+	network := []models.NetworkMetrics{
+		{Interface: "lo", IPv4: "127.0.0.1/8", IPv6: "::1/128"},
+		{Interface: "eth0", IPv4: "10.0.0.5/24", IPv6: "fe80::1%eth0/64"},
+		{Interface: "eth1", IPv4: "203.0.113.10/24"},
+	}
+	container := func(name, status string, ports ...models.ContainerPort) models.DockerContainer {
+		return models.DockerContainer{ID: name + "-id", Name: name, Image: name + ":latest", Status: status, Ports: ports}
+	}
+
+	tests := []struct {
+		name       string
+		containers []models.DockerContainer
+		externalIP string
+		want       []string
+	}{
+		{
+			name:       "모든 IPv4 인터페이스 (루프백 제외)",
+			containers: []models.DockerContainer{container("web", "running", models.ContainerPort{ContainerPort: "80", HostPort: "0.0.0.0:8080", Protocol: "TCP"})},
+			want:       []string{"eth0 10.0.0.5 8080/tcp web", "eth1 203.0.113.10 8080/tcp web public"},
+		},
+		{
+			name:       "IPv6 전체 바인딩",
+			containers: []models.DockerContainer{container("web", "Up 5 minutes", models.ContainerPort{ContainerPort: "80", HostPort: "[::]:8080"})},
+			want:       []string{"eth0 fe80::1 8080/tcp web"},
+		},
+		{
+			name:       "특정 주소와 인터페이스에 없는 주소",
+			containers: []models.DockerContainer{container("db", "running", models.ContainerPort{ContainerPort: "5432", HostPort: "127.0.0.1:5432"}, models.ContainerPort{ContainerPort: "53", HostPort: "192.168.9.9:53", Protocol: "udp"})},
+			want:       []string{"lo 127.0.0.1 5432/tcp db", " 192.168.9.9 53/udp db"},
+		},
+		{
+			name:       "외부 IP와 같은 사설 주소는 공개",
+			containers: []models.DockerContainer{container("web", "running", models.ContainerPort{ContainerPort: "80", HostPort: "10.0.0.5:80"})},
+			externalIP: "10.0.0.5",
+			want:       []string{"eth0 10.0.0.5 80/tcp web public"},
+		},
+		{
+			name: "중지된 컨테이너와 공개하지 않은 포트 제외",
+			containers: []models.DockerContainer{
+				container("old", "exited", models.ContainerPort{ContainerPort: "80", HostPort: "10.0.0.5:80"}),
+				container("internal", "running", models.ContainerPort{ContainerPort: "6379"}),
+			},
+		},
+		{
+			name: "주소, 포트 번호, 프로토콜, 이름 순 정렬",
+			containers: []models.DockerContainer{
+				container("b", "running", models.ContainerPort{ContainerPort: "1", HostPort: "10.0.0.5:10000"}, models.ContainerPort{ContainerPort: "1", HostPort: "10.0.0.5:9000", Protocol: "udp"}),
+				container("a", "running", models.ContainerPort{ContainerPort: "1", HostPort: "10.0.0.5:9000", Protocol: "udp"}, models.ContainerPort{ContainerPort: "1", HostPort: "10.0.0.5:9000"}),
+			},
+			want: []string{"eth0 10.0.0.5 9000/tcp a", "eth0 10.0.0.5 9000/udp a", "eth0 10.0.0.5 9000/udp b", "eth0 10.0.0.5 10000/tcp b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := brief(inventory(tt.containers, network, tt.externalIP)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("inventory =\n%q\n기대\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBehindNAT(t *testing.T) {
+	network := []models.NetworkMetrics{
+		{Interface: "eth0", IPv4: "10.0.0.5/24, 10.0.0.6/24"},
+		{Interface: "eth1", IPv6: "2001:db8::5/64"},
+	}
+	tests := []struct {
+		externalIP string
+		want       bool
+	}{
+		{externalIP: "203.0.113.10", want: true},
+		{externalIP: "10.0.0.6"},
+		{externalIP: "2001:db8::5"},
+		{externalIP: ""},
+		{externalIP: "unknown"},
+	}
+	for _, tt := range tests {
+		if got := behindNAT(network, tt.externalIP); got != tt.want {
+			t.Errorf("behindNAT(%q) = %v, 기대 %v", tt.externalIP, got, tt.want)
+		}
+	}
+}
+
+func TestKey(t *testing.T) {
+	a := models.ExposedPort{Address: "10.0.0.5", HostPort: "80", Protocol: "tcp", ContainerName: "web"}
+	b := a
+	b.ContainerName, b.ContainerID = "web-2", "other"
+	if key(a) != key(b) {
+		t.Errorf("같은 주소와 포트인데 다른 키: %s, %s", key(a), key(b))
+	}
+	c := a
+	c.Protocol = "udp"
+	if key(a) == key(c) {
+		t.Errorf("다른 프로토콜인데 같은 키 %s", key(a))
+	}
+}
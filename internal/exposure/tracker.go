@@ -0,0 +1,171 @@
+package exposure
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"system-collector/internal/events"
+	"system-collector/internal/registry"
+	"system-collector/pkg/logger"
+	"system-collector/pkg/models"
+)
+
+// reexposeAfter는 공인 IP에서 닫힌 포트가 다시 열렸을 때 새 노출로 보기 위한 최소 시간입니다.
+// 컨테이너 재시작처럼 잠시 닫혔다 열리는 포트에 이벤트가 반복되지 않도록 합니다.
+const reexposeAfter = 24 * time.Hour
+
+// nodeState는 노드 하나의 마지막 공개 포트 현황입니다
+type nodeState struct {
+	exposure  models.NodeExposure
+	firstSeen map[string]time.Time // 공개 포트 키 -> 처음 본 시각 (닫히면 삭제)
+	public    map[string]time.Time // 공인 IP 공개 포트 키 -> 마지막으로 본 시각
+}
+
+// Tracker는 수집 큐의 Sink로 동작하며 노드의 컨테이너 포트 매핑과 인터페이스 주소를 합쳐
+// 어떤 호스트 포트가 어느 인터페이스에서 어떤 컨테이너로 공개되어 있는지 보관합니다.
+// 공인 IP(또는 노드의 외부 IP) 인터페이스에 새 포트가 공개되면 port_exposed 이벤트를 발행합니다.
+// 노드의 첫 메트릭스는 비교 기준으로만 사용합니다.
+type Tracker struct {
+	bus      *events.Bus
+	registry *registry.NodeRegistry
+
+	mu    sync.Mutex
+	nodes map[string]*nodeState
+}
+
+// NewTracker는 포트 공개 현황 Tracker를 생성합니다
+func NewTracker(bus *events.Bus, registry *registry.NodeRegistry) *Tracker {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("포트 공개 현황 트래커 초기화 중")
+
+	return &Tracker{
+		bus:      bus,
+		registry: registry,
+		nodes:    make(map[string]*nodeState),
+	}
+}
+
+// Name은 Sink 이름을 반환합니다
+func (t *Tracker) Name() string {
+	return "exposure"
+}
+
+// Write는 노드의 공개 포트 현황을 갱신하고 공인 IP에 새로 공개된 포트가 있으면 이벤트를 발행합니다
+func (t *Tracker) Write(ctx context.Context, metrics *models.SystemMetrics) error {
+	sugar := logger.FromContext(ctx)
+	nodeID := metrics.Key
+	externalIP := metrics.ExternalIP
+	if externalIP == "" {
+		if registered, ok := t.registry.Get(nodeID); ok {
+			externalIP = registered.ExternalIP
+		}
+	}
+	ports := inventory(metrics.Containers, metrics.Network, externalIP)
+	now := time.Now()
+
+	t.mu.Lock()
+	st, ok := t.nodes[nodeID]
+	baseline := !ok
+	if !ok {
+		st = &nodeState{firstSeen: make(map[string]time.Time), public: make(map[string]time.Time)}
+		t.nodes[nodeID] = st
+	}
+
+	var exposed []models.ExposedPort
+	current := make(map[string]time.Time, len(ports))
+	for i := range ports {
+		p := &ports[i]
+		k := key(*p)
+		first, ok := st.firstSeen[k]
+		if !ok {
+			first = now
+		}
+		p.FirstSeen = first
+		current[k] = first
+
+		if !p.Public {
+			continue
+		}
+		if last, ok := st.public[k]; !baseline && (!ok || now.Sub(last) >= reexposeAfter) {
+			exposed = append(exposed, *p)
+		}
+		st.public[k] = now
+	}
+	st.firstSeen = current
+	for k, last := range st.public {
+		if now.Sub(last) >= reexposeAfter {
+			delete(st.public, k)
+		}
+	}
+	st.exposure = models.NodeExposure{
+		NodeID:     nodeID,
+		Hostname:   metrics.System.Hostname,
+		ExternalIP: externalIP,
+		BehindNAT:  behindNAT(metrics.Network, externalIP),
+		Ports:      ports,
+		UpdatedAt:  now,
+	}
+	t.mu.Unlock()
+
+	name := t.registry.DisplayName(nodeID, metrics.System.Hostname)
+	for _, p := range exposed {
+		sugar.Infow("공인 IP에 새 포트 공개 감지", "address", p.Address, "port", p.HostPort, "protocol", p.Protocol, "container", p.ContainerName)
+		t.bus.Publish(models.Event{
+			NodeID:   nodeID,
+			Type:     models.EventPortExposed,
+			Severity: models.SeverityWarning,
+			Message: fmt.Sprintf("%s:%s/%s 포트가 공개됨 (인터페이스 %s, 컨테이너 %s:%s)",
+				p.Address, p.HostPort, p.Protocol, p.Interface, p.ContainerName, p.ContainerPort),
+			Data: map[string]interface{}{
+				"address":        p.Address,
+				"interface":      p.Interface,
+				"host_port":      p.HostPort,
+				"protocol":       p.Protocol,
+				"container_name": p.ContainerName,
+				"container_id":   p.ContainerID,
+				"container_port": p.ContainerPort,
+				"image":          p.Image,
+				"external_ip":    externalIP,
+				"labels":         map[string]string{"port": p.HostPort + "/" + p.Protocol, "container": p.ContainerName},
+				"node_name":      name,
+				"hostname":       metrics.System.Hostname,
+			},
+		})
+	}
+	return nil
+}
+
+// Forget은 노드의 공개 포트 현황을 버립니다
+func (t *Tracker) Forget(nodeID string) {
+	t.mu.Lock()
+	delete(t.nodes, nodeID)
+	t.mu.Unlock()
+}
+
+// Node는 노드의 공개 포트 현황을 반환합니다. 이 인스턴스가 추적하지 않는 노드는 false를 반환합니다.
+func (t *Tracker) Node(nodeID string) (models.NodeExposure, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	st, ok := t.nodes[nodeID]
+	if !ok {
+		return models.NodeExposure{}, false
+	}
+	return st.exposure, true
+}
+
+// All은 모든 노드의 공개 포트 현황을 노드 ID 순으로 반환합니다
+func (t *Tracker) All() []models.NodeExposure {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	all := make([]models.NodeExposure, 0, len(t.nodes))
+	for _, st := range t.nodes {
+		all = append(all, st.exposure)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].NodeID < all[j].NodeID })
+	return all
+}
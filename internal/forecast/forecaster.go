@@ -0,0 +1,239 @@
+package forecast
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	config "system-collector/configs"
+	"system-collector/internal/events"
+	"system-collector/internal/registry"
+	"system-collector/pkg/logger"
+	"system-collector/pkg/models"
+)
+
+// 예측 대상 자원
+const (
+	resourceBytes  = "bytes"
+	resourceInodes = "inodes"
+)
+
+// clearFactor는 경고를 해제하는 기준입니다. 예상 시간이 warn_before의 이 배수보다 길어져야 해제해
+// 추세가 기준 근처에서 흔들릴 때 경고가 반복되지 않도록 합니다.
+const clearFactor = 1.25
+
+// mount는 마운트 하나의 최근 샘플과 예측입니다
+type mount struct {
+	device      string
+	total       int64
+	inodesTotal int64
+	samples     []sample
+	forecast    models.DiskForecast
+	warned      map[string]bool // 자원 -> 경고 발행 여부
+}
+
+// Forecaster는 수집 큐의 Sink로 동작하며 노드의 마운트별 디스크 사용량과 아이노드 사용량의 증가 추세를
+// 최근 기간(window)의 선형 회귀로 구하고, 가득 찰 때까지의 예상 시간을 계산합니다.
+// 예상 시간이 warn_before보다 짧아지면 disk_full_predicted 이벤트를 발행합니다.
+type Forecaster struct {
+	bus      *events.Bus
+	registry *registry.NodeRegistry
+
+	mu    sync.Mutex
+	nodes map[string]map[string]*mount // 노드 ID -> 마운트 위치 -> 상태
+}
+
+// NewForecaster는 디스크 사용량 예측기를 생성합니다
+func NewForecaster(bus *events.Bus, registry *registry.NodeRegistry) *Forecaster {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("디스크 사용량 예측기 초기화 중")
+
+	return &Forecaster{
+		bus:      bus,
+		registry: registry,
+		nodes:    make(map[string]map[string]*mount),
+	}
+}
+
+// Name은 Sink 이름을 반환합니다
+func (f *Forecaster) Name() string {
+	return "forecast"
+}
+
+// Write는 마운트별 사용량을 샘플로 추가하고 예측을 갱신합니다.
+// 샘플은 resolution 간격으로 하나만 보관하며 window보다 오래된 샘플은 버립니다.
+func (f *Forecaster) Write(ctx context.Context, metrics *models.SystemMetrics) error {
+	cfg := config.Get().Forecast
+	if !cfg.Enabled {
+		return nil
+	}
+
+	nodeID := metrics.Key
+	now := time.Now()
+	resolution := time.Duration(cfg.Resolution) * time.Second
+	window := time.Duration(cfg.Window) * time.Second
+	warnBefore := time.Duration(cfg.WarnBefore) * time.Second
+
+	var detected []models.Event
+	f.mu.Lock()
+	mounts, ok := f.nodes[nodeID]
+	if !ok {
+		mounts = make(map[string]*mount)
+		f.nodes[nodeID] = mounts
+	}
+	seen := make(map[string]bool)
+	for _, d := range metrics.Disk {
+		if d.MountPoint == "" || d.Total <= 0 || d.ErrorFlag || seen[d.MountPoint] {
+			continue
+		}
+		seen[d.MountPoint] = true
+
+		m, ok := mounts[d.MountPoint]
+		// 크기가 바뀐 파일시스템(확장, 재생성)은 이전 샘플로 추세를 구할 수 없으므로 새로 시작
+		if !ok || m.device != d.Device || m.total != d.Total || m.inodesTotal != d.InodesTotal {
+			m = &mount{device: d.Device, total: d.Total, inodesTotal: d.InodesTotal, warned: make(map[string]bool)}
+			mounts[d.MountPoint] = m
+		}
+		if n := len(m.samples); n == 0 || now.Sub(m.samples[n-1].at) >= resolution {
+			m.samples = append(m.samples, sample{at: now, used: d.Used, inodes: d.InodesUsed})
+		}
+		cut := 0
+		for cut < len(m.samples) && now.Sub(m.samples[cut].at) > window {
+			cut++
+		}
+		m.samples = m.samples[cut:]
+
+		m.forecast = m.predict(nodeID, d, cfg.MinSamples, now)
+		for _, resource := range []string{resourceBytes, resourceInodes} {
+			if event, ok := m.check(resource, warnBefore); ok {
+				detected = append(detected, event)
+			}
+		}
+	}
+	// 사라진 마운트(해제된 볼륨 등)는 추적하지 않음
+	for mp := range mounts {
+		if !seen[mp] {
+			delete(mounts, mp)
+		}
+	}
+	f.mu.Unlock()
+
+	name := f.registry.DisplayName(nodeID, metrics.System.Hostname)
+	for _, event := range detected {
+		event.Data["node_name"] = name
+		event.Data["hostname"] = metrics.System.Hostname
+		f.bus.Publish(event)
+	}
+	return nil
+}
+
+// predict는 마운트의 용량과 아이노드 추세를 계산합니다
+func (m *mount) predict(nodeID string, d models.DiskMetrics, minSamples int, now time.Time) models.DiskForecast {
+	enough := len(m.samples) >= minSamples
+	fc := models.DiskForecast{
+		NodeID:     nodeID,
+		MountPoint: d.MountPoint,
+		Device:     d.Device,
+		Bytes:      trend(m.samples, func(s sample) int64 { return s.used }, d.Used, d.Total, enough, now),
+		Samples:    len(m.samples),
+		Since:      m.samples[0].at,
+		UpdatedAt:  now,
+	}
+	if d.InodesTotal > 0 {
+		inodes := trend(m.samples, func(s sample) int64 { return s.inodes }, d.InodesUsed, d.InodesTotal, enough, now)
+		fc.Inodes = &inodes
+	}
+	return fc
+}
+
+// check는 자원의 예상 시간이 기준보다 짧아졌으면 경고 이벤트를 반환합니다.
+// 이미 경고한 자원은 예상 시간이 충분히 길어질 때까지 다시 경고하지 않습니다.
+func (m *mount) check(resource string, warnBefore time.Duration) (models.Event, bool) {
+	t := &m.forecast.Bytes
+	if resource == resourceInodes {
+		if m.forecast.Inodes == nil {
+			delete(m.warned, resource)
+			return models.Event{}, false
+		}
+		t = m.forecast.Inodes
+	}
+	if warnBefore <= 0 {
+		return models.Event{}, false
+	}
+
+	var left time.Duration
+	if t.HoursToFull != nil {
+		left = time.Duration(*t.HoursToFull * float64(time.Hour))
+	}
+	if m.warned[resource] {
+		if t.HoursToFull == nil || left > time.Duration(float64(warnBefore)*clearFactor) {
+			delete(m.warned, resource)
+		}
+		return models.Event{}, false
+	}
+	if t.HoursToFull == nil || left >= warnBefore {
+		return models.Event{}, false
+	}
+	m.warned[resource] = true
+
+	what := "용량이"
+	if resource == resourceInodes {
+		what = "아이노드가"
+	}
+	fc := m.forecast
+	return models.Event{
+		NodeID:   fc.NodeID,
+		Type:     models.EventDiskFullPredicted,
+		Severity: models.SeverityWarning,
+		Message:  fmt.Sprintf("%s의 %s 약 %.1f시간 후 가득 찰 것으로 예상됨", fc.MountPoint, what, *t.HoursToFull),
+		Data: map[string]interface{}{
+			"metric":        "disk.hours_to_full",
+			"labels":        map[string]string{"mount": fc.MountPoint, "device": fc.Device, "resource": resource},
+			"value":         *t.HoursToFull,
+			"full_at":       *t.FullAt,
+			"used":          t.Used,
+			"total":         t.Total,
+			"rate_per_hour": t.RatePerHour,
+		},
+	}, true
+}
+
+// Forget은 노드의 샘플과 예측을 지웁니다
+func (f *Forecaster) Forget(nodeID string) {
+	f.mu.Lock()
+	delete(f.nodes, nodeID)
+	f.mu.Unlock()
+}
+
+// Node는 노드의 마운트별 예측을 마운트 위치 순으로 반환합니다. 이 인스턴스가 추적하지 않는 노드는 false를 반환합니다.
+func (f *Forecaster) Node(nodeID string) ([]models.DiskForecast, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	mounts, ok := f.nodes[nodeID]
+	if !ok {
+		return nil, false
+	}
+	forecasts := make([]models.DiskForecast, 0, len(mounts))
+	for _, m := range mounts {
+		forecasts = append(forecasts, m.forecast)
+	}
+	sort.Slice(forecasts, func(i, j int) bool { return forecasts[i].MountPoint < forecasts[j].MountPoint })
+	return forecasts, true
+}
+
+// All은 모든 노드의 마운트별 예측을 반환합니다
+func (f *Forecaster) All() []models.DiskForecast {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	forecasts := []models.DiskForecast{}
+	for _, mounts := range f.nodes {
+		for _, m := range mounts {
+			forecasts = append(forecasts, m.forecast)
+		}
+	}
+	return forecasts
+}
@@ -0,0 +1,83 @@
+package forecast
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"system-collector/internal/admin"
+	"system-collector/internal/httpapi"
+	"system-collector/pkg/models"
+)
+
+// Handler는 디스크 사용량 예측 API를 제공합니다. 관리 API와 같은 인증을 사용합니다.
+// 예측은 노드를 처리하는 인스턴스의 메모리에만 있으므로 클러스터 모드에서는 해당 인스턴스에 조회해야 합니다.
+type Handler struct {
+	forecaster *Forecaster
+}
+
+// NewHandler는 디스크 사용량 예측 핸들러를 생성합니다
+func NewHandler(forecaster *Forecaster) *Handler {
+	return &Handler{forecaster: forecaster}
+}
+
+// RegisterRoutes는 핸들러를 mux에 등록합니다
+func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/forecasts/disks", admin.RequireAdmin(h.handleAll))
+	mux.HandleFunc("GET /api/nodes/{nodeID}/forecasts/disks", admin.RequireAdmin(h.handleNode))
+}
+
+// handleAll은 모든 노드의 예측을 가득 찰 때까지 남은 시간이 짧은 순으로 반환합니다.
+// within 쿼리 파라미터(예: 24h)가 있으면 그 안에 용량이나 아이노드가 가득 찰 것으로 예상되는 마운트만 반환합니다.
+func (h *Handler) handleAll(w http.ResponseWriter, r *http.Request) {
+	within := math.Inf(1)
+	if v := r.URL.Query().Get("within"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			httpapi.WriteError(w, http.StatusBadRequest, "within은 양의 기간이어야 합니다 (예: 24h)")
+			return
+		}
+		within = d.Hours()
+	}
+
+	forecasts := []models.DiskForecast{}
+	for _, fc := range h.forecaster.All() {
+		if hoursToFull(fc) <= within {
+			forecasts = append(forecasts, fc)
+		}
+	}
+	sort.SliceStable(forecasts, func(i, j int) bool {
+		a, b := hoursToFull(forecasts[i]), hoursToFull(forecasts[j])
+		if a != b {
+			return a < b
+		}
+		if forecasts[i].NodeID != forecasts[j].NodeID {
+			return forecasts[i].NodeID < forecasts[j].NodeID
+		}
+		return forecasts[i].MountPoint < forecasts[j].MountPoint
+	})
+	httpapi.WriteJSON(w, http.StatusOK, forecasts)
+}
+
+// handleNode는 노드의 마운트별 예측을 반환합니다
+func (h *Handler) handleNode(w http.ResponseWriter, r *http.Request) {
+	forecasts, ok := h.forecaster.Node(r.PathValue("nodeID"))
+	if !ok {
+		httpapi.WriteError(w, http.StatusNotFound, "디스크 사용량 예측이 없습니다")
+		return
+	}
+	httpapi.WriteJSON(w, http.StatusOK, forecasts)
+}
+
+// hoursToFull은 용량과 아이노드 중 먼저 가득 찰 때까지의 예상 시간입니다. 예측이 없으면 무한대입니다.
+func hoursToFull(fc models.DiskForecast) float64 {
+	hours := math.Inf(1)
+	if fc.Bytes.HoursToFull != nil {
+		hours = *fc.Bytes.HoursToFull
+	}
+	if fc.Inodes != nil && fc.Inodes.HoursToFull != nil {
+		hours = min(hours, *fc.Inodes.HoursToFull)
+	}
+	return hours
+}
@@ -0,0 +1,74 @@
+package forecast
+
+import (
+	"time"
+
+	"system-collector/pkg/models"
+)
+
+// maxHoursToFull보다 먼 예측은 의미가 없으므로 가득 차지 않는 것으로 봅니다 (약 10년)
+const maxHoursToFull = 10 * 365 * 24
+
+// sample은 마운트 하나의 사용량 샘플입니다
+type sample struct {
+	at     time.Time
+	used   int64
+	inodes int64
+}
+
+// slope는 최소제곱 선형 회귀로 구한 초당 증가량을 반환합니다. 시간 폭이 없으면 false를 반환합니다.
+func slope(samples []sample, value func(sample) int64) (float64, bool) {
+	n := float64(len(samples))
+	if n < 2 {
+		return 0, false
+	}
+
+	// 첫 샘플 기준 경과 시간(초)을 x로 사용하고, 평균을 빼 계산 오차를 줄임
+	origin := samples[0].at
+	var meanX, meanY float64
+	for _, s := range samples {
+		meanX += s.at.Sub(origin).Seconds()
+		meanY += float64(value(s))
+	}
+	meanX /= n
+	meanY /= n
+
+	var sxy, sxx float64
+	for _, s := range samples {
+		dx := s.at.Sub(origin).Seconds() - meanX
+		sxy += dx * (float64(value(s)) - meanY)
+		sxx += dx * dx
+	}
+	if sxx == 0 {
+		return 0, false
+	}
+	return sxy / sxx, true
+}
+
+// trend는 현재 사용량과 회귀 기울기로 가득 찰 때까지의 예상 시간을 계산합니다.
+// 샘플이 부족하면 사용량만 채웁니다.
+func trend(samples []sample, value func(sample) int64, used, total int64, enough bool, now time.Time) models.Trend {
+	t := models.Trend{Used: used, Total: total}
+	if !enough {
+		return t
+	}
+	perSec, ok := slope(samples, value)
+	if !ok {
+		return t
+	}
+	t.RatePerHour = perSec * 3600
+	if perSec <= 0 {
+		return t
+	}
+
+	remaining := max(total-used, 0)
+	seconds := float64(remaining) / perSec
+	hours := seconds / 3600
+	if hours > maxHoursToFull {
+		return t
+	}
+	fullAt := now.Add(time.Duration(seconds * float64(time.Second)))
+	t.HoursToFull = &hours
+	t.FullAt = &fullAt
+	return t
+}
@@ -0,0 +1,149 @@
+package forecast
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"system-collector/pkg/models"
+)
+
+var origin = time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+
+// linear는 step 간격으로 start에서 perStep씩 늘어나는 샘플 n개를 만듭니다
+func linear(n int, step time.Duration, start, perStep int64) []sample {
+	samples := make([]sample, n)
+	for i := range samples {
+		samples[i] = sample{at: origin.Add(time.Duration(i) * step), used: start + int64(i)*perStep}
+	}
+	return samples
+}
+
+func used(s sample) int64 { return s.used }
+
+func TestSlope(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []sample
+		want    float64
+		ok      bool
+	}{
+		{name: "분당 60 증가", samples: linear(10, time.Minute, 1000, 60), want: 1, ok: true},
+		{name: "변화 없음", samples: linear(10, time.Minute, 1000, 0), want: 0, ok: true},
+		{name: "감소", samples: linear(5, time.Second, 1000, -10), want: -10, ok: true},
+		{
+			name: "흔들리는 값의 추세",
+			samples: []sample{
+				{at: origin, used: 100},
+				{at: origin.Add(10 * time.Second), used: 130},
+				{at: origin.Add(20 * time.Second), used: 110},
+				{at: origin.Add(30 * time.Second), used: 140},
+			},
+			want: 1, ok: true,
+		},
+		{name: "샘플 하나", samples: linear(1, time.Minute, 1000, 60)},
+		{name: "시간 폭 없음", samples: linear(3, 0, 1000, 60)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := slope(tt.samples, used)
+			if ok != tt.ok || math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("slope = %v, %v, 기대 %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestTrend(t *testing.T) {
+	now := origin.Add(time.Hour)
+	tests := []struct {
+		name      string
+		samples   []sample
+		used      int64
+		total     int64
+		enough    bool
+		wantRate  float64
+		wantHours float64 // hasHours가 false이면 가득 차지 않음
+		hasHours  bool
+	}{
+		{name: "샘플 부족", samples: linear(10, time.Minute, 0, 60), used: 540, total: 1000},
+		{name: "시간당 3600 증가, 7200 남음", samples: linear(10, time.Minute, 0, 60), used: 2800, total: 10000, enough: true, wantRate: 3600, wantHours: 2, hasHours: true},
+		{name: "감소", samples: linear(10, time.Minute, 1000, -60), used: 460, total: 1000, enough: true, wantRate: -3600},
+		{name: "이미 가득 참", samples: linear(10, time.Minute, 0, 60), used: 1200, total: 1000, enough: true, wantRate: 3600, wantHours: 0, hasHours: true},
+		{name: "10년보다 먼 예측", samples: linear(10, time.Hour, 0, 1), used: 9, total: 1 << 40, enough: true, wantRate: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := trend(tt.samples, used, tt.used, tt.total, tt.enough, now)
+			if got.Used != tt.used || got.Total != tt.total {
+				t.Errorf("사용량 = %d/%d, 기대 %d/%d", got.Used, got.Total, tt.used, tt.total)
+			}
+			if math.Abs(got.RatePerHour-tt.wantRate) > 1e-6 {
+				t.Errorf("RatePerHour = %v, 기대 %v", got.RatePerHour, tt.wantRate)
+			}
+			switch {
+			case !tt.hasHours:
+				if got.HoursToFull != nil || got.FullAt != nil {
+					t.Errorf("HoursToFull = %v, FullAt = %v, 비어 있어야 함", got.HoursToFull, got.FullAt)
+				}
+			case got.HoursToFull == nil || got.FullAt == nil:
+				t.Errorf("HoursToFull이 비어 있음, 기대 %v", tt.wantHours)
+			default:
+				if math.Abs(*got.HoursToFull-tt.wantHours) > 1e-6 {
+					t.Errorf("HoursToFull = %v, 기대 %v", *got.HoursToFull, tt.wantHours)
+				}
+				if want := now.Add(time.Duration(tt.wantHours * float64(time.Hour))); got.FullAt.Sub(want).Abs() > time.Millisecond {
+					t.Errorf("FullAt = %v, 기대 %v", *got.FullAt, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMountCheck(t *testing.T) {
+	hours := func(h float64) *float64 { return &h }
+	fullAt := origin
+
+	// 각 단계의 예상 시간을 차례로 적용 (warn_before 10시간, 해제 기준 12.5시간)
+	steps := []struct {
+		name  string
+		hours *float64
+		warn  bool
+	}{
+		{name: "여유 있음", hours: hours(48)},
+		{name: "기준보다 짧아짐", hours: hours(8), warn: true},
+		{name: "이미 경고함", hours: hours(5)},
+		{name: "기준 근처에서 흔들림", hours: hours(11)},
+		{name: "다시 짧아져도 해제 전이면 경고하지 않음", hours: hours(9)},
+		{name: "충분히 길어져 해제", hours: hours(13)},
+		{name: "다시 경고", hours: hours(9), warn: true},
+		{name: "사용량이 줄어 해제", hours: nil},
+		{name: "해제 후 다시 경고", hours: hours(1), warn: true},
+	}
+
+	m := &mount{device: "/dev/sda1", warned: make(map[string]bool)}
+	for _, step := range steps {
+		m.forecast = models.DiskForecast{
+			NodeID:     "node-1",
+			MountPoint: "/",
+			Device:     "/dev/sda1",
+			Bytes:      models.Trend{Used: 90, Total: 100, RatePerHour: 1, HoursToFull: step.hours, FullAt: &fullAt},
+		}
+		event, warn := m.check(resourceBytes, 10*time.Hour)
+		if warn != step.warn {
+			t.Fatalf("%s: 경고 = %v, 기대 %v", step.name, warn, step.warn)
+		}
+		if warn && (event.Type != models.EventDiskFullPredicted || event.Data["value"] != *step.hours) {
+			t.Errorf("%s: 이벤트 = %+v", step.name, event)
+		}
+	}
+
+	// 아이노드를 보고하지 않는 파일시스템과 warn_before 0은 경고하지 않음
+	if _, warn := m.check(resourceInodes, 10*time.Hour); warn {
+		t.Error("아이노드 정보 없이 경고")
+	}
+	m.warned = make(map[string]bool)
+	if _, warn := m.check(resourceBytes, 0); warn {
+		t.Error("warn_before 0인데 경고")
+	}
+}
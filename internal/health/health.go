@@ -0,0 +1,173 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"system-collector/internal/admin"
+	"system-collector/internal/httpapi"
+	"system-collector/internal/ingest"
+	"system-collector/pkg/logger"
+	"system-collector/pkg/version"
+)
+
+// checkTimeout은 readiness 검사 하나에 허용되는 최대 시간입니다
+const checkTimeout = 2 * time.Second
+
+// queueSaturationLimit는 수집 큐가 이 비율 이상 차 있으면 준비되지 않은 것으로 판단합니다
+const queueSaturationLimit = 0.9
+
+// Check는 readiness 판단에 사용하는 검사 함수입니다
+type Check struct {
+	Name string
+	Fn   func(ctx context.Context) error
+}
+
+// ClientSource는 연결 상태 정보를 제공하는 WebSocket 서버입니다
+type ClientSource interface {
+	ClientCount() int
+	NodeLastSeen() map[string]time.Time
+	ShuttingDown() bool
+}
+
+// Handler는 /healthz, /readyz, /debug/status 엔드포인트를 제공합니다
+type Handler struct {
+	checks    []Check
+	clients   ClientSource
+	queue     *ingest.Queue
+	startedAt time.Time
+}
+
+// CheckResult는 readiness 검사 결과입니다
+type CheckResult struct {
+	Name  string `json:"name"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// QueueStatus는 수집 큐 상태입니다
+type QueueStatus struct {
+	Length   int                         `json:"length"`
+	Capacity int                         `json:"capacity"`
+	Dropped  uint64                      `json:"dropped"`
+	Sinks    map[string]ingest.SinkStats `json:"sinks"`
+}
+
+// Status는 /debug/status 응답 본문입니다
+type Status struct {
+	Version          string               `json:"version"`
+	Commit           string               `json:"commit"`
+	BuildTime        string               `json:"build_time"`
+	StartedAt        time.Time            `json:"started_at"`
+	Uptime           string               `json:"uptime"`
+	ConnectedClients int                  `json:"connected_clients"`
+	NodeLastSeen     map[string]time.Time `json:"node_last_seen"`
+	Queue            QueueStatus          `json:"queue"`
+}
+
+// NewHandler는 헬스 체크 핸들러를 생성합니다. checks는 readiness 검사에 추가됩니다.
+func NewHandler(clients ClientSource, queue *ingest.Queue, checks ...Check) *Handler {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("헬스 체크 핸들러 초기화 중")
+
+	h := &Handler{
+		clients:   clients,
+		queue:     queue,
+		startedAt: time.Now(),
+	}
+	h.checks = append(h.checks, Check{Name: "shutdown", Fn: h.checkShutdown})
+	h.checks = append(h.checks, checks...)
+	h.checks = append(h.checks, Check{Name: "ingest_queue", Fn: h.checkQueue})
+	return h
+}
+
+// RegisterRoutes는 핸들러를 mux에 등록합니다
+func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /healthz", h.handleHealthz)
+	mux.HandleFunc("GET /readyz", h.handleReadyz)
+	mux.HandleFunc("GET /debug/status", admin.RequireAdmin(h.handleStatus))
+}
+
+func (h *Handler) checkShutdown(ctx context.Context) error {
+	if h.clients.ShuttingDown() {
+		return fmt.Errorf("서버가 종료 중입니다")
+	}
+	return nil
+}
+
+func (h *Handler) checkQueue(ctx context.Context) error {
+	capacity := h.queue.Cap()
+	if capacity == 0 {
+		return nil
+	}
+	length := h.queue.Len()
+	if float64(length)/float64(capacity) >= queueSaturationLimit {
+		return fmt.Errorf("수집 큐 포화: %d/%d", length, capacity)
+	}
+	return nil
+}
+
+// handleHealthz는 프로세스가 요청을 처리할 수 있는지만 확인합니다
+func (h *Handler) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	httpapi.WriteJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleReadyz는 모든 의존성 검사를 병렬로 실행하고 하나라도 실패하면 503을 반환합니다
+func (h *Handler) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	results := make([]CheckResult, len(h.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range h.checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+			defer cancel()
+
+			results[i] = CheckResult{Name: check.Name, OK: true}
+			if err := check.Fn(ctx); err != nil {
+				results[i].OK = false
+				results[i].Error = err.Error()
+			}
+		}(i, check)
+	}
+	wg.Wait()
+
+	status := http.StatusOK
+	for _, result := range results {
+		if !result.OK {
+			status = http.StatusServiceUnavailable
+			sugar := logger.GetCustomLogger()
+			sugar.Warnw("readiness 검사 실패", "check", result.Name, "error", result.Error)
+		}
+	}
+
+	httpapi.WriteJSON(w, status, map[string]interface{}{
+		"ready":  status == http.StatusOK,
+		"checks": results,
+	})
+}
+
+// handleStatus는 운영 진단용 내부 상태를 반환합니다
+func (h *Handler) handleStatus(w http.ResponseWriter, r *http.Request) {
+	status := Status{
+		Version:          version.Version,
+		Commit:           version.Commit,
+		BuildTime:        version.BuildTime,
+		StartedAt:        h.startedAt,
+		Uptime:           time.Since(h.startedAt).Round(time.Second).String(),
+		ConnectedClients: h.clients.ClientCount(),
+		NodeLastSeen:     h.clients.NodeLastSeen(),
+		Queue: QueueStatus{
+			Length:   h.queue.Len(),
+			Capacity: h.queue.Cap(),
+			Dropped:  h.queue.Dropped(),
+			Sinks:    h.queue.SinkStats(),
+		},
+	}
+	httpapi.WriteJSON(w, http.StatusOK, status)
+}
@@ -0,0 +1,202 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	config "system-collector/configs"
+	"system-collector/internal/ingest"
+	"system-collector/pkg/models"
+)
+
+// stubClients는 정해진 값을 돌려주는 ClientSource입니다
+type stubClients struct {
+	count        int
+	lastSeen     map[string]time.Time
+	shuttingDown bool
+}
+
+func (s *stubClients) ClientCount() int                   { return s.count }
+func (s *stubClients) NodeLastSeen() map[string]time.Time { return s.lastSeen }
+func (s *stubClients) ShuttingDown() bool                 { return s.shuttingDown }
+
+// nopSink는 메트릭스를 버리는 Sink입니다
+type nopSink struct{}
+
+func (nopSink) Name() string                                       { return "nop" }
+func (nopSink) Write(context.Context, *models.SystemMetrics) error { return nil }
+
+// loadTestConfig는 admin 토큰을 지정한 최소 설정을 로드합니다
+func loadTestConfig(t *testing.T) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	yaml := "influxdb: {token: test, org: test, bucket: test}\npostgres: {user: test, dbname: test}\nadmin: {token: admin-token}\n"
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatalf("설정 파일 쓰기 실패: %v", err)
+	}
+	if err := config.Load(path); err != nil {
+		t.Fatalf("설정 로드 실패: %v", err)
+	}
+}
+
+// fillQueue는 워커를 시작하지 않은 큐에 메트릭스 n개를 넣습니다
+func fillQueue(t *testing.T, q *ingest.Queue, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if err := q.Enqueue(context.Background(), &models.SystemMetrics{Key: "node-1"}); err != nil {
+			t.Fatalf("%d번째 Enqueue: %v", i, err)
+		}
+	}
+}
+
+func serve(h *Handler, req *http.Request) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestReadyz(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+	tests := []struct {
+		name         string
+		shuttingDown bool
+		queued       int
+		postgres     func(context.Context) error
+		influxdb     func(context.Context) error
+		timeout      time.Duration // 요청 컨텍스트 제한 시간 (0이면 없음)
+		wantStatus   int
+		wantFailed   []string
+	}{
+		{name: "모두 정상", queued: 8, postgres: ok, influxdb: ok, wantStatus: http.StatusOK},
+		{name: "수집 큐 90% 이상", queued: 9, postgres: ok, influxdb: ok, wantStatus: http.StatusServiceUnavailable, wantFailed: []string{"ingest_queue"}},
+		{name: "종료 중", shuttingDown: true, postgres: ok, influxdb: ok, wantStatus: http.StatusServiceUnavailable, wantFailed: []string{"shutdown"}},
+		{
+			name:       "PostgreSQL ping 실패",
+			postgres:   func(context.Context) error { return errors.New("connection refused") },
+			influxdb:   ok,
+			wantStatus: http.StatusServiceUnavailable,
+			wantFailed: []string{"postgres"},
+		},
+		{
+			name:     "InfluxDB ping 시간 초과",
+			postgres: ok,
+			influxdb: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			timeout:    10 * time.Millisecond,
+			wantStatus: http.StatusServiceUnavailable,
+			wantFailed: []string{"influxdb"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := ingest.NewQueue(10, 1, nopSink{})
+			fillQueue(t, q, tt.queued)
+			h := NewHandler(&stubClients{shuttingDown: tt.shuttingDown}, q,
+				Check{Name: "postgres", Fn: tt.postgres},
+				Check{Name: "influxdb", Fn: tt.influxdb})
+
+			req := httptest.NewRequest("GET", "/readyz", nil)
+			if tt.timeout > 0 {
+				// checkTimeout(2초)을 기다리지 않도록 요청 컨텍스트를 먼저 만료
+				ctx, cancel := context.WithTimeout(req.Context(), tt.timeout)
+				defer cancel()
+				req = req.WithContext(ctx)
+			}
+			rec := serve(h, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("상태 코드 %d, 기대 %d", rec.Code, tt.wantStatus)
+			}
+			var body struct {
+				Ready  bool          `json:"ready"`
+				Checks []CheckResult `json:"checks"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("응답 파싱 실패: %v (%s)", err, rec.Body)
+			}
+			if body.Ready != (tt.wantStatus == http.StatusOK) {
+				t.Errorf("ready = %v", body.Ready)
+			}
+
+			// 검사는 shutdown, 추가 검사, ingest_queue 순서로 모두 보고됨
+			names := []string{"shutdown", "postgres", "influxdb", "ingest_queue"}
+			if len(body.Checks) != len(names) {
+				t.Fatalf("검사 결과 %+v, %v 기대", body.Checks, names)
+			}
+			var failed []string
+			for i, c := range body.Checks {
+				if c.Name != names[i] {
+					t.Errorf("%d번째 검사 %s, 기대 %s", i, c.Name, names[i])
+				}
+				if !c.OK {
+					failed = append(failed, c.Name)
+					if c.Error == "" {
+						t.Errorf("실패한 검사 %s에 오류 메시지가 없음", c.Name)
+					}
+				}
+			}
+			if !reflect.DeepEqual(failed, tt.wantFailed) {
+				t.Errorf("실패한 검사 %v, 기대 %v", failed, tt.wantFailed)
+			}
+		})
+	}
+}
+
+func TestHealthz(t *testing.T) {
+	// 의존성이 실패해도 프로세스가 살아 있으면 정상
+	h := NewHandler(&stubClients{shuttingDown: true}, ingest.NewQueue(10, 1),
+		Check{Name: "postgres", Fn: func(context.Context) error { return errors.New("down") }})
+	if rec := serve(h, httptest.NewRequest("GET", "/healthz", nil)); rec.Code != http.StatusOK {
+		t.Errorf("상태 코드 %d, 기대 200", rec.Code)
+	}
+}
+
+func TestDebugStatus(t *testing.T) {
+	loadTestConfig(t)
+	seen := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	clients := &stubClients{count: 3, lastSeen: map[string]time.Time{"node-1": seen}}
+	q := ingest.NewQueue(10, 2, nopSink{})
+	fillQueue(t, q, 2)
+	h := NewHandler(clients, q)
+
+	// admin 토큰이 있으면 인증 필요
+	if rec := serve(h, httptest.NewRequest("GET", "/debug/status", nil)); rec.Code != http.StatusUnauthorized {
+		t.Errorf("토큰 없이 상태 코드 %d, 기대 401", rec.Code)
+	}
+
+	req := httptest.NewRequest("GET", "/debug/status", nil)
+	req.Header.Set("Authorization", "Bearer admin-token")
+	rec := serve(h, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("상태 코드 %d, 기대 200 (%s)", rec.Code, rec.Body)
+	}
+
+	var status Status
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("응답 파싱 실패: %v (%s)", err, rec.Body)
+	}
+	if status.ConnectedClients != 3 || !status.NodeLastSeen["node-1"].Equal(seen) {
+		t.Errorf("연결 상태 = %d, %v", status.ConnectedClients, status.NodeLastSeen)
+	}
+	if status.Queue.Length != 2 || status.Queue.Capacity != 10 || status.Queue.Dropped != 0 {
+		t.Errorf("큐 상태 = %+v, 길이 2, 용량 10 기대", status.Queue)
+	}
+	if _, ok := status.Queue.Sinks["nop"]; !ok || len(status.Queue.Sinks) != 1 {
+		t.Errorf("Sink 통계 = %+v, nop 하나 기대", status.Queue.Sinks)
+	}
+	if status.StartedAt.IsZero() || status.Uptime == "" || status.Version == "" {
+		t.Errorf("버전과 실행 시간 = %+v", status)
+	}
+}
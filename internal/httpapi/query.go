@@ -0,0 +1,15 @@
+package httpapi
+
+import "time"
+
+// ParseSince는 since 쿼리 파라미터를 시각으로 바꿉니다.
+// RFC3339 시각이나 지금부터 거슬러 올라갈 기간(예: 24h)을 받습니다.
+func ParseSince(s string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, true
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return time.Now().Add(-d), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,28 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"system-collector/pkg/logger"
+)
+
+// ErrorResponse는 API 오류 응답 본문입니다
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// WriteJSON은 v를 JSON으로 직렬화하여 status 코드와 함께 응답합니다
+func WriteJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		sugar := logger.GetCustomLogger()
+		sugar.Errorw("API 응답 전송 실패", "error", err)
+	}
+}
+
+// WriteError는 오류 메시지를 JSON 형식으로 응답합니다
+func WriteError(w http.ResponseWriter, status int, msg string) {
+	WriteJSON(w, status, ErrorResponse{Error: msg})
+}
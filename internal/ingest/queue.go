@@ -0,0 +1,320 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	config "system-collector/configs"
+	"system-collector/internal/telemetry"
+	"system-collector/pkg/logger"
+	"system-collector/pkg/models"
+)
+
+// ErrQueueClosed는 종료된 큐에 메트릭스를 넣으려 할 때 반환됩니다
+var ErrQueueClosed = errors.New("수집 큐가 종료되었습니다")
+
+// ErrQueueFull은 큐가 가득 차 메트릭스를 버렸을 때 반환됩니다
+var ErrQueueFull = errors.New("수집 큐가 가득 찼습니다")
+
+// enqueueTimeout은 큐가 가득 찼을 때 자리가 날 때까지 기다리는 최대 시간입니다
+const enqueueTimeout = time.Second
+
+// Sink는 큐에서 꺼낸 메트릭스를 처리하는 대상입니다
+type Sink interface {
+	// Name은 로그와 통계에 사용할 Sink 이름을 반환합니다
+	Name() string
+	// Write는 메트릭스 하나를 처리합니다. ctx에는 메시지의 로그 필드(연결/메시지/노드 ID)가 들어 있습니다.
+	Write(ctx context.Context, metrics *models.SystemMetrics) error
+}
+
+// Flusher는 종료 전에 버퍼를 비워야 하는 Sink가 구현합니다
+type Flusher interface {
+	Flush()
+}
+
+// SinkStats는 Sink별 처리 통계입니다
+type SinkStats struct {
+	// Writes는 처리한 메트릭스 수입니다
+	Writes uint64 `json:"writes"`
+	// Errors는 처리에 실패한 메트릭스 수입니다
+	Errors uint64 `json:"errors"`
+	// LastWrite는 마지막으로 처리를 마친 시간입니다
+	LastWrite time.Time `json:"last_write"`
+	// Lag는 마지막 메트릭스가 큐에 들어온 뒤 처리되기까지 걸린 시간입니다
+	Lag time.Duration `json:"lag_ns"`
+}
+
+type item struct {
+	ctx        context.Context
+	metrics    *models.SystemMetrics
+	enqueuedAt time.Time
+}
+
+// Queue는 수신한 메트릭스를 워커 풀로 전달하는 버퍼 큐입니다.
+// 같은 노드의 메트릭스는 항상 같은 워커가 처리하므로 노드 단위 순서가 보장됩니다.
+// Forward로 다음 단계 큐를 연결하면 이 큐의 Sink가 처리한 메트릭스를 이어서 넘깁니다.
+type Queue struct {
+	shards   []chan item
+	capacity int
+	sinks    []Sink
+	next     *Queue
+	mu       sync.RWMutex
+	closed   bool
+	wg       sync.WaitGroup
+	dropped  atomic.Uint64
+
+	statsMu sync.Mutex
+	stats   map[string]*SinkStats
+}
+
+// NewQueue는 전체 버퍼 크기 size와 워커 수 workers로 큐를 생성합니다
+func NewQueue(size, workers int, sinks ...Sink) *Queue {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("수집 큐 초기화 중", "size", size, "workers", workers)
+
+	if workers < 1 {
+		workers = 1
+	}
+	shardSize := size / workers
+	if shardSize < 1 {
+		shardSize = 1
+	}
+
+	q := &Queue{
+		shards:   make([]chan item, workers),
+		capacity: shardSize * workers,
+		sinks:    sinks,
+		stats:    make(map[string]*SinkStats, len(sinks)),
+	}
+	for i := range q.shards {
+		q.shards[i] = make(chan item, shardSize)
+	}
+	for _, sink := range sinks {
+		q.stats[sink.Name()] = &SinkStats{}
+	}
+	return q
+}
+
+// Forward는 이 큐에서 처리를 마친 메트릭스를 next 큐로 넘기도록 연결합니다 (Start 전에 호출).
+// next가 가득 차 있으면 기다리지 않고 next에서만 버리므로, next의 느린 Sink가 이 큐를 막지 않습니다.
+// Drain은 이 큐를 비운 뒤 next도 비웁니다.
+func (q *Queue) Forward(next *Queue) {
+	q.next = next
+}
+
+// Start는 워커 고루틴을 시작합니다. Forward로 연결한 다음 단계 큐도 함께 시작합니다.
+func (q *Queue) Start() {
+	for _, shard := range q.shards {
+		q.wg.Add(1)
+		go q.worker(shard)
+	}
+	if q.next != nil {
+		q.next.Start()
+	}
+}
+
+func (q *Queue) worker(shard chan item) {
+	defer q.wg.Done()
+
+	for it := range shard {
+		sugar := logger.FromContext(it.ctx)
+		timeout := sinkTimeout()
+		for _, sink := range q.sinks {
+			if !sinkEnabled(sink.Name()) {
+				continue
+			}
+			start := time.Now()
+			err := q.write(it.ctx, sink, it.metrics, timeout)
+			telemetry.SinkWriteDuration.WithLabelValues(sink.Name()).Observe(time.Since(start).Seconds())
+			if err != nil {
+				telemetry.SinkWriteErrors.WithLabelValues(sink.Name()).Inc()
+				sugar.Errorw("메트릭스 저장 실패", "sink", sink.Name(), "error", err)
+			}
+			q.recordWrite(sink.Name(), it.enqueuedAt, err)
+		}
+		if q.next != nil {
+			q.next.offer(it)
+		}
+	}
+}
+
+// write는 timeout이 있으면 제한 시간을 둔 컨텍스트로 Sink에 메트릭스를 씁니다
+func (q *Queue) write(ctx context.Context, sink Sink, metrics *models.SystemMetrics, timeout time.Duration) error {
+	if timeout <= 0 {
+		return sink.Write(ctx, metrics)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return sink.Write(ctx, metrics)
+}
+
+// sinkEnabled는 설정의 ingest.disabled_sinks에 없는 Sink인지 반환합니다 (설정을 다시 로드하면 바로 반영)
+func sinkEnabled(name string) bool {
+	cfg := config.Get()
+	return cfg == nil || !slices.Contains(cfg.Ingest.DisabledSinks, name)
+}
+
+// sinkTimeout은 설정의 ingest.sink_timeout을 반환합니다 (0은 제한 없음, 설정을 다시 로드하면 바로 반영)
+func sinkTimeout() time.Duration {
+	cfg := config.Get()
+	if cfg == nil {
+		return 0
+	}
+	return time.Duration(cfg.Ingest.SinkTimeout) * time.Second
+}
+
+func (q *Queue) recordWrite(name string, enqueuedAt time.Time, err error) {
+	q.statsMu.Lock()
+	defer q.statsMu.Unlock()
+
+	st := q.stats[name]
+	now := time.Now()
+	st.Writes++
+	if err != nil {
+		st.Errors++
+	}
+	st.LastWrite = now
+	st.Lag = now.Sub(enqueuedAt)
+}
+
+// Enqueue는 메트릭스를 노드에 해당하는 워커 큐에 넣습니다.
+// 큐가 가득 찬 상태가 enqueueTimeout 이상 지속되면 메트릭스를 버리고 ErrQueueFull을 반환합니다.
+func (q *Queue) Enqueue(ctx context.Context, metrics *models.SystemMetrics) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	// 메시지 처리가 끝나도 Sink 쓰기가 취소되지 않도록 로그 필드만 유지
+	it := item{ctx: context.WithoutCancel(ctx), metrics: metrics, enqueuedAt: time.Now()}
+	shard := q.shards[q.shardIndex(metrics.Key)]
+	select {
+	case shard <- it:
+		return nil
+	default:
+	}
+
+	timer := time.NewTimer(enqueueTimeout)
+	defer timer.Stop()
+	select {
+	case shard <- it:
+		return nil
+	case <-timer.C:
+		q.dropped.Add(1)
+		return ErrQueueFull
+	}
+}
+
+// offer는 앞 단계에서 처리를 마친 메트릭스를 기다리지 않고 넣습니다. 가득 차 있으면 버립니다.
+// 앞 단계의 워커가 모두 끝난 뒤에만 Drain하므로 종료된 큐에 넣는 경우는 없습니다.
+func (q *Queue) offer(it item) {
+	select {
+	case q.shards[q.shardIndex(it.metrics.Key)] <- it:
+	default:
+		q.dropped.Add(1)
+		sugar := logger.FromContext(it.ctx)
+		sugar.Warnw("다음 처리 단계가 가득 차 메트릭스를 버림", "sinks", q.sinkNames())
+	}
+}
+
+func (q *Queue) sinkNames() []string {
+	names := make([]string, len(q.sinks))
+	for i, sink := range q.sinks {
+		names[i] = sink.Name()
+	}
+	return names
+}
+
+func (q *Queue) shardIndex(nodeID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(nodeID))
+	return int(h.Sum32() % uint32(len(q.shards)))
+}
+
+// Len은 큐에 대기 중인 메트릭스 수를 반환합니다
+func (q *Queue) Len() int {
+	n := 0
+	for _, shard := range q.shards {
+		n += len(shard)
+	}
+	return n
+}
+
+// Cap은 큐의 전체 버퍼 크기를 반환합니다
+func (q *Queue) Cap() int {
+	return q.capacity
+}
+
+// Dropped는 큐가 가득 차 버려진 메트릭스 수를 반환합니다
+func (q *Queue) Dropped() uint64 {
+	return q.dropped.Load()
+}
+
+// SinkStats는 Forward로 연결한 다음 단계를 포함한 Sink별 처리 통계의 복사본을 반환합니다
+func (q *Queue) SinkStats() map[string]SinkStats {
+	result := make(map[string]SinkStats)
+	if q.next != nil {
+		result = q.next.SinkStats()
+	}
+
+	q.statsMu.Lock()
+	defer q.statsMu.Unlock()
+	for name, st := range q.stats {
+		result[name] = *st
+	}
+	return result
+}
+
+// Drain은 새 메트릭스 수신을 막고 대기 중인 메트릭스를 모두 처리한 뒤 Sink를 플러시합니다.
+// ctx가 먼저 만료되면 남은 메트릭스 수와 함께 오류를 반환합니다.
+func (q *Queue) Drain(ctx context.Context) error {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("수집 큐 비우는 중", "pending", q.Len())
+
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		for _, shard := range q.shards {
+			close(shard)
+		}
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		pending := q.Len()
+		sugar.Errorw("수집 큐 비우기 시간 초과", "pending", pending)
+		return fmt.Errorf("수집 큐 비우기 시간 초과: %d개 남음", pending)
+	}
+
+	for _, sink := range q.sinks {
+		if f, ok := sink.(Flusher); ok {
+			f.Flush()
+		}
+	}
+
+	// 이 큐의 워커가 모두 끝났으므로 다음 단계로 넘어갈 메트릭스도 더 없음
+	if q.next != nil {
+		if err := q.next.Drain(ctx); err != nil {
+			return err
+		}
+	}
+
+	sugar.Infow("수집 큐 비우기 완료")
+	return nil
+}
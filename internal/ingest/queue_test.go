@@ -0,0 +1,325 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	config "system-collector/configs"
+	"system-collector/pkg/models"
+)
+
+// recordSink는 처리한 메트릭스와 플러시 순서를 기록합니다
+type recordSink struct {
+	name   string // 비어 있으면 record
+	mu     sync.Mutex
+	events []string
+	nodes  map[string][]int
+	block  chan struct{} // nil이 아니면 닫힐 때까지 Write를 막음
+}
+
+func newRecordSink() *recordSink {
+	return &recordSink{nodes: make(map[string][]int)}
+}
+
+func (s *recordSink) Name() string {
+	if s.name == "" {
+		return "record"
+	}
+	return s.name
+}
+
+func (s *recordSink) Write(ctx context.Context, metrics *models.SystemMetrics) error {
+	if s.block != nil {
+		<-s.block
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, "write")
+	s.nodes[metrics.Key] = append(s.nodes[metrics.Key], int(metrics.Timestamp.Unix()))
+	return nil
+}
+
+func (s *recordSink) Flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, "flush")
+}
+
+func metricsFor(nodeID string, seq int) *models.SystemMetrics {
+	return &models.SystemMetrics{Key: nodeID, Timestamp: time.Unix(int64(seq), 0)}
+}
+
+func TestQueueNodeOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		workers int
+		nodes   int
+		perNode int
+	}{
+		{name: "워커 1개", workers: 1, nodes: 5, perNode: 50},
+		{name: "워커 4개", workers: 4, nodes: 20, perNode: 50},
+		{name: "노드보다 많은 워커", workers: 16, nodes: 3, perNode: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := newRecordSink()
+			q := NewQueue(64, tt.workers, sink)
+			q.Start()
+
+			var wg sync.WaitGroup
+			for n := 0; n < tt.nodes; n++ {
+				wg.Add(1)
+				go func(nodeID string) {
+					defer wg.Done()
+					for i := 0; i < tt.perNode; i++ {
+						if err := q.Enqueue(context.Background(), metricsFor(nodeID, i)); err != nil {
+							t.Errorf("Enqueue: %v", err)
+						}
+					}
+				}(fmt.Sprintf("node-%d", n))
+			}
+			wg.Wait()
+			if err := q.Drain(context.Background()); err != nil {
+				t.Fatalf("Drain: %v", err)
+			}
+
+			if len(sink.nodes) != tt.nodes {
+				t.Fatalf("처리한 노드 %d개, 기대 %d개", len(sink.nodes), tt.nodes)
+			}
+			for nodeID, seqs := range sink.nodes {
+				if len(seqs) != tt.perNode {
+					t.Errorf("%s: %d개 처리, 기대 %d개", nodeID, len(seqs), tt.perNode)
+				}
+				for i, seq := range seqs {
+					if seq != i {
+						t.Errorf("%s: %d번째 메트릭스 순번 %d (노드 단위 순서가 깨짐)", nodeID, i, seq)
+						break
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestQueueShardIndex(t *testing.T) {
+	q := NewQueue(8, 4)
+	for _, nodeID := range []string{"", "node-1", "node-2", "아주-긴-노드-이름-0123456789"} {
+		idx := q.shardIndex(nodeID)
+		if idx < 0 || idx >= 4 {
+			t.Errorf("shardIndex(%q) = %d, 범위 밖", nodeID, idx)
+		}
+		if again := q.shardIndex(nodeID); again != idx {
+			t.Errorf("shardIndex(%q)가 호출마다 다름: %d, %d", nodeID, idx, again)
+		}
+	}
+	if q.Cap() != 8 {
+		t.Errorf("Cap = %d, 기대 8", q.Cap())
+	}
+}
+
+func TestQueueDrainFlushesAfterWrites(t *testing.T) {
+	sink := newRecordSink()
+	sink.block = make(chan struct{})
+	q := NewQueue(16, 2, sink)
+	q.Start()
+
+	for i := 0; i < 10; i++ {
+		if err := q.Enqueue(context.Background(), metricsFor(fmt.Sprintf("node-%d", i%3), i)); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+
+	drained := make(chan error, 1)
+	go func() { drained <- q.Drain(context.Background()) }()
+
+	// Drain이 시작되면 새 메트릭스는 거부 (그 전에 들어간 메트릭스는 처리)
+	accepted := 10
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		err := q.Enqueue(context.Background(), metricsFor("late", accepted))
+		if errors.Is(err, ErrQueueClosed) {
+			break
+		}
+		if err == nil {
+			accepted++
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Drain 중 Enqueue = %v, ErrQueueClosed 기대", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	close(sink.block)
+
+	if err := <-drained; err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if len(sink.events) != accepted+1 {
+		t.Fatalf("이벤트 %v, 쓰기 %d개와 플러시 기대", sink.events, accepted)
+	}
+	for i, e := range sink.events[:accepted] {
+		if e != "write" {
+			t.Fatalf("이벤트 %d = %s, 플러시 전에 모든 쓰기가 끝나야 함 (%v)", i, e, sink.events)
+		}
+	}
+	if last := sink.events[len(sink.events)-1]; last != "flush" {
+		t.Errorf("마지막 이벤트 = %s, flush 기대", last)
+	}
+}
+
+func TestQueueDrainTimeout(t *testing.T) {
+	sink := newRecordSink()
+	sink.block = make(chan struct{})
+	defer close(sink.block)
+	q := NewQueue(4, 1, sink)
+	q.Start()
+
+	for i := 0; i < 3; i++ {
+		if err := q.Enqueue(context.Background(), metricsFor("node-1", i)); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := q.Drain(ctx); err == nil {
+		t.Fatal("막힌 Sink로 Drain이 성공함, 시간 초과 기대")
+	}
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	for _, e := range sink.events {
+		if e == "flush" {
+			t.Error("시간 초과인데 플러시됨")
+		}
+	}
+}
+
+func TestQueueFull(t *testing.T) {
+	// 워커를 시작하지 않으면 샤드 크기만큼만 들어감
+	q := NewQueue(1, 1, newRecordSink())
+	if err := q.Enqueue(context.Background(), metricsFor("node-1", 0)); err != nil {
+		t.Fatalf("첫 Enqueue: %v", err)
+	}
+	if err := q.Enqueue(context.Background(), metricsFor("node-1", 1)); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("가득 찬 큐 Enqueue = %v, ErrQueueFull 기대", err)
+	}
+	if q.Dropped() != 1 || q.Len() != 1 {
+		t.Errorf("Dropped = %d, Len = %d, 기대 1, 1", q.Dropped(), q.Len())
+	}
+}
+
+func TestQueueForward(t *testing.T) {
+	primary := newRecordSink()
+	tracker := newRecordSink()
+	tracker.name = "tracker"
+	tracker.block = make(chan struct{})
+
+	q := NewQueue(64, 1, primary)
+	next := NewQueue(4, 1, tracker)
+	q.Forward(next)
+	q.Start()
+
+	// 다음 단계가 막혀 있어도 앞 단계는 가득 차지 않고 모두 처리
+	const total = 50
+	for i := 0; i < total; i++ {
+		if err := q.Enqueue(context.Background(), metricsFor("node-1", i)); err != nil {
+			t.Fatalf("%d번째 Enqueue: %v", i, err)
+		}
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		primary.mu.Lock()
+		n := len(primary.nodes["node-1"])
+		primary.mu.Unlock()
+		if n == total {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("앞 단계가 %d/%d개만 처리함 (다음 단계에 막힘)", n, total)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if q.Dropped() != 0 {
+		t.Errorf("앞 단계에서 버린 메트릭스 %d개", q.Dropped())
+	}
+	// 버퍼 4개와 막힌 Write 하나를 넘는 메트릭스는 다음 단계에서만 버림
+	kept := total - int(next.Dropped())
+	if kept < 4 || kept > 5 {
+		t.Errorf("다음 단계가 받은 메트릭스 %d개, 4~5개 기대", kept)
+	}
+
+	close(tracker.block)
+	if err := q.Drain(context.Background()); err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+
+	// 다음 단계도 비우고 플러시하며, 넘겨받은 메트릭스는 노드 단위 순서 유지
+	tracker.mu.Lock()
+	seqs, events := tracker.nodes["node-1"], tracker.events
+	tracker.mu.Unlock()
+	if len(seqs) != kept || events[len(events)-1] != "flush" {
+		t.Fatalf("다음 단계 처리 %v, 이벤트 %v, %d개 처리 후 플러시 기대", seqs, events, kept)
+	}
+	for i := 1; i < len(seqs); i++ {
+		if seqs[i] <= seqs[i-1] {
+			t.Errorf("다음 단계 처리 순서 %v (노드 단위 순서가 깨짐)", seqs)
+			break
+		}
+	}
+
+	// 통계는 두 단계의 Sink를 모두 포함
+	stats := q.SinkStats()
+	if stats["record"].Writes != total || stats["tracker"].Writes != uint64(kept) {
+		t.Errorf("Sink 통계 %+v", stats)
+	}
+	if err := q.Enqueue(context.Background(), metricsFor("node-1", total)); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("Drain 후 Enqueue = %v, ErrQueueClosed 기대", err)
+	}
+}
+
+// slowSink는 ctx가 끝날 때까지 Write를 막고 ctx의 오류를 반환합니다
+type slowSink struct{}
+
+func (slowSink) Name() string { return "slow" }
+
+func (slowSink) Write(ctx context.Context, metrics *models.SystemMetrics) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestQueueSinkTimeout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	yaml := "influxdb: {token: test, org: test, bucket: test}\npostgres: {user: test, dbname: test}\ningest: {sink_timeout: 1}\n"
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatalf("설정 파일 쓰기 실패: %v", err)
+	}
+	if err := config.Load(path); err != nil {
+		t.Fatalf("설정 로드 실패: %v", err)
+	}
+
+	after := newRecordSink()
+	q := NewQueue(4, 1, slowSink{}, after)
+	q.Start()
+	if err := q.Enqueue(context.Background(), metricsFor("node-1", 0)); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := q.Drain(ctx); err != nil {
+		t.Fatalf("Drain: %v (제한 시간이 적용되지 않음)", err)
+	}
+
+	// 제한 시간을 넘긴 Sink는 실패로 기록하고 다음 Sink는 계속 처리
+	stats := q.SinkStats()
+	if stats["slow"].Errors != 1 || stats["record"].Writes != 1 || stats["record"].Errors != 0 {
+		t.Errorf("Sink 통계 %+v", stats)
+	}
+}
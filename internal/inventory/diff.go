@@ -0,0 +1,174 @@
+package inventory
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"system-collector/pkg/models"
+)
+
+// 인벤토리 구성 요소
+const (
+	ComponentOS     = "os"
+	ComponentCPU    = "cpu"
+	ComponentMemory = "memory"
+	ComponentDisk   = "disk"
+	ComponentNIC    = "nic"
+)
+
+// Diff는 이전 인벤토리와 현재 인벤토리를 비교하여 변경 이력을 만듭니다.
+// 디스크/NIC는 장치명으로 짝을 지어 필드별로 비교하고, 추가/제거는 항목당 하나의 이력으로 기록합니다.
+func Diff(old, cur *models.NodeInventory) []models.InventoryChange {
+	d := &differ{nodeID: cur.NodeID, at: cur.UpdatedAt}
+
+	d.fields(ComponentOS, "", osFields(old.OS), osFields(cur.OS))
+	d.fields(ComponentCPU, "", cpuFields(old.CPU), cpuFields(cur.CPU))
+	d.fields(ComponentMemory, "", memoryFields(old.Memory), memoryFields(cur.Memory))
+
+	oldDisks := make(map[string][]field, len(old.Disks))
+	for _, disk := range old.Disks {
+		oldDisks[disk.Device] = diskFields(disk)
+	}
+	curDisks := make(map[string][]field, len(cur.Disks))
+	for _, disk := range cur.Disks {
+		curDisks[disk.Device] = diskFields(disk)
+	}
+	d.items(ComponentDisk, oldDisks, curDisks)
+
+	oldNICs := make(map[string][]field, len(old.NICs))
+	for _, nic := range old.NICs {
+		oldNICs[nic.Interface] = nicFields(nic)
+	}
+	curNICs := make(map[string][]field, len(cur.NICs))
+	for _, nic := range cur.NICs {
+		curNICs[nic.Interface] = nicFields(nic)
+	}
+	d.items(ComponentNIC, oldNICs, curNICs)
+
+	return d.changes
+}
+
+// field는 비교할 인벤토리 필드 이름과 문자열로 변환한 값입니다
+type field struct {
+	name  string
+	value string
+}
+
+type differ struct {
+	nodeID  string
+	at      time.Time
+	changes []models.InventoryChange
+}
+
+func (d *differ) add(component, item, name, oldValue, newValue string) {
+	d.changes = append(d.changes, models.InventoryChange{
+		NodeID:    d.nodeID,
+		Component: component,
+		Item:      item,
+		Field:     name,
+		OldValue:  oldValue,
+		NewValue:  newValue,
+		ChangedAt: d.at,
+	})
+}
+
+// fields는 같은 순서로 나열된 두 필드 목록을 비교합니다
+func (d *differ) fields(component, item string, old, cur []field) {
+	for i := range cur {
+		if old[i].value != cur[i].value {
+			d.add(component, item, cur[i].name, old[i].value, cur[i].value)
+		}
+	}
+}
+
+// items는 이름으로 짝지은 항목 목록을 비교합니다
+func (d *differ) items(component string, old, cur map[string][]field) {
+	names := make([]string, 0, len(old)+len(cur))
+	for name := range old {
+		names = append(names, name)
+	}
+	for name := range cur {
+		if _, ok := old[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		oldFields, hadOld := old[name]
+		curFields, hasCur := cur[name]
+		switch {
+		case !hadOld:
+			d.add(component, name, "", "", summary(curFields))
+		case !hasCur:
+			d.add(component, name, "", summary(oldFields), "")
+		default:
+			d.fields(component, name, oldFields, curFields)
+		}
+	}
+}
+
+// summary는 추가/제거된 항목을 "name=value" 목록으로 요약합니다
+func summary(fields []field) string {
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		parts[i] = fmt.Sprintf("%s=%s", f.name, f.value)
+	}
+	return strings.Join(parts, " ")
+}
+
+func osFields(os models.InventoryOS) []field {
+	return []field{
+		{"hostname", os.Hostname},
+		{"name", os.Name},
+		{"platform", os.Platform},
+		{"platform_family", os.PlatformFamily},
+		{"version", os.Version},
+		{"architecture", os.Architecture},
+		{"kernel_version", os.KernelVersion},
+	}
+}
+
+func cpuFields(cpu models.InventoryCPU) []field {
+	return []field{
+		{"model", cpu.Model},
+		{"vendor", cpu.Vendor},
+		{"architecture", cpu.Architecture},
+		{"cores", strconv.Itoa(cpu.Cores)},
+		{"logical_cores", strconv.Itoa(cpu.LogicalCores)},
+		{"l3_cache_size", strconv.Itoa(cpu.L3CacheSize)},
+		{"max_clock_speed", strconv.FormatFloat(cpu.MaxClockSpeed, 'f', -1, 64)},
+	}
+}
+
+func memoryFields(mem models.InventoryMemory) []field {
+	return []field{
+		{"total", strconv.FormatInt(mem.Total, 10)},
+		{"swap_total", strconv.FormatInt(mem.SwapTotal, 10)},
+		{"data_rate", strconv.FormatUint(mem.DataRate, 10)},
+		{"using_slot_count", strconv.FormatUint(uint64(mem.UsingSlotCount), 10)},
+		{"total_slot_count", strconv.FormatUint(uint64(mem.TotalSlotCount), 10)},
+		{"form_factor", mem.FormFactor},
+	}
+}
+
+func diskFields(disk models.InventoryDisk) []field {
+	return []field{
+		{"mount_point", disk.MountPoint},
+		{"filesystem_type", disk.FilesystemType},
+		{"model_name", disk.ModelName},
+		{"type", disk.Type},
+		{"total", strconv.FormatInt(disk.Total, 10)},
+	}
+}
+
+func nicFields(nic models.InventoryNIC) []field {
+	return []field{
+		{"mac", nic.MAC},
+		{"mtu", strconv.Itoa(nic.MTU)},
+		{"speed", strconv.FormatUint(nic.Speed, 10)},
+	}
+}
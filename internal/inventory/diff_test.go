@@ -0,0 +1,162 @@
+package inventory
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"system-collector/pkg/models"
+)
+
+func baseInventory() *models.NodeInventory {
+	return &models.NodeInventory{
+		NodeID: "node-1",
+		OS:     models.InventoryOS{Hostname: "web-1", Name: "linux", KernelVersion: "6.1.0"},
+		CPU:    models.InventoryCPU{Model: "Xeon", Cores: 8, LogicalCores: 16, MaxClockSpeed: 3.5},
+		Memory: models.InventoryMemory{Total: 32 << 30, UsingSlotCount: 2, TotalSlotCount: 4},
+		Disks: []models.InventoryDisk{
+			{Device: "/dev/sda", MountPoint: "/", FilesystemType: "ext4", Total: 100},
+		},
+		NICs: []models.InventoryNIC{
+			{Interface: "eth0", MAC: "aa:bb:cc:dd:ee:ff", MTU: 1500, Speed: 1000},
+		},
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(inv *models.NodeInventory)
+		want   []models.InventoryChange
+	}{
+		{
+			name:   "변경 없음",
+			modify: func(inv *models.NodeInventory) {},
+		},
+		{
+			name:   "커널 업데이트",
+			modify: func(inv *models.NodeInventory) { inv.OS.KernelVersion = "6.1.1" },
+			want:   []models.InventoryChange{{Component: ComponentOS, Field: "kernel_version", OldValue: "6.1.0", NewValue: "6.1.1"}},
+		},
+		{
+			name: "CPU와 메모리 증설",
+			modify: func(inv *models.NodeInventory) {
+				inv.CPU.Cores = 16
+				inv.Memory.Total = 64 << 30
+				inv.Memory.UsingSlotCount = 4
+			},
+			want: []models.InventoryChange{
+				{Component: ComponentCPU, Field: "cores", OldValue: "8", NewValue: "16"},
+				{Component: ComponentMemory, Field: "total", OldValue: "34359738368", NewValue: "68719476736"},
+				{Component: ComponentMemory, Field: "using_slot_count", OldValue: "2", NewValue: "4"},
+			},
+		},
+		{
+			name:   "소수점 클럭",
+			modify: func(inv *models.NodeInventory) { inv.CPU.MaxClockSpeed = 3.75 },
+			want:   []models.InventoryChange{{Component: ComponentCPU, Field: "max_clock_speed", OldValue: "3.5", NewValue: "3.75"}},
+		},
+		{
+			name:   "디스크 용량 변경",
+			modify: func(inv *models.NodeInventory) { inv.Disks[0].Total = 200 },
+			want:   []models.InventoryChange{{Component: ComponentDisk, Item: "/dev/sda", Field: "total", OldValue: "100", NewValue: "200"}},
+		},
+		{
+			name: "디스크 추가",
+			modify: func(inv *models.NodeInventory) {
+				inv.Disks = append(inv.Disks, models.InventoryDisk{Device: "/dev/sdb", MountPoint: "/data", FilesystemType: "xfs", Type: "ssd", Total: 500})
+			},
+			want: []models.InventoryChange{{
+				Component: ComponentDisk, Item: "/dev/sdb",
+				NewValue: "mount_point=/data filesystem_type=xfs model_name= type=ssd total=500",
+			}},
+		},
+		{
+			name: "NIC 교체",
+			modify: func(inv *models.NodeInventory) {
+				inv.NICs = []models.InventoryNIC{{Interface: "ens3", MAC: "11:22:33:44:55:66", MTU: 9000, Speed: 10000}}
+			},
+			want: []models.InventoryChange{
+				{Component: ComponentNIC, Item: "ens3", NewValue: "mac=11:22:33:44:55:66 mtu=9000 speed=10000"},
+				{Component: ComponentNIC, Item: "eth0", OldValue: "mac=aa:bb:cc:dd:ee:ff mtu=1500 speed=1000"},
+			},
+		},
+		{
+			name: "디스크 순서만 바뀜",
+			modify: func(inv *models.NodeInventory) {
+				inv.Disks = append([]models.InventoryDisk{{Device: "/dev/nvme0n1", Total: 1}}, inv.Disks...)
+			},
+			want: []models.InventoryChange{{
+				Component: ComponentDisk, Item: "/dev/nvme0n1",
+				NewValue: "mount_point= filesystem_type= model_name= type= total=1",
+			}},
+		},
+	}
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := baseInventory()
+			cur := baseInventory()
+			tt.modify(cur)
+			cur.UpdatedAt = at
+
+			got := Diff(old, cur)
+			for i := range tt.want {
+				tt.want[i].NodeID = "node-1"
+				tt.want[i].ChangedAt = at
+			}
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff =\n%+v\n기대\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtract(t *testing.T) {
+	metrics := &models.SystemMetrics{
+		Key:    "node-1",
+		System: models.SystemInfo{Hostname: "web-1", OSKernelVersion: "6.1.0"},
+		CPU:    models.CPUMetrics{Model: "Xeon", TotalCores: 8},
+		Disk: []models.DiskMetrics{
+			{Device: "/dev/sdb", MountPoint: "/data"},
+			{Device: "/dev/sda", MountPoint: "/"},
+			{Device: "/dev/sda", MountPoint: "/boot"},
+			{Device: ""},
+		},
+		Network: []models.NetworkMetrics{{Interface: "eth1"}, {Interface: "eth0"}, {Interface: "eth0", MTU: 9000}},
+	}
+	inv := Extract(metrics)
+
+	if inv.NodeID != "node-1" || inv.OS.Hostname != "web-1" || inv.CPU.Cores != 8 {
+		t.Errorf("기본 항목 = %+v", inv)
+	}
+	var disks []string
+	for _, d := range inv.Disks {
+		disks = append(disks, d.Device+" "+d.MountPoint)
+	}
+	// 같은 장치는 첫 항목만, 장치명 순으로 정렬
+	if want := []string{"/dev/sda /", "/dev/sdb /data"}; !reflect.DeepEqual(disks, want) {
+		t.Errorf("디스크 = %v, 기대 %v", disks, want)
+	}
+	if len(inv.NICs) != 2 || inv.NICs[0].Interface != "eth0" || inv.NICs[0].MTU != 0 {
+		t.Errorf("NIC = %+v", inv.NICs)
+	}
+
+	tests := []struct {
+		name string
+		inv  *models.NodeInventory
+		want bool
+	}{
+		{name: "커널 버전", inv: &models.NodeInventory{OS: models.InventoryOS{KernelVersion: "6.1"}}, want: true},
+		{name: "CPU 모델", inv: &models.NodeInventory{CPU: models.InventoryCPU{Model: "Xeon"}}, want: true},
+		{name: "시스템 정보 없음", inv: &models.NodeInventory{OS: models.InventoryOS{Hostname: "web-1"}}, want: false},
+	}
+	for _, tt := range tests {
+		if got := hasInventory(tt.inv); got != tt.want {
+			t.Errorf("hasInventory(%s) = %v, 기대 %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package inventory
+
+import (
+	"sort"
+	"time"
+
+	"system-collector/pkg/models"
+)
+
+// Extract는 메트릭스에서 인벤토리 항목만 추려냅니다.
+// 디스크와 NIC는 장치명/인터페이스명 순으로 정렬하며, 같은 장치가 여러 번 나오면 첫 항목만 사용합니다.
+func Extract(metrics *models.SystemMetrics) *models.NodeInventory {
+	inv := &models.NodeInventory{
+		NodeID: metrics.Key,
+		OS: models.InventoryOS{
+			Hostname:       metrics.System.Hostname,
+			Name:           metrics.System.OSName,
+			Platform:       metrics.System.Platform,
+			PlatformFamily: metrics.System.PlatformFamily,
+			Version:        metrics.System.OSVersion,
+			Architecture:   metrics.System.OSArchitecture,
+			KernelVersion:  metrics.System.OSKernelVersion,
+		},
+		CPU: models.InventoryCPU{
+			Model:         metrics.CPU.Model,
+			Vendor:        metrics.CPU.Vendor,
+			Architecture:  metrics.CPU.Architecture,
+			Cores:         metrics.CPU.TotalCores,
+			LogicalCores:  metrics.CPU.TotalLogicalCores,
+			L3CacheSize:   metrics.CPU.L3CacheSize,
+			MaxClockSpeed: metrics.CPU.MaxClockSpeed,
+		},
+		Memory: models.InventoryMemory{
+			Total:          metrics.Memory.Total,
+			SwapTotal:      metrics.Memory.SwapTotal,
+			DataRate:       metrics.Memory.DataRate,
+			UsingSlotCount: metrics.Memory.UsingSlotCount,
+			TotalSlotCount: metrics.Memory.TotalSlotCount,
+			FormFactor:     metrics.Memory.FormFactor,
+		},
+		Disks:     []models.InventoryDisk{},
+		NICs:      []models.InventoryNIC{},
+		UpdatedAt: time.Now(),
+	}
+
+	seenDisks := make(map[string]bool)
+	for _, d := range metrics.Disk {
+		if d.Device == "" || seenDisks[d.Device] {
+			continue
+		}
+		seenDisks[d.Device] = true
+		inv.Disks = append(inv.Disks, models.InventoryDisk{
+			Device:         d.Device,
+			MountPoint:     d.MountPoint,
+			FilesystemType: d.FilesystemType,
+			ModelName:      d.ModelName,
+			Type:           d.Type,
+			Total:          d.Total,
+		})
+	}
+	sort.Slice(inv.Disks, func(i, j int) bool { return inv.Disks[i].Device < inv.Disks[j].Device })
+
+	seenNICs := make(map[string]bool)
+	for _, n := range metrics.Network {
+		if n.Interface == "" || seenNICs[n.Interface] {
+			continue
+		}
+		seenNICs[n.Interface] = true
+		inv.NICs = append(inv.NICs, models.InventoryNIC{
+			Interface: n.Interface,
+			MAC:       n.MAC,
+			MTU:       n.MTU,
+			Speed:     n.Speed,
+		})
+	}
+	sort.Slice(inv.NICs, func(i, j int) bool { return inv.NICs[i].Interface < inv.NICs[j].Interface })
+
+	return inv
+}
+
+// hasInventory는 메트릭스에 인벤토리로 쓸 만한 정보가 있는지 반환합니다.
+// 시스템 정보가 빠진 메트릭스로 모든 항목이 지워진 것처럼 기록되지 않도록 합니다.
+func hasInventory(inv *models.NodeInventory) bool {
+	return inv.OS.KernelVersion != "" || inv.CPU.Model != ""
+}
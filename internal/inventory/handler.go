@@ -0,0 +1,67 @@
+package inventory
+
+import (
+	"net/http"
+	"strconv"
+
+	"system-collector/internal/admin"
+	"system-collector/internal/httpapi"
+	"system-collector/internal/repository"
+)
+
+// 변경 이력 조회 시 limit 기본값과 최대값
+const (
+	defaultChangeLimit = 100
+	maxChangeLimit     = 1000
+)
+
+// Handler는 노드 인벤토리 조회 API를 제공합니다. 관리 API와 같은 인증을 사용합니다.
+type Handler struct {
+	repo *repository.InventoryRepository
+}
+
+// NewHandler는 인벤토리 API 핸들러를 생성합니다
+func NewHandler(repo *repository.InventoryRepository) *Handler {
+	return &Handler{repo: repo}
+}
+
+// RegisterRoutes는 핸들러를 mux에 등록합니다
+func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/nodes/{nodeID}/inventory", admin.RequireAdmin(h.handleInventory))
+	mux.HandleFunc("GET /api/nodes/{nodeID}/inventory/changes", admin.RequireAdmin(h.handleChanges))
+}
+
+// handleInventory는 노드의 현재 인벤토리를 반환합니다
+func (h *Handler) handleInventory(w http.ResponseWriter, r *http.Request) {
+	inv, err := h.repo.GetInventory(r.Context(), r.PathValue("nodeID"))
+	if err != nil {
+		httpapi.WriteError(w, http.StatusInternalServerError, "인벤토리 조회 실패")
+		return
+	}
+	if inv == nil {
+		httpapi.WriteError(w, http.StatusNotFound, "인벤토리가 없습니다")
+		return
+	}
+	httpapi.WriteJSON(w, http.StatusOK, inv)
+}
+
+// handleChanges는 노드의 인벤토리 변경 이력을 최신순으로 반환합니다.
+// component(os, cpu, memory, disk, nic)와 limit 쿼리 파라미터를 지원합니다.
+func (h *Handler) handleChanges(w http.ResponseWriter, r *http.Request) {
+	limit := defaultChangeLimit
+	if s := r.URL.Query().Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			httpapi.WriteError(w, http.StatusBadRequest, "limit는 양의 정수여야 합니다")
+			return
+		}
+		limit = min(n, maxChangeLimit)
+	}
+
+	changes, err := h.repo.GetChanges(r.Context(), r.PathValue("nodeID"), r.URL.Query().Get("component"), limit)
+	if err != nil {
+		httpapi.WriteError(w, http.StatusInternalServerError, "인벤토리 변경 이력 조회 실패")
+		return
+	}
+	httpapi.WriteJSON(w, http.StatusOK, changes)
+}
@@ -0,0 +1,107 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"system-collector/internal/repository"
+	"system-collector/pkg/logger"
+	"system-collector/pkg/models"
+)
+
+// Tracker는 수집 큐의 Sink로 동작하며 노드 인벤토리의 변경을 감지해 저장합니다.
+// 마지막으로 저장한 인벤토리를 메모리에 두고, 바뀐 경우에만 PostgreSQL에 씁니다.
+type Tracker struct {
+	repo *repository.InventoryRepository
+
+	mu   sync.Mutex
+	last map[string]*models.NodeInventory // nodeID -> 마지막으로 저장한 인벤토리
+}
+
+// NewTracker는 인벤토리 Tracker를 생성합니다
+func NewTracker(repo *repository.InventoryRepository) *Tracker {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("인벤토리 트래커 초기화 중")
+
+	return &Tracker{
+		repo: repo,
+		last: make(map[string]*models.NodeInventory),
+	}
+}
+
+// Name은 Sink 이름을 반환합니다
+func (t *Tracker) Name() string {
+	return "inventory"
+}
+
+// Write는 메트릭스의 인벤토리를 이전 값과 비교하고 변경이 있으면 이력과 함께 저장합니다.
+// 같은 노드의 메트릭스는 수집 큐의 같은 워커가 순서대로 처리합니다.
+func (t *Tracker) Write(ctx context.Context, metrics *models.SystemMetrics) error {
+	sugar := logger.FromContext(ctx)
+
+	cur := Extract(metrics)
+	if !hasInventory(cur) {
+		return nil
+	}
+
+	prev, err := t.previous(ctx, metrics.Key)
+	if err != nil {
+		return err
+	}
+
+	var changes []models.InventoryChange
+	if prev != nil {
+		changes = Diff(prev, cur)
+		if len(changes) == 0 {
+			return nil
+		}
+	}
+
+	if err := t.repo.SaveInventory(ctx, cur, changes); err != nil {
+		return fmt.Errorf("인벤토리 저장 실패: %v", err)
+	}
+	for _, c := range changes {
+		sugar.Infow("노드 인벤토리 변경 감지",
+			"nodeID", c.NodeID,
+			"component", c.Component,
+			"item", c.Item,
+			"field", c.Field,
+			"old", c.OldValue,
+			"new", c.NewValue)
+	}
+
+	t.mu.Lock()
+	t.last[metrics.Key] = cur
+	t.mu.Unlock()
+	return nil
+}
+
+// Forget은 노드의 캐시된 인벤토리를 버립니다. 노드가 다른 인스턴스로 옮겨 간 동안
+// 저장된 변경을 놓치지 않도록 다음 메트릭스에서 DB의 값을 다시 읽습니다.
+func (t *Tracker) Forget(nodeID string) {
+	t.mu.Lock()
+	delete(t.last, nodeID)
+	t.mu.Unlock()
+}
+
+// previous는 마지막으로 저장한 인벤토리를 반환합니다. 메모리에 없으면 DB에서 읽습니다.
+func (t *Tracker) previous(ctx context.Context, nodeID string) (*models.NodeInventory, error) {
+	t.mu.Lock()
+	prev, ok := t.last[nodeID]
+	t.mu.Unlock()
+	if ok {
+		return prev, nil
+	}
+
+	prev, err := t.repo.GetInventory(ctx, nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("인벤토리 조회 실패: %v", err)
+	}
+	if prev != nil {
+		t.mu.Lock()
+		t.last[nodeID] = prev
+		t.mu.Unlock()
+	}
+	return prev, nil
+}
@@ -0,0 +1,34 @@
+package iphistory
+
+import (
+	"net/http"
+
+	"system-collector/internal/admin"
+	"system-collector/internal/httpapi"
+	"system-collector/internal/repository"
+)
+
+// Handler는 노드 외부 IP 이력 조회 API를 제공합니다. 관리 API와 같은 인증을 사용합니다.
+type Handler struct {
+	repo *repository.IPHistoryRepository
+}
+
+// NewHandler는 외부 IP 이력 API 핸들러를 생성합니다
+func NewHandler(repo *repository.IPHistoryRepository) *Handler {
+	return &Handler{repo: repo}
+}
+
+// RegisterRoutes는 핸들러를 mux에 등록합니다
+func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/nodes/{nodeID}/ip-history", admin.RequireAdmin(h.handleHistory))
+}
+
+// handleHistory는 노드가 사용한 외부 IP 목록을 최근 사용 순으로 반환합니다
+func (h *Handler) handleHistory(w http.ResponseWriter, r *http.Request) {
+	history, err := h.repo.GetHistory(r.Context(), r.PathValue("nodeID"))
+	if err != nil {
+		httpapi.WriteError(w, http.StatusInternalServerError, "외부 IP 이력 조회 실패")
+		return
+	}
+	httpapi.WriteJSON(w, http.StatusOK, history)
+}
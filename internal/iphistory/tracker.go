@@ -0,0 +1,256 @@
+package iphistory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"system-collector/internal/events"
+	"system-collector/pkg/geoip"
+	"system-collector/pkg/logger"
+	"system-collector/pkg/models"
+)
+
+// flushInterval은 IP가 바뀌지 않았을 때 last_seen/sample_count를 DB에 반영하는 주기입니다
+const flushInterval = 5 * time.Minute
+
+// nodeState는 노드의 현재 외부 IP와 아직 DB에 반영하지 않은 사용 기록입니다
+type nodeState struct {
+	ip        string
+	info      geoip.Info
+	firstSeen time.Time
+	lastSeen  time.Time
+	pending   int64
+	flushedAt time.Time
+}
+
+// IPHistoryStore는 외부 IP 이력 저장소입니다 (repository.IPHistoryRepository)
+type IPHistoryStore interface {
+	RecordIP(ctx context.Context, entry *models.IPHistoryEntry) error
+}
+
+// NodeStore는 노드의 현재 외부 IP를 읽고 쓰는 저장소입니다 (repository.NodeRepository)
+type NodeStore interface {
+	GetNode(ctx context.Context, nodeID string) (*models.Node, error)
+	UpdateNodeExternalIP(ctx context.Context, nodeID, externalIP string) error
+}
+
+// Tracker는 수집 큐의 Sink로 동작하며 노드의 외부 IP 이력을 관리합니다.
+// 같은 노드의 메트릭스는 같은 워커가 순서대로 처리하므로 노드별 상태는 DB 반영이 성공한 뒤에만 바뀝니다.
+type Tracker struct {
+	repo     IPHistoryStore
+	nodeRepo NodeStore
+	resolver *geoip.Resolver
+	bus      *events.Bus
+
+	mu    sync.Mutex
+	nodes map[string]*nodeState
+}
+
+// NewTracker는 외부 IP 이력 Tracker를 생성합니다.
+// nodes의 external_ip를 각 노드의 현재 IP로 사용하며, resolver가 nil이면 위치 정보 없이 기록합니다.
+func NewTracker(repo IPHistoryStore, nodeRepo NodeStore, resolver *geoip.Resolver, bus *events.Bus, nodes []models.Node) *Tracker {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("외부 IP 이력 트래커 초기화 중", "geoip", resolver.Enabled())
+
+	t := &Tracker{
+		repo:     repo,
+		nodeRepo: nodeRepo,
+		resolver: resolver,
+		bus:      bus,
+		nodes:    make(map[string]*nodeState),
+	}
+	for _, node := range nodes {
+		if node.ExternalIP == "" {
+			continue
+		}
+		t.nodes[node.NodeID] = &nodeState{
+			ip:   node.ExternalIP,
+			info: resolver.Lookup(node.ExternalIP),
+		}
+		sugar.Infow("기존 노드 외부 IP 로드", "nodeID", node.NodeID, "externalIP", node.ExternalIP)
+	}
+	return t
+}
+
+// Name은 Sink 이름을 반환합니다
+func (t *Tracker) Name() string {
+	return "ip_history"
+}
+
+// Write는 메트릭스의 외부 IP를 기록합니다.
+// IP가 바뀌면 즉시 이력과 nodes.external_ip를 갱신하고 이벤트를 발행하며,
+// 같은 IP는 flushInterval마다 모아서 반영합니다.
+func (t *Tracker) Write(ctx context.Context, metrics *models.SystemMetrics) error {
+	sugar := logger.FromContext(ctx)
+
+	ip := metrics.ExternalIP
+	if ip == "" {
+		return nil
+	}
+	now := time.Now()
+
+	prev, err := t.current(ctx, metrics.Key)
+	if err != nil {
+		return err
+	}
+
+	if prev != nil && prev.ip == ip {
+		prev.lastSeen = now
+		prev.pending++
+		if now.Sub(prev.flushedAt) < flushInterval {
+			return nil
+		}
+		return t.flush(ctx, metrics.Key, prev, now)
+	}
+
+	// IP 변경: 이전 IP의 남은 기록을 먼저 반영
+	if prev != nil && prev.pending > 0 {
+		if err := t.flush(ctx, metrics.Key, prev, now); err != nil {
+			return err
+		}
+	}
+
+	cur := &nodeState{
+		ip:        ip,
+		info:      t.resolver.Lookup(ip),
+		firstSeen: now,
+		lastSeen:  now,
+		pending:   1,
+	}
+	if err := t.flush(ctx, metrics.Key, cur, now); err != nil {
+		return err
+	}
+	if err := t.nodeRepo.UpdateNodeExternalIP(ctx, metrics.Key, ip); err != nil {
+		// 상태를 바꾸지 않으므로 다음 메트릭스에서 다시 시도
+		return fmt.Errorf("노드 외부 IP 업데이트 실패: %v", err)
+	}
+
+	t.mu.Lock()
+	t.nodes[metrics.Key] = cur
+	t.mu.Unlock()
+
+	if prev == nil {
+		sugar.Infow("노드 외부 IP 등록", "nodeID", metrics.Key, "externalIP", ip)
+		return nil
+	}
+
+	sugar.Infow("노드 외부 IP 변경 감지",
+		"nodeID", metrics.Key,
+		"oldExternalIP", prev.ip,
+		"newExternalIP", ip)
+	t.publish(metrics.Key, prev, cur)
+	return nil
+}
+
+// current는 노드의 현재 외부 IP 상태를 반환합니다. 메모리에 없으면 nodes.external_ip를 읽습니다.
+func (t *Tracker) current(ctx context.Context, nodeID string) (*nodeState, error) {
+	t.mu.Lock()
+	st, ok := t.nodes[nodeID]
+	t.mu.Unlock()
+	if ok {
+		return st, nil
+	}
+
+	node, err := t.nodeRepo.GetNode(ctx, nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("노드 조회 실패: %v", err)
+	}
+	if node == nil || node.ExternalIP == "" {
+		return nil, nil
+	}
+	st = &nodeState{ip: node.ExternalIP, info: t.resolver.Lookup(node.ExternalIP)}
+	t.mu.Lock()
+	t.nodes[nodeID] = st
+	t.mu.Unlock()
+	return st, nil
+}
+
+// Forget은 노드의 외부 IP 상태를 버립니다. 노드가 다른 인스턴스로 옮겨 간 동안
+// 바뀐 IP를 놓치지 않도록 다음 메트릭스에서 DB의 값을 다시 읽습니다.
+func (t *Tracker) Forget(nodeID string) {
+	t.mu.Lock()
+	delete(t.nodes, nodeID)
+	t.mu.Unlock()
+}
+
+// flush는 노드 상태에 쌓인 사용 기록을 DB에 반영합니다
+func (t *Tracker) flush(ctx context.Context, nodeID string, st *nodeState, now time.Time) error {
+	firstSeen := st.firstSeen
+	if firstSeen.IsZero() {
+		firstSeen = now
+	}
+	lastSeen := st.lastSeen
+	if lastSeen.IsZero() {
+		lastSeen = now
+	}
+
+	entry := &models.IPHistoryEntry{
+		NodeID:      nodeID,
+		IP:          st.ip,
+		FirstSeen:   firstSeen,
+		LastSeen:    lastSeen,
+		SampleCount: st.pending,
+		CountryCode: st.info.CountryCode,
+		CountryName: st.info.CountryName,
+		ASN:         st.info.ASN,
+		ASOrg:       st.info.ASOrg,
+	}
+	if err := t.repo.RecordIP(ctx, entry); err != nil {
+		return fmt.Errorf("외부 IP 이력 저장 실패: %v", err)
+	}
+	st.pending = 0
+	st.flushedAt = now
+	return nil
+}
+
+// publish는 IP 변경 이벤트를 발행하고, 국가나 ASN이 바뀌었으면 재배치 이벤트를 추가로 발행합니다
+func (t *Tracker) publish(nodeID string, prev, cur *nodeState) {
+	if t.bus == nil {
+		return
+	}
+
+	data := map[string]interface{}{
+		"old_ip":      prev.ip,
+		"new_ip":      cur.ip,
+		"old_country": prev.info.CountryCode,
+		"new_country": cur.info.CountryCode,
+		"old_asn":     prev.info.ASN,
+		"new_asn":     cur.info.ASN,
+		"old_as_org":  prev.info.ASOrg,
+		"new_as_org":  cur.info.ASOrg,
+	}
+	t.bus.Publish(models.Event{
+		NodeID:   nodeID,
+		Type:     models.EventExternalIPChanged,
+		Severity: models.SeverityInfo,
+		Message:  fmt.Sprintf("외부 IP 변경: %s -> %s", prev.ip, cur.ip),
+		Data:     data,
+	})
+
+	countryChanged := prev.info.CountryCode != "" && cur.info.CountryCode != "" && prev.info.CountryCode != cur.info.CountryCode
+	asnChanged := prev.info.ASN != 0 && cur.info.ASN != 0 && prev.info.ASN != cur.info.ASN
+	if !countryChanged && !asnChanged {
+		return
+	}
+
+	var msg string
+	switch {
+	case countryChanged && asnChanged:
+		msg = fmt.Sprintf("외부 IP의 국가와 ASN 변경: %s/AS%d -> %s/AS%d",
+			prev.info.CountryCode, prev.info.ASN, cur.info.CountryCode, cur.info.ASN)
+	case countryChanged:
+		msg = fmt.Sprintf("외부 IP의 국가 변경: %s -> %s", prev.info.CountryCode, cur.info.CountryCode)
+	default:
+		msg = fmt.Sprintf("외부 IP의 ASN 변경: AS%d(%s) -> AS%d(%s)",
+			prev.info.ASN, prev.info.ASOrg, cur.info.ASN, cur.info.ASOrg)
+	}
+	t.bus.Publish(models.Event{
+		NodeID:   nodeID,
+		Type:     models.EventExternalIPRelocated,
+		Severity: models.SeverityWarning,
+		Message:  msg,
+		Data:     data,
+	})
+}
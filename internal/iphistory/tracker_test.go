@@ -0,0 +1,238 @@
+package iphistory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"system-collector/internal/events"
+	"system-collector/pkg/geoip"
+	"system-collector/pkg/models"
+)
+
+// fakeHistory는 RecordIP로 저장된 이력을 모으는 IPHistoryStore입니다
+type fakeHistory struct {
+	mu      sync.Mutex
+	entries []models.IPHistoryEntry
+}
+
+func (s *fakeHistory) RecordIP(_ context.Context, entry *models.IPHistoryEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, *entry)
+	return nil
+}
+
+// recorded는 저장된 이력을 "IP:샘플 수" 형식으로 반환하고 목록을 비웁니다
+func (s *fakeHistory) recorded() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []string
+	for _, e := range s.entries {
+		out = append(out, fmt.Sprintf("%s:%d", e.IP, e.SampleCount))
+	}
+	s.entries = nil
+	return out
+}
+
+// fakeNodes는 노드의 external_ip를 메모리에 두는 NodeStore입니다
+type fakeNodes struct {
+	mu        sync.Mutex
+	ips       map[string]string
+	updateErr error
+}
+
+func (s *fakeNodes) GetNode(_ context.Context, nodeID string) (*models.Node, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ip, ok := s.ips[nodeID]
+	if !ok {
+		return nil, nil
+	}
+	return &models.Node{NodeID: nodeID, ExternalIP: ip}, nil
+}
+
+func (s *fakeNodes) UpdateNodeExternalIP(_ context.Context, nodeID, externalIP string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.updateErr != nil {
+		return s.updateErr
+	}
+	if s.ips == nil {
+		s.ips = make(map[string]string)
+	}
+	s.ips[nodeID] = externalIP
+	return nil
+}
+
+// newTestTracker는 발행된 이벤트 유형을 모으는 버스와 함께 Tracker를 만듭니다.
+// 이벤트는 반환된 함수를 호출하면 버스를 닫은 뒤 돌려줍니다.
+func newTestTracker(t *testing.T, history IPHistoryStore, nodes NodeStore) (*Tracker, func() []models.Event) {
+	t.Helper()
+	bus := events.NewBus(nil, 100)
+	var mu sync.Mutex
+	var published []models.Event
+	bus.Subscribe(func(e models.Event) {
+		mu.Lock()
+		published = append(published, e)
+		mu.Unlock()
+	})
+	bus.Start()
+
+	tracker := NewTracker(history, nodes, nil, bus, nil)
+	return tracker, func() []models.Event {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := bus.Close(ctx); err != nil {
+			t.Fatalf("이벤트 버스 종료: %v", err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		return published
+	}
+}
+
+func write(t *testing.T, tracker *Tracker, ip string) {
+	t.Helper()
+	if err := tracker.Write(context.Background(), &models.SystemMetrics{Key: "node-1", ExternalIP: ip}); err != nil {
+		t.Fatalf("Write(%s): %v", ip, err)
+	}
+}
+
+func TestTrackerBatching(t *testing.T) {
+	history := &fakeHistory{}
+	nodes := &fakeNodes{ips: map[string]string{"node-1": "1.1.1.1"}}
+	tracker, published := newTestTracker(t, history, nodes)
+
+	// DB에서 읽은 상태는 반영한 적이 없으므로 첫 메트릭스는 바로 저장
+	write(t, tracker, "1.1.1.1")
+	if got, want := history.recorded(), []string{"1.1.1.1:1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("저장된 이력 %v, 기대 %v", got, want)
+	}
+
+	// 이후 같은 IP는 바로 저장하지 않고 모아 둠
+	for i := 0; i < 3; i++ {
+		write(t, tracker, "1.1.1.1")
+	}
+	if got := history.recorded(); got != nil {
+		t.Fatalf("flushInterval 전에 저장된 이력 %v", got)
+	}
+
+	// flushInterval이 지나면 모아 둔 샘플을 한 번에 저장
+	tracker.nodes["node-1"].flushedAt = time.Now().Add(-flushInterval)
+	write(t, tracker, "1.1.1.1")
+	if got, want := history.recorded(), []string{"1.1.1.1:4"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("저장된 이력 %v, 기대 %v", got, want)
+	}
+	write(t, tracker, "1.1.1.1")
+
+	// IP가 바뀌면 이전 IP의 남은 샘플을 먼저 저장하고 새 IP를 바로 저장
+	write(t, tracker, "2.2.2.2")
+	if got, want := history.recorded(), []string{"1.1.1.1:1", "2.2.2.2:1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("IP 변경 후 저장된 이력 %v, 기대 %v", got, want)
+	}
+	if nodes.ips["node-1"] != "2.2.2.2" {
+		t.Errorf("nodes.external_ip = %s, 기대 2.2.2.2", nodes.ips["node-1"])
+	}
+
+	// 빈 IP는 무시
+	write(t, tracker, "")
+	if got := history.recorded(); got != nil {
+		t.Errorf("빈 IP로 저장된 이력 %v", got)
+	}
+
+	events := published()
+	if len(events) != 1 || events[0].Type != models.EventExternalIPChanged ||
+		events[0].Data["old_ip"] != "1.1.1.1" || events[0].Data["new_ip"] != "2.2.2.2" {
+		t.Errorf("이벤트 = %+v, external_ip_changed 하나 기대", events)
+	}
+}
+
+func TestTrackerFirstIP(t *testing.T) {
+	history := &fakeHistory{}
+	nodes := &fakeNodes{}
+	tracker, published := newTestTracker(t, history, nodes)
+
+	// 처음 보는 노드는 등록만 하고 변경 이벤트를 발행하지 않음
+	write(t, tracker, "1.1.1.1")
+	if got, want := history.recorded(), []string{"1.1.1.1:1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("저장된 이력 %v, 기대 %v", got, want)
+	}
+	if nodes.ips["node-1"] != "1.1.1.1" {
+		t.Errorf("nodes.external_ip = %q, 기대 1.1.1.1", nodes.ips["node-1"])
+	}
+	if events := published(); len(events) != 0 {
+		t.Errorf("처음 등록에 이벤트 %+v 발행", events)
+	}
+}
+
+func TestTrackerUpdateFailure(t *testing.T) {
+	history := &fakeHistory{}
+	nodes := &fakeNodes{ips: map[string]string{"node-1": "1.1.1.1"}, updateErr: errors.New("connection refused")}
+	tracker, published := newTestTracker(t, history, nodes)
+	write(t, tracker, "1.1.1.1")
+
+	if err := tracker.Write(context.Background(), &models.SystemMetrics{Key: "node-1", ExternalIP: "2.2.2.2"}); err == nil {
+		t.Fatal("external_ip 업데이트 실패가 반환되지 않음")
+	}
+	// 메모리 상태는 바뀌지 않아 다음 메트릭스에서 다시 변경으로 처리
+	if ip := tracker.nodes["node-1"].ip; ip != "1.1.1.1" {
+		t.Fatalf("업데이트 실패 후 현재 IP %s, 기대 1.1.1.1", ip)
+	}
+	history.recorded()
+
+	nodes.updateErr = nil
+	write(t, tracker, "2.2.2.2")
+	if got, want := history.recorded(), []string{"2.2.2.2:1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("재시도 후 저장된 이력 %v, 기대 %v", got, want)
+	}
+	if ip := tracker.nodes["node-1"].ip; ip != "2.2.2.2" || nodes.ips["node-1"] != "2.2.2.2" {
+		t.Errorf("재시도 후 현재 IP %s, nodes.external_ip %s", ip, nodes.ips["node-1"])
+	}
+
+	// 변경 이벤트는 반영에 성공했을 때 한 번만 발행
+	events := published()
+	if len(events) != 1 || events[0].Type != models.EventExternalIPChanged {
+		t.Errorf("이벤트 = %+v, external_ip_changed 하나 기대", events)
+	}
+}
+
+func TestPublishRelocated(t *testing.T) {
+	seoul := geoip.Info{CountryCode: "KR", ASN: 4766, ASOrg: "Korea Telecom"}
+	tests := []struct {
+		name string
+		prev geoip.Info
+		cur  geoip.Info
+		want bool
+	}{
+		{name: "국가와 ASN 같음", prev: seoul, cur: seoul},
+		{name: "국가 변경", prev: seoul, cur: geoip.Info{CountryCode: "JP", ASN: 4766}, want: true},
+		{name: "ASN 변경", prev: seoul, cur: geoip.Info{CountryCode: "KR", ASN: 9318}, want: true},
+		{name: "이전 위치 정보 없음", prev: geoip.Info{}, cur: seoul},
+		{name: "새 위치 정보 없음", prev: seoul, cur: geoip.Info{}},
+		{name: "국가만 있고 ASN은 한쪽만", prev: geoip.Info{CountryCode: "KR"}, cur: geoip.Info{CountryCode: "KR", ASN: 9318}},
+		{name: "ASN만 있고 국가는 한쪽만", prev: geoip.Info{ASN: 4766}, cur: geoip.Info{CountryCode: "US", ASN: 4766}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker, published := newTestTracker(t, &fakeHistory{}, &fakeNodes{})
+			tracker.publish("node-1", &nodeState{ip: "1.1.1.1", info: tt.prev}, &nodeState{ip: "2.2.2.2", info: tt.cur})
+
+			var types []string
+			for _, e := range published() {
+				types = append(types, e.Type)
+			}
+			want := []string{models.EventExternalIPChanged}
+			if tt.want {
+				want = append(want, models.EventExternalIPRelocated)
+			}
+			if !reflect.DeepEqual(types, want) {
+				t.Errorf("이벤트 %v, 기대 %v", types, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,396 @@
+package liveness
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	config "system-collector/configs"
+	"system-collector/internal/events"
+	"system-collector/internal/repository"
+	"system-collector/internal/telemetry"
+	"system-collector/pkg/logger"
+	"system-collector/pkg/models"
+)
+
+// 설정이 없을 때의 기본값
+const (
+	defaultExpectedInterval   = 5 * time.Second
+	defaultStaleAfterMissed   = 3
+	defaultOfflineAfterMissed = 12
+	defaultCheckInterval      = 5 * time.Second
+)
+
+// intervalWeight는 노드별 전송 주기 지수이동평균에서 새 관측값의 가중치입니다
+const intervalWeight = 0.2
+
+// nodeState는 노드 하나의 수신 상태입니다
+type nodeState struct {
+	lastSeen time.Time
+	// interval은 관측한 메트릭스 전송 주기의 지수이동평균입니다 (아직 모르면 0)
+	interval time.Duration
+	status   int
+}
+
+// statusUpdate는 DB에 반영할 상태 전이입니다
+type statusUpdate struct {
+	nodeID   string
+	from     int
+	to       int
+	lastSeen time.Time
+	reason   string
+	// owned가 false이면 다른 인스턴스가 노드를 소유하므로 DB와 이벤트에 반영하지 않습니다
+	owned bool
+}
+
+// Ownership은 노드 상태를 이 인스턴스가 반영해야 하는지 판단합니다 (클러스터 모드)
+type Ownership interface {
+	Owns(nodeID string) bool
+}
+
+// Tracker는 노드별 마지막 메트릭스 수신 시간으로 온라인/지연/오프라인 상태를 판단합니다.
+// 소켓이 열려 있어도 메트릭스가 설정된 횟수만큼 오지 않으면 상태를 바꾸며,
+// 상태가 바뀔 때만 nodes.status를 갱신하고 이벤트를 발행합니다.
+type Tracker struct {
+	repo      *repository.NodeRepository
+	bus       *events.Bus
+	ownership Ownership
+
+	mu    sync.Mutex
+	nodes map[string]*nodeState
+
+	// pending은 아직 DB에 반영하지 않은 노드별 상태 전이입니다.
+	// 반영이 밀리면 같은 노드의 전이를 하나로 합치므로 노드 수 이상 늘어나지 않고 Seen을 막지 않습니다.
+	pendingMu sync.Mutex
+	pending   map[string]*statusUpdate
+	order     []string // pending에 처음 들어온 순서
+	closed    bool
+	// wake는 반영할 전이가 생겼음을 알립니다 (버퍼 1, 가득 차면 이미 알린 것)
+	wake chan struct{}
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewTracker는 노드 생존 상태 Tracker를 생성합니다. bus가 nil이면 이벤트를 발행하지 않고,
+// ownership이 nil이면 모든 노드의 상태를 반영합니다.
+func NewTracker(repo *repository.NodeRepository, bus *events.Bus, ownership Ownership) *Tracker {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("노드 생존 상태 트래커 초기화 중")
+
+	return &Tracker{
+		repo:      repo,
+		bus:       bus,
+		ownership: ownership,
+		nodes:     make(map[string]*nodeState),
+		pending:   make(map[string]*statusUpdate),
+		wake:      make(chan struct{}, 1),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// Reconcile은 이전 실행에서 정리되지 않은 온라인/지연 상태를 오프라인으로 되돌립니다.
+// 연결되어 있는 노드는 다음 메트릭스를 받을 때 다시 온라인이 됩니다.
+func (t *Tracker) Reconcile() error {
+	sugar := logger.GetCustomLogger()
+
+	nodeIDs, err := t.repo.ResetNodeStatuses(context.Background())
+	if err != nil {
+		return fmt.Errorf("노드 상태 초기화 실패: %v", err)
+	}
+	if len(nodeIDs) > 0 {
+		sugar.Infow("남아 있던 노드 온라인 상태를 오프라인으로 정리", "count", len(nodeIDs), "nodes", nodeIDs)
+	}
+	return nil
+}
+
+// Start는 상태 반영 고루틴과 주기적인 상태 점검을 시작합니다
+func (t *Tracker) Start() {
+	go t.persist()
+	go func() {
+		ticker := time.NewTicker(checkInterval())
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-t.stop:
+				return
+			case <-ticker.C:
+				t.check(time.Now())
+			}
+		}
+	}()
+}
+
+// Stop은 상태 점검을 멈추고 남은 상태 전이를 모두 반영할 때까지 기다립니다
+func (t *Tracker) Stop() {
+	close(t.stop)
+
+	t.pendingMu.Lock()
+	t.closed = true
+	t.pendingMu.Unlock()
+	t.signal()
+
+	<-t.done
+}
+
+// Seen은 노드에서 메트릭스를 받았음을 기록합니다
+func (t *Tracker) Seen(nodeID string) {
+	now := time.Now()
+
+	t.mu.Lock()
+	st, ok := t.nodes[nodeID]
+	if !ok {
+		st = &nodeState{status: models.NodeStatusOffline}
+		t.nodes[nodeID] = st
+	}
+	if st.status == models.NodeStatusOnline && !st.lastSeen.IsZero() {
+		gap := now.Sub(st.lastSeen)
+		if st.interval == 0 {
+			st.interval = gap
+		} else {
+			st.interval = time.Duration(intervalWeight*float64(gap) + (1-intervalWeight)*float64(st.interval))
+		}
+	}
+	st.lastSeen = now
+	from := st.status
+	st.status = models.NodeStatusOnline
+	t.mu.Unlock()
+
+	if from != models.NodeStatusOnline {
+		t.enqueue(statusUpdate{nodeID: nodeID, from: from, to: models.NodeStatusOnline, lastSeen: now, reason: "metrics_received"})
+	}
+}
+
+// Disconnected는 노드의 연결이 끊겼을 때 즉시 오프라인으로 표시합니다
+func (t *Tracker) Disconnected(nodeID string) {
+	t.mu.Lock()
+	st, ok := t.nodes[nodeID]
+	if !ok || st.status == models.NodeStatusOffline {
+		t.mu.Unlock()
+		return
+	}
+	from := st.status
+	st.status = models.NodeStatusOffline
+	st.interval = 0
+	lastSeen := st.lastSeen
+	t.mu.Unlock()
+
+	t.enqueue(statusUpdate{nodeID: nodeID, from: from, to: models.NodeStatusOffline, lastSeen: lastSeen, reason: "disconnected"})
+}
+
+// LastSeen은 노드별 마지막 메트릭스 수신 시간을 반환합니다
+func (t *Tracker) LastSeen() map[string]time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result := make(map[string]time.Time, len(t.nodes))
+	for nodeID, st := range t.nodes {
+		result[nodeID] = st.lastSeen
+	}
+	return result
+}
+
+// Status는 노드의 현재 상태를 반환합니다. 모르는 노드는 오프라인입니다.
+func (t *Tracker) Status(nodeID string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if st, ok := t.nodes[nodeID]; ok {
+		return st.status
+	}
+	return models.NodeStatusOffline
+}
+
+// check는 각 노드가 놓친 전송 횟수를 계산해 지연/오프라인으로 전환합니다
+func (t *Tracker) check(now time.Time) {
+	expected, staleAfter, offlineAfter := thresholds()
+
+	var transitions []statusUpdate
+	t.mu.Lock()
+	for nodeID, st := range t.nodes {
+		if st.status == models.NodeStatusOffline {
+			continue
+		}
+
+		// 노드가 기본 주기보다 느리게 보내면 관측한 주기를 기준으로 판단
+		interval := expected
+		if st.interval > interval {
+			interval = st.interval
+		}
+		missed := int(now.Sub(st.lastSeen) / interval)
+
+		to := st.status
+		switch {
+		case missed >= offlineAfter:
+			to = models.NodeStatusOffline
+		case missed >= staleAfter:
+			to = models.NodeStatusStale
+		}
+		if to == st.status {
+			continue
+		}
+
+		transitions = append(transitions, statusUpdate{
+			nodeID:   nodeID,
+			from:     st.status,
+			to:       to,
+			lastSeen: st.lastSeen,
+			reason:   fmt.Sprintf("missed_%d_intervals", missed),
+		})
+		st.status = to
+		if to == models.NodeStatusOffline {
+			st.interval = 0
+		}
+	}
+	t.mu.Unlock()
+
+	for _, u := range transitions {
+		t.enqueue(u)
+	}
+}
+
+// enqueue는 상태 전이를 반영 대기열에 넣습니다. 호출자를 막지 않으며,
+// 같은 노드의 전이가 아직 반영되지 않았으면 처음 상태(from)는 두고 나머지를 새 전이로 바꿉니다.
+func (t *Tracker) enqueue(u statusUpdate) {
+	// 소유권은 전이가 발생한 시점에 판단 (반영 전에 다른 인스턴스로 넘어갈 수 있음)
+	u.owned = t.ownership == nil || t.ownership.Owns(u.nodeID)
+
+	t.pendingMu.Lock()
+	if t.closed {
+		t.pendingMu.Unlock()
+		// 종료 후에는 바로 반영
+		t.apply(u)
+		return
+	}
+	if p, ok := t.pending[u.nodeID]; ok {
+		p.to, p.lastSeen, p.reason, p.owned = u.to, u.lastSeen, u.reason, u.owned
+		t.pendingMu.Unlock()
+		telemetry.StatusUpdatesCoalesced.Inc()
+		return
+	}
+	t.pending[u.nodeID] = &u
+	t.order = append(t.order, u.nodeID)
+	t.pendingMu.Unlock()
+	t.signal()
+}
+
+func (t *Tracker) signal() {
+	select {
+	case t.wake <- struct{}{}:
+	default:
+	}
+}
+
+// take는 대기 중인 전이를 들어온 순서대로 꺼내고, 종료 중인지 함께 반환합니다
+func (t *Tracker) take() ([]statusUpdate, bool) {
+	t.pendingMu.Lock()
+	defer t.pendingMu.Unlock()
+
+	batch := make([]statusUpdate, 0, len(t.order))
+	for _, nodeID := range t.order {
+		batch = append(batch, *t.pending[nodeID])
+	}
+	clear(t.pending)
+	t.order = t.order[:0]
+	return batch, t.closed
+}
+
+// persist는 상태 전이를 순서대로 DB에 반영합니다
+func (t *Tracker) persist() {
+	defer close(t.done)
+	for range t.wake {
+		batch, closed := t.take()
+		for _, u := range batch {
+			// 합쳐진 결과 처음 상태로 돌아왔으면 반영할 변화가 없음
+			if u.from == u.to {
+				continue
+			}
+			t.apply(u)
+		}
+		if closed {
+			return
+		}
+	}
+}
+
+func (t *Tracker) apply(u statusUpdate) {
+	ctx := logger.WithContext(context.Background(), "nodeID", u.nodeID)
+	sugar := logger.FromContext(ctx)
+
+	if !u.owned {
+		sugar.Debugw("다른 인스턴스가 소유한 노드의 상태 변경은 반영하지 않음",
+			"nodeID", u.nodeID, "to", models.NodeStatusName(u.to), "reason", u.reason)
+		return
+	}
+
+	if err := t.repo.UpdateNodeStatus(ctx, u.nodeID, u.to); err != nil {
+		sugar.Errorw("노드 상태 업데이트 실패", "nodeID", u.nodeID, "status", u.to, "error", err)
+	}
+	sugar.Infow("노드 상태 변경",
+		"nodeID", u.nodeID,
+		"from", models.NodeStatusName(u.from),
+		"to", models.NodeStatusName(u.to),
+		"reason", u.reason)
+
+	if t.bus == nil {
+		return
+	}
+
+	event := models.Event{
+		NodeID: u.nodeID,
+		Data: map[string]interface{}{
+			"from":      models.NodeStatusName(u.from),
+			"to":        models.NodeStatusName(u.to),
+			"reason":    u.reason,
+			"last_seen": u.lastSeen,
+		},
+	}
+	switch u.to {
+	case models.NodeStatusOnline:
+		event.Type = models.EventNodeOnline
+		event.Severity = models.SeverityInfo
+		event.Message = "노드 온라인"
+	case models.NodeStatusStale:
+		event.Type = models.EventNodeStale
+		event.Severity = models.SeverityWarning
+		event.Message = fmt.Sprintf("노드 메트릭스 수신 지연 (마지막 수신: %s)", u.lastSeen.Format(time.RFC3339))
+	default:
+		event.Type = models.EventNodeOffline
+		event.Severity = models.SeverityCritical
+		if u.reason == "disconnected" {
+			event.Severity = models.SeverityWarning
+		}
+		event.Message = fmt.Sprintf("노드 오프라인 (%s)", u.reason)
+	}
+	t.bus.Publish(event)
+}
+
+// thresholds는 설정에서 기본 전송 주기와 지연/오프라인 판단 기준 횟수를 읽습니다
+func thresholds() (time.Duration, int, int) {
+	cfg := config.Get().Liveness
+
+	expected := time.Duration(cfg.ExpectedInterval) * time.Second
+	if expected <= 0 {
+		expected = defaultExpectedInterval
+	}
+	staleAfter := cfg.StaleAfterMissed
+	if staleAfter <= 0 {
+		staleAfter = defaultStaleAfterMissed
+	}
+	offlineAfter := cfg.OfflineAfterMissed
+	if offlineAfter <= staleAfter {
+		offlineAfter = max(defaultOfflineAfterMissed, staleAfter+1)
+	}
+	return expected, staleAfter, offlineAfter
+}
+
+func checkInterval() time.Duration {
+	interval := time.Duration(config.Get().Liveness.CheckInterval) * time.Second
+	if interval <= 0 {
+		return defaultCheckInterval
+	}
+	return interval
+}
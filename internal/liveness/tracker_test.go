@@ -0,0 +1,132 @@
+package liveness
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	config "system-collector/configs"
+	"system-collector/pkg/models"
+)
+
+func loadTestConfig(t *testing.T) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	yaml := "influxdb: {token: test, org: test, bucket: test}\npostgres: {user: test, dbname: test}\n"
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatalf("설정 파일 쓰기 실패: %v", err)
+	}
+	if err := config.Load(path); err != nil {
+		t.Fatalf("설정 로드 실패: %v", err)
+	}
+}
+
+func TestSeenDoesNotBlock(t *testing.T) {
+	// 반영 고루틴이 멈춰 있어도 (DB 지연) Seen과 Disconnected는 막히지 않음
+	tr := NewTracker(nil, nil, nil)
+
+	const nodes = 5000
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for round := 0; round < 3; round++ {
+			for i := 0; i < nodes; i++ {
+				nodeID := fmt.Sprintf("node-%d", i)
+				tr.Seen(nodeID)
+				tr.Disconnected(nodeID)
+			}
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("상태 전이 반영이 밀려 Seen이 막힘")
+	}
+
+	batch, closed := tr.take()
+	if closed {
+		t.Error("종료하지 않았는데 closed = true")
+	}
+	if len(batch) != nodes {
+		t.Fatalf("대기 중인 전이 %d개, 노드당 하나씩 %d개 기대", len(batch), nodes)
+	}
+	for i, u := range batch {
+		if want := fmt.Sprintf("node-%d", i); u.nodeID != want {
+			t.Fatalf("%d번째 전이 노드 = %s, 기대 %s (들어온 순서 유지)", i, u.nodeID, want)
+		}
+	}
+}
+
+func TestEnqueueCoalesce(t *testing.T) {
+	seen := time.Unix(100, 0)
+	tests := []struct {
+		name    string
+		updates []statusUpdate
+		want    statusUpdate
+	}{
+		{
+			name:    "전이 하나",
+			updates: []statusUpdate{{from: models.NodeStatusOffline, to: models.NodeStatusOnline, reason: "metrics_received"}},
+			want:    statusUpdate{from: models.NodeStatusOffline, to: models.NodeStatusOnline, reason: "metrics_received"},
+		},
+		{
+			name: "지연 후 오프라인",
+			updates: []statusUpdate{
+				{from: models.NodeStatusOnline, to: models.NodeStatusStale, reason: "missed_3_intervals"},
+				{from: models.NodeStatusStale, to: models.NodeStatusOffline, reason: "missed_12_intervals", lastSeen: seen},
+			},
+			want: statusUpdate{from: models.NodeStatusOnline, to: models.NodeStatusOffline, reason: "missed_12_intervals", lastSeen: seen},
+		},
+		{
+			name: "처음 상태로 돌아옴",
+			updates: []statusUpdate{
+				{from: models.NodeStatusOnline, to: models.NodeStatusOffline, reason: "disconnected"},
+				{from: models.NodeStatusOffline, to: models.NodeStatusOnline, reason: "metrics_received"},
+			},
+			want: statusUpdate{from: models.NodeStatusOnline, to: models.NodeStatusOnline, reason: "metrics_received"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := NewTracker(nil, nil, nil)
+			for _, u := range tt.updates {
+				u.nodeID = "node-1"
+				tr.enqueue(u)
+			}
+			batch, _ := tr.take()
+			if len(batch) != 1 {
+				t.Fatalf("전이 %d개, 1개 기대", len(batch))
+			}
+			got := batch[0]
+			if got.from != tt.want.from || got.to != tt.want.to || got.reason != tt.want.reason || !got.lastSeen.Equal(tt.want.lastSeen) || !got.owned {
+				t.Errorf("합친 전이 = %+v, 기대 %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStopSkipsNoopTransitions(t *testing.T) {
+	loadTestConfig(t)
+	tr := NewTracker(nil, nil, nil)
+
+	// 반영 전에 온라인 -> 오프라인 -> 온라인이 합쳐지면 DB에 쓸 변화가 없음 (repo가 nil이라 쓰면 패닉)
+	tr.enqueue(statusUpdate{nodeID: "node-1", from: models.NodeStatusOnline, to: models.NodeStatusOffline})
+	tr.enqueue(statusUpdate{nodeID: "node-1", from: models.NodeStatusOffline, to: models.NodeStatusOnline})
+	tr.Start()
+
+	stopped := make(chan struct{})
+	go func() {
+		tr.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop이 끝나지 않음")
+	}
+	if batch, closed := tr.take(); len(batch) != 0 || !closed {
+		t.Errorf("Stop 후 남은 전이 %d개, closed = %v", len(batch), closed)
+	}
+}
@@ -0,0 +1,349 @@
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"system-collector/pkg/logger"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// lockKey는 여러 인스턴스가 동시에 마이그레이션하지 않도록 잡는 PostgreSQL advisory lock 키입니다
+const lockKey = 0x5343_4d49 // "SCMI"
+
+// Migration은 버전 하나의 스키마 변경입니다. 파일 이름은 <버전>_<이름>.up.sql / .down.sql 형식입니다.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+	// Checksum은 Up SQL의 SHA-256입니다. 적용한 뒤 파일이 바뀌었는지 확인하는 데 사용합니다.
+	Checksum string
+}
+
+// Status는 마이그레이션 하나의 적용 상태입니다
+type Status struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+	// Modified는 적용한 뒤 SQL 파일이 바뀌었는지 나타냅니다
+	Modified bool `json:"modified"`
+	// Unknown은 DB에는 적용되어 있지만 이 수집기에 없는 버전인지 나타냅니다 (더 새로운 수집기가 적용)
+	Unknown bool `json:"unknown"`
+}
+
+// applied는 schema_migrations의 한 행입니다
+type applied struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// Migrator는 수집기에 포함된 SQL 마이그레이션을 적용하고 되돌립니다
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// NewMigrator는 포함된 마이그레이션 파일을 읽어 Migrator를 생성합니다
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	migrations, err := load(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// load는 dir의 마이그레이션 파일을 버전 순서로 읽습니다
+func load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("마이그레이션 목록 읽기 실패: %v", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		file := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(file, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(file, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+		base := strings.TrimSuffix(file, "."+direction+".sql")
+		prefix, name, ok := strings.Cut(base, "_")
+		version, err := strconv.Atoi(prefix)
+		if !ok || err != nil || version <= 0 {
+			return nil, fmt.Errorf("마이그레이션 파일 이름이 올바르지 않습니다: %s", file)
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, file))
+		if err != nil {
+			return nil, fmt.Errorf("마이그레이션 파일 읽기 실패 (%s): %v", file, err)
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("버전 %d의 마이그레이션 이름이 다릅니다: %s, %s", version, m.Name, name)
+		}
+		if direction == "up" {
+			m.Up = string(content)
+			sum := sha256.Sum256(content)
+			m.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("버전 %d의 up 마이그레이션이 없습니다", m.Version)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Latest는 수집기에 포함된 가장 높은 마이그레이션 버전을 반환합니다
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up은 적용하지 않은 마이그레이션을 버전 순서로 최대 steps개 적용합니다 (steps가 0 이하면 모두).
+// 이미 적용한 마이그레이션의 파일이 바뀌었으면 아무것도 적용하지 않고 오류를 반환합니다.
+func (m *Migrator) Up(ctx context.Context, steps int) ([]int, error) {
+	sugar := logger.FromContext(ctx)
+
+	var done []int
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		state, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(state); err != nil {
+			return err
+		}
+		for version := range state {
+			if m.find(version) == nil {
+				sugar.Warnw("이 수집기에 없는 마이그레이션이 DB에 적용되어 있음", "version", version, "name", state[version].name)
+			}
+		}
+
+		for _, mig := range m.migrations {
+			if steps > 0 && len(done) >= steps {
+				break
+			}
+			if _, ok := state[mig.Version]; ok {
+				continue
+			}
+			sugar.Infow("마이그레이션 적용 중", "version", mig.Version, "name", mig.Name)
+			err := m.run(ctx, conn, mig.Up,
+				`INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+				mig.Version, mig.Name, mig.Checksum)
+			if err != nil {
+				return fmt.Errorf("마이그레이션 %d_%s 적용 실패: %v", mig.Version, mig.Name, err)
+			}
+			done = append(done, mig.Version)
+		}
+		return nil
+	})
+	if err != nil {
+		return done, err
+	}
+	sugar.Infow("마이그레이션 완료", "applied", done, "latest", m.Latest())
+	return done, nil
+}
+
+// Down은 적용한 마이그레이션을 최신 버전부터 steps개 되돌립니다 (steps가 0 이하면 1개)
+func (m *Migrator) Down(ctx context.Context, steps int) ([]int, error) {
+	sugar := logger.FromContext(ctx)
+	if steps <= 0 {
+		steps = 1
+	}
+
+	var done []int
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		state, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		versions := make([]int, 0, len(state))
+		for version := range state {
+			versions = append(versions, version)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+
+		for _, version := range versions {
+			if len(done) >= steps {
+				break
+			}
+			mig := m.find(version)
+			if mig == nil {
+				return fmt.Errorf("버전 %d(%s)는 이 수집기에 없어 되돌릴 수 없습니다", version, state[version].name)
+			}
+			if mig.Down == "" {
+				return fmt.Errorf("버전 %d_%s에는 down 마이그레이션이 없습니다", mig.Version, mig.Name)
+			}
+			sugar.Infow("마이그레이션 되돌리는 중", "version", mig.Version, "name", mig.Name)
+			err := m.run(ctx, conn, mig.Down, `DELETE FROM schema_migrations WHERE version = $1`, mig.Version)
+			if err != nil {
+				return fmt.Errorf("마이그레이션 %d_%s 되돌리기 실패: %v", mig.Version, mig.Name, err)
+			}
+			done = append(done, mig.Version)
+		}
+		return nil
+	})
+	if err != nil {
+		return done, err
+	}
+	sugar.Infow("마이그레이션 되돌리기 완료", "reverted", done)
+	return done, nil
+}
+
+// Status는 모든 마이그레이션의 적용 상태를 버전 순서로 반환합니다
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var result []Status
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		state, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			st := Status{Version: mig.Version, Name: mig.Name}
+			if a, ok := state[mig.Version]; ok {
+				appliedAt := a.appliedAt
+				st.Applied = true
+				st.AppliedAt = &appliedAt
+				st.Modified = a.checksum != mig.Checksum
+			}
+			result = append(result, st)
+		}
+		for version, a := range state {
+			if m.find(version) == nil {
+				appliedAt := a.appliedAt
+				result = append(result, Status{Version: version, Name: a.name, Applied: true, AppliedAt: &appliedAt, Unknown: true})
+			}
+		}
+		return nil
+	})
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, err
+}
+
+// withLock은 전용 연결에서 advisory lock을 잡고 fn을 실행합니다.
+// 다른 인스턴스가 마이그레이션 중이면 끝날 때까지 기다립니다.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	sugar := logger.FromContext(ctx)
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("마이그레이션 연결 실패: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockKey); err != nil {
+		return fmt.Errorf("마이그레이션 잠금 실패: %v", err)
+	}
+	defer func() {
+		// ctx가 취소되었어도 잠금은 풀어야 함 (연결이 닫히면 어차피 풀림)
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, lockKey); err != nil {
+			sugar.Errorw("마이그레이션 잠금 해제 실패", "error", err)
+		}
+	}()
+
+	_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version    INTEGER PRIMARY KEY,
+	name       TEXT NOT NULL,
+	checksum   TEXT NOT NULL,
+	applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+)`)
+	if err != nil {
+		return fmt.Errorf("schema_migrations 테이블 생성 실패: %v", err)
+	}
+	return fn(conn)
+}
+
+// applied는 DB에 적용된 마이그레이션을 버전별로 반환합니다
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int]applied, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("적용된 마이그레이션 조회 실패: %v", err)
+	}
+	defer rows.Close()
+
+	state := make(map[int]applied)
+	for rows.Next() {
+		var version int
+		var a applied
+		if err := rows.Scan(&version, &a.name, &a.checksum, &a.appliedAt); err != nil {
+			return nil, fmt.Errorf("적용된 마이그레이션 스캔 실패: %v", err)
+		}
+		state[version] = a
+	}
+	return state, rows.Err()
+}
+
+// verify는 적용한 마이그레이션의 파일이 그 뒤로 바뀌지 않았는지 확인합니다
+func (m *Migrator) verify(state map[int]applied) error {
+	var modified []string
+	for _, mig := range m.migrations {
+		if a, ok := state[mig.Version]; ok && a.checksum != mig.Checksum {
+			modified = append(modified, fmt.Sprintf("%d_%s", mig.Version, mig.Name))
+		}
+	}
+	if len(modified) > 0 {
+		return fmt.Errorf("적용한 뒤 내용이 바뀐 마이그레이션이 있습니다 (새 버전으로 추가해야 합니다): %s", strings.Join(modified, ", "))
+	}
+	return nil
+}
+
+// run은 하나의 트랜잭션에서 마이그레이션 SQL과 schema_migrations 기록을 실행합니다
+func (m *Migrator) run(ctx context.Context, conn *sql.Conn, script, record string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// 인자 없이 실행하면 여러 문장을 한 번에 실행할 수 있음
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (m *Migrator) find(version int) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
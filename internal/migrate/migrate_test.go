@@ -0,0 +1,214 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    fstest.MapFS
+		versions []int
+		wantErr  string
+	}{
+		{
+			name: "버전 순서로 정렬",
+			files: fstest.MapFS{
+				"m/0002_second.up.sql":   {Data: []byte("CREATE TABLE b (id INT);")},
+				"m/0002_second.down.sql": {Data: []byte("DROP TABLE b;")},
+				"m/0001_first.up.sql":    {Data: []byte("CREATE TABLE a (id INT);")},
+				"m/README.md":            {Data: []byte("무시")},
+			},
+			versions: []int{1, 2},
+		},
+		{
+			name:    "up 없음",
+			files:   fstest.MapFS{"m/0001_first.down.sql": {Data: []byte("DROP TABLE a;")}},
+			wantErr: "up 마이그레이션이 없습니다",
+		},
+		{
+			name:    "잘못된 파일 이름",
+			files:   fstest.MapFS{"m/first.up.sql": {Data: []byte("SELECT 1;")}},
+			wantErr: "파일 이름이 올바르지 않습니다",
+		},
+		{
+			name: "같은 버전 다른 이름",
+			files: fstest.MapFS{
+				"m/0001_first.up.sql":   {Data: []byte("SELECT 1;")},
+				"m/0001_other.down.sql": {Data: []byte("SELECT 1;")},
+			},
+			wantErr: "이름이 다릅니다",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := load(tt.files, "m")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("오류 = %v, %q 포함 기대", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			var versions []int
+			for _, m := range migrations {
+				versions = append(versions, m.Version)
+				if m.Checksum == "" {
+					t.Errorf("버전 %d의 체크섬이 비어 있음", m.Version)
+				}
+			}
+			if fmt.Sprint(versions) != fmt.Sprint(tt.versions) {
+				t.Errorf("버전 = %v, 기대 %v", versions, tt.versions)
+			}
+		})
+	}
+}
+
+// TestEmbeddedMigrations는 포함된 마이그레이션 파일이 모두 읽히고 되돌릴 수 있는지 확인합니다
+func TestEmbeddedMigrations(t *testing.T) {
+	migrations, err := load(migrationFiles, "migrations")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Errorf("버전 %d가 연속되지 않음 (기대 %d)", m.Version, i+1)
+		}
+		if m.Down == "" {
+			t.Errorf("버전 %d_%s에 down 마이그레이션이 없음", m.Version, m.Name)
+		}
+	}
+}
+
+// testDB는 TEST_POSTGRES_DSN(postgres:// URL)의 서버에 임시 데이터베이스를 만들어 반환하고, 테스트가 끝나면 삭제합니다.
+// 환경 변수가 없으면 테스트를 건너뜁니다.
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN이 없어 PostgreSQL 통합 테스트를 건너뜀")
+	}
+	u, err := url.Parse(dsn)
+	if err != nil || u.Scheme == "" {
+		t.Fatalf("TEST_POSTGRES_DSN은 postgres:// URL이어야 합니다: %v", err)
+	}
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("PostgreSQL 연결 실패: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	name := fmt.Sprintf("collector_migrate_test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec("CREATE DATABASE " + name); err != nil {
+		t.Fatalf("임시 데이터베이스 생성 실패: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec("DROP DATABASE IF EXISTS " + name + " WITH (FORCE)"); err != nil {
+			t.Errorf("임시 데이터베이스 삭제 실패: %v", err)
+		}
+	})
+
+	u.Path = "/" + name
+	db, err := sql.Open("postgres", u.String())
+	if err != nil {
+		t.Fatalf("임시 데이터베이스 연결 실패: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// tables는 public 스키마의 테이블 이름을 반환합니다
+func tables(t *testing.T, db *sql.DB) []string {
+	t.Helper()
+	rows, err := db.Query(`SELECT table_name FROM information_schema.tables WHERE table_schema = 'public' ORDER BY table_name`)
+	if err != nil {
+		t.Fatalf("테이블 조회 실패: %v", err)
+	}
+	defer rows.Close()
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatalf("테이블 스캔 실패: %v", err)
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestMigrateUpDown(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+
+	m, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("NewMigrator: %v", err)
+	}
+
+	done, err := m.Up(ctx, 0)
+	if err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if len(done) != m.Latest() {
+		t.Fatalf("적용한 마이그레이션 %v, 기대 %d개", done, m.Latest())
+	}
+	status, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	for _, st := range status {
+		if !st.Applied || st.Modified || st.Unknown {
+			t.Errorf("버전 %d 상태 = %+v", st.Version, st)
+		}
+	}
+
+	// 다시 적용하면 아무것도 하지 않음
+	if done, err := m.Up(ctx, 0); err != nil || len(done) != 0 {
+		t.Fatalf("두 번째 Up = %v, %v", done, err)
+	}
+
+	if done, err := m.Down(ctx, m.Latest()); err != nil || len(done) != m.Latest() {
+		t.Fatalf("Down = %v, %v", done, err)
+	}
+	if left := tables(t, db); len(left) != 1 || left[0] != "schema_migrations" {
+		t.Errorf("모두 되돌린 뒤 남은 테이블 = %v, schema_migrations만 기대", left)
+	}
+
+	// 되돌린 뒤 다시 적용할 수 있어야 함
+	if done, err := m.Up(ctx, 0); err != nil || len(done) != m.Latest() {
+		t.Fatalf("되돌린 뒤 Up = %v, %v", done, err)
+	}
+}
+
+func TestMigrateModifiedChecksum(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+
+	m, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("NewMigrator: %v", err)
+	}
+	if _, err := m.Up(ctx, 1); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE schema_migrations SET checksum = 'changed' WHERE version = 1`); err != nil {
+		t.Fatalf("체크섬 변경 실패: %v", err)
+	}
+	if _, err := m.Up(ctx, 0); err == nil || !strings.Contains(err.Error(), "내용이 바뀐") {
+		t.Fatalf("Up 오류 = %v, 바뀐 마이그레이션 오류 기대", err)
+	}
+}
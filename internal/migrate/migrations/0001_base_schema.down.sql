@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS logs;
+DROP TABLE IF EXISTS commands;
+DROP TABLE IF EXISTS nodes;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,43 @@
+-- 수집기가 사용하는 기본 테이블 (웹 서버와 공유)
+-- 이미 운영 중인 DB에도 적용할 수 있도록 없는 테이블과 컬럼만 추가합니다.
+
+CREATE TABLE IF NOT EXISTS users (
+	google_id   VARCHAR(255),
+	email       VARCHAR(255),
+	name        VARCHAR(255),
+	obscura_key VARCHAR(255) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS nodes (
+	node_id     VARCHAR(255) PRIMARY KEY,
+	obscura_key VARCHAR(255) NOT NULL,
+	server_type VARCHAR(64) NOT NULL DEFAULT '',
+	node_name   VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+ALTER TABLE nodes ADD COLUMN IF NOT EXISTS status SMALLINT NOT NULL DEFAULT 0;
+ALTER TABLE nodes ADD COLUMN IF NOT EXISTS external_ip VARCHAR(64);
+ALTER TABLE nodes ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE INDEX IF NOT EXISTS nodes_obscura_key_idx ON nodes (obscura_key);
+
+CREATE TABLE IF NOT EXISTS commands (
+	command_id     SERIAL PRIMARY KEY,
+	node_id        VARCHAR(255) NOT NULL,
+	command_type   VARCHAR(64) NOT NULL,
+	command_status SMALLINT NOT NULL DEFAULT 0,
+	target         TEXT NOT NULL DEFAULT '',
+	created_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS commands_node_idx ON commands (node_id);
+
+CREATE TABLE IF NOT EXISTS logs (
+	id        BIGSERIAL PRIMARY KEY,
+	node_id   VARCHAR(255) NOT NULL,
+	timestamp TIMESTAMPTZ NOT NULL,
+	level     VARCHAR(16) NOT NULL DEFAULT '',
+	content   TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS logs_node_idx ON logs (node_id, timestamp DESC);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS cluster_instances;
+DROP TABLE IF EXISTS node_leases;
+DROP TABLE IF EXISTS node_ip_history;
+DROP TABLE IF EXISTS node_events;
+DROP TABLE IF EXISTS node_inventory_changes;
+DROP TABLE IF EXISTS node_inventory;
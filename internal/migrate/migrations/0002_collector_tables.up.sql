@@ -0,0 +1,62 @@
+-- 노드 인벤토리
+CREATE TABLE IF NOT EXISTS node_inventory (
+	node_id    VARCHAR(255) PRIMARY KEY,
+	inventory  JSONB NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS node_inventory_changes (
+	id         BIGSERIAL PRIMARY KEY,
+	node_id    VARCHAR(255) NOT NULL,
+	component  VARCHAR(32) NOT NULL,
+	item       TEXT NOT NULL DEFAULT '',
+	field      TEXT NOT NULL DEFAULT '',
+	old_value  TEXT NOT NULL DEFAULT '',
+	new_value  TEXT NOT NULL DEFAULT '',
+	changed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS node_inventory_changes_node_idx ON node_inventory_changes (node_id, changed_at DESC);
+
+-- 노드 이벤트
+CREATE TABLE IF NOT EXISTS node_events (
+	id         BIGSERIAL PRIMARY KEY,
+	node_id    VARCHAR(255) NOT NULL,
+	type       VARCHAR(64) NOT NULL,
+	severity   VARCHAR(16) NOT NULL,
+	message    TEXT NOT NULL DEFAULT '',
+	data       JSONB,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS node_events_node_idx ON node_events (node_id, created_at DESC);
+
+-- 외부 IP 이력
+CREATE TABLE IF NOT EXISTS node_ip_history (
+	node_id      VARCHAR(255) NOT NULL,
+	ip           VARCHAR(64) NOT NULL,
+	first_seen   TIMESTAMPTZ NOT NULL,
+	last_seen    TIMESTAMPTZ NOT NULL,
+	sample_count BIGINT NOT NULL DEFAULT 0,
+	country_code VARCHAR(8) NOT NULL DEFAULT '',
+	country_name TEXT NOT NULL DEFAULT '',
+	asn          BIGINT NOT NULL DEFAULT 0,
+	as_org       TEXT NOT NULL DEFAULT '',
+	PRIMARY KEY (node_id, ip)
+);
+
+-- 클러스터 노드 리스와 인스턴스
+CREATE TABLE IF NOT EXISTS node_leases (
+	node_id     VARCHAR(255) PRIMARY KEY,
+	instance_id VARCHAR(255) NOT NULL,
+	acquired_at TIMESTAMPTZ NOT NULL,
+	expires_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS node_leases_instance_idx ON node_leases (instance_id);
+
+CREATE TABLE IF NOT EXISTS cluster_instances (
+	instance_id  VARCHAR(255) PRIMARY KEY,
+	started_at   TIMESTAMPTZ NOT NULL,
+	heartbeat_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS commands_notify ON commands;
+DROP FUNCTION IF EXISTS notify_node_command();
+DROP TRIGGER IF EXISTS nodes_change_notify ON nodes;
+DROP FUNCTION IF EXISTS notify_node_change();
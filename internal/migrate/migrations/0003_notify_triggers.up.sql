@@ -0,0 +1,29 @@
+-- nodes의 소유자/유형이 바뀌면 node_changes 채널로 node_id를 알림 (노드 레지스트리 캐시 무효화)
+CREATE OR REPLACE FUNCTION notify_node_change() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP = 'DELETE' THEN
+		PERFORM pg_notify('node_changes', OLD.node_id);
+		RETURN OLD;
+	END IF;
+	PERFORM pg_notify('node_changes', NEW.node_id);
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS nodes_change_notify ON nodes;
+CREATE TRIGGER nodes_change_notify
+	AFTER INSERT OR DELETE OR UPDATE OF node_id, obscura_key, server_type ON nodes
+	FOR EACH ROW EXECUTE FUNCTION notify_node_change();
+
+-- commands에 명령어가 추가되면 node_commands 채널로 node_id를 알림 (즉시 전달)
+CREATE OR REPLACE FUNCTION notify_node_command() RETURNS trigger AS $$
+BEGIN
+	PERFORM pg_notify('node_commands', NEW.node_id);
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS commands_notify ON commands;
+CREATE TRIGGER commands_notify
+	AFTER INSERT ON commands
+	FOR EACH ROW EXECUTE FUNCTION notify_node_command();
@@ -0,0 +1,47 @@
+-- 알림 규칙 (설정 파일의 alerting.rules와 함께 사용)
+CREATE TABLE IF NOT EXISTS alert_rules (
+	id          BIGSERIAL PRIMARY KEY,
+	name        VARCHAR(255) NOT NULL UNIQUE,
+	expr        TEXT NOT NULL,
+	for_period  VARCHAR(32) NOT NULL DEFAULT '',
+	severity    VARCHAR(16) NOT NULL DEFAULT 'warning',
+	match       JSONB,
+	user_key    VARCHAR(255) NOT NULL DEFAULT '',
+	node_id     VARCHAR(255) NOT NULL DEFAULT '',
+	server_type VARCHAR(64) NOT NULL DEFAULT '',
+	enabled     BOOLEAN NOT NULL DEFAULT true,
+	created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- 발생한 알림 이력 (해소되지 않은 알림은 fingerprint당 하나)
+CREATE TABLE IF NOT EXISTS alerts (
+	id          BIGSERIAL PRIMARY KEY,
+	fingerprint VARCHAR(64) NOT NULL,
+	rule_key    VARCHAR(255) NOT NULL,
+	rule_name   VARCHAR(255) NOT NULL,
+	node_id     VARCHAR(255) NOT NULL,
+	labels      JSONB,
+	severity    VARCHAR(16) NOT NULL,
+	value       DOUBLE PRECISION NOT NULL DEFAULT 0,
+	message     TEXT NOT NULL DEFAULT '',
+	silenced    BOOLEAN NOT NULL DEFAULT false,
+	started_at  TIMESTAMPTZ NOT NULL,
+	fired_at    TIMESTAMPTZ NOT NULL,
+	resolved_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS alerts_firing_idx ON alerts (fingerprint) WHERE resolved_at IS NULL;
+CREATE INDEX IF NOT EXISTS alerts_node_idx ON alerts (node_id, fired_at DESC);
+
+-- 알림 사일런스
+CREATE TABLE IF NOT EXISTS alert_silences (
+	id         BIGSERIAL PRIMARY KEY,
+	matchers   JSONB NOT NULL,
+	comment    TEXT NOT NULL DEFAULT '',
+	created_by VARCHAR(255) NOT NULL DEFAULT '',
+	starts_at  TIMESTAMPTZ NOT NULL,
+	ends_at    TIMESTAMPTZ NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS alert_silences_ends_idx ON alert_silences (ends_at);
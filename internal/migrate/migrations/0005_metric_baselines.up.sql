@@ -0,0 +1,11 @@
+-- 노드별 지표 시리즈의 학습된 기준값 (이상 탐지)
+CREATE TABLE IF NOT EXISTS metric_baselines (
+	node_id      VARCHAR(255) NOT NULL,
+	series       VARCHAR(512) NOT NULL,
+	mean         DOUBLE PRECISION NOT NULL,
+	variance     DOUBLE PRECISION NOT NULL,
+	sample_count BIGINT NOT NULL,
+	hourly       JSONB,
+	updated_at   TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (node_id, series)
+);
@@ -0,0 +1,18 @@
+-- 컨테이너 생명주기 이벤트 (연속된 컨테이너 목록의 차이)
+CREATE TABLE IF NOT EXISTS container_events (
+	id             BIGSERIAL PRIMARY KEY,
+	node_id        VARCHAR(255) NOT NULL,
+	container_name VARCHAR(255) NOT NULL,
+	container_id   VARCHAR(128) NOT NULL DEFAULT '',
+	image          TEXT NOT NULL DEFAULT '',
+	type           VARCHAR(64) NOT NULL,
+	severity       VARCHAR(16) NOT NULL,
+	old_value      TEXT NOT NULL DEFAULT '',
+	new_value      TEXT NOT NULL DEFAULT '',
+	restart_delta  INTEGER NOT NULL DEFAULT 0,
+	message        TEXT NOT NULL DEFAULT '',
+	created_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS container_events_node_idx ON container_events (node_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS container_events_name_idx ON container_events (node_id, container_name, created_at DESC);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS critical_services;
+DROP TABLE IF EXISTS service_events;
@@ -0,0 +1,26 @@
+-- systemd 서비스 상태 변화 이력
+CREATE TABLE IF NOT EXISTS service_events (
+	id           BIGSERIAL PRIMARY KEY,
+	node_id      VARCHAR(255) NOT NULL,
+	unit         VARCHAR(255) NOT NULL,
+	type         VARCHAR(64) NOT NULL,
+	severity     VARCHAR(16) NOT NULL,
+	old_value    VARCHAR(255) NOT NULL DEFAULT '',
+	new_value    VARCHAR(255) NOT NULL DEFAULT '',
+	active_state VARCHAR(64) NOT NULL DEFAULT '',
+	sub_state    VARCHAR(64) NOT NULL DEFAULT '',
+	message      TEXT NOT NULL DEFAULT '',
+	created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS service_events_node_idx ON service_events (node_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS service_events_unit_idx ON service_events (node_id, unit, created_at DESC);
+
+-- 노드별로 항상 실행 중이어야 하는 서비스
+CREATE TABLE IF NOT EXISTS critical_services (
+	node_id    VARCHAR(255) NOT NULL,
+	unit       VARCHAR(255) NOT NULL,
+	comment    TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	PRIMARY KEY (node_id, unit)
+);
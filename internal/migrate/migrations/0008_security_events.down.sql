@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS security_allowlist;
+DROP TABLE IF EXISTS known_processes;
+DROP TABLE IF EXISTS security_events;
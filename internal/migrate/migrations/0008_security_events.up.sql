@@ -0,0 +1,35 @@
+-- 프로세스 목록에서 감지한 보안 이벤트
+CREATE TABLE IF NOT EXISTS security_events (
+	id           BIGSERIAL PRIMARY KEY,
+	node_id      VARCHAR(255) NOT NULL,
+	type         VARCHAR(64) NOT NULL,
+	severity     VARCHAR(16) NOT NULL,
+	process_name VARCHAR(255) NOT NULL DEFAULT '',
+	pid          INTEGER NOT NULL DEFAULT 0,
+	username     VARCHAR(255) NOT NULL DEFAULT '',
+	command      TEXT NOT NULL DEFAULT '',
+	old_value    VARCHAR(255) NOT NULL DEFAULT '',
+	new_value    VARCHAR(255) NOT NULL DEFAULT '',
+	message      TEXT NOT NULL DEFAULT '',
+	created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS security_events_node_idx ON security_events (node_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS security_events_type_idx ON security_events (type, created_at DESC);
+
+-- 노드에서 한 번이라도 실행된 프로세스 이름 (새 프로세스 감지 기준)
+CREATE TABLE IF NOT EXISTS known_processes (
+	node_id    VARCHAR(255) NOT NULL,
+	name       VARCHAR(255) NOT NULL,
+	first_seen TIMESTAMPTZ NOT NULL DEFAULT now(),
+	PRIMARY KEY (node_id, name)
+);
+
+-- 노드별로 보안 이벤트를 발행하지 않을 프로세스 이름
+CREATE TABLE IF NOT EXISTS security_allowlist (
+	node_id      VARCHAR(255) NOT NULL,
+	process_name VARCHAR(255) NOT NULL,
+	comment      TEXT NOT NULL DEFAULT '',
+	created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+	PRIMARY KEY (node_id, process_name)
+);
@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	config "system-collector/configs"
+)
+
+// 채널 설정 기본값
+const (
+	defaultMaxRetries = 3
+	defaultTimeout    = 10 * time.Second
+)
+
+// Channel은 메시지를 보내는 알림 채널입니다
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, msg *Message) error
+}
+
+// permanentError는 재시도해도 성공하지 않는 전송 오류입니다 (템플릿 오류, 잘못된 요청, 인증 실패 등)
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// isPermanent는 재시도하지 않을 오류인지 반환합니다
+func isPermanent(err error) bool {
+	var perm *permanentError
+	return errors.As(err, &perm)
+}
+
+// newChannel은 설정으로 채널을 생성합니다
+func newChannel(cfg config.NotifyChannel) (Channel, error) {
+	tmpl, err := parseTemplates(cfg.TitleTemplate, cfg.Template)
+	if err != nil {
+		return nil, fmt.Errorf("알림 채널 %q: %v", cfg.Name, err)
+	}
+	timeout := defaultTimeout
+	if cfg.Timeout > 0 {
+		timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+
+	switch cfg.Type {
+	case webhookKind, slackKind, discordKind:
+		return newWebhookChannel(cfg, tmpl, timeout), nil
+	case "email":
+		return newEmailChannel(cfg, tmpl, timeout), nil
+	}
+	return nil, fmt.Errorf("알림 채널 %q: 알 수 없는 유형 %q", cfg.Name, cfg.Type)
+}
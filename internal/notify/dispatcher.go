@@ -0,0 +1,300 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	config "system-collector/configs"
+	"system-collector/internal/telemetry"
+	"system-collector/pkg/logger"
+	"system-collector/pkg/models"
+	"system-collector/pkg/ratelimit"
+)
+
+// 재시도 대기 시간 (지수 증가)
+const (
+	initialBackoff = time.Second
+	maxBackoff     = 30 * time.Second
+)
+
+// worker는 채널 하나의 전송 큐입니다. 채널마다 하나의 고루틴이 순서대로 보냅니다.
+type worker struct {
+	channel     Channel
+	minSeverity int
+	maxRetries  int
+	bucket      *ratelimit.Bucket
+	queue       chan *Message
+	done        chan struct{}
+}
+
+// group은 아직 보내지 않은 같은 그룹의 항목입니다
+type group struct {
+	items []Item
+	timer *time.Timer
+}
+
+// Dispatcher는 이벤트 버스의 이벤트를 그룹으로 모아 알림 채널로 보냅니다.
+// 채널별로 최소 심각도, 분당 전송 수 제한, 실패 시 재시도를 적용합니다.
+type Dispatcher struct {
+	events    map[string]bool
+	groupBy   []string
+	groupWait time.Duration
+	workers   []*worker
+
+	mu     sync.Mutex
+	groups map[string]*group
+	closed bool
+
+	stop chan struct{}
+}
+
+// NewDispatcher는 설정의 채널로 Dispatcher를 생성합니다. 잘못된 채널은 건너뛰고 오류를 모아 반환합니다.
+func NewDispatcher() (*Dispatcher, error) {
+	sugar := logger.GetCustomLogger()
+	cfg := config.Get().Notify
+	sugar.Infow("알림 디스패처 초기화 중", "channels", len(cfg.Channels), "groupWait", cfg.GroupWait)
+
+	d := &Dispatcher{
+		events:    make(map[string]bool),
+		groupBy:   cfg.GroupBy,
+		groupWait: time.Duration(cfg.GroupWait) * time.Second,
+		groups:    make(map[string]*group),
+		stop:      make(chan struct{}),
+	}
+	for _, eventType := range cfg.Events {
+		d.events[eventType] = true
+	}
+
+	var errs []string
+	for _, chCfg := range cfg.Channels {
+		channel, err := newChannel(chCfg)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		w := &worker{
+			channel:     channel,
+			minSeverity: severityRank(chCfg.MinSeverity),
+			maxRetries:  defaultMaxRetries,
+			bucket:      ratelimit.NewBucket(float64(chCfg.RateLimit)/60, chCfg.RateLimit),
+			queue:       make(chan *Message, cfg.QueueSize),
+			done:        make(chan struct{}),
+		}
+		if chCfg.MaxRetries > 0 {
+			w.maxRetries = chCfg.MaxRetries
+		}
+		d.workers = append(d.workers, w)
+	}
+	if len(errs) > 0 {
+		return d, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return d, nil
+}
+
+// Start는 채널별 전송 고루틴을 시작합니다
+func (d *Dispatcher) Start() {
+	for _, w := range d.workers {
+		go d.run(w)
+	}
+}
+
+// Handle은 이벤트 버스 구독 함수입니다. 보낼 유형의 이벤트를 그룹에 추가하고,
+// group_wait가 지나면 그룹의 항목을 한 메시지로 보냅니다.
+func (d *Dispatcher) Handle(event models.Event) {
+	if len(d.workers) == 0 || !d.events[event.Type] {
+		return
+	}
+	item := itemFromEvent(event)
+	key := d.groupKey(item)
+
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return
+	}
+	g, ok := d.groups[key]
+	if !ok {
+		g = &group{}
+		d.groups[key] = g
+	}
+	g.items = append(g.items, item)
+	if d.groupWait > 0 {
+		if !ok {
+			g.timer = time.AfterFunc(d.groupWait, func() { d.flush(key) })
+		}
+		d.mu.Unlock()
+		return
+	}
+	d.flushLocked(key)
+	d.mu.Unlock()
+}
+
+// groupKey는 group_by 기준으로 항목의 그룹을 만듭니다 (예: node_id=node-1)
+func (d *Dispatcher) groupKey(item Item) string {
+	parts := make([]string, 0, len(d.groupBy))
+	for _, by := range d.groupBy {
+		var value string
+		switch by {
+		case "node_id":
+			value = item.NodeID
+		case "rule":
+			value = item.Rule
+			if value == "" {
+				value = item.Type
+			}
+		case "severity":
+			value = item.Severity
+		case "type":
+			value = item.Type
+		}
+		parts = append(parts, by+"="+value)
+	}
+	if len(parts) == 0 {
+		return "all"
+	}
+	return strings.Join(parts, ",")
+}
+
+// flush는 group_wait가 지난 그룹을 보냅니다
+func (d *Dispatcher) flush(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.flushLocked(key)
+}
+
+// flushLocked는 그룹의 항목을 메시지로 만들어 채널별 큐에 넣습니다. d.mu를 잡은 상태에서 호출해야 합니다.
+func (d *Dispatcher) flushLocked(key string) {
+	g, ok := d.groups[key]
+	delete(d.groups, key)
+	if !ok || len(g.items) == 0 {
+		return
+	}
+
+	for _, w := range d.workers {
+		items := slices.DeleteFunc(slices.Clone(g.items), func(item Item) bool {
+			return severityRank(item.Severity) < w.minSeverity
+		})
+		if len(items) == 0 {
+			continue
+		}
+		w.enqueue(newMessage(key, items))
+	}
+}
+
+// enqueue는 메시지를 채널 큐에 넣습니다. 큐가 가득 차면 버립니다.
+func (w *worker) enqueue(msg *Message) {
+	sugar := logger.GetCustomLogger()
+	select {
+	case w.queue <- msg:
+	default:
+		telemetry.NotificationsSent.WithLabelValues(w.channel.Name(), "dropped").Inc()
+		sugar.Errorw("알림 큐가 가득 차 메시지를 버림", "channel", w.channel.Name(), "group", msg.Group, "items", len(msg.Items))
+	}
+}
+
+// run은 채널 큐의 메시지를 전송 수 제한에 맞춰 보내고, 실패하면 재시도합니다
+func (d *Dispatcher) run(w *worker) {
+	defer close(w.done)
+	ctx := logger.WithContext(context.Background(), "channel", w.channel.Name())
+	sugar := logger.FromContext(ctx)
+
+	for msg := range w.queue {
+		for !w.bucket.Allow() {
+			time.Sleep(time.Second)
+		}
+
+		if err := d.send(ctx, w, msg); err != nil {
+			telemetry.NotificationsSent.WithLabelValues(w.channel.Name(), "failed").Inc()
+			sugar.Errorw("알림 전송 실패", "group", msg.Group, "items", len(msg.Items), "error", err)
+			continue
+		}
+		telemetry.NotificationsSent.WithLabelValues(w.channel.Name(), "sent").Inc()
+		sugar.Infow("알림 전송 완료", "group", msg.Group, "status", msg.Status, "items", len(msg.Items))
+	}
+}
+
+// send는 메시지를 보내고 실패하면 max_retries번까지 간격을 늘려 가며 다시 보냅니다.
+// 종료 중에는 재시도 대기 없이 마지막 시도의 오류를 반환합니다.
+func (d *Dispatcher) send(ctx context.Context, w *worker, msg *Message) error {
+	sugar := logger.FromContext(ctx)
+
+	backoff := initialBackoff
+	var err error
+	for attempt := 0; ; attempt++ {
+		if err = w.channel.Send(ctx, msg); err == nil || isPermanent(err) || attempt >= w.maxRetries {
+			return err
+		}
+		sugar.Warnw("알림 전송 재시도", "group", msg.Group, "attempt", attempt+1, "backoff", backoff.String(), "error", err)
+		select {
+		case <-d.stop:
+			return err
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// Test는 모든 채널(name이 있으면 해당 채널)로 시험 메시지를 바로 보내고 채널별 결과를 반환합니다.
+// 그룹, 전송 수 제한, 재시도를 거치지 않습니다.
+func (d *Dispatcher) Test(ctx context.Context, name string) (map[string]string, error) {
+	value := 42.0
+	msg := newMessage("test", []Item{{
+		Type:     "notify_test",
+		State:    StatusFiring,
+		Severity: models.SeverityInfo,
+		NodeID:   "test-node",
+		NodeName: "test-node",
+		Hostname: "test-host",
+		Rule:     "notify-test",
+		Metric:   "cpu.usage",
+		Value:    &value,
+		Message:  "알림 채널 시험 메시지입니다",
+		Time:     time.Now(),
+	}})
+
+	results := make(map[string]string)
+	for _, w := range d.workers {
+		if name != "" && w.channel.Name() != name {
+			continue
+		}
+		if err := w.channel.Send(ctx, msg); err != nil {
+			results[w.channel.Name()] = err.Error()
+		} else {
+			results[w.channel.Name()] = "ok"
+		}
+	}
+	if name != "" && len(results) == 0 {
+		return nil, fmt.Errorf("알림 채널 %q가 없습니다", name)
+	}
+	return results, nil
+}
+
+// Stop은 모아 둔 그룹을 바로 보내고, 채널 큐의 메시지를 모두 보낼 때까지 기다립니다.
+// 이벤트 버스를 닫은 뒤 호출해야 합니다.
+func (d *Dispatcher) Stop(ctx context.Context) error {
+	d.mu.Lock()
+	d.closed = true
+	for key, g := range d.groups {
+		if g.timer != nil {
+			g.timer.Stop()
+		}
+		d.flushLocked(key)
+	}
+	for _, w := range d.workers {
+		close(w.queue)
+	}
+	d.mu.Unlock()
+	close(d.stop)
+	for _, w := range d.workers {
+		select {
+		case <-w.done:
+		case <-ctx.Done():
+			return fmt.Errorf("알림 전송 대기 시간 초과: %v", ctx.Err())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,330 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"system-collector/pkg/models"
+	"system-collector/pkg/ratelimit"
+)
+
+// fakeChannel은 보낸 메시지를 기록하고, failures번까지 err로 실패하는 채널입니다
+type fakeChannel struct {
+	name string
+
+	mu       sync.Mutex
+	attempts int
+	failures int
+	err      error
+	sent     []*Message
+}
+
+func (c *fakeChannel) Name() string { return c.name }
+
+func (c *fakeChannel) Send(ctx context.Context, msg *Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.attempts++
+	if c.attempts <= c.failures {
+		return c.err
+	}
+	c.sent = append(c.sent, msg)
+	return nil
+}
+
+func (c *fakeChannel) messages() []*Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*Message(nil), c.sent...)
+}
+
+// newTestWorker는 분당 rateLimit개(0은 제한 없음)를 보내는 채널 워커를 만듭니다
+func newTestWorker(channel Channel, minSeverity string, rateLimit, maxRetries int) *worker {
+	return &worker{
+		channel:     channel,
+		minSeverity: severityRank(minSeverity),
+		maxRetries:  maxRetries,
+		bucket:      ratelimit.NewBucket(float64(rateLimit)/60, rateLimit),
+		queue:       make(chan *Message, 10),
+		done:        make(chan struct{}),
+	}
+}
+
+func newTestDispatcher(groupBy []string, groupWait time.Duration, workers ...*worker) *Dispatcher {
+	return &Dispatcher{
+		events:    map[string]bool{models.EventAlertFiring: true, models.EventAlertResolved: true},
+		groupBy:   groupBy,
+		groupWait: groupWait,
+		workers:   workers,
+		groups:    make(map[string]*group),
+		stop:      make(chan struct{}),
+	}
+}
+
+func alertEvent(nodeID, rule, severity string, at time.Time) models.Event {
+	return models.Event{
+		NodeID:    nodeID,
+		Type:      models.EventAlertFiring,
+		Severity:  severity,
+		Message:   rule + " 발생",
+		Data:      map[string]interface{}{"rule": rule, "severity": severity},
+		CreatedAt: at,
+	}
+}
+
+// waitFor는 cond가 true가 될 때까지 최대 5초 기다립니다
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("대기 시간 초과")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func stopDispatcher(t *testing.T, d *Dispatcher) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := d.Stop(ctx); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+}
+
+func TestDispatcherRetry(t *testing.T) {
+	transient := errors.New("일시적 오류")
+	tests := []struct {
+		name         string
+		failures     int
+		err          error
+		maxRetries   int
+		wantAttempts int
+		wantSent     int
+	}{
+		{name: "한 번 실패 후 성공", failures: 1, err: transient, maxRetries: 3, wantAttempts: 2, wantSent: 1},
+		{name: "재시도 소진", failures: 10, err: transient, maxRetries: 1, wantAttempts: 2, wantSent: 0},
+		{name: "재시도하지 않는 오류", failures: 10, err: &permanentError{errors.New("잘못된 요청")}, maxRetries: 3, wantAttempts: 1, wantSent: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channel := &fakeChannel{name: "fake", failures: tt.failures, err: tt.err}
+			d := newTestDispatcher(nil, 0, newTestWorker(channel, "", 0, tt.maxRetries))
+			d.Start()
+
+			d.Handle(alertEvent("node-1", "high-cpu", models.SeverityWarning, time.Now()))
+			// 종료하면 재시도 대기를 건너뛰므로 기대한 시도가 끝날 때까지 기다림 (첫 재시도 대기 1초)
+			waitFor(t, func() bool {
+				channel.mu.Lock()
+				defer channel.mu.Unlock()
+				return channel.attempts >= tt.wantAttempts
+			})
+			time.Sleep(50 * time.Millisecond)
+			stopDispatcher(t, d)
+
+			if channel.attempts != tt.wantAttempts || len(channel.sent) != tt.wantSent {
+				t.Errorf("시도 %d번, 전송 %d건, 기대 %d번, %d건", channel.attempts, len(channel.sent), tt.wantAttempts, tt.wantSent)
+			}
+		})
+	}
+}
+
+func TestDispatcherStopSkipsBackoff(t *testing.T) {
+	channel := &fakeChannel{name: "fake", failures: 10, err: errors.New("일시적 오류")}
+	w := newTestWorker(channel, "", 0, 10)
+	d := newTestDispatcher(nil, 0, w)
+	d.Start()
+	d.Handle(alertEvent("node-1", "high-cpu", models.SeverityWarning, time.Now()))
+
+	// 재시도 대기 중에 종료하면 남은 재시도를 기다리지 않음
+	start := time.Now()
+	stopDispatcher(t, d)
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("Stop이 재시도 대기를 기다림 (%v)", elapsed)
+	}
+}
+
+func TestDispatcherRateLimitPerChannel(t *testing.T) {
+	limited := &fakeChannel{name: "limited"}
+	unlimited := &fakeChannel{name: "unlimited"}
+	d := newTestDispatcher([]string{"node_id"}, 0,
+		newTestWorker(limited, "", 1, 0),
+		newTestWorker(unlimited, "", 0, 0),
+	)
+	d.Start()
+
+	now := time.Now()
+	for _, nodeID := range []string{"node-1", "node-2", "node-3"} {
+		d.Handle(alertEvent(nodeID, "high-cpu", models.SeverityWarning, now))
+	}
+
+	waitFor(t, func() bool { return len(unlimited.messages()) == 3 })
+	// 분당 1건이므로 나머지는 다음 토큰을 기다림
+	time.Sleep(100 * time.Millisecond)
+	if got := len(limited.messages()); got != 1 {
+		t.Errorf("제한 있는 채널 전송 %d건, 1건 기대", got)
+	}
+}
+
+func TestDispatcherGrouping(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := []models.Event{
+		alertEvent("node-1", "high-cpu", models.SeverityWarning, base.Add(2*time.Second)),
+		alertEvent("node-1", "disk-full", models.SeverityCritical, base.Add(time.Second)),
+		alertEvent("node-2", "high-cpu", models.SeverityWarning, base),
+	}
+
+	tests := []struct {
+		name      string
+		groupBy   []string
+		groupWait time.Duration
+		want      map[string]int // 그룹별 항목 수
+	}{
+		{name: "노드별로 모음", groupBy: []string{"node_id"}, groupWait: 50 * time.Millisecond,
+			want: map[string]int{"node_id=node-1": 2, "node_id=node-2": 1}},
+		{name: "규칙별로 모음", groupBy: []string{"rule"}, groupWait: 50 * time.Millisecond,
+			want: map[string]int{"rule=high-cpu": 2, "rule=disk-full": 1}},
+		{name: "기준 없음", groupWait: 50 * time.Millisecond,
+			want: map[string]int{"all": 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channel := &fakeChannel{name: "fake"}
+			d := newTestDispatcher(tt.groupBy, tt.groupWait, newTestWorker(channel, "", 0, 0))
+			d.Start()
+			for _, e := range events {
+				d.Handle(e)
+			}
+
+			waitFor(t, func() bool { return len(channel.messages()) >= len(tt.want) })
+			stopDispatcher(t, d)
+
+			got := make(map[string]int)
+			for _, msg := range channel.messages() {
+				got[msg.Group] += len(msg.Items)
+				if !sort.SliceIsSorted(msg.Items, func(a, b int) bool { return msg.Items[a].Time.Before(msg.Items[b].Time) }) {
+					t.Errorf("%s 항목이 시간 순이 아님", msg.Group)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("그룹 = %v, 기대 %v", got, tt.want)
+			}
+			for group, n := range tt.want {
+				if got[group] != n {
+					t.Errorf("%s 항목 %d건, 기대 %d건", group, got[group], n)
+				}
+			}
+		})
+	}
+}
+
+func TestDispatcherStopFlushesGroups(t *testing.T) {
+	channel := &fakeChannel{name: "fake"}
+	d := newTestDispatcher([]string{"node_id"}, time.Hour, newTestWorker(channel, "", 0, 0))
+	d.Start()
+	d.Handle(alertEvent("node-1", "high-cpu", models.SeverityWarning, time.Now()))
+
+	// group_wait가 지나지 않아도 종료할 때 보냄
+	stopDispatcher(t, d)
+	if msgs := channel.messages(); len(msgs) != 1 || msgs[0].Group != "node_id=node-1" {
+		t.Errorf("종료 시 보낸 메시지 = %v", msgs)
+	}
+	// 종료 후 이벤트는 무시
+	d.Handle(alertEvent("node-2", "high-cpu", models.SeverityWarning, time.Now()))
+}
+
+func TestDispatcherMinSeverity(t *testing.T) {
+	all := &fakeChannel{name: "all"}
+	critical := &fakeChannel{name: "critical"}
+	d := newTestDispatcher([]string{"node_id"}, 0,
+		newTestWorker(all, "", 0, 0),
+		newTestWorker(critical, models.SeverityCritical, 0, 0),
+	)
+	d.Start()
+	d.Handle(alertEvent("node-1", "high-cpu", models.SeverityWarning, time.Now()))
+	d.Handle(alertEvent("node-2", "disk-full", models.SeverityCritical, time.Now()))
+	// 보낼 유형이 아닌 이벤트는 무시
+	d.Handle(models.Event{NodeID: "node-3", Type: "node_offline", Severity: models.SeverityCritical})
+	stopDispatcher(t, d)
+
+	if got := len(all.messages()); got != 2 {
+		t.Errorf("모든 심각도 채널 %d건, 2건 기대", got)
+	}
+	if msgs := critical.messages(); len(msgs) != 1 || msgs[0].Items[0].Rule != "disk-full" {
+		t.Errorf("critical 채널 메시지 = %v", msgs)
+	}
+}
+
+func TestGroupKey(t *testing.T) {
+	item := Item{Type: models.EventAlertFiring, NodeID: "node-1", Severity: models.SeverityWarning, Rule: "high-cpu"}
+	tests := []struct {
+		groupBy []string
+		item    Item
+		want    string
+	}{
+		{groupBy: nil, item: item, want: "all"},
+		{groupBy: []string{"node_id"}, item: item, want: "node_id=node-1"},
+		{groupBy: []string{"node_id", "severity"}, item: item, want: "node_id=node-1,severity=warning"},
+		{groupBy: []string{"rule", "type"}, item: item, want: "rule=high-cpu,type=alert_firing"},
+		// 규칙이 없는 이벤트는 유형으로 묶음
+		{groupBy: []string{"rule"}, item: Item{Type: "node_offline"}, want: "rule=node_offline"},
+	}
+	for _, tt := range tests {
+		d := newTestDispatcher(tt.groupBy, 0)
+		if got := d.groupKey(tt.item); got != tt.want {
+			t.Errorf("groupKey(%v) = %q, 기대 %q", tt.groupBy, got, tt.want)
+		}
+	}
+}
+
+func TestNewMessage(t *testing.T) {
+	tests := []struct {
+		name         string
+		items        []Item
+		wantStatus   string
+		wantSeverity string
+	}{
+		{name: "발생 중", items: []Item{{State: StatusFiring, Severity: models.SeverityWarning}}, wantStatus: StatusFiring, wantSeverity: models.SeverityWarning},
+		{name: "모두 해소", items: []Item{{State: StatusResolved, Severity: models.SeverityCritical}, {State: StatusResolved, Severity: models.SeverityWarning}},
+			wantStatus: StatusResolved, wantSeverity: models.SeverityCritical},
+		{name: "해소와 발생 섞임", items: []Item{{State: StatusResolved, Severity: models.SeverityCritical}, {State: StatusFiring, Severity: models.SeverityInfo}},
+			wantStatus: StatusFiring, wantSeverity: models.SeverityCritical},
+		{name: "알림이 아닌 이벤트", items: []Item{{Severity: models.SeverityInfo}}, wantStatus: StatusFiring, wantSeverity: models.SeverityInfo},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := newMessage("g", tt.items)
+			if msg.Status != tt.wantStatus || msg.Severity != tt.wantSeverity {
+				t.Errorf("상태 %s, 심각도 %s, 기대 %s, %s", msg.Status, msg.Severity, tt.wantStatus, tt.wantSeverity)
+			}
+		})
+	}
+}
+
+func TestItemFromEvent(t *testing.T) {
+	event := models.Event{
+		NodeID:   "node-1",
+		Type:     models.EventAlertResolved,
+		Severity: models.SeverityInfo,
+		Message:  "해소",
+		Data: map[string]interface{}{
+			"rule": "high-cpu", "metric": "cpu.usage", "value": 42.0, "severity": models.SeverityCritical,
+			"node_name": "web-1", "hostname": "web-1.local", "labels": map[string]string{"mount": "/"},
+		},
+	}
+	item := itemFromEvent(event)
+	if item.State != StatusResolved || item.Severity != models.SeverityCritical || item.Rule != "high-cpu" || item.Metric != "cpu.usage" {
+		t.Errorf("항목 = %+v", item)
+	}
+	if item.Value == nil || *item.Value != 42 || item.Labels["mount"] != "/" {
+		t.Errorf("값 또는 레이블 = %v, %v", item.Value, item.Labels)
+	}
+	if item.Node() != "web-1 (web-1.local)" || item.Label() != StatusResolved {
+		t.Errorf("Node = %q, Label = %q", item.Node(), item.Label())
+	}
+}
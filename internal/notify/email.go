@@ -0,0 +1,131 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	config "system-collector/configs"
+)
+
+// emailChannel은 SMTP로 메시지를 보내는 채널입니다
+type emailChannel struct {
+	name     string
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+	to       []string
+	startTLS bool
+	tmpl     *templates
+	timeout  time.Duration
+}
+
+func newEmailChannel(cfg config.NotifyChannel, tmpl *templates, timeout time.Duration) *emailChannel {
+	return &emailChannel{
+		name:     cfg.Name,
+		host:     cfg.SMTP.Host,
+		port:     cfg.SMTP.Port,
+		username: cfg.SMTP.Username,
+		password: cfg.SMTP.Password,
+		from:     cfg.SMTP.From,
+		to:       cfg.SMTP.To,
+		startTLS: cfg.SMTP.StartTLS,
+		tmpl:     tmpl,
+		timeout:  timeout,
+	}
+}
+
+// Name은 채널 이름을 반환합니다
+func (c *emailChannel) Name() string {
+	return c.name
+}
+
+// Send는 메시지를 메일로 보냅니다. 서버가 STARTTLS를 지원하면 암호화 연결로 전환합니다.
+func (c *emailChannel) Send(ctx context.Context, msg *Message) error {
+	title, text, err := c.tmpl.render(msg)
+	if err != nil {
+		return &permanentError{err}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(c.host, strconv.Itoa(c.port)))
+	if err != nil {
+		return fmt.Errorf("SMTP 서버 연결 실패: %v", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, c.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("SMTP 세션 시작 실패: %v", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: c.host}); err != nil {
+			return fmt.Errorf("SMTP STARTTLS 실패: %v", err)
+		}
+	} else if c.startTLS {
+		return &permanentError{fmt.Errorf("SMTP 서버가 STARTTLS를 지원하지 않습니다")}
+	}
+
+	if c.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", c.username, c.password, c.host)); err != nil {
+			return &permanentError{fmt.Errorf("SMTP 인증 실패: %v", err)}
+		}
+	}
+	if err := client.Mail(c.from); err != nil {
+		return fmt.Errorf("SMTP MAIL FROM 실패: %v", err)
+	}
+	for _, to := range c.to {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("SMTP RCPT TO %s 실패: %v", to, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA 실패: %v", err)
+	}
+	if _, err := w.Write(c.buildMail(title, text)); err != nil {
+		return fmt.Errorf("메일 본문 전송 실패: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("메일 전송 실패: %v", err)
+	}
+	return client.Quit()
+}
+
+// buildMail은 UTF-8 텍스트 메일을 만듭니다. 본문은 base64로 인코딩합니다.
+func (c *emailChannel) buildMail(subject, body string) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + c.from + "\r\n")
+	b.WriteString("To: " + strings.Join(c.to, ", ") + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(body))
+	for len(encoded) > 76 {
+		b.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	b.WriteString(encoded + "\r\n")
+	return []byte(b.String())
+}
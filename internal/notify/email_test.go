@@ -0,0 +1,180 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"mime"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+
+	config "system-collector/configs"
+)
+
+// smtpMail은 가짜 SMTP 서버가 받은 메일입니다
+type smtpMail struct {
+	from string
+	to   []string
+	data string
+}
+
+// fakeSMTP는 STARTTLS와 인증 없이 메일 한 통씩 받는 SMTP 서버를 시작하고 주소와 받은 메일 채널을 반환합니다
+func fakeSMTP(t *testing.T) (string, int, chan smtpMail) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("SMTP 리스너 생성 실패: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	mails := make(chan smtpMail, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveSMTP(conn, mails)
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	p, _ := strconv.Atoi(port)
+	return host, p, mails
+}
+
+func serveSMTP(conn net.Conn, mails chan smtpMail) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 fake ESMTP")
+
+	var mail smtpMail
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			tp.PrintfLine("250 fake")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			mail.from = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+			tp.PrintfLine("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			mail.to = append(mail.to, strings.Trim(line[len("RCPT TO:"):], "<> "))
+			tp.PrintfLine("250 OK")
+		case cmd == "DATA":
+			tp.PrintfLine("354 go ahead")
+			// ReadDotBytes는 CRLF를 LF로 바꿔 반환
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			mail.data = string(data)
+			mails <- mail
+			mail = smtpMail{}
+			tp.PrintfLine("250 queued")
+		case cmd == "QUIT":
+			tp.PrintfLine("221 bye")
+			return
+		default:
+			tp.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+func emailConfig(host string, port int) config.NotifyChannel {
+	cfg := config.NotifyChannel{Name: "mail", Type: "email"}
+	cfg.SMTP.Host = host
+	cfg.SMTP.Port = port
+	cfg.SMTP.From = "collector@example.com"
+	cfg.SMTP.To = []string{"ops@example.com", "dev@example.com"}
+	return cfg
+}
+
+func TestEmailSend(t *testing.T) {
+	host, port, mails := fakeSMTP(t)
+	channel, err := newChannel(emailConfig(host, port))
+	if err != nil {
+		t.Fatalf("newChannel: %v", err)
+	}
+	if err := channel.Send(context.Background(), testMessage()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	mail := <-mails
+	if mail.from != "collector@example.com" || strings.Join(mail.to, ",") != "ops@example.com,dev@example.com" {
+		t.Errorf("봉투 = %s -> %v", mail.from, mail.to)
+	}
+
+	header, body, ok := strings.Cut(mail.data, "\n\n")
+	if !ok {
+		t.Fatalf("헤더와 본문 구분이 없음: %q", mail.data)
+	}
+	reader := textproto.NewReader(bufio.NewReader(strings.NewReader(header + "\n\n")))
+	h, err := reader.ReadMIMEHeader()
+	if err != nil {
+		t.Fatalf("헤더 파싱 실패: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(h.Get("Subject"))
+	if err != nil || !strings.HasPrefix(subject, "[FIRING] node_id=node-1 - 1건") {
+		t.Errorf("Subject = %q, %v", subject, err)
+	}
+	if h.Get("Content-Transfer-Encoding") != "base64" || !strings.Contains(h.Get("Content-Type"), "charset=UTF-8") {
+		t.Errorf("헤더 = %v", h)
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(body), "\n") {
+		if len(line) > 76 {
+			t.Errorf("본문 줄 길이 %d, 76자 이하 기대", len(line))
+		}
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(strings.TrimSpace(body), "\n", ""))
+	if err != nil {
+		t.Fatalf("본문 base64 디코딩 실패: %v", err)
+	}
+	if !strings.Contains(string(decoded), "CPU 사용률이 높습니다") {
+		t.Errorf("본문 = %q", decoded)
+	}
+}
+
+func TestEmailRequireStartTLS(t *testing.T) {
+	host, port, mails := fakeSMTP(t)
+	cfg := emailConfig(host, port)
+	cfg.SMTP.StartTLS = true
+	channel, err := newChannel(cfg)
+	if err != nil {
+		t.Fatalf("newChannel: %v", err)
+	}
+
+	err = channel.Send(context.Background(), testMessage())
+	if err == nil || !isPermanent(err) {
+		t.Fatalf("STARTTLS 미지원 서버 오류 = %v, 재시도하지 않는 오류 기대", err)
+	}
+	select {
+	case mail := <-mails:
+		t.Errorf("암호화 없이 메일을 보냄: %v", mail)
+	default:
+	}
+}
+
+func TestEmailConnectionError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("리스너 생성 실패: %v", err)
+	}
+	addr := ln.Addr().(*net.TCPAddr)
+	ln.Close()
+
+	channel, err := newChannel(emailConfig("127.0.0.1", addr.Port))
+	if err != nil {
+		t.Fatalf("newChannel: %v", err)
+	}
+	if err := channel.Send(context.Background(), testMessage()); err == nil || isPermanent(err) {
+		t.Errorf("연결 실패 오류 = %v, 재시도할 오류 기대", err)
+	}
+}
@@ -0,0 +1,47 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"system-collector/internal/admin"
+	"system-collector/internal/httpapi"
+)
+
+// Handler는 알림 채널 관리 API를 제공합니다
+type Handler struct {
+	dispatcher *Dispatcher
+}
+
+// NewHandler는 알림 채널 관리 핸들러를 생성합니다
+func NewHandler(dispatcher *Dispatcher) *Handler {
+	return &Handler{dispatcher: dispatcher}
+}
+
+// RegisterRoutes는 핸들러를 mux에 등록합니다
+func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /admin/notify/test", admin.RequireAdmin(h.handleTest))
+}
+
+// testRequest는 시험 메시지 요청입니다. Channel이 비어 있으면 모든 채널로 보냅니다.
+type testRequest struct {
+	Channel string `json:"channel"`
+}
+
+// handleTest는 채널로 시험 메시지를 보내고 채널별 결과("ok" 또는 오류)를 반환합니다
+func (h *Handler) handleTest(w http.ResponseWriter, r *http.Request) {
+	var req testRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		httpapi.WriteError(w, http.StatusBadRequest, "요청 본문이 올바른 JSON이 아닙니다")
+		return
+	}
+
+	results, err := h.dispatcher.Test(r.Context(), req.Channel)
+	if err != nil {
+		httpapi.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	httpapi.WriteJSON(w, http.StatusOK, results)
+}
@@ -0,0 +1,196 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"system-collector/pkg/models"
+)
+
+// 메시지 상태
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+)
+
+// Item은 메시지에 담기는 알림 또는 이벤트 하나입니다
+type Item struct {
+	Type string `json:"type"`
+	// State는 알림 상태(firing, resolved)이며, 알림이 아닌 이벤트는 비어 있습니다
+	State    string            `json:"state,omitempty"`
+	Severity string            `json:"severity"`
+	NodeID   string            `json:"node_id"`
+	NodeName string            `json:"node_name,omitempty"`
+	Hostname string            `json:"hostname,omitempty"`
+	Rule     string            `json:"rule,omitempty"`
+	Metric   string            `json:"metric,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Value    *float64          `json:"value,omitempty"`
+	Message  string            `json:"message"`
+	Time     time.Time         `json:"time"`
+}
+
+// Node는 노드 표시 이름을 반환합니다 (이름, 호스트명이 다르면 괄호로 함께 표시)
+func (i Item) Node() string {
+	name := i.NodeName
+	if name == "" {
+		name = i.NodeID
+	}
+	if i.Hostname != "" && i.Hostname != name {
+		return fmt.Sprintf("%s (%s)", name, i.Hostname)
+	}
+	return name
+}
+
+// Label은 항목의 상태 표시입니다. 알림은 상태, 이벤트는 심각도를 사용합니다.
+func (i Item) Label() string {
+	if i.State != "" {
+		return i.State
+	}
+	return i.Severity
+}
+
+// Message는 채널로 보내는 메시지 하나입니다. 같은 그룹의 알림과 이벤트를 묶습니다.
+type Message struct {
+	// Group은 묶은 기준과 값입니다 (예: node_id=node-1,severity=critical)
+	Group string `json:"group"`
+	// Status는 발생 중인 알림이나 이벤트가 하나라도 있으면 firing, 모두 해소 알림이면 resolved입니다
+	Status string `json:"status"`
+	// Severity는 항목 중 가장 높은 심각도입니다
+	Severity string `json:"severity"`
+	Items    []Item `json:"items"`
+}
+
+// severityRank는 심각도의 순서입니다
+func severityRank(severity string) int {
+	switch severity {
+	case models.SeverityCritical:
+		return 2
+	case models.SeverityWarning:
+		return 1
+	}
+	return 0
+}
+
+// newMessage는 항목을 시간 순으로 정렬해 메시지를 만듭니다
+func newMessage(group string, items []Item) *Message {
+	sort.SliceStable(items, func(a, b int) bool { return items[a].Time.Before(items[b].Time) })
+
+	msg := &Message{Group: group, Status: StatusResolved, Severity: models.SeverityInfo, Items: items}
+	for _, item := range items {
+		if item.State != StatusResolved {
+			msg.Status = StatusFiring
+		}
+		if severityRank(item.Severity) > severityRank(msg.Severity) {
+			msg.Severity = item.Severity
+		}
+	}
+	return msg
+}
+
+// itemFromEvent는 이벤트를 메시지 항목으로 변환합니다.
+// 알림 이벤트의 Data에는 알림 엔진이 넣은 rule, metric, value, labels, node_name, hostname이 들어 있습니다.
+func itemFromEvent(event models.Event) Item {
+	item := Item{
+		Type:     event.Type,
+		Severity: event.Severity,
+		NodeID:   event.NodeID,
+		Message:  event.Message,
+		Time:     event.CreatedAt,
+	}
+	switch event.Type {
+	case models.EventAlertFiring:
+		item.State = StatusFiring
+	case models.EventAlertResolved:
+		item.State = StatusResolved
+	}
+
+	data := event.Data
+	item.NodeName, _ = data["node_name"].(string)
+	item.Hostname, _ = data["hostname"].(string)
+	item.Rule, _ = data["rule"].(string)
+	item.Metric, _ = data["metric"].(string)
+	// 해소 이벤트는 info로 발행되므로 알림 본래의 심각도를 사용
+	if severity, ok := data["severity"].(string); ok && severity != "" {
+		item.Severity = severity
+	}
+	if labels, ok := data["labels"].(map[string]string); ok {
+		item.Labels = labels
+	}
+	if value, ok := data["value"].(float64); ok {
+		item.Value = &value
+	}
+	return item
+}
+
+// 기본 메시지 템플릿
+const (
+	defaultTitleTemplate = `[{{upper .Status}}] {{.Group}} - {{len .Items}}건{{if ne .Status "resolved"}} ({{.Severity}}){{end}}`
+	defaultTemplate      = `{{range .Items}}- [{{upper .Label}}] {{.Node}}: {{.Message}} ({{.Time.Local.Format "2006-01-02 15:04:05"}})
+{{end}}`
+)
+
+var templateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"value": func(v *float64) string {
+		if v == nil {
+			return ""
+		}
+		return fmt.Sprintf("%g", *v)
+	},
+	"join": func(labels map[string]string) string {
+		keys := make([]string, 0, len(labels))
+		for k := range labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, len(keys))
+		for i, k := range keys {
+			parts[i] = k + "=" + labels[k]
+		}
+		return strings.Join(parts, ",")
+	},
+}
+
+// templates는 채널의 제목과 본문 템플릿입니다
+type templates struct {
+	title *template.Template
+	body  *template.Template
+}
+
+// parseTemplates는 제목과 본문 템플릿을 파싱합니다. 비어 있으면 기본 템플릿을 사용합니다.
+func parseTemplates(title, body string) (*templates, error) {
+	if title == "" {
+		title = defaultTitleTemplate
+	}
+	if body == "" {
+		body = defaultTemplate
+	}
+
+	t := &templates{}
+	var err error
+	if t.title, err = template.New("title").Funcs(templateFuncs).Parse(title); err != nil {
+		return nil, fmt.Errorf("제목 템플릿 파싱 실패: %v", err)
+	}
+	if t.body, err = template.New("body").Funcs(templateFuncs).Parse(body); err != nil {
+		return nil, fmt.Errorf("본문 템플릿 파싱 실패: %v", err)
+	}
+	return t, nil
+}
+
+// render는 메시지의 제목과 본문을 만듭니다
+func (t *templates) render(msg *Message) (string, string, error) {
+	var title, body bytes.Buffer
+	if err := t.title.Execute(&title, msg); err != nil {
+		return "", "", fmt.Errorf("제목 템플릿 실행 실패: %v", err)
+	}
+	if err := t.body.Execute(&body, msg); err != nil {
+		return "", "", fmt.Errorf("본문 템플릿 실행 실패: %v", err)
+	}
+	return strings.TrimSpace(title.String()), strings.TrimSpace(body.String()), nil
+}
@@ -0,0 +1,124 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	config "system-collector/configs"
+	"system-collector/pkg/logger"
+)
+
+// HTTP 채널 유형
+const (
+	// webhookKind는 메시지 전체를 JSON으로 보냅니다
+	webhookKind = "webhook"
+	// slackKind, discordKind는 각 서비스의 incoming webhook 형식({"text"}, {"content"})으로 보냅니다
+	slackKind   = "slack"
+	discordKind = "discord"
+)
+
+// discordMaxContent는 Discord 메시지 본문의 최대 길이입니다
+const discordMaxContent = 2000
+
+// webhookChannel은 HTTP POST로 메시지를 보내는 채널입니다
+type webhookChannel struct {
+	name    string
+	kind    string
+	url     string
+	headers map[string]string
+	tmpl    *templates
+	client  *http.Client
+}
+
+func newWebhookChannel(cfg config.NotifyChannel, tmpl *templates, timeout time.Duration) *webhookChannel {
+	return &webhookChannel{
+		name:    cfg.Name,
+		kind:    cfg.Type,
+		url:     cfg.URL,
+		headers: cfg.Headers,
+		tmpl:    tmpl,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// Name은 채널 이름을 반환합니다
+func (c *webhookChannel) Name() string {
+	return c.name
+}
+
+// webhookPayload는 webhook 채널이 보내는 JSON입니다
+type webhookPayload struct {
+	*Message
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+// Send는 메시지를 채널 형식의 JSON으로 보냅니다
+func (c *webhookChannel) Send(ctx context.Context, msg *Message) error {
+	title, text, err := c.tmpl.render(msg)
+	if err != nil {
+		return &permanentError{err}
+	}
+
+	var payload interface{}
+	switch c.kind {
+	case slackKind:
+		payload = map[string]string{"text": "*" + title + "*\n" + text}
+	case discordKind:
+		payload = map[string]string{"content": truncate("**"+title+"**\n"+text, discordMaxContent)}
+	default:
+		payload = webhookPayload{Message: msg, Title: title, Text: text}
+	}
+	return postJSON(ctx, c.client, c.url, c.headers, payload)
+}
+
+// postJSON은 payload를 JSON으로 POST합니다. 4xx 응답은 429, 408을 빼고 재시도하지 않는 오류로 반환합니다.
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, payload interface{}) error {
+	sugar := logger.FromContext(ctx)
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return &permanentError{fmt.Errorf("알림 JSON 변환 실패: %v", err)}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return &permanentError{fmt.Errorf("알림 요청 생성 실패: %v", err)}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("알림 전송 실패: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		sugar.Debugw("알림 전송 성공", "status", resp.StatusCode)
+		return nil
+	}
+	bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	err = fmt.Errorf("알림 전송 실패: HTTP %d: %s", resp.StatusCode, bytes.TrimSpace(bodyBytes))
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusRequestTimeout {
+		return &permanentError{err}
+	}
+	return err
+}
+
+// truncate는 문자열을 최대 max자(rune)로 자릅니다
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
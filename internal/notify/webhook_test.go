@@ -0,0 +1,205 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	config "system-collector/configs"
+	"system-collector/pkg/models"
+)
+
+// testMessage는 발생 중인 알림 하나가 담긴 메시지를 만듭니다
+func testMessage() *Message {
+	value := 95.5
+	return newMessage("node_id=node-1", []Item{{
+		Type:     models.EventAlertFiring,
+		State:    StatusFiring,
+		Severity: models.SeverityCritical,
+		NodeID:   "node-1",
+		NodeName: "web-1",
+		Rule:     "high-cpu",
+		Metric:   "cpu.usage",
+		Value:    &value,
+		Message:  "CPU 사용률이 높습니다",
+		Time:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}})
+}
+
+// capture는 받은 요청 본문과 헤더를 보관하는 테스트 서버를 시작합니다
+func capture(t *testing.T, status int) (*httptest.Server, chan *http.Request, chan []byte) {
+	t.Helper()
+	reqs := make(chan *http.Request, 10)
+	bodies := make(chan []byte, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		reqs <- r
+		bodies <- body
+		w.WriteHeader(status)
+		io.WriteString(w, "response body")
+	}))
+	t.Cleanup(srv.Close)
+	return srv, reqs, bodies
+}
+
+func TestWebhookPayload(t *testing.T) {
+	tests := []struct {
+		kind  string
+		check func(t *testing.T, body map[string]interface{})
+	}{
+		{
+			kind: webhookKind,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if body["group"] != "node_id=node-1" || body["status"] != StatusFiring || body["severity"] != models.SeverityCritical {
+					t.Errorf("메시지 필드 = %v", body)
+				}
+				if !strings.HasPrefix(body["title"].(string), "[FIRING] node_id=node-1 - 1건") {
+					t.Errorf("title = %q", body["title"])
+				}
+				items := body["items"].([]interface{})
+				if len(items) != 1 || items[0].(map[string]interface{})["rule"] != "high-cpu" {
+					t.Errorf("items = %v", items)
+				}
+			},
+		},
+		{
+			kind: slackKind,
+			check: func(t *testing.T, body map[string]interface{}) {
+				text, _ := body["text"].(string)
+				if len(body) != 1 || !strings.HasPrefix(text, "*[FIRING] node_id=node-1") || !strings.Contains(text, "CPU 사용률이 높습니다") {
+					t.Errorf("Slack 본문 = %v", body)
+				}
+			},
+		},
+		{
+			kind: discordKind,
+			check: func(t *testing.T, body map[string]interface{}) {
+				content, _ := body["content"].(string)
+				if len(body) != 1 || !strings.HasPrefix(content, "**[FIRING] node_id=node-1") || !strings.Contains(content, "web-1") {
+					t.Errorf("Discord 본문 = %v", body)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			srv, reqs, bodies := capture(t, http.StatusOK)
+			channel, err := newChannel(config.NotifyChannel{
+				Name:    "test",
+				Type:    tt.kind,
+				URL:     srv.URL,
+				Headers: map[string]string{"X-Token": "secret"},
+			})
+			if err != nil {
+				t.Fatalf("newChannel: %v", err)
+			}
+			if err := channel.Send(context.Background(), testMessage()); err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+
+			req := <-reqs
+			if req.Method != http.MethodPost || req.Header.Get("Content-Type") != "application/json" || req.Header.Get("X-Token") != "secret" {
+				t.Errorf("요청 = %s, Content-Type %q, X-Token %q", req.Method, req.Header.Get("Content-Type"), req.Header.Get("X-Token"))
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(<-bodies, &body); err != nil {
+				t.Fatalf("본문 JSON 파싱 실패: %v", err)
+			}
+			tt.check(t, body)
+		})
+	}
+}
+
+func TestPostJSONStatus(t *testing.T) {
+	tests := []struct {
+		status    int
+		wantErr   bool
+		permanent bool
+	}{
+		{status: http.StatusOK},
+		{status: http.StatusNoContent},
+		{status: http.StatusBadRequest, wantErr: true, permanent: true},
+		{status: http.StatusUnauthorized, wantErr: true, permanent: true},
+		{status: http.StatusRequestTimeout, wantErr: true},
+		{status: http.StatusTooManyRequests, wantErr: true},
+		{status: http.StatusInternalServerError, wantErr: true},
+		{status: http.StatusBadGateway, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			srv, _, _ := capture(t, tt.status)
+			err := postJSON(context.Background(), srv.Client(), srv.URL, nil, map[string]string{"a": "b"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("오류 = %v, 오류 기대 %v", err, tt.wantErr)
+			}
+			if err != nil && isPermanent(err) != tt.permanent {
+				t.Errorf("재시도 불가 = %v, 기대 %v (%v)", isPermanent(err), tt.permanent, err)
+			}
+			if err != nil && !strings.Contains(err.Error(), "response body") {
+				t.Errorf("오류에 응답 본문이 없음: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostJSONConnectionError(t *testing.T) {
+	srv, _, _ := capture(t, http.StatusOK)
+	url := srv.URL
+	srv.Close()
+
+	err := postJSON(context.Background(), http.DefaultClient, url, nil, map[string]string{})
+	if err == nil || isPermanent(err) {
+		t.Fatalf("연결 실패 오류 = %v, 재시도할 오류 기대", err)
+	}
+}
+
+func TestDiscordTruncate(t *testing.T) {
+	tests := []struct {
+		in   string
+		max  int
+		want string
+	}{
+		{in: "짧은 글", max: 10, want: "짧은 글"},
+		{in: "가나다라마", max: 5, want: "가나다라마"},
+		{in: "가나다라마바", max: 5, want: "가나다라…"},
+	}
+	for _, tt := range tests {
+		if got := truncate(tt.in, tt.max); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, 기대 %q", tt.in, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestNewChannelErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.NotifyChannel
+	}{
+		{name: "알 수 없는 유형", cfg: config.NotifyChannel{Name: "x", Type: "sms"}},
+		{name: "잘못된 제목 템플릿", cfg: config.NotifyChannel{Name: "x", Type: webhookKind, TitleTemplate: "{{.Group"}},
+		{name: "잘못된 본문 템플릿", cfg: config.NotifyChannel{Name: "x", Type: slackKind, Template: "{{range}}"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newChannel(tt.cfg); err == nil {
+				t.Error("오류가 없음")
+			}
+		})
+	}
+}
+
+func TestTemplateExecutionErrorIsPermanent(t *testing.T) {
+	srv, _, _ := capture(t, http.StatusOK)
+	channel, err := newChannel(config.NotifyChannel{Name: "x", Type: webhookKind, URL: srv.URL, Template: "{{.Missing}}"})
+	if err != nil {
+		t.Fatalf("newChannel: %v", err)
+	}
+	if err := channel.Send(context.Background(), testMessage()); err == nil || !isPermanent(err) {
+		t.Errorf("템플릿 실행 오류 = %v, 재시도하지 않는 오류 기대", err)
+	}
+}
@@ -0,0 +1,176 @@
+package processes
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"system-collector/internal/admin"
+	"system-collector/internal/httpapi"
+	"system-collector/pkg/models"
+)
+
+// maxPatternLength는 name 쿼리 파라미터 정규식의 최대 길이입니다
+const maxPatternLength = 256
+
+// Handler는 프로세스 트리와 집계 API를 제공합니다. 명령어 인자를 그대로 반환하므로 관리 API와 같은 인증을 사용합니다.
+// 프로세스 목록은 노드를 처리하는 인스턴스의 메모리에만 있으므로 클러스터 모드에서는 해당 인스턴스에 조회해야 합니다.
+type Handler struct {
+	table *Table
+}
+
+// NewHandler는 프로세스 핸들러를 생성합니다
+func NewHandler(table *Table) *Handler {
+	return &Handler{table: table}
+}
+
+// RegisterRoutes는 핸들러를 mux에 등록합니다
+func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/nodes/{nodeID}/processes", admin.RequireAdmin(h.handleProcesses))
+	mux.HandleFunc("GET /api/nodes/{nodeID}/processes/summary", admin.RequireAdmin(h.handleSummary))
+}
+
+// processesResponse는 프로세스 조회 응답입니다
+type processesResponse struct {
+	NodeID    string    `json:"node_id"`
+	Hostname  string    `json:"hostname"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Total     int       `json:"total"`
+	Matched   int       `json:"matched"`
+	// Processes는 format=tree이면 []*models.ProcessNode, format=flat이면 []models.ProcessInfo입니다
+	Processes interface{} `json:"processes"`
+}
+
+// handleProcesses는 노드의 마지막 프로세스 목록을 트리(기본값) 또는 CPU 사용률 순 목록으로 반환합니다.
+// user, name(정규식), min_cpu, min_memory(바이트), format(tree|flat) 쿼리 파라미터를 지원합니다.
+// 트리에는 조건에 맞는 프로세스와 그 상위 프로세스가 포함됩니다.
+func (h *Handler) handleProcesses(w http.ResponseWriter, r *http.Request) {
+	snap, filter, ok := h.lookup(w, r)
+	if !ok {
+		return
+	}
+
+	resp := processesResponse{
+		NodeID:    snap.NodeID,
+		Hostname:  snap.Hostname,
+		UpdatedAt: snap.UpdatedAt,
+		Total:     len(snap.Processes),
+	}
+	matched := []models.ProcessInfo{}
+	for _, p := range snap.Processes {
+		if filter.Match(p) {
+			matched = append(matched, p)
+		}
+	}
+	resp.Matched = len(matched)
+
+	switch r.URL.Query().Get("format") {
+	case "", "tree":
+		roots := BuildTree(snap.Processes, filter)
+		if roots == nil {
+			roots = []*models.ProcessNode{}
+		}
+		resp.Processes = roots
+	case "flat":
+		sort.SliceStable(matched, func(i, j int) bool {
+			if matched[i].CPUUsage != matched[j].CPUUsage {
+				return matched[i].CPUUsage > matched[j].CPUUsage
+			}
+			return matched[i].PID < matched[j].PID
+		})
+		resp.Processes = matched
+	default:
+		httpapi.WriteError(w, http.StatusBadRequest, "format은 tree 또는 flat이어야 합니다")
+		return
+	}
+	httpapi.WriteJSON(w, http.StatusOK, resp)
+}
+
+// handleSummary는 조건에 맞는 프로세스를 이름 또는 사용자별로 합산해 반환합니다.
+// by(name|user, 기본값 name)와 sort(cpu|memory|count, 기본값 cpu) 쿼리 파라미터와 handleProcesses의 조건을 지원합니다.
+func (h *Handler) handleSummary(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	by := q.Get("by")
+	switch by {
+	case "":
+		by = "name"
+	case "name", "user":
+	default:
+		httpapi.WriteError(w, http.StatusBadRequest, "by는 name 또는 user여야 합니다")
+		return
+	}
+
+	var less func(a, b models.ProcessGroup) bool
+	switch q.Get("sort") {
+	case "", "cpu":
+		less = func(a, b models.ProcessGroup) bool { return a.CPUUsage > b.CPUUsage }
+	case "memory":
+		less = func(a, b models.ProcessGroup) bool { return a.MemoryRSS > b.MemoryRSS }
+	case "count":
+		less = func(a, b models.ProcessGroup) bool { return a.Count > b.Count }
+	default:
+		httpapi.WriteError(w, http.StatusBadRequest, "sort는 cpu, memory, count 중 하나여야 합니다")
+		return
+	}
+
+	snap, filter, ok := h.lookup(w, r)
+	if !ok {
+		return
+	}
+
+	groups := Aggregate(snap.Processes, filter, by)
+	sort.Slice(groups, func(i, j int) bool {
+		if less(groups[i], groups[j]) {
+			return true
+		}
+		if less(groups[j], groups[i]) {
+			return false
+		}
+		return groups[i].Key < groups[j].Key
+	})
+	httpapi.WriteJSON(w, http.StatusOK, groups)
+}
+
+// lookup은 쿼리 파라미터로 조건을 만들고 노드의 프로세스 목록을 찾습니다.
+// 실패하면 오류 응답을 쓰고 false를 반환합니다.
+func (h *Handler) lookup(w http.ResponseWriter, r *http.Request) (Snapshot, Filter, bool) {
+	q := r.URL.Query()
+	filter := Filter{User: q.Get("user")}
+	if s := q.Get("name"); s != "" {
+		if len(s) > maxPatternLength {
+			httpapi.WriteError(w, http.StatusBadRequest, "name 정규식이 너무 깁니다")
+			return Snapshot{}, Filter{}, false
+		}
+		re, err := regexp.Compile(s)
+		if err != nil {
+			httpapi.WriteError(w, http.StatusBadRequest, "name이 올바른 정규식이 아닙니다")
+			return Snapshot{}, Filter{}, false
+		}
+		filter.Name = re
+	}
+	if s := q.Get("min_cpu"); s != "" {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || v < 0 {
+			httpapi.WriteError(w, http.StatusBadRequest, "min_cpu는 0 이상의 숫자여야 합니다")
+			return Snapshot{}, Filter{}, false
+		}
+		filter.MinCPU = v
+	}
+	if s := q.Get("min_memory"); s != "" {
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || v < 0 {
+			httpapi.WriteError(w, http.StatusBadRequest, "min_memory는 0 이상의 정수(바이트)여야 합니다")
+			return Snapshot{}, Filter{}, false
+		}
+		filter.MinMemory = v
+	}
+
+	snap, ok := h.table.Get(r.PathValue("nodeID"))
+	if !ok {
+		httpapi.WriteError(w, http.StatusNotFound, "프로세스 목록이 없습니다")
+		return Snapshot{}, Filter{}, false
+	}
+	return snap, filter, true
+}
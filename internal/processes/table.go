@@ -0,0 +1,73 @@
+package processes
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"system-collector/pkg/logger"
+	"system-collector/pkg/models"
+)
+
+// Snapshot은 노드에서 마지막으로 수신한 프로세스 목록입니다
+type Snapshot struct {
+	NodeID    string
+	Hostname  string
+	UpdatedAt time.Time
+	Processes []models.ProcessInfo
+}
+
+// Table은 수집 큐의 Sink로 동작하며 노드별로 마지막 프로세스 목록을 메모리에 보관합니다.
+// InfluxDB에는 프로세스가 개별 포인트로 저장되어 부모-자식 관계를 조회하기 어려우므로
+// 트리와 집계 API는 이 목록을 사용합니다.
+type Table struct {
+	mu    sync.RWMutex
+	nodes map[string]Snapshot
+}
+
+// NewTable은 프로세스 테이블을 생성합니다
+func NewTable() *Table {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("프로세스 테이블 초기화 중")
+
+	return &Table{nodes: make(map[string]Snapshot)}
+}
+
+// Name은 Sink 이름을 반환합니다
+func (t *Table) Name() string {
+	return "processes"
+}
+
+// Write는 노드의 프로세스 목록을 교체합니다. 프로세스 목록이 비어 있는 메트릭스는 건너뜁니다.
+// 메트릭스는 다른 Sink와 공유하므로 목록을 수정하지 않고 참조만 보관합니다.
+func (t *Table) Write(ctx context.Context, metrics *models.SystemMetrics) error {
+	if len(metrics.Processes) == 0 {
+		return nil
+	}
+
+	t.mu.Lock()
+	t.nodes[metrics.Key] = Snapshot{
+		NodeID:    metrics.Key,
+		Hostname:  metrics.System.Hostname,
+		UpdatedAt: time.Now(),
+		Processes: metrics.Processes,
+	}
+	t.mu.Unlock()
+	return nil
+}
+
+// Get은 노드의 마지막 프로세스 목록을 반환합니다. 이 인스턴스가 모르는 노드이면 false를 반환합니다.
+func (t *Table) Get(nodeID string) (Snapshot, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	snap, ok := t.nodes[nodeID]
+	return snap, ok
+}
+
+// Forget은 노드의 프로세스 목록을 버립니다
+func (t *Table) Forget(nodeID string) {
+	t.mu.Lock()
+	delete(t.nodes, nodeID)
+	t.mu.Unlock()
+}
@@ -0,0 +1,156 @@
+package processes
+
+import (
+	"regexp"
+	"sort"
+
+	"system-collector/pkg/models"
+)
+
+// Filter는 프로세스 조회 조건입니다. 비어 있는 조건은 적용하지 않습니다.
+type Filter struct {
+	User string
+	// Name은 프로세스 이름 또는 명령어에 적용하는 정규식입니다
+	Name      *regexp.Regexp
+	MinCPU    float64
+	MinMemory int64
+}
+
+// Match는 프로세스가 모든 조건에 맞는지 반환합니다
+func (f Filter) Match(p models.ProcessInfo) bool {
+	return (f.User == "" || p.User == f.User) &&
+		(f.Name == nil || f.Name.MatchString(p.Name) || f.Name.MatchString(p.Command)) &&
+		p.CPUUsage >= f.MinCPU &&
+		p.MemoryRSS >= f.MinMemory
+}
+
+// BuildTree는 PPID로 프로세스 트리를 만들고 루트 프로세스를 반환합니다.
+// 조건에 맞는 프로세스와 그 상위 프로세스만 포함하며, 부모가 목록에 없는 프로세스는 루트가 됩니다.
+// PID 재사용으로 부모 관계가 순환하면 순환을 끊어 루트로 만듭니다.
+// 형제 프로세스는 하위 트리의 CPU 사용률이 높은 순입니다.
+func BuildTree(list []models.ProcessInfo, filter Filter) []*models.ProcessNode {
+	byPID := make(map[int]*models.ProcessNode, len(list))
+	for _, p := range list {
+		if _, dup := byPID[p.PID]; dup {
+			continue
+		}
+		byPID[p.PID] = &models.ProcessNode{ProcessInfo: p, Matched: filter.Match(p)}
+	}
+
+	// 포함할 프로세스: 조건에 맞는 프로세스와 그 상위 프로세스
+	keep := make(map[int]bool, len(byPID))
+	for pid, n := range byPID {
+		if !n.Matched {
+			continue
+		}
+		// 이미 포함된 프로세스를 만나면 그 위는 처리된 것이므로 멈춤 (순환도 여기서 멈춤)
+		for cur := pid; !keep[cur]; {
+			keep[cur] = true
+			parent, ok := byPID[byPID[cur].PPID]
+			if !ok {
+				break
+			}
+			cur = parent.PID
+		}
+	}
+
+	pids := make([]int, 0, len(keep))
+	for pid := range keep {
+		pids = append(pids, pid)
+	}
+	sort.Ints(pids)
+
+	// 자식 -> 부모 연결 (순환하는 연결은 끊음)
+	links := make(map[int]int, len(pids))
+	for _, pid := range pids {
+		if ppid := byPID[pid].PPID; ppid != pid && keep[ppid] {
+			links[pid] = ppid
+		}
+	}
+	for _, pid := range pids {
+		seen := map[int]bool{pid: true}
+		for cur := pid; ; {
+			parent, ok := links[cur]
+			if !ok {
+				break
+			}
+			if parent == pid {
+				delete(links, pid)
+				break
+			}
+			if seen[parent] {
+				break
+			}
+			seen[parent] = true
+			cur = parent
+		}
+	}
+
+	var roots []*models.ProcessNode
+	for _, pid := range pids {
+		n := byPID[pid]
+		if parent, ok := links[pid]; ok {
+			byPID[parent].Children = append(byPID[parent].Children, n)
+		} else {
+			roots = append(roots, n)
+		}
+	}
+	for _, root := range roots {
+		sumTree(root)
+	}
+	sortNodes(roots)
+	return roots
+}
+
+// sumTree는 하위 트리의 CPU 사용률과 메모리 사용량 합계를 계산하고 자식을 정렬합니다
+func sumTree(n *models.ProcessNode) {
+	n.TreeCPUUsage = n.CPUUsage
+	n.TreeMemoryRSS = n.MemoryRSS
+	for _, c := range n.Children {
+		sumTree(c)
+		n.TreeCPUUsage += c.TreeCPUUsage
+		n.TreeMemoryRSS += c.TreeMemoryRSS
+	}
+	sortNodes(n.Children)
+}
+
+func sortNodes(nodes []*models.ProcessNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].TreeCPUUsage != nodes[j].TreeCPUUsage {
+			return nodes[i].TreeCPUUsage > nodes[j].TreeCPUUsage
+		}
+		return nodes[i].PID < nodes[j].PID
+	})
+}
+
+// Aggregate는 조건에 맞는 프로세스를 이름(by가 "user"이면 사용자)별로 합산합니다
+func Aggregate(list []models.ProcessInfo, filter Filter, by string) []models.ProcessGroup {
+	groups := make(map[string]*models.ProcessGroup)
+	for _, p := range list {
+		if !filter.Match(p) {
+			continue
+		}
+		key := p.Name
+		if by == "user" {
+			key = p.User
+		}
+		g, ok := groups[key]
+		if !ok {
+			g = &models.ProcessGroup{Key: key}
+			groups[key] = g
+		}
+		g.Count++
+		g.Threads += p.Threads
+		g.CPUUsage += p.CPUUsage
+		g.MemoryRSS += p.MemoryRSS
+		g.OpenFiles += p.OpenFiles
+		g.IOReadBytes += p.IOReadBytes
+		g.IOWriteBytes += p.IOWriteBytes
+	}
+
+	result := make([]models.ProcessGroup, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, *g)
+	}
+	return result
+}
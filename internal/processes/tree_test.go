@@ -0,0 +1,155 @@
+package processes
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"system-collector/pkg/models"
+)
+
+func proc(pid, ppid int, name string, cpu float64) models.ProcessInfo {
+	return models.ProcessInfo{PID: pid, PPID: ppid, Name: name, User: "root", CPUUsage: cpu, MemoryRSS: int64(pid) * 10}
+}
+
+// render는 트리를 "PID(자식, ...)" 형식으로 줄이며, 조건에 맞지 않아 연결용으로 들어간 프로세스는 *를 붙입니다
+func render(nodes []*models.ProcessNode) string {
+	parts := make([]string, len(nodes))
+	for i, n := range nodes {
+		s := fmt.Sprint(n.PID)
+		if !n.Matched {
+			s += "*"
+		}
+		if len(n.Children) > 0 {
+			s += "(" + render(n.Children) + ")"
+		}
+		parts[i] = s
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestBuildTree(t *testing.T) {
+	list := []models.ProcessInfo{
+		proc(1, 0, "systemd", 0.1),
+		proc(100, 1, "sshd", 0.2),
+		proc(200, 100, "bash", 0),
+		proc(300, 200, "vim", 5),
+		proc(400, 1, "nginx", 1),
+		proc(401, 400, "nginx", 20),
+		proc(402, 400, "nginx", 30),
+		proc(500, 999, "orphan", 2),
+	}
+
+	tests := []struct {
+		name   string
+		list   []models.ProcessInfo
+		filter Filter
+		want   string
+	}{
+		{
+			name: "전체 트리 (하위 트리 CPU 순)",
+			list: list,
+			want: "1(400(402 401) 100(200(300))) 500",
+		},
+		{
+			name:   "조건에 맞는 프로세스와 상위 프로세스",
+			list:   list,
+			filter: Filter{Name: regexp.MustCompile("^vim$")},
+			want:   "1*(100*(200*(300)))",
+		},
+		{
+			name:   "CPU 조건",
+			list:   list,
+			filter: Filter{MinCPU: 10},
+			want:   "1*(400*(402 401))",
+		},
+		{
+			name:   "맞는 프로세스 없음",
+			list:   list,
+			filter: Filter{User: "nobody"},
+			want:   "",
+		},
+		{
+			name: "PID 재사용으로 생긴 순환",
+			list: []models.ProcessInfo{proc(10, 20, "a", 1), proc(20, 10, "b", 2), proc(30, 10, "c", 0)},
+			want: "10(20 30)",
+		},
+		{
+			name: "자기 자신이 부모",
+			list: []models.ProcessInfo{proc(1, 1, "init", 0), proc(2, 1, "child", 0)},
+			want: "1(2)",
+		},
+		{
+			name: "중복 PID는 첫 항목만",
+			list: []models.ProcessInfo{proc(1, 0, "init", 0), proc(2, 1, "a", 0), proc(2, 0, "b", 0)},
+			want: "1(2)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := render(BuildTree(tt.list, tt.filter)); got != tt.want {
+				t.Errorf("BuildTree = %q, 기대 %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildTreeTotals(t *testing.T) {
+	roots := BuildTree([]models.ProcessInfo{
+		proc(1, 0, "init", 1),
+		proc(2, 1, "a", 2),
+		proc(3, 2, "b", 4),
+	}, Filter{})
+	if len(roots) != 1 {
+		t.Fatalf("루트 %d개, 1개 기대", len(roots))
+	}
+	if roots[0].TreeCPUUsage != 7 || roots[0].TreeMemoryRSS != 60 {
+		t.Errorf("루트 합계 CPU %v, 메모리 %d, 기대 7, 60", roots[0].TreeCPUUsage, roots[0].TreeMemoryRSS)
+	}
+	if child := roots[0].Children[0]; child.TreeCPUUsage != 6 || child.TreeMemoryRSS != 50 {
+		t.Errorf("자식 합계 CPU %v, 메모리 %d, 기대 6, 50", child.TreeCPUUsage, child.TreeMemoryRSS)
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	list := []models.ProcessInfo{
+		{PID: 1, Name: "nginx", User: "root", CPUUsage: 1, MemoryRSS: 100, Threads: 1},
+		{PID: 2, Name: "nginx", User: "www-data", CPUUsage: 10, MemoryRSS: 200, Threads: 4},
+		{PID: 3, Name: "postgres", User: "postgres", CPUUsage: 5, MemoryRSS: 1000, Threads: 2},
+	}
+	tests := []struct {
+		name   string
+		by     string
+		filter Filter
+		want   []models.ProcessGroup
+	}{
+		{
+			name: "이름별",
+			by:   "name",
+			want: []models.ProcessGroup{
+				{Key: "nginx", Count: 2, Threads: 5, CPUUsage: 11, MemoryRSS: 300},
+				{Key: "postgres", Count: 1, Threads: 2, CPUUsage: 5, MemoryRSS: 1000},
+			},
+		},
+		{
+			name:   "사용자별, 메모리 조건",
+			by:     "user",
+			filter: Filter{MinMemory: 150},
+			want: []models.ProcessGroup{
+				{Key: "postgres", Count: 1, Threads: 2, CPUUsage: 5, MemoryRSS: 1000},
+				{Key: "www-data", Count: 1, Threads: 4, CPUUsage: 10, MemoryRSS: 200},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Aggregate(list, tt.filter, tt.by)
+			sort.Slice(got, func(i, j int) bool { return got[i].Key < got[j].Key })
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Aggregate = %+v, 기대 %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"system-collector/internal/repository"
+	"system-collector/internal/storage"
+	"system-collector/pkg/logger"
+	"system-collector/pkg/models"
+
+	"github.com/lib/pq"
+)
+
+// ErrNodeOwnership은 이미 등록된 노드 ID가 다른 사용자의 obscura key로 전송되었을 때 반환됩니다
+var ErrNodeOwnership = errors.New("다른 사용자에게 등록된 노드입니다")
+
+// listenerPingInterval은 LISTEN 연결이 살아 있는지 확인하는 주기입니다
+const listenerPingInterval = 90 * time.Second
+
+// NodeRegistry는 nodes 테이블을 캐시하고 노드 등록과 소유자 확인을 담당합니다.
+// 다른 인스턴스나 관리 도구가 nodes 테이블을 바꾸면 LISTEN/NOTIFY로 해당 항목을 무효화합니다.
+type NodeRegistry struct {
+	repo *repository.NodeRepository
+
+	mu    sync.RWMutex
+	nodes map[string]*models.Node
+
+	// resolveMu는 캐시에 없는 노드의 조회/생성을 직렬화하여 같은 노드가 두 번 생성되지 않게 합니다
+	resolveMu sync.Mutex
+
+	listener *pq.Listener
+	stop     chan struct{}
+}
+
+// NewNodeRegistry는 모든 노드를 읽어 캐시를 채운 NodeRegistry를 생성합니다.
+// 초기 로드에 실패해도 빈 캐시로 시작하며, 이후 노드는 Resolve 시 DB에서 조회합니다.
+func NewNodeRegistry(repo *repository.NodeRepository) *NodeRegistry {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("노드 레지스트리 초기화 중")
+
+	r := &NodeRegistry{
+		repo:  repo,
+		nodes: make(map[string]*models.Node),
+		stop:  make(chan struct{}),
+	}
+	if err := r.Reload(); err != nil {
+		sugar.Errorw("노드 레지스트리 초기 로드 실패", "error", err)
+	}
+	return r
+}
+
+// Reload는 캐시를 비우고 nodes 테이블 전체를 다시 읽습니다
+func (r *NodeRegistry) Reload() error {
+	sugar := logger.GetCustomLogger()
+
+	nodes, err := r.repo.GetAllNodes(context.Background())
+	if err != nil {
+		return fmt.Errorf("노드 목록 조회 실패: %v", err)
+	}
+
+	cache := make(map[string]*models.Node, len(nodes))
+	for _, node := range nodes {
+		cache[node.NodeID] = node
+	}
+
+	r.mu.Lock()
+	r.nodes = cache
+	r.mu.Unlock()
+
+	sugar.Infow("노드 레지스트리 로드 완료", "count", len(cache))
+	return nil
+}
+
+// Get은 캐시된 노드의 복사본을 반환합니다
+func (r *NodeRegistry) Get(nodeID string) (models.Node, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	node, ok := r.nodes[nodeID]
+	if !ok {
+		return models.Node{}, false
+	}
+	return *node, true
+}
+
+// DisplayName은 이벤트와 알림에 표시할 노드 이름을 반환합니다. 이름이 없거나 등록되지 않은 노드는 hostname을 반환합니다.
+func (r *NodeRegistry) DisplayName(nodeID, hostname string) string {
+	if node, ok := r.Get(nodeID); ok && node.Name != "" {
+		return node.Name
+	}
+	return hostname
+}
+
+// All은 캐시된 모든 노드의 복사본을 반환합니다
+func (r *NodeRegistry) All() []models.Node {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	nodes := make([]models.Node, 0, len(r.nodes))
+	for _, node := range r.nodes {
+		nodes = append(nodes, *node)
+	}
+	return nodes
+}
+
+// Invalidate는 노드를 캐시에서 제거합니다. 다음 Resolve에서 DB를 다시 조회합니다.
+func (r *NodeRegistry) Invalidate(nodeID string) {
+	r.mu.Lock()
+	delete(r.nodes, nodeID)
+	r.mu.Unlock()
+}
+
+func (r *NodeRegistry) put(node *models.Node) {
+	r.mu.Lock()
+	r.nodes[node.NodeID] = node
+	r.mu.Unlock()
+}
+
+// Resolve는 메트릭스를 보낸 노드를 확인합니다.
+// 처음 보는 노드는 전송한 obscura key의 소유로 등록하고, 이미 등록된 노드가
+// 다른 key로 전송되면 ErrNodeOwnership을 반환합니다.
+// 추정한 서버 유형이 저장된 값과 다르면 함께 갱신합니다 (컨테이너 호스트는 유지).
+func (r *NodeRegistry) Resolve(ctx context.Context, metrics *models.SystemMetrics) (models.Node, error) {
+	node, ok := r.Get(metrics.Key)
+	if !ok {
+		loaded, err := r.load(ctx, metrics)
+		if err != nil {
+			return models.Node{}, err
+		}
+		node = *loaded
+	}
+
+	if node.ObscuraKey != metrics.USER_ID {
+		return node, ErrNodeOwnership
+	}
+
+	if serverType := nextServerType(node.ServerType, metrics); serverType != node.ServerType {
+		r.updateServerType(ctx, &node, serverType)
+	}
+	return node, nil
+}
+
+// load는 캐시에 없는 노드를 DB에서 조회하고, 없으면 새로 생성합니다
+func (r *NodeRegistry) load(ctx context.Context, metrics *models.SystemMetrics) (*models.Node, error) {
+	sugar := logger.FromContext(ctx)
+
+	r.resolveMu.Lock()
+	defer r.resolveMu.Unlock()
+
+	// 기다리는 동안 다른 고루틴이 먼저 로드했을 수 있음
+	if node, ok := r.Get(metrics.Key); ok {
+		return &node, nil
+	}
+
+	node, err := r.repo.GetNode(ctx, metrics.Key)
+	if err != nil {
+		return nil, fmt.Errorf("노드 조회 실패: %v", err)
+	}
+	if node != nil {
+		r.put(node)
+		copied := *node
+		return &copied, nil
+	}
+
+	node = &models.Node{
+		NodeID:     metrics.Key,
+		ObscuraKey: metrics.USER_ID,
+		ServerType: InferServerType(metrics),
+		ExternalIP: metrics.ExternalIP,
+		Name:       metrics.System.Hostname,
+	}
+	if err := r.repo.CreateNode(ctx, node); err != nil {
+		// 다른 인스턴스가 먼저 생성했다면 그 값을 사용
+		existing, getErr := r.repo.GetNode(ctx, metrics.Key)
+		if getErr != nil || existing == nil {
+			return nil, fmt.Errorf("노드 생성 실패: %v", err)
+		}
+		node = existing
+	} else {
+		sugar.Infow("노드 생성 성공", "nodeID", node.NodeID, "serverType", node.ServerType)
+	}
+
+	r.put(node)
+	copied := *node
+	return &copied, nil
+}
+
+func (r *NodeRegistry) updateServerType(ctx context.Context, node *models.Node, serverType string) {
+	sugar := logger.FromContext(ctx)
+
+	if err := r.repo.UpdateNodeServerType(ctx, node.NodeID, serverType); err != nil {
+		sugar.Errorw("노드 서버 유형 갱신 실패", "nodeID", node.NodeID, "error", err)
+		return
+	}
+	sugar.Infow("노드 서버 유형 변경", "nodeID", node.NodeID, "old", node.ServerType, "new", serverType)
+
+	node.ServerType = serverType
+	updated := *node
+	r.put(&updated)
+}
+
+// Listen은 newListener로 별도의 LISTEN 연결을 열어 노드 변경 알림을 구독합니다.
+// 알림을 받으면 해당 노드를 무효화하고, 연결이 끊겼다가 복구되면 전체를 다시 로드합니다.
+func (r *NodeRegistry) Listen(newListener storage.ListenerFactory) error {
+	sugar := logger.GetCustomLogger()
+
+	listener := newListener(func(ev pq.ListenerEventType, err error) {
+		switch ev {
+		case pq.ListenerEventDisconnected:
+			sugar.Errorw("노드 변경 알림 연결 끊김", "error", err)
+		case pq.ListenerEventReconnected:
+			sugar.Infow("노드 변경 알림 연결 복구")
+		case pq.ListenerEventConnectionAttemptFailed:
+			sugar.Errorw("노드 변경 알림 연결 시도 실패", "error", err)
+		}
+	})
+	if err := listener.Listen(repository.NodeChangeChannel); err != nil {
+		listener.Close()
+		return fmt.Errorf("노드 변경 채널 구독 실패: %v", err)
+	}
+	r.listener = listener
+
+	go func() {
+		ticker := time.NewTicker(listenerPingInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.stop:
+				return
+			case n, ok := <-listener.Notify:
+				if !ok {
+					return
+				}
+				// nil 알림은 재연결을 뜻하며, 그 사이의 알림을 놓쳤을 수 있음
+				if n == nil {
+					if err := r.Reload(); err != nil {
+						sugar.Errorw("노드 레지스트리 다시 로드 실패", "error", err)
+					}
+					continue
+				}
+				sugar.Debugw("노드 변경 알림 수신", "nodeID", n.Extra)
+				r.Invalidate(n.Extra)
+			case <-ticker.C:
+				go listener.Ping()
+			}
+		}
+	}()
+
+	sugar.Infow("노드 변경 알림 구독 시작", "channel", repository.NodeChangeChannel)
+	return nil
+}
+
+// Close는 변경 알림 구독을 중단합니다
+func (r *NodeRegistry) Close() {
+	close(r.stop)
+	if r.listener != nil {
+		r.listener.Close()
+	}
+}
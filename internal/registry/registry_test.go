@@ -0,0 +1,27 @@
+package registry
+
+import (
+	"testing"
+
+	"system-collector/pkg/models"
+)
+
+func TestDisplayName(t *testing.T) {
+	r := &NodeRegistry{nodes: map[string]*models.Node{
+		"named":   {NodeID: "named", Name: "web-01"},
+		"unnamed": {NodeID: "unnamed"},
+	}}
+	tests := []struct {
+		nodeID string
+		want   string
+	}{
+		{nodeID: "named", want: "web-01"},
+		{nodeID: "unnamed", want: "host-a"},
+		{nodeID: "unknown", want: "host-a"},
+	}
+	for _, tt := range tests {
+		if got := r.DisplayName(tt.nodeID, "host-a"); got != tt.want {
+			t.Errorf("DisplayName(%s) = %q, 기대 %q", tt.nodeID, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package registry
+
+import (
+	"strings"
+
+	"system-collector/pkg/models"
+)
+
+// vmMarkers는 하이퍼바이저가 노출하는 CPU/디스크 모델명의 일부입니다
+var vmMarkers = []string{
+	"qemu",
+	"kvm",
+	"vmware",
+	"virtualbox",
+	"vbox",
+	"hyper-v",
+	"virtual cpu",
+	"virtual disk",
+	"xen",
+	"amazon elastic block store",
+	"google persistentdisk",
+	"bochs",
+}
+
+// dmiMarkers는 가상 머신과 클라우드 인스턴스의 DMI 제조사/제품명 일부입니다
+var dmiMarkers = []string{
+	"virtual machine",
+	"amazon ec2",
+	"google compute engine",
+	"openstack",
+	"digitalocean",
+	"parallels",
+}
+
+// vmMACPrefixes는 하이퍼바이저가 가상 NIC에 할당하는 MAC OUI입니다
+var vmMACPrefixes = []string{
+	"52:54:00", // QEMU/KVM
+	"00:0c:29", // VMware
+	"00:50:56", // VMware
+	"00:05:69", // VMware
+	"08:00:27", // VirtualBox
+	"00:15:5d", // Hyper-V
+	"00:16:3e", // Xen
+}
+
+// InferServerType은 메트릭스로부터 서버 유형을 추정합니다.
+// 도커 컨테이너가 하나라도 보고되면 (중지된 컨테이너 포함) VM 여부와 관계없이 컨테이너 호스트로 봅니다.
+func InferServerType(metrics *models.SystemMetrics) string {
+	if len(metrics.Containers) > 0 {
+		return models.ServerTypeContainerHost
+	}
+	if isVirtualMachine(metrics) {
+		return models.ServerTypeVM
+	}
+	return models.ServerTypeBareMetal
+}
+
+// nextServerType은 저장된 유형과 메트릭스로 갱신할 서버 유형을 정합니다.
+// 컨테이너 호스트는 에이전트가 도커에 접근하지 못하거나 컨테이너를 모두 지운 순간
+// 빈 목록을 보낼 수 있으므로, 한 번 컨테이너 호스트가 되면 되돌리지 않습니다.
+func nextServerType(current string, metrics *models.SystemMetrics) string {
+	if current == models.ServerTypeContainerHost {
+		return current
+	}
+	return InferServerType(metrics)
+}
+
+func isVirtualMachine(metrics *models.SystemMetrics) bool {
+	switch strings.ToLower(metrics.System.VirtualizationRole) {
+	case "guest":
+		return true
+	case "host":
+		return false
+	}
+	if metrics.CPU.HasHypervisor {
+		return true
+	}
+	for _, dmi := range []string{metrics.System.SysVendor, metrics.System.ProductName} {
+		if containsAny(dmi, dmiMarkers) || containsMarker(dmi) {
+			return true
+		}
+	}
+
+	// 가상화 정보를 보내지 않는 에이전트는 하이퍼바이저가 노출하는 모델명과 MAC으로 판단
+	if containsMarker(metrics.CPU.Model) {
+		return true
+	}
+	for _, disk := range metrics.Disk {
+		if containsMarker(disk.ModelName) {
+			return true
+		}
+	}
+	for _, nic := range metrics.Network {
+		mac := strings.ToLower(nic.MAC)
+		for _, prefix := range vmMACPrefixes {
+			if strings.HasPrefix(mac, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsMarker(s string) bool {
+	return containsAny(s, vmMarkers)
+}
+
+func containsAny(s string, markers []string) bool {
+	s = strings.ToLower(s)
+	if s == "" {
+		return false
+	}
+	for _, marker := range markers {
+		if strings.Contains(s, marker) {
+			return true
+		}
+	}
+	return false
+}
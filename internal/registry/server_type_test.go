@@ -0,0 +1,119 @@
+package registry
+
+import (
+	"testing"
+
+	"system-collector/pkg/models"
+)
+
+func TestInferServerType(t *testing.T) {
+	tests := []struct {
+		name    string
+		metrics models.SystemMetrics
+		want    string
+	}{
+		{
+			name: "VMX/SVM이 없는 x86 물리 서버",
+			metrics: models.SystemMetrics{
+				CPU:    models.CPUMetrics{Architecture: "x86_64", Model: "Intel(R) Xeon(R) E-2236"},
+				System: models.SystemInfo{SysVendor: "Dell Inc.", ProductName: "PowerEdge R240"},
+			},
+			want: models.ServerTypeBareMetal,
+		},
+		{
+			name:    "신호가 없는 arm 서버",
+			metrics: models.SystemMetrics{CPU: models.CPUMetrics{Architecture: "aarch64"}},
+			want:    models.ServerTypeBareMetal,
+		},
+		{
+			name:    "hypervisor 플래그",
+			metrics: models.SystemMetrics{CPU: models.CPUMetrics{Architecture: "x86_64", Model: "Intel(R) Xeon(R) Platinum 8375C", HasHypervisor: true}},
+			want:    models.ServerTypeVM,
+		},
+		{
+			name:    "가상화 역할 guest",
+			metrics: models.SystemMetrics{System: models.SystemInfo{VirtualizationSystem: "kvm", VirtualizationRole: "guest"}},
+			want:    models.ServerTypeVM,
+		},
+		{
+			name: "가상화 역할 host는 모델명보다 우선",
+			metrics: models.SystemMetrics{
+				CPU:    models.CPUMetrics{Model: "QEMU Virtual CPU", HasHypervisor: true},
+				System: models.SystemInfo{VirtualizationSystem: "kvm", VirtualizationRole: "host"},
+			},
+			want: models.ServerTypeBareMetal,
+		},
+		{
+			name:    "DMI 제품명 (Hyper-V)",
+			metrics: models.SystemMetrics{System: models.SystemInfo{SysVendor: "Microsoft Corporation", ProductName: "Virtual Machine"}},
+			want:    models.ServerTypeVM,
+		},
+		{
+			name:    "DMI 제품명 (EC2)",
+			metrics: models.SystemMetrics{System: models.SystemInfo{SysVendor: "Amazon EC2", ProductName: "m5.large"}},
+			want:    models.ServerTypeVM,
+		},
+		{
+			name:    "DMI 제조사 (QEMU)",
+			metrics: models.SystemMetrics{System: models.SystemInfo{SysVendor: "QEMU", ProductName: "Standard PC (Q35 + ICH9, 2009)"}},
+			want:    models.ServerTypeVM,
+		},
+		{
+			name:    "CPU 모델명",
+			metrics: models.SystemMetrics{CPU: models.CPUMetrics{Model: "QEMU Virtual CPU version 2.5+"}},
+			want:    models.ServerTypeVM,
+		},
+		{
+			name:    "디스크 모델명",
+			metrics: models.SystemMetrics{Disk: []models.DiskMetrics{{ModelName: "Amazon Elastic Block Store"}}},
+			want:    models.ServerTypeVM,
+		},
+		{
+			name:    "NIC MAC OUI",
+			metrics: models.SystemMetrics{Network: []models.NetworkMetrics{{MAC: "52:54:00:12:34:56"}}},
+			want:    models.ServerTypeVM,
+		},
+		{
+			name: "컨테이너가 있으면 VM보다 우선",
+			metrics: models.SystemMetrics{
+				CPU:        models.CPUMetrics{HasHypervisor: true},
+				Containers: []models.DockerContainer{{Name: "web", Status: "exited"}},
+			},
+			want: models.ServerTypeContainerHost,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InferServerType(&tt.metrics); got != tt.want {
+				t.Errorf("InferServerType = %s, 기대 %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextServerType(t *testing.T) {
+	withContainer := &models.SystemMetrics{Containers: []models.DockerContainer{{Name: "web", Status: "running"}}}
+	vm := &models.SystemMetrics{CPU: models.CPUMetrics{HasHypervisor: true}}
+	bare := &models.SystemMetrics{}
+
+	tests := []struct {
+		name    string
+		current string
+		metrics *models.SystemMetrics
+		want    string
+	}{
+		{name: "새 노드", current: "", metrics: vm, want: models.ServerTypeVM},
+		{name: "컨테이너 호스트로 승격", current: models.ServerTypeVM, metrics: withContainer, want: models.ServerTypeContainerHost},
+		{name: "컨테이너 목록이 비어도 유지 (VM)", current: models.ServerTypeContainerHost, metrics: vm, want: models.ServerTypeContainerHost},
+		{name: "컨테이너 목록이 비어도 유지 (물리)", current: models.ServerTypeContainerHost, metrics: bare, want: models.ServerTypeContainerHost},
+		{name: "VM 유지", current: models.ServerTypeVM, metrics: vm, want: models.ServerTypeVM},
+		{name: "잘못 분류된 VM 정정", current: models.ServerTypeVM, metrics: bare, want: models.ServerTypeBareMetal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextServerType(tt.current, tt.metrics); got != tt.want {
+				t.Errorf("nextServerType(%q) = %s, 기대 %s", tt.current, got, tt.want)
+			}
+		})
+	}
+}
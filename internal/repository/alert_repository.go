@@ -0,0 +1,287 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"system-collector/internal/telemetry"
+	"system-collector/pkg/logger"
+	"system-collector/pkg/models"
+	"time"
+)
+
+type AlertRepository struct {
+	db *sql.DB
+}
+
+func NewAlertRepository(db *sql.DB) *AlertRepository {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("AlertRepository 초기화 중")
+
+	return &AlertRepository{
+		db: db,
+	}
+}
+
+// GetRules는 DB에 저장된 알림 규칙을 모두 조회합니다
+func (r *AlertRepository) GetRules(ctx context.Context) ([]models.AlertRule, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT id, name, expr, for_period, severity, match, user_key, node_id, server_type, enabled
+		FROM alert_rules ORDER BY id`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		telemetry.PostgresError("AlertRepository", "GetRules")
+		sugar.Errorw("알림 규칙 조회 실패", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []models.AlertRule{}
+	for rows.Next() {
+		rule := models.AlertRule{Source: models.RuleSourceDB}
+		var match []byte
+		if err := rows.Scan(&rule.ID, &rule.Name, &rule.Expr, &rule.For, &rule.Severity, &match,
+			&rule.UserKey, &rule.NodeID, &rule.ServerType, &rule.Enabled); err != nil {
+			telemetry.PostgresError("AlertRepository", "GetRules")
+			sugar.Errorw("알림 규칙 스캔 실패", "error", err)
+			return nil, err
+		}
+		if len(match) > 0 {
+			if err := json.Unmarshal(match, &rule.Match); err != nil {
+				return nil, fmt.Errorf("알림 규칙 레이블 조건 역직렬화 실패: %v", err)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+// CreateRule은 알림 규칙을 저장하고 생성된 ID를 rule.ID에 설정합니다
+func (r *AlertRepository) CreateRule(ctx context.Context, rule *models.AlertRule) error {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	sugar.Infow("알림 규칙 생성", "name", rule.Name, "expr", rule.Expr)
+
+	match, err := nullableJSON(rule.Match)
+	if err != nil {
+		return fmt.Errorf("알림 규칙 레이블 조건 직렬화 실패: %v", err)
+	}
+
+	query := `INSERT INTO alert_rules (name, expr, for_period, severity, match, user_key, node_id, server_type, enabled)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id`
+	err = r.db.QueryRowContext(ctx, query, rule.Name, rule.Expr, rule.For, rule.Severity, match,
+		rule.UserKey, rule.NodeID, rule.ServerType, rule.Enabled).Scan(&rule.ID)
+	if err != nil {
+		telemetry.PostgresError("AlertRepository", "CreateRule")
+		sugar.Errorw("알림 규칙 생성 실패", "name", rule.Name, "error", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteRule은 알림 규칙을 삭제합니다. 규칙이 없으면 false를 반환합니다.
+func (r *AlertRepository) DeleteRule(ctx context.Context, id int64) (bool, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	sugar.Infow("알림 규칙 삭제", "id", id)
+
+	res, err := r.db.ExecContext(ctx, `DELETE FROM alert_rules WHERE id = $1`, id)
+	if err != nil {
+		telemetry.PostgresError("AlertRepository", "DeleteRule")
+		sugar.Errorw("알림 규칙 삭제 실패", "id", id, "error", err)
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// SaveFiring은 발생한 알림을 저장하고 생성된 ID를 alert.ID에 설정합니다.
+// 같은 fingerprint의 해소되지 않은 알림이 이미 있으면 그 알림의 ID를 사용합니다.
+func (r *AlertRepository) SaveFiring(ctx context.Context, alert *models.Alert) error {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	labels, err := nullableJSON(alert.Labels)
+	if err != nil {
+		return fmt.Errorf("알림 레이블 직렬화 실패: %v", err)
+	}
+
+	query := `INSERT INTO alerts (fingerprint, rule_key, rule_name, node_id, labels, severity, value, message, silenced, started_at, fired_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (fingerprint) WHERE resolved_at IS NULL
+		DO UPDATE SET value = EXCLUDED.value, message = EXCLUDED.message
+		RETURNING id`
+	err = r.db.QueryRowContext(ctx, query, alert.Fingerprint, alert.RuleKey, alert.RuleName, alert.NodeID, labels,
+		alert.Severity, alert.Value, alert.Message, alert.Silenced, alert.StartedAt, alert.FiredAt).Scan(&alert.ID)
+	if err != nil {
+		telemetry.PostgresError("AlertRepository", "SaveFiring")
+		sugar.Errorw("알림 저장 실패", "fingerprint", alert.Fingerprint, "error", err)
+		return err
+	}
+	return nil
+}
+
+// Resolve는 알림을 해소 상태로 바꿉니다
+func (r *AlertRepository) Resolve(ctx context.Context, id int64, resolvedAt time.Time, value float64) error {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `UPDATE alerts SET resolved_at = $2, value = $3 WHERE id = $1 AND resolved_at IS NULL`
+	if _, err := r.db.ExecContext(ctx, query, id, resolvedAt, value); err != nil {
+		telemetry.PostgresError("AlertRepository", "Resolve")
+		sugar.Errorw("알림 해소 저장 실패", "id", id, "error", err)
+		return err
+	}
+	return nil
+}
+
+// GetFiringAlerts는 노드의 해소되지 않은 알림을 조회합니다
+func (r *AlertRepository) GetFiringAlerts(ctx context.Context, nodeID string) ([]models.Alert, error) {
+	return r.queryAlerts(ctx, "GetFiringAlerts",
+		`WHERE node_id = $1 AND resolved_at IS NULL ORDER BY fired_at`, nodeID)
+}
+
+// GetAlerts는 알림 이력을 최신순으로 조회합니다. nodeID가 비어 있으면 모든 노드를,
+// state가 firing이면 해소되지 않은 알림만, resolved면 해소된 알림만 조회합니다.
+func (r *AlertRepository) GetAlerts(ctx context.Context, nodeID, state string, limit int) ([]models.Alert, error) {
+	return r.queryAlerts(ctx, "GetAlerts",
+		`WHERE ($1 = '' OR node_id = $1)
+			AND ($2 = '' OR ($2 = 'firing' AND resolved_at IS NULL) OR ($2 = 'resolved' AND resolved_at IS NOT NULL))
+		ORDER BY fired_at DESC, id DESC
+		LIMIT $3`, nodeID, state, limit)
+}
+
+func (r *AlertRepository) queryAlerts(ctx context.Context, method, where string, args ...interface{}) ([]models.Alert, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT id, fingerprint, rule_key, rule_name, node_id, labels, severity, value, message, silenced,
+		started_at, fired_at, resolved_at
+		FROM alerts ` + where
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		telemetry.PostgresError("AlertRepository", method)
+		sugar.Errorw("알림 조회 실패", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	alerts := []models.Alert{}
+	for rows.Next() {
+		var a models.Alert
+		var labels []byte
+		var firedAt time.Time
+		var resolvedAt sql.NullTime
+		if err := rows.Scan(&a.ID, &a.Fingerprint, &a.RuleKey, &a.RuleName, &a.NodeID, &labels, &a.Severity,
+			&a.Value, &a.Message, &a.Silenced, &a.StartedAt, &firedAt, &resolvedAt); err != nil {
+			telemetry.PostgresError("AlertRepository", method)
+			sugar.Errorw("알림 스캔 실패", "error", err)
+			return nil, err
+		}
+		if len(labels) > 0 {
+			if err := json.Unmarshal(labels, &a.Labels); err != nil {
+				return nil, fmt.Errorf("알림 레이블 역직렬화 실패: %v", err)
+			}
+		}
+		a.FiredAt = &firedAt
+		a.State = models.AlertFiring
+		if resolvedAt.Valid {
+			a.ResolvedAt = &resolvedAt.Time
+			a.State = models.AlertResolved
+		}
+		alerts = append(alerts, a)
+	}
+	return alerts, rows.Err()
+}
+
+// GetSilences는 아직 끝나지 않은 사일런스를 조회합니다
+func (r *AlertRepository) GetSilences(ctx context.Context) ([]models.Silence, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT id, matchers, comment, created_by, starts_at, ends_at
+		FROM alert_silences WHERE ends_at > now() ORDER BY starts_at`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		telemetry.PostgresError("AlertRepository", "GetSilences")
+		sugar.Errorw("사일런스 조회 실패", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	silences := []models.Silence{}
+	for rows.Next() {
+		var s models.Silence
+		var matchers []byte
+		if err := rows.Scan(&s.ID, &matchers, &s.Comment, &s.CreatedBy, &s.StartsAt, &s.EndsAt); err != nil {
+			telemetry.PostgresError("AlertRepository", "GetSilences")
+			sugar.Errorw("사일런스 스캔 실패", "error", err)
+			return nil, err
+		}
+		if err := json.Unmarshal(matchers, &s.Matchers); err != nil {
+			return nil, fmt.Errorf("사일런스 조건 역직렬화 실패: %v", err)
+		}
+		silences = append(silences, s)
+	}
+	return silences, rows.Err()
+}
+
+// CreateSilence는 사일런스를 저장하고 생성된 ID를 silence.ID에 설정합니다
+func (r *AlertRepository) CreateSilence(ctx context.Context, silence *models.Silence) error {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	sugar.Infow("사일런스 생성", "matchers", silence.Matchers, "endsAt", silence.EndsAt)
+
+	matchers, err := json.Marshal(silence.Matchers)
+	if err != nil {
+		return fmt.Errorf("사일런스 조건 직렬화 실패: %v", err)
+	}
+
+	query := `INSERT INTO alert_silences (matchers, comment, created_by, starts_at, ends_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`
+	err = r.db.QueryRowContext(ctx, query, matchers, silence.Comment, silence.CreatedBy, silence.StartsAt, silence.EndsAt).Scan(&silence.ID)
+	if err != nil {
+		telemetry.PostgresError("AlertRepository", "CreateSilence")
+		sugar.Errorw("사일런스 생성 실패", "error", err)
+		return err
+	}
+	return nil
+}
+
+// ExpireSilence는 사일런스를 바로 끝냅니다. 유효한 사일런스가 없으면 false를 반환합니다.
+func (r *AlertRepository) ExpireSilence(ctx context.Context, id int64) (bool, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	sugar.Infow("사일런스 종료", "id", id)
+
+	res, err := r.db.ExecContext(ctx, `UPDATE alert_silences SET ends_at = now() WHERE id = $1 AND ends_at > now()`, id)
+	if err != nil {
+		telemetry.PostgresError("AlertRepository", "ExpireSilence")
+		sugar.Errorw("사일런스 종료 실패", "id", id, "error", err)
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// nullableJSON은 비어 있는 맵을 NULL로, 나머지는 JSON으로 변환합니다
+func nullableJSON(m map[string]string) ([]byte, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(m)
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"system-collector/internal/telemetry"
+	"system-collector/pkg/logger"
+	"system-collector/pkg/models"
+)
+
+type BaselineRepository struct {
+	db *sql.DB
+}
+
+func NewBaselineRepository(db *sql.DB) *BaselineRepository {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("BaselineRepository 초기화 중")
+
+	return &BaselineRepository{
+		db: db,
+	}
+}
+
+// GetBaselines는 노드의 지표 기준값을 시리즈 이름 순으로 조회합니다
+func (r *BaselineRepository) GetBaselines(ctx context.Context, nodeID string) ([]models.MetricBaseline, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT node_id, series, mean, variance, sample_count, hourly, updated_at
+		FROM metric_baselines WHERE node_id = $1 ORDER BY series`
+	rows, err := r.db.QueryContext(ctx, query, nodeID)
+	if err != nil {
+		telemetry.PostgresError("BaselineRepository", "GetBaselines")
+		sugar.Errorw("지표 기준값 조회 실패", "nodeID", nodeID, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	baselines := []models.MetricBaseline{}
+	for rows.Next() {
+		var b models.MetricBaseline
+		var hourly []byte
+		if err := rows.Scan(&b.NodeID, &b.Series, &b.Mean, &b.Variance, &b.Count, &hourly, &b.UpdatedAt); err != nil {
+			telemetry.PostgresError("BaselineRepository", "GetBaselines")
+			sugar.Errorw("지표 기준값 스캔 실패", "error", err)
+			return nil, err
+		}
+		if len(hourly) > 0 {
+			if err := json.Unmarshal(hourly, &b.Hourly); err != nil {
+				return nil, fmt.Errorf("시간대별 기준값 역직렬화 실패: %v", err)
+			}
+		}
+		baselines = append(baselines, b)
+	}
+	return baselines, rows.Err()
+}
+
+// SaveBaselines는 기준값을 하나의 트랜잭션으로 저장합니다
+func (r *BaselineRepository) SaveBaselines(ctx context.Context, baselines []models.MetricBaseline) error {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		telemetry.PostgresError("BaselineRepository", "SaveBaselines")
+		sugar.Errorw("트랜잭션 시작 실패", "error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	upsert := `INSERT INTO metric_baselines (node_id, series, mean, variance, sample_count, hourly, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (node_id, series) DO UPDATE SET mean = EXCLUDED.mean, variance = EXCLUDED.variance,
+			sample_count = EXCLUDED.sample_count, hourly = EXCLUDED.hourly, updated_at = EXCLUDED.updated_at`
+	for _, b := range baselines {
+		var hourly []byte
+		if len(b.Hourly) > 0 {
+			if hourly, err = json.Marshal(b.Hourly); err != nil {
+				return fmt.Errorf("시간대별 기준값 직렬화 실패: %v", err)
+			}
+		}
+		if _, err := tx.ExecContext(ctx, upsert, b.NodeID, b.Series, b.Mean, b.Variance, b.Count, hourly, b.UpdatedAt); err != nil {
+			telemetry.PostgresError("BaselineRepository", "SaveBaselines")
+			sugar.Errorw("지표 기준값 저장 실패", "nodeID", b.NodeID, "series", b.Series, "error", err)
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		telemetry.PostgresError("BaselineRepository", "SaveBaselines")
+		sugar.Errorw("트랜잭션 커밋 실패", "error", err)
+		return err
+	}
+	return nil
+}
@@ -1,13 +1,22 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"system-collector/internal/telemetry"
 	"system-collector/pkg/logger"
 	"system-collector/pkg/models"
+
+	"github.com/lib/pq"
 )
 
+// CommandNotifyChannel은 새 명령어가 등록되면 node_id를 보내는 PostgreSQL LISTEN/NOTIFY 채널입니다.
+// 알림 트리거는 마이그레이션 0003_notify_triggers에서 생성합니다.
+const CommandNotifyChannel = "node_commands"
+
 type CommandRepository struct {
-	db *sql.DB
+	db    *sql.DB
+	stmts *statements // 자주 실행하는 쿼리의 prepared statement
 }
 
 func NewCommandRepository(db *sql.DB) *CommandRepository {
@@ -15,18 +24,22 @@ func NewCommandRepository(db *sql.DB) *CommandRepository {
 	sugar.Infow("CommandRepository 초기화 중")
 
 	return &CommandRepository{
-		db: db,
+		db:    db,
+		stmts: newStatements(db),
 	}
 }
 
 // GetCommandsByNodeID 특정 노드의 명령어 조회
-func (r *CommandRepository) GetCommandsByNodeID(nodeID string) ([]models.Command, error) {
-	sugar := logger.GetCustomLogger()
+func (r *CommandRepository) GetCommandsByNodeID(ctx context.Context, nodeID string) ([]models.Command, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
 	sugar.Infow("노드의 명령어 조회 시작", "nodeID", nodeID)
 
 	query := `SELECT command_id, node_id, command_type, command_status, target FROM commands WHERE node_id = $1`
-	rows, err := r.db.Query(query, nodeID)
+	rows, err := r.stmts.query(ctx, query, nodeID)
 	if err != nil {
+		telemetry.PostgresError("CommandRepository", "GetCommandsByNodeID")
 		sugar.Errorw("명령어 조회 SQL 오류", "nodeID", nodeID, "error", err)
 		return nil, err
 	}
@@ -42,6 +55,7 @@ func (r *CommandRepository) GetCommandsByNodeID(nodeID string) ([]models.Command
 			&cmd.CommandStatus,
 			&cmd.Target,
 		); err != nil {
+			telemetry.PostgresError("CommandRepository", "GetCommandsByNodeID")
 			sugar.Errorw("명령어 데이터 스캔 오류", "error", err)
 			return nil, err
 		}
@@ -53,13 +67,16 @@ func (r *CommandRepository) GetCommandsByNodeID(nodeID string) ([]models.Command
 }
 
 // deleteCommandsByNodeID 특정 노드의 모든 명령어 삭제
-func (r *CommandRepository) DeleteCommandsByNodeID(nodeID string) error {
-	sugar := logger.GetCustomLogger()
+func (r *CommandRepository) DeleteCommandsByNodeID(ctx context.Context, nodeID string) error {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
 	sugar.Infow("노드의 명령어 삭제 시작", "nodeID", nodeID)
 
 	query := `DELETE FROM commands WHERE node_id = $1`
-	_, err := r.db.Exec(query, nodeID)
+	_, err := r.db.ExecContext(ctx, query, nodeID)
 	if err != nil {
+		telemetry.PostgresError("CommandRepository", "DeleteCommandsByNodeID")
 		sugar.Errorw("명령어 삭제 SQL 오류", "nodeID", nodeID, "error", err)
 		return err
 	}
@@ -67,3 +84,20 @@ func (r *CommandRepository) DeleteCommandsByNodeID(nodeID string) error {
 	sugar.Infow("노드의 명령어 삭제 완료", "nodeID", nodeID)
 	return nil
 }
+
+// DeleteCommands는 전달을 마친 명령어를 삭제합니다
+func (r *CommandRepository) DeleteCommands(ctx context.Context, nodeID string, commandIDs []int) error {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	sugar.Infow("전달한 명령어 삭제 시작", "nodeID", nodeID, "commandIDs", commandIDs)
+
+	query := `DELETE FROM commands WHERE node_id = $1 AND command_id = ANY($2)`
+	_, err := r.db.ExecContext(ctx, query, nodeID, pq.Array(commandIDs))
+	if err != nil {
+		telemetry.PostgresError("CommandRepository", "DeleteCommands")
+		sugar.Errorw("명령어 삭제 SQL 오류", "nodeID", nodeID, "error", err)
+		return err
+	}
+	return nil
+}
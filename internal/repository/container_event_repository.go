@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"system-collector/internal/telemetry"
+	"system-collector/pkg/logger"
+	"system-collector/pkg/models"
+	"time"
+)
+
+type ContainerEventRepository struct {
+	db *sql.DB
+}
+
+func NewContainerEventRepository(db *sql.DB) *ContainerEventRepository {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("ContainerEventRepository 초기화 중")
+
+	return &ContainerEventRepository{
+		db: db,
+	}
+}
+
+// ContainerEventFilter는 컨테이너 이벤트 조회 조건입니다. 비어 있는 조건은 사용하지 않습니다.
+type ContainerEventFilter struct {
+	NodeID        string
+	ContainerName string
+	Type          string
+	Since         time.Time
+	Limit         int
+}
+
+// SaveContainerEvents는 컨테이너 이벤트를 하나의 트랜잭션으로 저장하고 생성된 ID를 설정합니다
+func (r *ContainerEventRepository) SaveContainerEvents(ctx context.Context, events []models.ContainerEvent) error {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		telemetry.PostgresError("ContainerEventRepository", "SaveContainerEvents")
+		sugar.Errorw("트랜잭션 시작 실패", "error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	insert := `INSERT INTO container_events (node_id, container_name, container_id, image, type, severity,
+			old_value, new_value, restart_delta, message, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`
+	for i := range events {
+		e := &events[i]
+		err := tx.QueryRowContext(ctx, insert, e.NodeID, e.ContainerName, e.ContainerID, e.Image, e.Type, e.Severity,
+			e.OldValue, e.NewValue, e.RestartDelta, e.Message, e.CreatedAt).Scan(&e.ID)
+		if err != nil {
+			telemetry.PostgresError("ContainerEventRepository", "SaveContainerEvents")
+			sugar.Errorw("컨테이너 이벤트 저장 실패", "nodeID", e.NodeID, "container", e.ContainerName, "type", e.Type, "error", err)
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		telemetry.PostgresError("ContainerEventRepository", "SaveContainerEvents")
+		sugar.Errorw("트랜잭션 커밋 실패", "error", err)
+		return err
+	}
+	return nil
+}
+
+// GetContainerEvents는 조건에 맞는 컨테이너 이벤트를 최신순으로 조회합니다
+func (r *ContainerEventRepository) GetContainerEvents(ctx context.Context, filter ContainerEventFilter) ([]models.ContainerEvent, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var since sql.NullTime
+	if !filter.Since.IsZero() {
+		since = sql.NullTime{Time: filter.Since, Valid: true}
+	}
+	query := `SELECT id, node_id, container_name, container_id, image, type, severity, old_value, new_value,
+			restart_delta, message, created_at
+		FROM container_events
+		WHERE ($1 = '' OR node_id = $1)
+			AND ($2 = '' OR container_name = $2)
+			AND ($3 = '' OR type = $3)
+			AND ($4::timestamptz IS NULL OR created_at >= $4)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $5`
+	rows, err := r.db.QueryContext(ctx, query, filter.NodeID, filter.ContainerName, filter.Type, since, filter.Limit)
+	if err != nil {
+		telemetry.PostgresError("ContainerEventRepository", "GetContainerEvents")
+		sugar.Errorw("컨테이너 이벤트 조회 실패", "nodeID", filter.NodeID, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.ContainerEvent{}
+	for rows.Next() {
+		var e models.ContainerEvent
+		if err := rows.Scan(&e.ID, &e.NodeID, &e.ContainerName, &e.ContainerID, &e.Image, &e.Type, &e.Severity,
+			&e.OldValue, &e.NewValue, &e.RestartDelta, &e.Message, &e.CreatedAt); err != nil {
+			telemetry.PostgresError("ContainerEventRepository", "GetContainerEvents")
+			sugar.Errorw("컨테이너 이벤트 스캔 실패", "error", err)
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
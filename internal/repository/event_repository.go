@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"system-collector/internal/telemetry"
+	"system-collector/pkg/logger"
+	"system-collector/pkg/models"
+)
+
+type EventRepository struct {
+	db *sql.DB
+}
+
+func NewEventRepository(db *sql.DB) *EventRepository {
+	sugar := logger.GetCustomLogger()
+	sugar.Infof("EventRepository 초기화 중")
+
+	return &EventRepository{
+		db: db,
+	}
+}
+
+// SaveEvent는 이벤트를 저장하고 생성된 ID를 event.ID에 설정합니다
+func (r *EventRepository) SaveEvent(ctx context.Context, event *models.Event) error {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var data []byte
+	if event.Data != nil {
+		raw, err := json.Marshal(event.Data)
+		if err != nil {
+			return fmt.Errorf("이벤트 데이터 직렬화 실패: %v", err)
+		}
+		data = raw
+	}
+
+	query := `INSERT INTO node_events (node_id, type, severity, message, data, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	err := r.db.QueryRowContext(ctx, query, event.NodeID, event.Type, event.Severity, event.Message, data, event.CreatedAt).Scan(&event.ID)
+	if err != nil {
+		telemetry.PostgresError("EventRepository", "SaveEvent")
+		sugar.Errorw("이벤트 저장 실패", "nodeID", event.NodeID, "type", event.Type, "error", err)
+		return err
+	}
+	return nil
+}
+
+// GetEvents는 노드의 이벤트를 최신순으로 조회합니다.
+// eventType이 비어 있지 않으면 해당 유형만 조회합니다.
+func (r *EventRepository) GetEvents(ctx context.Context, nodeID, eventType string, limit int) ([]models.Event, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT id, node_id, type, severity, message, data, created_at
+		FROM node_events
+		WHERE node_id = $1 AND ($2 = '' OR type = $2)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3`
+	rows, err := r.db.QueryContext(ctx, query, nodeID, eventType, limit)
+	if err != nil {
+		telemetry.PostgresError("EventRepository", "GetEvents")
+		sugar.Errorw("이벤트 조회 실패", "nodeID", nodeID, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.Event{}
+	for rows.Next() {
+		var e models.Event
+		var data []byte
+		if err := rows.Scan(&e.ID, &e.NodeID, &e.Type, &e.Severity, &e.Message, &data, &e.CreatedAt); err != nil {
+			telemetry.PostgresError("EventRepository", "GetEvents")
+			sugar.Errorw("이벤트 스캔 실패", "error", err)
+			return nil, err
+		}
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &e.Data); err != nil {
+				return nil, fmt.Errorf("이벤트 데이터 역직렬화 실패: %v", err)
+			}
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"system-collector/internal/telemetry"
+	"system-collector/pkg/logger"
+	"system-collector/pkg/models"
+)
+
+type InventoryRepository struct {
+	db *sql.DB
+}
+
+func NewInventoryRepository(db *sql.DB) *InventoryRepository {
+	sugar := logger.GetCustomLogger()
+	sugar.Infof("InventoryRepository 초기화 중")
+
+	return &InventoryRepository{
+		db: db,
+	}
+}
+
+// GetInventory는 노드의 현재 인벤토리를 조회합니다. 없으면 nil을 반환합니다.
+func (r *InventoryRepository) GetInventory(ctx context.Context, nodeID string) (*models.NodeInventory, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT inventory, updated_at FROM node_inventory WHERE node_id = $1`
+	var raw []byte
+	var inv models.NodeInventory
+	err := r.db.QueryRowContext(ctx, query, nodeID).Scan(&raw, &inv.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		telemetry.PostgresError("InventoryRepository", "GetInventory")
+		sugar.Errorw("인벤토리 조회 실패", "nodeID", nodeID, "error", err)
+		return nil, err
+	}
+
+	updatedAt := inv.UpdatedAt
+	if err := json.Unmarshal(raw, &inv); err != nil {
+		return nil, fmt.Errorf("인벤토리 역직렬화 실패: %v", err)
+	}
+	inv.UpdatedAt = updatedAt
+	return &inv, nil
+}
+
+// SaveInventory는 현재 인벤토리를 저장하고 변경 이력을 하나의 트랜잭션으로 기록합니다
+func (r *InventoryRepository) SaveInventory(ctx context.Context, inv *models.NodeInventory, changes []models.InventoryChange) error {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	raw, err := json.Marshal(inv)
+	if err != nil {
+		return fmt.Errorf("인벤토리 직렬화 실패: %v", err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		telemetry.PostgresError("InventoryRepository", "SaveInventory")
+		sugar.Errorw("트랜잭션 시작 실패", "error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	upsert := `INSERT INTO node_inventory (node_id, inventory, updated_at) VALUES ($1, $2, $3)
+		ON CONFLICT (node_id) DO UPDATE SET inventory = EXCLUDED.inventory, updated_at = EXCLUDED.updated_at`
+	if _, err := tx.ExecContext(ctx, upsert, inv.NodeID, raw, inv.UpdatedAt); err != nil {
+		telemetry.PostgresError("InventoryRepository", "SaveInventory")
+		sugar.Errorw("인벤토리 저장 실패", "nodeID", inv.NodeID, "error", err)
+		return err
+	}
+
+	insert := `INSERT INTO node_inventory_changes (node_id, component, item, field, old_value, new_value, changed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	for _, c := range changes {
+		if _, err := tx.ExecContext(ctx, insert, c.NodeID, c.Component, c.Item, c.Field, c.OldValue, c.NewValue, c.ChangedAt); err != nil {
+			telemetry.PostgresError("InventoryRepository", "SaveInventory")
+			sugar.Errorw("인벤토리 변경 이력 저장 실패", "nodeID", inv.NodeID, "error", err)
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		telemetry.PostgresError("InventoryRepository", "SaveInventory")
+		sugar.Errorw("트랜잭션 커밋 실패", "error", err)
+		return err
+	}
+	return nil
+}
+
+// GetChanges는 노드의 인벤토리 변경 이력을 최신순으로 조회합니다.
+// component가 비어 있지 않으면 해당 구성 요소의 이력만 조회합니다.
+func (r *InventoryRepository) GetChanges(ctx context.Context, nodeID, component string, limit int) ([]models.InventoryChange, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT id, node_id, component, item, field, old_value, new_value, changed_at
+		FROM node_inventory_changes
+		WHERE node_id = $1 AND ($2 = '' OR component = $2)
+		ORDER BY changed_at DESC, id DESC
+		LIMIT $3`
+	rows, err := r.db.QueryContext(ctx, query, nodeID, component, limit)
+	if err != nil {
+		telemetry.PostgresError("InventoryRepository", "GetChanges")
+		sugar.Errorw("인벤토리 변경 이력 조회 실패", "nodeID", nodeID, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []models.InventoryChange{}
+	for rows.Next() {
+		var c models.InventoryChange
+		if err := rows.Scan(&c.ID, &c.NodeID, &c.Component, &c.Item, &c.Field, &c.OldValue, &c.NewValue, &c.ChangedAt); err != nil {
+			telemetry.PostgresError("InventoryRepository", "GetChanges")
+			sugar.Errorw("인벤토리 변경 이력 스캔 실패", "error", err)
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
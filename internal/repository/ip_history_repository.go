@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"system-collector/internal/telemetry"
+	"system-collector/pkg/logger"
+	"system-collector/pkg/models"
+)
+
+type IPHistoryRepository struct {
+	db *sql.DB
+}
+
+func NewIPHistoryRepository(db *sql.DB) *IPHistoryRepository {
+	sugar := logger.GetCustomLogger()
+	sugar.Infof("IPHistoryRepository 초기화 중")
+
+	return &IPHistoryRepository{
+		db: db,
+	}
+}
+
+// RecordIP는 노드의 외부 IP 사용 기록을 추가하거나 갱신합니다.
+// 이미 있는 IP면 first_seen은 유지하고 last_seen과 sample_count만 늘립니다.
+func (r *IPHistoryRepository) RecordIP(ctx context.Context, entry *models.IPHistoryEntry) error {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `INSERT INTO node_ip_history
+		(node_id, ip, first_seen, last_seen, sample_count, country_code, country_name, asn, as_org)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (node_id, ip) DO UPDATE SET
+			last_seen = GREATEST(node_ip_history.last_seen, EXCLUDED.last_seen),
+			sample_count = node_ip_history.sample_count + EXCLUDED.sample_count,
+			country_code = COALESCE(NULLIF(EXCLUDED.country_code, ''), node_ip_history.country_code),
+			country_name = COALESCE(NULLIF(EXCLUDED.country_name, ''), node_ip_history.country_name),
+			asn = COALESCE(NULLIF(EXCLUDED.asn, 0), node_ip_history.asn),
+			as_org = COALESCE(NULLIF(EXCLUDED.as_org, ''), node_ip_history.as_org)`
+	_, err := r.db.ExecContext(ctx, query, entry.NodeID, entry.IP, entry.FirstSeen, entry.LastSeen, entry.SampleCount,
+		entry.CountryCode, entry.CountryName, entry.ASN, entry.ASOrg)
+	if err != nil {
+		telemetry.PostgresError("IPHistoryRepository", "RecordIP")
+		sugar.Errorw("외부 IP 이력 저장 실패", "nodeID", entry.NodeID, "ip", entry.IP, "error", err)
+		return err
+	}
+	return nil
+}
+
+// GetHistory는 노드가 사용한 외부 IP 목록을 최근 사용 순으로 조회합니다
+func (r *IPHistoryRepository) GetHistory(ctx context.Context, nodeID string) ([]models.IPHistoryEntry, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT node_id, ip, first_seen, last_seen, sample_count, country_code, country_name, asn, as_org
+		FROM node_ip_history WHERE node_id = $1 ORDER BY last_seen DESC`
+	rows, err := r.db.QueryContext(ctx, query, nodeID)
+	if err != nil {
+		telemetry.PostgresError("IPHistoryRepository", "GetHistory")
+		sugar.Errorw("외부 IP 이력 조회 실패", "nodeID", nodeID, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []models.IPHistoryEntry{}
+	for rows.Next() {
+		var e models.IPHistoryEntry
+		if err := rows.Scan(&e.NodeID, &e.IP, &e.FirstSeen, &e.LastSeen, &e.SampleCount,
+			&e.CountryCode, &e.CountryName, &e.ASN, &e.ASOrg); err != nil {
+			telemetry.PostgresError("IPHistoryRepository", "GetHistory")
+			sugar.Errorw("외부 IP 이력 스캔 실패", "error", err)
+			return nil, err
+		}
+		history = append(history, e)
+	}
+	return history, rows.Err()
+}
@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"system-collector/internal/telemetry"
+	"system-collector/pkg/logger"
+	"system-collector/pkg/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type LeaseRepository struct {
+	db *sql.DB
+}
+
+func NewLeaseRepository(db *sql.DB) *LeaseRepository {
+	sugar := logger.GetCustomLogger()
+	sugar.Infof("LeaseRepository 초기화 중")
+
+	return &LeaseRepository{
+		db: db,
+	}
+}
+
+// AcquireLease는 노드의 리스를 instanceID로 가져오고, 만료되지 않은 리스를 가진 이전 인스턴스 ID를 반환합니다.
+// 노드가 새로 연결된 인스턴스가 항상 소유자가 됩니다.
+func (r *LeaseRepository) AcquireLease(ctx context.Context, nodeID, instanceID string, ttl time.Duration) (string, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		telemetry.PostgresError("LeaseRepository", "AcquireLease")
+		sugar.Errorw("트랜잭션 시작 실패", "error", err)
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var previous string
+	err = tx.QueryRowContext(ctx, `SELECT instance_id FROM node_leases WHERE node_id = $1 AND expires_at > now() FOR UPDATE`, nodeID).Scan(&previous)
+	if err != nil && err != sql.ErrNoRows {
+		telemetry.PostgresError("LeaseRepository", "AcquireLease")
+		sugar.Errorw("노드 리스 조회 실패", "nodeID", nodeID, "error", err)
+		return "", err
+	}
+
+	query := `INSERT INTO node_leases (node_id, instance_id, acquired_at, expires_at)
+		VALUES ($1, $2, now(), now() + $3 * interval '1 millisecond')
+		ON CONFLICT (node_id) DO UPDATE SET
+			instance_id = EXCLUDED.instance_id,
+			acquired_at = EXCLUDED.acquired_at,
+			expires_at = EXCLUDED.expires_at`
+	if _, err := tx.ExecContext(ctx, query, nodeID, instanceID, ttl.Milliseconds()); err != nil {
+		telemetry.PostgresError("LeaseRepository", "AcquireLease")
+		sugar.Errorw("노드 리스 획득 실패", "nodeID", nodeID, "error", err)
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		telemetry.PostgresError("LeaseRepository", "AcquireLease")
+		sugar.Errorw("트랜잭션 커밋 실패", "error", err)
+		return "", err
+	}
+	return previous, nil
+}
+
+// RenewLeases는 instanceID가 가진 노드 리스의 만료 시간을 연장하고, 실제로 연장된 노드 ID를 반환합니다.
+// 반환되지 않은 노드는 다른 인스턴스가 가져갔거나 이미 만료되어 삭제된 것입니다.
+func (r *LeaseRepository) RenewLeases(ctx context.Context, instanceID string, nodeIDs []string, ttl time.Duration) ([]string, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `UPDATE node_leases SET expires_at = now() + $3 * interval '1 millisecond'
+		WHERE instance_id = $1 AND node_id = ANY($2)
+		RETURNING node_id`
+	rows, err := r.db.QueryContext(ctx, query, instanceID, pq.Array(nodeIDs), ttl.Milliseconds())
+	if err != nil {
+		telemetry.PostgresError("LeaseRepository", "RenewLeases")
+		sugar.Errorw("노드 리스 연장 실패", "instanceID", instanceID, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var renewed []string
+	for rows.Next() {
+		var nodeID string
+		if err := rows.Scan(&nodeID); err != nil {
+			telemetry.PostgresError("LeaseRepository", "RenewLeases")
+			sugar.Errorw("노드 ID 스캔 실패", "error", err)
+			return nil, err
+		}
+		renewed = append(renewed, nodeID)
+	}
+	return renewed, rows.Err()
+}
+
+// ReleaseLease는 instanceID가 가진 노드 리스를 반납합니다
+func (r *LeaseRepository) ReleaseLease(ctx context.Context, nodeID, instanceID string) error {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `DELETE FROM node_leases WHERE node_id = $1 AND instance_id = $2`
+	if _, err := r.db.ExecContext(ctx, query, nodeID, instanceID); err != nil {
+		telemetry.PostgresError("LeaseRepository", "ReleaseLease")
+		sugar.Errorw("노드 리스 반납 실패", "nodeID", nodeID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// ReleaseAll은 instanceID가 가진 모든 노드 리스를 반납하고 해당 노드 ID를 반환합니다
+func (r *LeaseRepository) ReleaseAll(ctx context.Context, instanceID string) ([]string, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `DELETE FROM node_leases WHERE instance_id = $1 RETURNING node_id`, instanceID)
+	if err != nil {
+		telemetry.PostgresError("LeaseRepository", "ReleaseAll")
+		sugar.Errorw("인스턴스 리스 반납 실패", "instanceID", instanceID, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var nodeIDs []string
+	for rows.Next() {
+		var nodeID string
+		if err := rows.Scan(&nodeID); err != nil {
+			telemetry.PostgresError("LeaseRepository", "ReleaseAll")
+			sugar.Errorw("노드 ID 스캔 실패", "error", err)
+			return nil, err
+		}
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	return nodeIDs, rows.Err()
+}
+
+// ExpireLeases는 만료된 리스를 삭제하고 삭제한 리스를 반환합니다.
+// 여러 인스턴스가 동시에 호출해도 각 리스는 한 인스턴스에만 반환됩니다.
+func (r *LeaseRepository) ExpireLeases(ctx context.Context) ([]models.NodeLease, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `DELETE FROM node_leases WHERE expires_at <= now()
+		RETURNING node_id, instance_id, acquired_at, expires_at`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		telemetry.PostgresError("LeaseRepository", "ExpireLeases")
+		sugar.Errorw("만료 리스 정리 실패", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var leases []models.NodeLease
+	for rows.Next() {
+		var l models.NodeLease
+		if err := rows.Scan(&l.NodeID, &l.InstanceID, &l.AcquiredAt, &l.ExpiresAt); err != nil {
+			telemetry.PostgresError("LeaseRepository", "ExpireLeases")
+			sugar.Errorw("리스 스캔 실패", "error", err)
+			return nil, err
+		}
+		leases = append(leases, l)
+	}
+	return leases, rows.Err()
+}
+
+// Heartbeat는 인스턴스의 생존 시간을 기록합니다
+func (r *LeaseRepository) Heartbeat(ctx context.Context, instanceID string, startedAt time.Time) error {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `INSERT INTO cluster_instances (instance_id, started_at, heartbeat_at) VALUES ($1, $2, now())
+		ON CONFLICT (instance_id) DO UPDATE SET started_at = EXCLUDED.started_at, heartbeat_at = now()`
+	if _, err := r.db.ExecContext(ctx, query, instanceID, startedAt); err != nil {
+		telemetry.PostgresError("LeaseRepository", "Heartbeat")
+		sugar.Errorw("인스턴스 heartbeat 기록 실패", "instanceID", instanceID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// RemoveInstance는 인스턴스 등록을 삭제합니다
+func (r *LeaseRepository) RemoveInstance(ctx context.Context, instanceID string) error {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM cluster_instances WHERE instance_id = $1`, instanceID); err != nil {
+		telemetry.PostgresError("LeaseRepository", "RemoveInstance")
+		sugar.Errorw("인스턴스 삭제 실패", "instanceID", instanceID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// RemoveDeadInstances는 heartbeat가 maxAge보다 오래된 인스턴스 등록을 삭제합니다
+func (r *LeaseRepository) RemoveDeadInstances(ctx context.Context, maxAge time.Duration) ([]string, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `DELETE FROM cluster_instances WHERE heartbeat_at < now() - $1 * interval '1 millisecond' RETURNING instance_id`
+	rows, err := r.db.QueryContext(ctx, query, maxAge.Milliseconds())
+	if err != nil {
+		telemetry.PostgresError("LeaseRepository", "RemoveDeadInstances")
+		sugar.Errorw("중단된 인스턴스 정리 실패", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var instanceIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			telemetry.PostgresError("LeaseRepository", "RemoveDeadInstances")
+			sugar.Errorw("인스턴스 ID 스캔 실패", "error", err)
+			return nil, err
+		}
+		instanceIDs = append(instanceIDs, id)
+	}
+	return instanceIDs, rows.Err()
+}
+
+// GetInstances는 등록된 인스턴스와 인스턴스별 유효 리스 수를 조회합니다
+func (r *LeaseRepository) GetInstances(ctx context.Context) ([]models.ClusterInstance, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT i.instance_id, i.started_at, i.heartbeat_at,
+			(SELECT count(*) FROM node_leases l WHERE l.instance_id = i.instance_id AND l.expires_at > now())
+		FROM cluster_instances i ORDER BY i.instance_id`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		telemetry.PostgresError("LeaseRepository", "GetInstances")
+		sugar.Errorw("인스턴스 조회 실패", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	instances := []models.ClusterInstance{}
+	for rows.Next() {
+		var inst models.ClusterInstance
+		if err := rows.Scan(&inst.InstanceID, &inst.StartedAt, &inst.HeartbeatAt, &inst.LeaseCount); err != nil {
+			telemetry.PostgresError("LeaseRepository", "GetInstances")
+			sugar.Errorw("인스턴스 스캔 실패", "error", err)
+			return nil, err
+		}
+		instances = append(instances, inst)
+	}
+	return instances, rows.Err()
+}
+
+// Notify는 channel로 NOTIFY를 보냅니다
+func (r *LeaseRepository) Notify(ctx context.Context, channel, payload string) error {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	if _, err := r.db.ExecContext(ctx, `SELECT pg_notify($1, $2)`, channel, payload); err != nil {
+		telemetry.PostgresError("LeaseRepository", "Notify")
+		sugar.Errorw("NOTIFY 전송 실패", "channel", channel, "error", err)
+		return err
+	}
+	return nil
+}
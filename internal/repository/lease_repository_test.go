@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"system-collector/internal/migrate"
+)
+
+// testDB는 TEST_POSTGRES_DSN(postgres:// URL)의 서버에 임시 데이터베이스를 만들고 스키마를 적용해 반환합니다.
+// 테스트가 끝나면 데이터베이스를 삭제하며, 환경 변수가 없으면 테스트를 건너뜁니다.
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN이 없어 PostgreSQL 통합 테스트를 건너뜀")
+	}
+	u, err := url.Parse(dsn)
+	if err != nil || u.Scheme == "" {
+		t.Fatalf("TEST_POSTGRES_DSN은 postgres:// URL이어야 합니다: %v", err)
+	}
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("PostgreSQL 연결 실패: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	name := fmt.Sprintf("collector_repository_test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec("CREATE DATABASE " + name); err != nil {
+		t.Fatalf("임시 데이터베이스 생성 실패: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec("DROP DATABASE IF EXISTS " + name + " WITH (FORCE)"); err != nil {
+			t.Errorf("임시 데이터베이스 삭제 실패: %v", err)
+		}
+	})
+
+	u.Path = "/" + name
+	db, err := sql.Open("postgres", u.String())
+	if err != nil {
+		t.Fatalf("임시 데이터베이스 연결 실패: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	m, err := migrate.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("NewMigrator: %v", err)
+	}
+	if _, err := m.Up(context.Background(), 0); err != nil {
+		t.Fatalf("스키마 적용 실패: %v", err)
+	}
+	return db
+}
+
+func TestAcquireLease(t *testing.T) {
+	repo := NewLeaseRepository(testDB(t))
+	ctx := context.Background()
+
+	steps := []struct {
+		instanceID   string
+		ttl          time.Duration
+		wantPrevious string
+	}{
+		{instanceID: "instance-a", ttl: time.Minute},
+		{instanceID: "instance-a", ttl: time.Minute, wantPrevious: "instance-a"},
+		// 새로 연결된 인스턴스가 항상 리스를 가져가고 이전 소유자를 돌려받음
+		{instanceID: "instance-b", ttl: -time.Second, wantPrevious: "instance-a"},
+		// 만료된 리스의 소유자는 이전 소유자로 보지 않음
+		{instanceID: "instance-a", ttl: time.Minute},
+	}
+	for i, s := range steps {
+		previous, err := repo.AcquireLease(ctx, "node-1", s.instanceID, s.ttl)
+		if err != nil {
+			t.Fatalf("%d단계 AcquireLease: %v", i, err)
+		}
+		if previous != s.wantPrevious {
+			t.Errorf("%d단계 (%s) 이전 소유자 %q, 기대 %q", i, s.instanceID, previous, s.wantPrevious)
+		}
+	}
+}
+
+func TestRenewLeases(t *testing.T) {
+	repo := NewLeaseRepository(testDB(t))
+	ctx := context.Background()
+
+	for nodeID, instanceID := range map[string]string{"node-1": "instance-a", "node-2": "instance-a", "node-3": "instance-b"} {
+		if _, err := repo.AcquireLease(ctx, nodeID, instanceID, -time.Second); err != nil {
+			t.Fatalf("AcquireLease(%s): %v", nodeID, err)
+		}
+	}
+	// node-2는 instance-b가 가져감
+	if _, err := repo.AcquireLease(ctx, "node-2", "instance-b", -time.Second); err != nil {
+		t.Fatalf("AcquireLease: %v", err)
+	}
+
+	renewed, err := repo.RenewLeases(ctx, "instance-a", []string{"node-1", "node-2", "node-3", "node-4"}, time.Minute)
+	if err != nil {
+		t.Fatalf("RenewLeases: %v", err)
+	}
+	if !reflect.DeepEqual(renewed, []string{"node-1"}) {
+		t.Errorf("연장된 노드 %v, 자신의 리스인 [node-1]만 기대", renewed)
+	}
+
+	// 연장된 리스는 만료 정리 대상에서 빠짐
+	expired, err := repo.ExpireLeases(ctx)
+	if err != nil {
+		t.Fatalf("ExpireLeases: %v", err)
+	}
+	var nodeIDs []string
+	for _, l := range expired {
+		nodeIDs = append(nodeIDs, l.NodeID)
+	}
+	sort.Strings(nodeIDs)
+	if !reflect.DeepEqual(nodeIDs, []string{"node-2", "node-3"}) {
+		t.Errorf("만료된 노드 %v, 기대 [node-2 node-3]", nodeIDs)
+	}
+}
+
+func TestExpireLeases(t *testing.T) {
+	repo := NewLeaseRepository(testDB(t))
+	ctx := context.Background()
+
+	if _, err := repo.AcquireLease(ctx, "node-1", "instance-a", -time.Second); err != nil {
+		t.Fatalf("AcquireLease: %v", err)
+	}
+	if _, err := repo.AcquireLease(ctx, "node-2", "instance-b", time.Minute); err != nil {
+		t.Fatalf("AcquireLease: %v", err)
+	}
+
+	expired, err := repo.ExpireLeases(ctx)
+	if err != nil {
+		t.Fatalf("ExpireLeases: %v", err)
+	}
+	if len(expired) != 1 || expired[0].NodeID != "node-1" || expired[0].InstanceID != "instance-a" ||
+		!expired[0].ExpiresAt.Before(time.Now()) || expired[0].AcquiredAt.IsZero() {
+		t.Fatalf("만료된 리스 %+v, node-1 하나 기대", expired)
+	}
+
+	// 정리한 리스는 삭제되어 다시 반환되지 않음
+	if expired, err := repo.ExpireLeases(ctx); err != nil || len(expired) != 0 {
+		t.Errorf("두 번째 ExpireLeases = %+v, %v, 빈 결과 기대", expired, err)
+	}
+	if renewed, err := repo.RenewLeases(ctx, "instance-a", []string{"node-1"}, time.Minute); err != nil || len(renewed) != 0 {
+		t.Errorf("만료 후 RenewLeases = %v, %v, 연장되지 않아야 함", renewed, err)
+	}
+}
@@ -1,13 +1,16 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"system-collector/internal/telemetry"
 	"system-collector/pkg/logger"
 	"system-collector/pkg/models"
 )
 
 type LogRepository struct {
-	db *sql.DB
+	db    *sql.DB
+	stmts *statements // 자주 실행하는 쿼리의 prepared statement
 }
 
 func NewLogRepository(db *sql.DB) *LogRepository {
@@ -15,23 +18,27 @@ func NewLogRepository(db *sql.DB) *LogRepository {
 	sugar.Infof("LogRepository 초기화 중")
 
 	return &LogRepository{
-		db: db,
+		db:    db,
+		stmts: newStatements(db),
 	}
 }
 
-func (r *LogRepository) SaveLogs(logs []models.LogMessage) error {
-	sugar := logger.GetCustomLogger()
-	sugar.Infof("로그 저장 시작 %d개", len(logs))
+func (r *LogRepository) SaveLogs(ctx context.Context, logs []models.LogMessage) error {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	sugar.Debugf("로그 저장 시작 %d개", len(logs))
 
 	query := `INSERT INTO logs (node_id, timestamp, level, content) VALUES ($1, $2, $3, $4)`
 	for _, log := range logs {
-		_, err := r.db.Exec(query, log.NodeID, log.Timestamp, log.Level, log.Content)
+		_, err := r.stmts.exec(ctx, query, log.NodeID, log.Timestamp, log.Level, log.Content)
 		if err != nil {
+			telemetry.PostgresError("LogRepository", "SaveLogs")
 			sugar.Errorw("로그 저장 실패", "error", err)
 			return err
 		}
 	}
 
-	sugar.Infof("로그 저장 완료 %d개", len(logs))
+	sugar.Debugf("로그 저장 완료 %d개", len(logs))
 	return nil
 }
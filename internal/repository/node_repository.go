@@ -1,13 +1,20 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"system-collector/internal/telemetry"
 	"system-collector/pkg/logger"
 	"system-collector/pkg/models"
 )
 
+// NodeChangeChannel은 노드 변경 알림을 보내는 PostgreSQL LISTEN/NOTIFY 채널입니다.
+// 알림 트리거는 마이그레이션 0003_notify_triggers에서 생성합니다.
+const NodeChangeChannel = "node_changes"
+
 type NodeRepository struct {
-	db *sql.DB
+	db    *sql.DB
+	stmts *statements // 자주 실행하는 쿼리의 prepared statement
 }
 
 func NewNodeRepository(db *sql.DB) *NodeRepository {
@@ -15,30 +22,38 @@ func NewNodeRepository(db *sql.DB) *NodeRepository {
 	sugar.Infow("NodeRepository 초기화 중")
 
 	return &NodeRepository{
-		db: db,
+		db:    db,
+		stmts: newStatements(db),
 	}
 }
 
-func (r *NodeRepository) CreateNode(node *models.Node) error {
-	sugar := logger.GetCustomLogger()
+func (r *NodeRepository) CreateNode(ctx context.Context, node *models.Node) error {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
 	sugar.Infow("노드 생성 시작", "node", node)
 
-	query := `INSERT INTO nodes (node_id, obscura_key, server_type, node_name) VALUES ($1, $2, $3, 'Default')`
-	_, err := r.db.Exec(query, node.NodeID, node.ObscuraKey, node.ServerType)
+	// 이름이 없으면 노드 ID를 이름으로 사용 (웹에서 바꿀 수 있음)
+	query := `INSERT INTO nodes (node_id, obscura_key, server_type, node_name) VALUES ($1, $2, $3, COALESCE(NULLIF($4, ''), $1))`
+	_, err := r.db.ExecContext(ctx, query, node.NodeID, node.ObscuraKey, node.ServerType, node.Name)
 	if err != nil {
+		telemetry.PostgresError("NodeRepository", "CreateNode")
 		sugar.Errorw("노드 생성 실패", "error", err)
 		return err
 	}
 	return nil
 }
 
-func (r *NodeRepository) GetAllNodes() ([]*models.Node, error) {
-	sugar := logger.GetCustomLogger()
+func (r *NodeRepository) GetAllNodes(ctx context.Context) ([]*models.Node, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
 	sugar.Infow("모든 노드 조회 시작")
 
-	query := `SELECT node_id, obscura_key, server_type, COALESCE(external_ip, '') FROM nodes`
-	rows, err := r.db.Query(query)
+	query := `SELECT node_id, obscura_key, server_type, COALESCE(external_ip, ''), COALESCE(node_name, '') FROM nodes`
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
+		telemetry.PostgresError("NodeRepository", "GetAllNodes")
 		sugar.Errorw("모든 노드 조회 실패", "error", err)
 		return nil, err
 	}
@@ -47,8 +62,9 @@ func (r *NodeRepository) GetAllNodes() ([]*models.Node, error) {
 	nodes := []*models.Node{}
 	for rows.Next() {
 		var node models.Node
-		err := rows.Scan(&node.NodeID, &node.ObscuraKey, &node.ServerType, &node.ExternalIP)
+		err := rows.Scan(&node.NodeID, &node.ObscuraKey, &node.ServerType, &node.ExternalIP, &node.Name)
 		if err != nil {
+			telemetry.PostgresError("NodeRepository", "GetAllNodes")
 			sugar.Errorw("노드 데이터 스캔 오류", "error", err)
 			return nil, err
 		}
@@ -59,27 +75,104 @@ func (r *NodeRepository) GetAllNodes() ([]*models.Node, error) {
 	return nodes, nil
 }
 
-func (r *NodeRepository) UpdateNodeStatus(nodeID string, status int) error {
-	sugar := logger.GetCustomLogger()
+// GetNode는 노드 하나를 조회합니다. 없으면 nil을 반환합니다.
+func (r *NodeRepository) GetNode(ctx context.Context, nodeID string) (*models.Node, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	sugar.Infow("노드 조회 시작", "nodeID", nodeID)
+
+	query := `SELECT node_id, obscura_key, server_type, COALESCE(external_ip, ''), COALESCE(node_name, '') FROM nodes WHERE node_id = $1`
+	var node models.Node
+	err := r.stmts.queryRow(ctx, query, nodeID).Scan(&node.NodeID, &node.ObscuraKey, &node.ServerType, &node.ExternalIP, &node.Name)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		telemetry.PostgresError("NodeRepository", "GetNode")
+		sugar.Errorw("노드 조회 실패", "nodeID", nodeID, "error", err)
+		return nil, err
+	}
+	return &node, nil
+}
+
+// UpdateNodeServerType은 노드의 서버 유형을 업데이트합니다
+func (r *NodeRepository) UpdateNodeServerType(ctx context.Context, nodeID, serverType string) error {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	sugar.Infow("노드 서버 유형 업데이트", "nodeID", nodeID, "serverType", serverType)
+
+	query := `UPDATE nodes SET server_type = $1 WHERE node_id = $2`
+	_, err := r.db.ExecContext(ctx, query, serverType, nodeID)
+	if err != nil {
+		telemetry.PostgresError("NodeRepository", "UpdateNodeServerType")
+		sugar.Errorw("노드 서버 유형 업데이트 실패", "nodeID", nodeID, "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *NodeRepository) UpdateNodeStatus(ctx context.Context, nodeID string, status int) error {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
 	sugar.Infof("노드 상태 업데이트: %s, %d", nodeID, status)
 
 	query := `UPDATE nodes SET status = $1 WHERE node_id = $2`
-	_, err := r.db.Exec(query, status, nodeID)
+	_, err := r.stmts.exec(ctx, query, status, nodeID)
 	if err != nil {
+		telemetry.PostgresError("NodeRepository", "UpdateNodeStatus")
 		sugar.Errorw("노드 상태 업데이트 실패", "error", err)
 		return err
 	}
 	return nil
 }
 
+// ResetNodeStatuses는 오프라인이 아닌 노드를 오프라인으로 바꾸고 해당 노드 ID를 반환합니다.
+// 수집기 시작 시 이전 실행에서 남은 상태를 정리하는 데 사용하며,
+// 다른 인스턴스가 유효한 리스를 가진 노드는 건드리지 않습니다.
+func (r *NodeRepository) ResetNodeStatuses(ctx context.Context) ([]string, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	sugar.Infow("노드 상태 초기화 시작")
+
+	query := `UPDATE nodes SET status = $1 WHERE status <> $1
+		AND NOT EXISTS (SELECT 1 FROM node_leases l WHERE l.node_id = nodes.node_id AND l.expires_at > now())
+		RETURNING node_id`
+	rows, err := r.db.QueryContext(ctx, query, models.NodeStatusOffline)
+	if err != nil {
+		telemetry.PostgresError("NodeRepository", "ResetNodeStatuses")
+		sugar.Errorw("노드 상태 초기화 실패", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var nodeIDs []string
+	for rows.Next() {
+		var nodeID string
+		if err := rows.Scan(&nodeID); err != nil {
+			telemetry.PostgresError("NodeRepository", "ResetNodeStatuses")
+			sugar.Errorw("노드 ID 스캔 실패", "error", err)
+			return nil, err
+		}
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	return nodeIDs, rows.Err()
+}
+
 // UpdateNodeExternalIP는 노드의 외부 IP 주소를 업데이트합니다.
-func (r *NodeRepository) UpdateNodeExternalIP(nodeID, externalIP string) error {
-	sugar := logger.GetCustomLogger()
+func (r *NodeRepository) UpdateNodeExternalIP(ctx context.Context, nodeID, externalIP string) error {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
 	sugar.Infow("노드 외부 IP 업데이트", "nodeID", nodeID, "externalIP", externalIP)
 
 	query := `UPDATE nodes SET external_ip = $1 WHERE node_id = $2`
-	_, err := r.db.Exec(query, externalIP, nodeID)
+	_, err := r.stmts.exec(ctx, query, externalIP, nodeID)
 	if err != nil {
+		telemetry.PostgresError("NodeRepository", "UpdateNodeExternalIP")
 		sugar.Errorw("노드 외부 IP 업데이트 실패", "error", err, "nodeID", nodeID, "externalIP", externalIP)
 		return err
 	}
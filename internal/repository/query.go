@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	config "system-collector/configs"
+	"system-collector/pkg/logger"
+	"time"
+)
+
+// defaultQueryTimeout은 설정이 없을 때 쿼리 하나에 허용하는 시간입니다
+const defaultQueryTimeout = 5 * time.Second
+
+// withTimeout은 ctx에 postgres.query_timeout 데드라인을 더합니다.
+// ctx에 더 이른 데드라인이 있으면 그 데드라인이 유지됩니다.
+func withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := defaultQueryTimeout
+	if cfg := config.Get(); cfg != nil && cfg.Postgres.QueryTimeout > 0 {
+		timeout = time.Duration(cfg.Postgres.QueryTimeout) * time.Second
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// statements는 자주 실행하는 쿼리의 prepared statement를 보관합니다.
+// 테이블이 만들어지기 전에 준비하지 않도록 저장소를 생성할 때가 아니라 처음 실행할 때 준비합니다.
+type statements struct {
+	db    *sql.DB
+	mu    sync.Mutex
+	stmts map[string]*sql.Stmt
+}
+
+func newStatements(db *sql.DB) *statements {
+	return &statements{db: db, stmts: make(map[string]*sql.Stmt)}
+}
+
+// get은 query의 prepared statement를 반환합니다. 처음 호출되면 준비하고,
+// 준비에 실패하면 nil을 반환하여 호출한 쪽이 준비 없이 실행하도록 합니다 (다음 호출에서 다시 준비).
+func (s *statements) get(ctx context.Context, query string) *sql.Stmt {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stmt, ok := s.stmts[query]; ok {
+		return stmt
+	}
+	stmt, err := s.db.PrepareContext(ctx, query)
+	if err != nil {
+		logger.FromContext(ctx).Debugw("쿼리 준비 실패, 준비 없이 실행", "error", err)
+		return nil
+	}
+	s.stmts[query] = stmt
+	return stmt
+}
+
+// exec는 query를 prepared statement로 실행합니다
+func (s *statements) exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if stmt := s.get(ctx, query); stmt != nil {
+		return stmt.ExecContext(ctx, args...)
+	}
+	return s.db.ExecContext(ctx, query, args...)
+}
+
+// query는 query를 prepared statement로 조회합니다
+func (s *statements) query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if stmt := s.get(ctx, query); stmt != nil {
+		return stmt.QueryContext(ctx, args...)
+	}
+	return s.db.QueryContext(ctx, query, args...)
+}
+
+// queryRow는 query를 prepared statement로 조회하여 한 행을 반환합니다
+func (s *statements) queryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if stmt := s.get(ctx, query); stmt != nil {
+		return stmt.QueryRowContext(ctx, args...)
+	}
+	return s.db.QueryRowContext(ctx, query, args...)
+}
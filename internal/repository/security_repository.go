@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"system-collector/internal/telemetry"
+	"system-collector/pkg/logger"
+	"system-collector/pkg/models"
+	"time"
+)
+
+type SecurityRepository struct {
+	db *sql.DB
+}
+
+func NewSecurityRepository(db *sql.DB) *SecurityRepository {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("SecurityRepository 초기화 중")
+
+	return &SecurityRepository{
+		db: db,
+	}
+}
+
+// SecurityEventFilter는 보안 이벤트 조회 조건입니다. 비어 있는 조건은 사용하지 않습니다.
+type SecurityEventFilter struct {
+	NodeID      string
+	ProcessName string
+	Type        string
+	Since       time.Time
+	Limit       int
+}
+
+// SaveSecurityEvents는 보안 이벤트를 하나의 트랜잭션으로 저장하고 생성된 ID를 설정합니다
+func (r *SecurityRepository) SaveSecurityEvents(ctx context.Context, events []models.SecurityEvent) error {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		telemetry.PostgresError("SecurityRepository", "SaveSecurityEvents")
+		sugar.Errorw("트랜잭션 시작 실패", "error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	insert := `INSERT INTO security_events (node_id, type, severity, process_name, pid, username, command, old_value, new_value, message, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`
+	for i := range events {
+		e := &events[i]
+		err := tx.QueryRowContext(ctx, insert, e.NodeID, e.Type, e.Severity, e.ProcessName, e.PID, e.User, e.Command,
+			e.OldValue, e.NewValue, e.Message, e.CreatedAt).Scan(&e.ID)
+		if err != nil {
+			telemetry.PostgresError("SecurityRepository", "SaveSecurityEvents")
+			sugar.Errorw("보안 이벤트 저장 실패", "nodeID", e.NodeID, "process", e.ProcessName, "type", e.Type, "error", err)
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		telemetry.PostgresError("SecurityRepository", "SaveSecurityEvents")
+		sugar.Errorw("트랜잭션 커밋 실패", "error", err)
+		return err
+	}
+	return nil
+}
+
+// GetSecurityEvents는 조건에 맞는 보안 이벤트를 최신순으로 조회합니다
+func (r *SecurityRepository) GetSecurityEvents(ctx context.Context, filter SecurityEventFilter) ([]models.SecurityEvent, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var since sql.NullTime
+	if !filter.Since.IsZero() {
+		since = sql.NullTime{Time: filter.Since, Valid: true}
+	}
+	query := `SELECT id, node_id, type, severity, process_name, pid, username, command, old_value, new_value, message, created_at
+		FROM security_events
+		WHERE ($1 = '' OR node_id = $1)
+			AND ($2 = '' OR process_name = $2)
+			AND ($3 = '' OR type = $3)
+			AND ($4::timestamptz IS NULL OR created_at >= $4)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $5`
+	rows, err := r.db.QueryContext(ctx, query, filter.NodeID, filter.ProcessName, filter.Type, since, filter.Limit)
+	if err != nil {
+		telemetry.PostgresError("SecurityRepository", "GetSecurityEvents")
+		sugar.Errorw("보안 이벤트 조회 실패", "nodeID", filter.NodeID, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.SecurityEvent{}
+	for rows.Next() {
+		var e models.SecurityEvent
+		if err := rows.Scan(&e.ID, &e.NodeID, &e.Type, &e.Severity, &e.ProcessName, &e.PID, &e.User, &e.Command,
+			&e.OldValue, &e.NewValue, &e.Message, &e.CreatedAt); err != nil {
+			telemetry.PostgresError("SecurityRepository", "GetSecurityEvents")
+			sugar.Errorw("보안 이벤트 스캔 실패", "error", err)
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// GetKnownProcesses는 노드에서 실행된 적이 있는 프로세스 이름을 조회합니다
+func (r *SecurityRepository) GetKnownProcesses(ctx context.Context, nodeID string) ([]string, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `SELECT name FROM known_processes WHERE node_id = $1`, nodeID)
+	if err != nil {
+		telemetry.PostgresError("SecurityRepository", "GetKnownProcesses")
+		sugar.Errorw("알려진 프로세스 조회 실패", "nodeID", nodeID, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			telemetry.PostgresError("SecurityRepository", "GetKnownProcesses")
+			sugar.Errorw("알려진 프로세스 스캔 실패", "error", err)
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// AddKnownProcesses는 노드의 프로세스 이름을 하나의 트랜잭션으로 추가합니다. 이미 있는 이름은 건너뜁니다.
+func (r *SecurityRepository) AddKnownProcesses(ctx context.Context, nodeID string, names []string) error {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		telemetry.PostgresError("SecurityRepository", "AddKnownProcesses")
+		sugar.Errorw("트랜잭션 시작 실패", "error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	insert := `INSERT INTO known_processes (node_id, name) VALUES ($1, $2) ON CONFLICT (node_id, name) DO NOTHING`
+	for _, name := range names {
+		if _, err := tx.ExecContext(ctx, insert, nodeID, name); err != nil {
+			telemetry.PostgresError("SecurityRepository", "AddKnownProcesses")
+			sugar.Errorw("알려진 프로세스 추가 실패", "nodeID", nodeID, "name", name, "error", err)
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		telemetry.PostgresError("SecurityRepository", "AddKnownProcesses")
+		sugar.Errorw("트랜잭션 커밋 실패", "error", err)
+		return err
+	}
+	return nil
+}
+
+// GetAllowlist는 노드의 보안 이벤트 허용 목록을 프로세스 이름 순으로 조회합니다
+func (r *SecurityRepository) GetAllowlist(ctx context.Context, nodeID string) ([]models.SecurityAllowlistEntry, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT node_id, process_name, comment, created_at FROM security_allowlist WHERE node_id = $1 ORDER BY process_name`
+	rows, err := r.db.QueryContext(ctx, query, nodeID)
+	if err != nil {
+		telemetry.PostgresError("SecurityRepository", "GetAllowlist")
+		sugar.Errorw("허용 목록 조회 실패", "nodeID", nodeID, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.SecurityAllowlistEntry{}
+	for rows.Next() {
+		var e models.SecurityAllowlistEntry
+		if err := rows.Scan(&e.NodeID, &e.ProcessName, &e.Comment, &e.CreatedAt); err != nil {
+			telemetry.PostgresError("SecurityRepository", "GetAllowlist")
+			sugar.Errorw("허용 목록 스캔 실패", "error", err)
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// AddAllowlistEntry는 노드의 허용 목록에 프로세스 이름을 추가합니다. 이미 있으면 설명만 바꿉니다.
+func (r *SecurityRepository) AddAllowlistEntry(ctx context.Context, entry *models.SecurityAllowlistEntry) error {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	sugar.Infow("허용 목록 추가", "nodeID", entry.NodeID, "process", entry.ProcessName)
+
+	query := `INSERT INTO security_allowlist (node_id, process_name, comment) VALUES ($1, $2, $3)
+		ON CONFLICT (node_id, process_name) DO UPDATE SET comment = EXCLUDED.comment
+		RETURNING created_at`
+	if err := r.db.QueryRowContext(ctx, query, entry.NodeID, entry.ProcessName, entry.Comment).Scan(&entry.CreatedAt); err != nil {
+		telemetry.PostgresError("SecurityRepository", "AddAllowlistEntry")
+		sugar.Errorw("허용 목록 추가 실패", "nodeID", entry.NodeID, "process", entry.ProcessName, "error", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteAllowlistEntry는 노드의 허용 목록에서 프로세스 이름을 삭제합니다. 없으면 false를 반환합니다.
+func (r *SecurityRepository) DeleteAllowlistEntry(ctx context.Context, nodeID, processName string) (bool, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	sugar.Infow("허용 목록 삭제", "nodeID", nodeID, "process", processName)
+
+	res, err := r.db.ExecContext(ctx, `DELETE FROM security_allowlist WHERE node_id = $1 AND process_name = $2`, nodeID, processName)
+	if err != nil {
+		telemetry.PostgresError("SecurityRepository", "DeleteAllowlistEntry")
+		sugar.Errorw("허용 목록 삭제 실패", "nodeID", nodeID, "process", processName, "error", err)
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"system-collector/internal/telemetry"
+	"system-collector/pkg/logger"
+	"system-collector/pkg/models"
+	"time"
+)
+
+type ServiceRepository struct {
+	db *sql.DB
+}
+
+func NewServiceRepository(db *sql.DB) *ServiceRepository {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("ServiceRepository 초기화 중")
+
+	return &ServiceRepository{
+		db: db,
+	}
+}
+
+// ServiceEventFilter는 서비스 이벤트 조회 조건입니다. 비어 있는 조건은 사용하지 않습니다.
+type ServiceEventFilter struct {
+	NodeID string
+	Unit   string
+	Type   string
+	Since  time.Time
+	Limit  int
+}
+
+// SaveServiceEvents는 서비스 이벤트를 하나의 트랜잭션으로 저장하고 생성된 ID를 설정합니다
+func (r *ServiceRepository) SaveServiceEvents(ctx context.Context, events []models.ServiceEvent) error {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		telemetry.PostgresError("ServiceRepository", "SaveServiceEvents")
+		sugar.Errorw("트랜잭션 시작 실패", "error", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	insert := `INSERT INTO service_events (node_id, unit, type, severity, old_value, new_value, active_state, sub_state, message, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`
+	for i := range events {
+		e := &events[i]
+		err := tx.QueryRowContext(ctx, insert, e.NodeID, e.Unit, e.Type, e.Severity, e.OldValue, e.NewValue,
+			e.ActiveState, e.SubState, e.Message, e.CreatedAt).Scan(&e.ID)
+		if err != nil {
+			telemetry.PostgresError("ServiceRepository", "SaveServiceEvents")
+			sugar.Errorw("서비스 이벤트 저장 실패", "nodeID", e.NodeID, "unit", e.Unit, "type", e.Type, "error", err)
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		telemetry.PostgresError("ServiceRepository", "SaveServiceEvents")
+		sugar.Errorw("트랜잭션 커밋 실패", "error", err)
+		return err
+	}
+	return nil
+}
+
+// GetServiceEvents는 조건에 맞는 서비스 이벤트를 최신순으로 조회합니다
+func (r *ServiceRepository) GetServiceEvents(ctx context.Context, filter ServiceEventFilter) ([]models.ServiceEvent, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var since sql.NullTime
+	if !filter.Since.IsZero() {
+		since = sql.NullTime{Time: filter.Since, Valid: true}
+	}
+	query := `SELECT id, node_id, unit, type, severity, old_value, new_value, active_state, sub_state, message, created_at
+		FROM service_events
+		WHERE ($1 = '' OR node_id = $1)
+			AND ($2 = '' OR unit = $2)
+			AND ($3 = '' OR type = $3)
+			AND ($4::timestamptz IS NULL OR created_at >= $4)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $5`
+	rows, err := r.db.QueryContext(ctx, query, filter.NodeID, filter.Unit, filter.Type, since, filter.Limit)
+	if err != nil {
+		telemetry.PostgresError("ServiceRepository", "GetServiceEvents")
+		sugar.Errorw("서비스 이벤트 조회 실패", "nodeID", filter.NodeID, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.ServiceEvent{}
+	for rows.Next() {
+		var e models.ServiceEvent
+		if err := rows.Scan(&e.ID, &e.NodeID, &e.Unit, &e.Type, &e.Severity, &e.OldValue, &e.NewValue,
+			&e.ActiveState, &e.SubState, &e.Message, &e.CreatedAt); err != nil {
+			telemetry.PostgresError("ServiceRepository", "GetServiceEvents")
+			sugar.Errorw("서비스 이벤트 스캔 실패", "error", err)
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// GetCriticalServices는 노드의 필수 서비스를 유닛 이름 순으로 조회합니다
+func (r *ServiceRepository) GetCriticalServices(ctx context.Context, nodeID string) ([]models.CriticalService, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT node_id, unit, comment, created_at FROM critical_services WHERE node_id = $1 ORDER BY unit`
+	rows, err := r.db.QueryContext(ctx, query, nodeID)
+	if err != nil {
+		telemetry.PostgresError("ServiceRepository", "GetCriticalServices")
+		sugar.Errorw("필수 서비스 조회 실패", "nodeID", nodeID, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	services := []models.CriticalService{}
+	for rows.Next() {
+		var s models.CriticalService
+		if err := rows.Scan(&s.NodeID, &s.Unit, &s.Comment, &s.CreatedAt); err != nil {
+			telemetry.PostgresError("ServiceRepository", "GetCriticalServices")
+			sugar.Errorw("필수 서비스 스캔 실패", "error", err)
+			return nil, err
+		}
+		services = append(services, s)
+	}
+	return services, rows.Err()
+}
+
+// AddCriticalService는 노드의 필수 서비스를 추가합니다. 이미 있으면 설명만 바꿉니다.
+func (r *ServiceRepository) AddCriticalService(ctx context.Context, service *models.CriticalService) error {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	sugar.Infow("필수 서비스 추가", "nodeID", service.NodeID, "unit", service.Unit)
+
+	query := `INSERT INTO critical_services (node_id, unit, comment) VALUES ($1, $2, $3)
+		ON CONFLICT (node_id, unit) DO UPDATE SET comment = EXCLUDED.comment
+		RETURNING created_at`
+	if err := r.db.QueryRowContext(ctx, query, service.NodeID, service.Unit, service.Comment).Scan(&service.CreatedAt); err != nil {
+		telemetry.PostgresError("ServiceRepository", "AddCriticalService")
+		sugar.Errorw("필수 서비스 추가 실패", "nodeID", service.NodeID, "unit", service.Unit, "error", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteCriticalService는 노드의 필수 서비스를 삭제합니다. 없으면 false를 반환합니다.
+func (r *ServiceRepository) DeleteCriticalService(ctx context.Context, nodeID, unit string) (bool, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	sugar.Infow("필수 서비스 삭제", "nodeID", nodeID, "unit", unit)
+
+	res, err := r.db.ExecContext(ctx, `DELETE FROM critical_services WHERE node_id = $1 AND unit = $2`, nodeID, unit)
+	if err != nil {
+		telemetry.PostgresError("ServiceRepository", "DeleteCriticalService")
+		sugar.Errorw("필수 서비스 삭제 실패", "nodeID", nodeID, "unit", unit, "error", err)
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
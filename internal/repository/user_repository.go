@@ -1,12 +1,15 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"system-collector/internal/telemetry"
 	"system-collector/pkg/logger"
 )
 
 type UserRepository struct {
-	db *sql.DB
+	db    *sql.DB
+	stmts *statements // 자주 실행하는 쿼리의 prepared statement
 }
 
 func NewUserRepository(db *sql.DB) *UserRepository {
@@ -14,24 +17,28 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 	sugar.Infow("UserRepository 초기화 중")
 
 	return &UserRepository{
-		db: db,
+		db:    db,
+		stmts: newStatements(db),
 	}
 }
 
-func (r *UserRepository) ExistsUserByObscuraKey(ObscuraKey string) (bool, error) {
-	sugar := logger.GetCustomLogger()
-	sugar.Infow("사용자 존재 여부 확인 시작", "ObscuraKey", ObscuraKey)
+func (r *UserRepository) ExistsUserByObscuraKey(ctx context.Context, ObscuraKey string) (bool, error) {
+	sugar := logger.FromContext(ctx)
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	sugar.Debugw("사용자 존재 여부 확인 시작", "ObscuraKey", ObscuraKey)
 
 	query := `SELECT EXISTS(SELECT 1 FROM users WHERE obscura_key = $1)`
-	row := r.db.QueryRow(query, ObscuraKey)
+	row := r.stmts.queryRow(ctx, query, ObscuraKey)
 
 	var exists bool
 	err := row.Scan(&exists)
 	if err != nil {
+		telemetry.PostgresError("UserRepository", "ExistsUserByObscuraKey")
 		sugar.Errorw("사용자 존재 여부 확인 오류", "error", err)
 		return false, err
 	}
 
-	sugar.Infow("사용자 존재 여부 확인 완료", "ObscuraKey", ObscuraKey, "exists", exists)
+	sugar.Debugw("사용자 존재 여부 확인 완료", "ObscuraKey", ObscuraKey, "exists", exists)
 	return exists, nil
 }
@@ -0,0 +1,85 @@
+package security
+
+import (
+	"regexp"
+	"strings"
+
+	"system-collector/pkg/models"
+)
+
+// minSpikeSamples는 급증을 판단하기 전에 평소 값을 학습할 최소 샘플 수입니다
+const minSpikeSamples = 10
+
+// executable은 명령어에서 실행 파일 경로(첫 번째 인자)를 반환합니다
+func executable(command string) string {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// suspiciousPath는 root 프로세스가 paths 중 하나의 경로에서 실행 중이면 그 경로를 반환합니다
+func suspiciousPath(p models.ProcessInfo, paths []string) (string, bool) {
+	if p.User != "root" {
+		return "", false
+	}
+	exe := executable(p.Command)
+	for _, path := range paths {
+		if path != "" && strings.HasPrefix(exe, path) {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// compilePatterns는 채굴 프로그램 정규식을 대소문자 구분 없이 컴파일합니다. 설정 검증을 통과한 값이므로 잘못된 정규식은 건너뜁니다.
+func compilePatterns(patterns []string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		if re, err := regexp.Compile("(?i)" + pattern); err == nil {
+			compiled = append(compiled, re)
+		}
+	}
+	return compiled
+}
+
+// knownMiner는 프로세스 이름이나 명령어가 채굴 프로그램 정규식에 맞으면 그 정규식을 반환합니다
+func knownMiner(p models.ProcessInfo, patterns []*regexp.Regexp) (string, bool) {
+	for _, re := range patterns {
+		if re.MatchString(p.Name) || re.MatchString(p.Command) {
+			return strings.TrimPrefix(re.String(), "(?i)"), true
+		}
+	}
+	return "", false
+}
+
+// counter는 프로세스 수나 스레드 수의 평소 값(지수 가중 이동 평균)과 급증 상태입니다
+type counter struct {
+	mean     float64
+	samples  int
+	spiking  bool
+	baseline float64 // 급증을 감지한 시점의 평소 값
+}
+
+// observe는 값을 평소 값과 비교한 뒤 평소 값에 반영합니다.
+// 급증 상태에 새로 들어갔으면 true와 그 시점의 평소 값을 반환합니다.
+func (c *counter) observe(v, alpha, ratio float64, minIncrease int) (bool, float64) {
+	entered := false
+	if c.samples >= minSpikeSamples {
+		spike := v >= c.mean*ratio && v-c.mean >= float64(minIncrease)
+		if spike && !c.spiking {
+			entered = true
+			c.baseline = c.mean
+		}
+		c.spiking = spike
+	}
+
+	if c.samples == 0 {
+		c.mean = v
+	} else {
+		c.mean += alpha * (v - c.mean)
+	}
+	c.samples++
+	return entered, c.baseline
+}
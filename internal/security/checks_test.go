@@ -0,0 +1,179 @@
+package security
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	config "system-collector/configs"
+	"system-collector/pkg/models"
+)
+
+func TestSuspiciousPath(t *testing.T) {
+	paths := []string{"/tmp/", "/dev/shm/", ""}
+	tests := []struct {
+		name string
+		p    models.ProcessInfo
+		want string
+		ok   bool
+	}{
+		{name: "root, /tmp", p: models.ProcessInfo{User: "root", Command: "/tmp/.x/kworker -c cfg"}, want: "/tmp/", ok: true},
+		{name: "root, /dev/shm", p: models.ProcessInfo{User: "root", Command: "/dev/shm/a"}, want: "/dev/shm/", ok: true},
+		{name: "root, 일반 경로", p: models.ProcessInfo{User: "root", Command: "/usr/sbin/sshd -D"}},
+		{name: "인자에만 /tmp", p: models.ProcessInfo{User: "root", Command: "/usr/bin/rm -rf /tmp/cache"}},
+		{name: "root가 아닌 사용자", p: models.ProcessInfo{User: "www-data", Command: "/tmp/run"}},
+		{name: "명령어 없음", p: models.ProcessInfo{User: "root"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := suspiciousPath(tt.p, paths)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("suspiciousPath = %q, %v, 기대 %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestKnownMiner(t *testing.T) {
+	patterns := compilePatterns([]string{"xmrig", `stratum\+(tcp|ssl)://`, "("})
+	if len(patterns) != 2 {
+		t.Fatalf("컴파일된 정규식 %d개, 잘못된 정규식을 빼고 2개 기대", len(patterns))
+	}
+
+	tests := []struct {
+		name string
+		p    models.ProcessInfo
+		want string
+		ok   bool
+	}{
+		{name: "이름 (대소문자 무시)", p: models.ProcessInfo{Name: "XMRig"}, want: "xmrig", ok: true},
+		{name: "명령어의 풀 주소", p: models.ProcessInfo{Name: "systemd-helper", Command: "./a -o stratum+tcp://pool:3333"}, want: `stratum\+(tcp|ssl)://`, ok: true},
+		{name: "일반 프로세스", p: models.ProcessInfo{Name: "nginx", Command: "nginx: worker process"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := knownMiner(tt.p, patterns)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("knownMiner = %q, %v, 기대 %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCounterObserve(t *testing.T) {
+	// 학습 샘플 10개(100) 뒤의 값 변화
+	tests := []struct {
+		name     string
+		values   []float64
+		entered  []int // 급증에 새로 들어간 값의 위치
+		baseline float64
+	}{
+		{name: "변화 없음", values: []float64{100, 100, 100}},
+		{name: "비율만 넘고 증가 수 부족", values: []float64{250}},
+		{name: "급증", values: []float64{400}, entered: []int{0}, baseline: 100},
+		{name: "급증이 이어지면 한 번만", values: []float64{400, 420, 450}, entered: []int{0}, baseline: 100},
+		{name: "회복 후 다시 급증", values: []float64{400, 100, 100, 100, 100, 100, 100, 100, 100, 100, 100, 600}, entered: []int{0, 11}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c counter
+			for i := 0; i < minSpikeSamples; i++ {
+				if entered, _ := c.observe(100, 0.1, 2, 200); entered {
+					t.Fatalf("학습 중 %d번째 샘플에서 급증", i)
+				}
+			}
+
+			var entered []int
+			var baseline float64
+			for i, v := range tt.values {
+				if ok, b := c.observe(v, 0.1, 2, 200); ok {
+					entered = append(entered, i)
+					baseline = b
+				}
+			}
+			if !reflect.DeepEqual(entered, tt.entered) {
+				t.Errorf("급증 감지 위치 %v, 기대 %v", entered, tt.entered)
+			}
+			if tt.baseline != 0 && baseline != tt.baseline {
+				t.Errorf("평소 값 %v, 기대 %v", baseline, tt.baseline)
+			}
+		})
+	}
+
+	// 학습이 끝나기 전에는 큰 값도 급증이 아님
+	var c counter
+	c.observe(10, 0.1, 2, 1)
+	if entered, _ := c.observe(10000, 0.1, 2, 1); entered {
+		t.Error("학습 중 급증으로 판단")
+	}
+}
+
+// brief는 비교하기 쉽게 이벤트를 "유형 이름/PID" 형식으로 줄입니다
+func brief(events []models.SecurityEvent) []string {
+	var out []string
+	for _, e := range events {
+		out = append(out, fmt.Sprintf("%s %s/%d", e.Type, e.ProcessName, e.PID))
+	}
+	return out
+}
+
+func TestCheckProcesses(t *testing.T) {
+	cfg := config.Default()
+	cfg.Security.SuspiciousPaths = []string{"/tmp/"}
+	patterns := compilePatterns([]string{"xmrig"})
+
+	sshd := models.ProcessInfo{PID: 1, Name: "sshd", User: "root", Command: "/usr/sbin/sshd"}
+	miner := models.ProcessInfo{PID: 50, Name: "xmrig", User: "root", Command: "/tmp/xmrig"}
+	backup := models.ProcessInfo{PID: 60, Name: "backup", User: "root", Command: "/tmp/backup.sh"}
+
+	// 각 단계의 프로세스 목록을 차례로 적용
+	steps := []struct {
+		name      string
+		processes []models.ProcessInfo
+		want      []string
+		newNames  []string
+	}{
+		{
+			name:      "첫 목록은 기준으로 저장",
+			processes: []models.ProcessInfo{sshd},
+			newNames:  []string{"sshd"},
+		},
+		{
+			name:      "채굴 프로그램이 의심 경로에서 실행",
+			processes: []models.ProcessInfo{sshd, miner},
+			want:      []string{"security_new_process xmrig/50", "security_known_miner xmrig/50", "security_suspicious_path xmrig/50"},
+			newNames:  []string{"xmrig"},
+		},
+		{
+			name:      "같은 프로세스는 다시 알리지 않음",
+			processes: []models.ProcessInfo{miner, sshd},
+		},
+		{
+			name:      "새 PID로 다시 실행",
+			processes: []models.ProcessInfo{sshd, {PID: 51, Name: "xmrig", User: "root", Command: "/tmp/xmrig"}},
+			want:      []string{"security_known_miner xmrig/51", "security_suspicious_path xmrig/51"},
+		},
+		{
+			name:      "허용 목록",
+			processes: []models.ProcessInfo{sshd, backup},
+			newNames:  []string{"backup"},
+		},
+	}
+
+	st := &nodeState{
+		known:    map[string]bool{},
+		learning: true,
+		allow:    map[string]bool{"backup": true},
+		flagged:  map[flag]bool{},
+	}
+	for _, step := range steps {
+		events, newNames := st.checkProcesses("node-1", step.processes, cfg, patterns, time.Now())
+		if got := brief(events); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("%s: 이벤트 =\n%q\n기대\n%q", step.name, got, step.want)
+		}
+		if !reflect.DeepEqual(newNames, step.newNames) {
+			t.Errorf("%s: 새 이름 = %v, 기대 %v", step.name, newNames, step.newNames)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package security
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"system-collector/internal/admin"
+	"system-collector/internal/httpapi"
+	"system-collector/internal/repository"
+	"system-collector/pkg/models"
+)
+
+// 보안 이벤트 조회 시 limit 기본값과 최대값
+const (
+	defaultEventLimit = 100
+	maxEventLimit     = 1000
+)
+
+// Handler는 보안 이벤트 조회와 허용 목록 관리 API를 제공합니다.
+// 조회와 변경 API 모두 관리 API와 같은 인증을 사용합니다.
+type Handler struct {
+	tracker *Tracker
+	repo    *repository.SecurityRepository
+}
+
+// NewHandler는 보안 이벤트 핸들러를 생성합니다
+func NewHandler(tracker *Tracker, repo *repository.SecurityRepository) *Handler {
+	return &Handler{tracker: tracker, repo: repo}
+}
+
+// RegisterRoutes는 핸들러를 mux에 등록합니다
+func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/security/events", admin.RequireAdmin(h.handleEvents))
+	mux.HandleFunc("GET /api/nodes/{nodeID}/security/events", admin.RequireAdmin(h.handleNodeEvents))
+	mux.HandleFunc("GET /api/nodes/{nodeID}/security/allowlist", admin.RequireAdmin(h.handleAllowlist))
+	mux.HandleFunc("POST /api/nodes/{nodeID}/security/allowlist", admin.RequireAdmin(h.handleAddAllowlist))
+	mux.HandleFunc("DELETE /api/nodes/{nodeID}/security/allowlist/{name}", admin.RequireAdmin(h.handleDeleteAllowlist))
+}
+
+// handleEvents는 보안 이벤트를 최신순으로 반환합니다.
+// node_id, process, type, since(RFC3339 또는 24h 같은 기간), limit 쿼리 파라미터를 지원합니다.
+func (h *Handler) handleEvents(w http.ResponseWriter, r *http.Request) {
+	h.writeEvents(w, r, r.URL.Query().Get("node_id"))
+}
+
+// handleNodeEvents는 노드의 보안 이벤트를 최신순으로 반환합니다
+func (h *Handler) handleNodeEvents(w http.ResponseWriter, r *http.Request) {
+	h.writeEvents(w, r, r.PathValue("nodeID"))
+}
+
+func (h *Handler) writeEvents(w http.ResponseWriter, r *http.Request, nodeID string) {
+	q := r.URL.Query()
+	filter := repository.SecurityEventFilter{
+		NodeID:      nodeID,
+		ProcessName: q.Get("process"),
+		Type:        q.Get("type"),
+		Limit:       defaultEventLimit,
+	}
+	if s := q.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			httpapi.WriteError(w, http.StatusBadRequest, "limit는 양의 정수여야 합니다")
+			return
+		}
+		filter.Limit = min(n, maxEventLimit)
+	}
+	if s := q.Get("since"); s != "" {
+		since, ok := httpapi.ParseSince(s)
+		if !ok {
+			httpapi.WriteError(w, http.StatusBadRequest, "since는 RFC3339 시각 또는 양의 기간이어야 합니다 (예: 24h)")
+			return
+		}
+		filter.Since = since
+	}
+
+	events, err := h.repo.GetSecurityEvents(r.Context(), filter)
+	if err != nil {
+		httpapi.WriteError(w, http.StatusInternalServerError, "보안 이벤트 조회 실패")
+		return
+	}
+	httpapi.WriteJSON(w, http.StatusOK, events)
+}
+
+// handleAllowlist는 노드의 허용 목록을 반환합니다
+func (h *Handler) handleAllowlist(w http.ResponseWriter, r *http.Request) {
+	entries, err := h.repo.GetAllowlist(r.Context(), r.PathValue("nodeID"))
+	if err != nil {
+		httpapi.WriteError(w, http.StatusInternalServerError, "허용 목록 조회 실패")
+		return
+	}
+	httpapi.WriteJSON(w, http.StatusOK, entries)
+}
+
+// allowlistRequest는 허용 목록 추가 요청입니다
+type allowlistRequest struct {
+	ProcessName string `json:"process_name"`
+	Comment     string `json:"comment"`
+}
+
+func (h *Handler) handleAddAllowlist(w http.ResponseWriter, r *http.Request) {
+	var req allowlistRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+		httpapi.WriteError(w, http.StatusBadRequest, "요청 본문이 올바른 JSON이 아닙니다")
+		return
+	}
+	req.ProcessName = strings.TrimSpace(req.ProcessName)
+	if req.ProcessName == "" {
+		httpapi.WriteError(w, http.StatusBadRequest, "process_name이 필요합니다")
+		return
+	}
+
+	entry := models.SecurityAllowlistEntry{NodeID: r.PathValue("nodeID"), ProcessName: req.ProcessName, Comment: req.Comment}
+	if err := h.repo.AddAllowlistEntry(r.Context(), &entry); err != nil {
+		httpapi.WriteError(w, http.StatusInternalServerError, "허용 목록 추가 실패")
+		return
+	}
+	h.tracker.InvalidateAllowlist(entry.NodeID)
+	httpapi.WriteJSON(w, http.StatusCreated, entry)
+}
+
+func (h *Handler) handleDeleteAllowlist(w http.ResponseWriter, r *http.Request) {
+	nodeID := r.PathValue("nodeID")
+	deleted, err := h.repo.DeleteAllowlistEntry(r.Context(), nodeID, r.PathValue("name"))
+	if err != nil {
+		httpapi.WriteError(w, http.StatusInternalServerError, "허용 목록 삭제 실패")
+		return
+	}
+	if !deleted {
+		httpapi.WriteError(w, http.StatusNotFound, "허용 목록에 없는 프로세스입니다")
+		return
+	}
+	h.tracker.InvalidateAllowlist(nodeID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,318 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	config "system-collector/configs"
+	"system-collector/internal/events"
+	"system-collector/internal/registry"
+	"system-collector/internal/repository"
+	"system-collector/pkg/logger"
+	"system-collector/pkg/models"
+)
+
+// allowlistRefresh는 노드의 허용 목록을 DB에서 다시 읽는 주기입니다.
+// 다른 인스턴스의 API로 바뀐 목록도 이 주기 안에 반영됩니다.
+const allowlistRefresh = time.Minute
+
+// 이미 보고한 의심 프로세스의 종류
+const (
+	flagMiner = "miner"
+	flagPath  = "path"
+)
+
+// flag는 이미 보고한 의심 프로세스입니다. 프로세스가 살아 있는 동안 같은 이벤트를 반복하지 않습니다.
+type flag struct {
+	pid  int
+	name string
+	kind string
+}
+
+// nodeState는 노드 하나의 알려진 프로세스 이름, 허용 목록, 프로세스/스레드 수의 평소 값입니다
+type nodeState struct {
+	known     map[string]bool // nil이면 아직 DB에서 읽지 못함
+	learning  bool            // 알려진 이름이 하나도 없으면 첫 목록을 이벤트 없이 기준으로 저장
+	allow     map[string]bool
+	allowAt   time.Time
+	flagged   map[flag]bool
+	processes counter
+	threads   counter
+}
+
+// Tracker는 수집 큐의 Sink로 동작하며 노드의 프로세스 목록에서 보안 이벤트를 감지합니다.
+//   - 노드에서 처음 실행된 프로세스 이름 (security_new_process)
+//   - 의심 경로(/tmp, /dev/shm 등)에서 실행 중인 root 프로세스 (security_suspicious_path)
+//   - 알려진 채굴 프로그램 (security_known_miner)
+//   - 프로세스 수, 스레드 수의 급증 (security_process_spike, security_thread_spike)
+//
+// 노드별 허용 목록에 있는 프로세스 이름은 보고하지 않습니다.
+// 감지한 이벤트는 security_events 테이블에 저장하고 이벤트 버스로 발행합니다.
+type Tracker struct {
+	repo     *repository.SecurityRepository
+	bus      *events.Bus
+	registry *registry.NodeRegistry
+
+	mu       sync.Mutex
+	nodes    map[string]*nodeState
+	patterns []*regexp.Regexp
+	compiled *config.Config // patterns를 컴파일한 설정 (설정이 다시 로드되면 다시 컴파일)
+}
+
+// NewTracker는 보안 이벤트 Tracker를 생성합니다
+func NewTracker(repo *repository.SecurityRepository, bus *events.Bus, registry *registry.NodeRegistry) *Tracker {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("보안 이벤트 트래커 초기화 중")
+
+	return &Tracker{
+		repo:     repo,
+		bus:      bus,
+		registry: registry,
+		nodes:    make(map[string]*nodeState),
+	}
+}
+
+// Name은 Sink 이름을 반환합니다
+func (t *Tracker) Name() string {
+	return "security"
+}
+
+// Write는 프로세스 목록과 프로세스/스레드 수를 확인해 보안 이벤트를 저장하고 발행합니다.
+// 프로세스 목록이 비어 있는 메트릭스는 프로세스 검사를 건너뜁니다.
+func (t *Tracker) Write(ctx context.Context, metrics *models.SystemMetrics) error {
+	cfg := config.Get()
+	if !cfg.Security.Enabled {
+		return nil
+	}
+	sugar := logger.FromContext(ctx)
+
+	nodeID := metrics.Key
+	st := t.state(ctx, nodeID, len(metrics.Processes) > 0)
+	now := time.Now()
+
+	t.mu.Lock()
+	if t.compiled != cfg {
+		t.patterns = compilePatterns(cfg.Security.MinerPatterns)
+		t.compiled = cfg
+	}
+	var detected []models.SecurityEvent
+	var newNames []string
+	if len(metrics.Processes) > 0 {
+		detected, newNames = st.checkProcesses(nodeID, metrics.Processes, cfg, t.patterns, now)
+	}
+	detected = append(detected, st.checkSpikes(nodeID, metrics.System, cfg, now)...)
+	t.mu.Unlock()
+
+	if len(newNames) > 0 {
+		if err := t.repo.AddKnownProcesses(ctx, nodeID, newNames); err != nil {
+			sugar.Errorw("알려진 프로세스 저장 실패, 메모리에만 반영", "count", len(newNames), "error", err)
+		}
+	}
+	if len(detected) == 0 {
+		return nil
+	}
+	if err := t.repo.SaveSecurityEvents(ctx, detected); err != nil {
+		return fmt.Errorf("보안 이벤트 저장 실패: %v", err)
+	}
+
+	name := t.registry.DisplayName(nodeID, metrics.System.Hostname)
+	for _, e := range detected {
+		sugar.Infow("보안 이벤트 감지", "type", e.Type, "process", e.ProcessName, "pid", e.PID, "user", e.User)
+		data := map[string]interface{}{
+			"security_event_id": e.ID,
+			"node_name":         name,
+			"hostname":          metrics.System.Hostname,
+		}
+		if e.ProcessName != "" {
+			data["process_name"] = e.ProcessName
+			data["pid"] = e.PID
+			data["user"] = e.User
+			data["command"] = e.Command
+			data["labels"] = map[string]string{"process": e.ProcessName}
+		} else {
+			data["old_value"] = e.OldValue
+			data["new_value"] = e.NewValue
+		}
+		t.bus.Publish(models.Event{
+			NodeID:   nodeID,
+			Type:     e.Type,
+			Severity: e.Severity,
+			Message:  e.Message,
+			Data:     data,
+		})
+	}
+	return nil
+}
+
+// state는 노드의 상태를 반환합니다. 허용 목록이 없거나 오래되었으면 DB에서 다시 읽고,
+// needKnown이 true이고 알려진 프로세스 이름을 아직 읽지 못했으면 함께 읽습니다.
+// 읽지 못하면 이전 값을 계속 사용하고 다음 메트릭스에서 다시 읽습니다.
+func (t *Tracker) state(ctx context.Context, nodeID string, needKnown bool) *nodeState {
+	sugar := logger.FromContext(ctx)
+
+	t.mu.Lock()
+	st, ok := t.nodes[nodeID]
+	stale := !ok || time.Since(st.allowAt) >= allowlistRefresh
+	loadKnown := needKnown && (!ok || st.known == nil)
+	t.mu.Unlock()
+	if !stale && !loadKnown {
+		return st
+	}
+
+	var allow []models.SecurityAllowlistEntry
+	var allowErr error
+	if stale {
+		if allow, allowErr = t.repo.GetAllowlist(ctx, nodeID); allowErr != nil {
+			sugar.Errorw("허용 목록 조회 실패, 이전 목록 사용", "error", allowErr)
+		}
+	}
+	var known []string
+	var knownErr error
+	if loadKnown {
+		if known, knownErr = t.repo.GetKnownProcesses(ctx, nodeID); knownErr != nil {
+			sugar.Errorw("알려진 프로세스 조회 실패, 새 프로세스 감지 보류", "error", knownErr)
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if cur, ok := t.nodes[nodeID]; ok {
+		st = cur
+	} else {
+		st = &nodeState{allow: make(map[string]bool), flagged: make(map[flag]bool)}
+		t.nodes[nodeID] = st
+	}
+	if stale {
+		st.allowAt = time.Now()
+		if allowErr == nil {
+			st.allow = make(map[string]bool, len(allow))
+			for _, e := range allow {
+				st.allow[e.ProcessName] = true
+			}
+		}
+	}
+	if loadKnown && knownErr == nil && st.known == nil {
+		st.known = make(map[string]bool, len(known))
+		for _, name := range known {
+			st.known[name] = true
+		}
+		st.learning = len(known) == 0
+	}
+	return st
+}
+
+// checkProcesses는 프로세스 목록에서 새 프로세스 이름, 의심 경로의 root 프로세스, 채굴 프로그램을 찾아
+// 이벤트와 새로 알게 된 프로세스 이름을 반환합니다. t.mu를 잡은 상태에서 호출해야 합니다.
+func (st *nodeState) checkProcesses(nodeID string, list []models.ProcessInfo, cfg *config.Config, patterns []*regexp.Regexp, now time.Time) ([]models.SecurityEvent, []string) {
+	sorted := make([]models.ProcessInfo, len(list))
+	copy(sorted, list)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].PID < sorted[j].PID })
+
+	var detected []models.SecurityEvent
+	var newNames []string
+	event := func(p models.ProcessInfo, eventType, severity, message string) models.SecurityEvent {
+		return models.SecurityEvent{
+			NodeID:      nodeID,
+			Type:        eventType,
+			Severity:    severity,
+			ProcessName: p.Name,
+			PID:         p.PID,
+			User:        p.User,
+			Command:     p.Command,
+			Message:     message,
+			CreatedAt:   now,
+		}
+	}
+
+	flagged := make(map[flag]bool, len(st.flagged))
+	for _, p := range sorted {
+		if p.Name == "" {
+			continue
+		}
+		allowed := st.allow[p.Name]
+
+		if st.known != nil && !st.known[p.Name] {
+			st.known[p.Name] = true
+			newNames = append(newNames, p.Name)
+			if !st.learning && !allowed {
+				detected = append(detected, event(p, models.EventSecurityNewProcess, models.SeverityInfo,
+					fmt.Sprintf("새 프로세스 %s 실행 (PID %d, 사용자 %s)", p.Name, p.PID, p.User)))
+			}
+		}
+		if allowed {
+			continue
+		}
+
+		if pattern, ok := knownMiner(p, patterns); ok {
+			key := flag{pid: p.PID, name: p.Name, kind: flagMiner}
+			flagged[key] = true
+			if !st.flagged[key] {
+				detected = append(detected, event(p, models.EventSecurityKnownMiner, models.SeverityCritical,
+					fmt.Sprintf("채굴 프로그램으로 의심되는 프로세스 %s 실행 중 (PID %d, 사용자 %s, 패턴 %s)", p.Name, p.PID, p.User, pattern)))
+			}
+		}
+		if path, ok := suspiciousPath(p, cfg.Security.SuspiciousPaths); ok {
+			key := flag{pid: p.PID, name: p.Name, kind: flagPath}
+			flagged[key] = true
+			if !st.flagged[key] {
+				detected = append(detected, event(p, models.EventSecuritySuspiciousPath, models.SeverityCritical,
+					fmt.Sprintf("root 프로세스 %s가 %s에서 실행 중 (PID %d, %s)", p.Name, path, p.PID, executable(p.Command))))
+			}
+		}
+	}
+	st.flagged = flagged
+	if st.known != nil {
+		st.learning = false
+	}
+	return detected, newNames
+}
+
+// checkSpikes는 프로세스 수와 스레드 수가 평소 값보다 크게 늘었으면 이벤트를 반환합니다.
+// t.mu를 잡은 상태에서 호출해야 합니다.
+func (st *nodeState) checkSpikes(nodeID string, system models.SystemInfo, cfg *config.Config, now time.Time) []models.SecurityEvent {
+	alpha := 2 / float64(cfg.Security.SpikeWindow+1)
+
+	var detected []models.SecurityEvent
+	check := func(c *counter, value uint64, eventType, label string) {
+		if value == 0 {
+			return
+		}
+		entered, baseline := c.observe(float64(value), alpha, cfg.Security.SpikeRatio, cfg.Security.SpikeMinIncrease)
+		if !entered {
+			return
+		}
+		detected = append(detected, models.SecurityEvent{
+			NodeID:    nodeID,
+			Type:      eventType,
+			Severity:  models.SeverityWarning,
+			OldValue:  strconv.FormatFloat(baseline, 'f', 0, 64),
+			NewValue:  strconv.FormatUint(value, 10),
+			Message:   fmt.Sprintf("%s 급증: 평소 %.0f개 -> %d개", label, baseline, value),
+			CreatedAt: now,
+		})
+	}
+	check(&st.processes, system.TotalProcesses, models.EventSecurityProcessSpike, "프로세스 수")
+	check(&st.threads, system.TotalThreads, models.EventSecurityThreadSpike, "스레드 수")
+	return detected
+}
+
+// Forget은 노드의 알려진 프로세스 이름, 허용 목록, 평소 값을 버립니다
+func (t *Tracker) Forget(nodeID string) {
+	t.mu.Lock()
+	delete(t.nodes, nodeID)
+	t.mu.Unlock()
+}
+
+// InvalidateAllowlist는 다음 메트릭스에서 노드의 허용 목록을 다시 읽도록 합니다
+func (t *Tracker) InvalidateAllowlist(nodeID string) {
+	t.mu.Lock()
+	if st, ok := t.nodes[nodeID]; ok {
+		st.allowAt = time.Time{}
+	}
+	t.mu.Unlock()
+}
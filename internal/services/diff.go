@@ -0,0 +1,112 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"system-collector/pkg/models"
+)
+
+// systemd ActiveState 값
+const (
+	stateActive    = "active"
+	stateReloading = "reloading"
+	stateFailed    = "failed"
+)
+
+// unit은 비교에 사용하는 서비스 하나의 상태입니다
+type unit struct {
+	name        string
+	activeState string
+	subState    string
+	enabled     bool
+}
+
+// up은 유닛이 실행 중인지 반환합니다 (설정을 다시 읽는 중인 유닛 포함)
+func (u unit) up() bool {
+	return u.activeState == stateActive || u.activeState == stateReloading
+}
+
+// snapshot은 서비스 목록을 유닛 이름별 상태로 바꿉니다
+func snapshot(services []models.ServiceInfo) map[string]unit {
+	units := make(map[string]unit, len(services))
+	for _, s := range services {
+		if s.Name == "" {
+			continue
+		}
+		units[s.Name] = unit{name: s.Name, activeState: s.ActiveState, subState: s.SubState, enabled: s.Enabled}
+	}
+	return units
+}
+
+func enabledString(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+// diff는 이전 목록과 현재 목록을 비교해 서비스 이벤트를 유닛 이름 순으로 반환합니다
+func diff(nodeID string, prev, cur map[string]unit, now time.Time) []models.ServiceEvent {
+	names := make([]string, 0, len(prev)+len(cur))
+	for name := range cur {
+		names = append(names, name)
+	}
+	for name := range prev {
+		if _, ok := cur[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var events []models.ServiceEvent
+	for _, name := range names {
+		p, hadPrev := prev[name]
+		c, hasCur := cur[name]
+		event := func(eventType, severity, oldValue, newValue, message string) models.ServiceEvent {
+			return models.ServiceEvent{
+				NodeID:      nodeID,
+				Unit:        name,
+				Type:        eventType,
+				Severity:    severity,
+				OldValue:    oldValue,
+				NewValue:    newValue,
+				ActiveState: c.activeState,
+				SubState:    c.subState,
+				Message:     message,
+				CreatedAt:   now,
+			}
+		}
+
+		switch {
+		case !hasCur:
+			events = append(events, event(models.EventServiceDisappeared, models.SeverityWarning, p.activeState, "",
+				fmt.Sprintf("서비스 %s 사라짐 (마지막 상태 %s)", name, p.activeState)))
+			continue
+		case !hadPrev:
+			events = append(events, event(models.EventServiceAppeared, models.SeverityInfo, "", c.activeState,
+				fmt.Sprintf("서비스 %s 나타남 (상태 %s/%s, %s)", name, c.activeState, c.subState, enabledString(c.enabled))))
+			continue
+		}
+
+		if p.activeState != c.activeState {
+			if c.activeState == stateFailed {
+				events = append(events, event(models.EventServiceFailed, models.SeverityWarning, p.activeState, c.activeState,
+					fmt.Sprintf("서비스 %s 실패 (%s -> %s/%s)", name, p.activeState, c.activeState, c.subState)))
+			} else {
+				events = append(events, event(models.EventServiceStateChanged, models.SeverityInfo, p.activeState, c.activeState,
+					fmt.Sprintf("서비스 %s 상태 변경 %s -> %s/%s", name, p.activeState, c.activeState, c.subState)))
+			}
+		}
+		if p.enabled != c.enabled {
+			eventType, severity := models.EventServiceEnabled, models.SeverityInfo
+			if !c.enabled {
+				eventType, severity = models.EventServiceDisabled, models.SeverityWarning
+			}
+			events = append(events, event(eventType, severity, enabledString(p.enabled), enabledString(c.enabled),
+				fmt.Sprintf("서비스 %s %s -> %s", name, enabledString(p.enabled), enabledString(c.enabled))))
+		}
+	}
+	return events
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"system-collector/pkg/models"
+)
+
+func service(name, active, sub string, enabled bool) models.ServiceInfo {
+	return models.ServiceInfo{Name: name, ActiveState: active, SubState: sub, Enabled: enabled}
+}
+
+// brief는 비교하기 쉽게 이벤트를 "유형 유닛 이전값->새값" 형식으로 줄입니다
+func brief(events []models.ServiceEvent) []string {
+	var out []string
+	for _, e := range events {
+		out = append(out, fmt.Sprintf("%s %s %s->%s", e.Type, e.Unit, e.OldValue, e.NewValue))
+	}
+	return out
+}
+
+func TestDiff(t *testing.T) {
+	nginx := service("nginx.service", "active", "running", true)
+
+	tests := []struct {
+		name string
+		prev []models.ServiceInfo
+		cur  []models.ServiceInfo
+		want []string
+	}{
+		{
+			name: "변화 없음",
+			prev: []models.ServiceInfo{nginx},
+			cur:  []models.ServiceInfo{nginx},
+		},
+		{
+			name: "하위 상태만 바뀜",
+			prev: []models.ServiceInfo{nginx},
+			cur:  []models.ServiceInfo{service("nginx.service", "active", "exited", true)},
+		},
+		{
+			name: "실패",
+			prev: []models.ServiceInfo{nginx},
+			cur:  []models.ServiceInfo{service("nginx.service", "failed", "failed", true)},
+			want: []string{"service_failed nginx.service active->failed"},
+		},
+		{
+			name: "상태 변경",
+			prev: []models.ServiceInfo{nginx},
+			cur:  []models.ServiceInfo{service("nginx.service", "inactive", "dead", true)},
+			want: []string{"service_state_changed nginx.service active->inactive"},
+		},
+		{
+			name: "비활성화",
+			prev: []models.ServiceInfo{nginx},
+			cur:  []models.ServiceInfo{service("nginx.service", "active", "running", false)},
+			want: []string{"service_disabled nginx.service enabled->disabled"},
+		},
+		{
+			name: "중지와 활성화",
+			prev: []models.ServiceInfo{service("cron.service", "active", "running", false)},
+			cur:  []models.ServiceInfo{service("cron.service", "inactive", "dead", true)},
+			want: []string{"service_state_changed cron.service active->inactive", "service_enabled cron.service disabled->enabled"},
+		},
+		{
+			name: "나타남과 사라짐",
+			prev: []models.ServiceInfo{service("old.service", "active", "running", true)},
+			cur:  []models.ServiceInfo{nginx, {Name: ""}},
+			want: []string{"service_appeared nginx.service ->active", "service_disappeared old.service active->"},
+		},
+	}
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := diff("node-1", snapshot(tt.prev), snapshot(tt.cur), now)
+			if got := brief(events); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diff =\n%q\n기대\n%q", got, tt.want)
+			}
+			for _, e := range events {
+				if e.NodeID != "node-1" || !e.CreatedAt.Equal(now) {
+					t.Errorf("이벤트 노드/시간 = %s, %v", e.NodeID, e.CreatedAt)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckCritical(t *testing.T) {
+	// 각 단계의 서비스 목록을 차례로 적용하며 필수 서비스 이벤트를 확인
+	steps := []struct {
+		name     string
+		services []models.ServiceInfo
+		want     []string
+	}{
+		{
+			name:     "실행 중",
+			services: []models.ServiceInfo{service("nginx.service", "active", "running", true), service("sshd.service", "active", "running", true)},
+		},
+		{
+			name:     "다시 읽는 중은 실행 중으로 봄",
+			services: []models.ServiceInfo{service("nginx.service", "reloading", "reload", true), service("sshd.service", "active", "running", true)},
+		},
+		{
+			name:     "nginx 실패",
+			services: []models.ServiceInfo{service("nginx.service", "failed", "failed", true), service("sshd.service", "active", "running", true)},
+			want:     []string{"critical_service_down nginx.service ->failed"},
+		},
+		{
+			name:     "계속 실패하면 다시 알리지 않음",
+			services: []models.ServiceInfo{service("nginx.service", "failed", "failed", true), service("sshd.service", "active", "running", true)},
+		},
+		{
+			name:     "nginx 복구, sshd 사라짐",
+			services: []models.ServiceInfo{service("nginx.service", "active", "running", true)},
+			want:     []string{"critical_service_recovered nginx.service ->active", "critical_service_down sshd.service ->"},
+		},
+	}
+
+	st := &nodeState{
+		critical: map[string]bool{"nginx.service": true, "sshd.service": true},
+		down:     make(map[string]bool),
+	}
+	for _, step := range steps {
+		st.units = snapshot(step.services)
+		events := st.checkCritical("node-1", time.Now())
+		if got := brief(events); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("%s: checkCritical =\n%q\n기대\n%q", step.name, got, step.want)
+		}
+	}
+	if len(st.down) != 1 || !st.down["sshd.service"] {
+		t.Errorf("down = %v, sshd.service만 기대", st.down)
+	}
+}
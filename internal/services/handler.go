@@ -0,0 +1,153 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"system-collector/internal/admin"
+	"system-collector/internal/httpapi"
+	"system-collector/internal/repository"
+	"system-collector/pkg/models"
+)
+
+// 서비스 이벤트 조회 시 limit 기본값과 최대값
+const (
+	defaultEventLimit = 100
+	maxEventLimit     = 1000
+)
+
+// Handler는 서비스 이벤트 조회와 필수 서비스 관리 API를 제공합니다.
+// 조회와 변경 API 모두 관리 API와 같은 인증을 사용합니다.
+type Handler struct {
+	tracker *Tracker
+	repo    *repository.ServiceRepository
+}
+
+// NewHandler는 서비스 핸들러를 생성합니다
+func NewHandler(tracker *Tracker, repo *repository.ServiceRepository) *Handler {
+	return &Handler{tracker: tracker, repo: repo}
+}
+
+// RegisterRoutes는 핸들러를 mux에 등록합니다
+func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/services/events", admin.RequireAdmin(h.handleEvents))
+	mux.HandleFunc("GET /api/nodes/{nodeID}/services/events", admin.RequireAdmin(h.handleNodeEvents))
+	mux.HandleFunc("GET /api/nodes/{nodeID}/services/critical", admin.RequireAdmin(h.handleCritical))
+	mux.HandleFunc("POST /api/nodes/{nodeID}/services/critical", admin.RequireAdmin(h.handleAddCritical))
+	mux.HandleFunc("DELETE /api/nodes/{nodeID}/services/critical/{unit}", admin.RequireAdmin(h.handleDeleteCritical))
+}
+
+// handleEvents는 서비스 이벤트를 최신순으로 반환합니다.
+// node_id, unit, type, since(RFC3339 또는 24h 같은 기간), limit 쿼리 파라미터를 지원합니다.
+func (h *Handler) handleEvents(w http.ResponseWriter, r *http.Request) {
+	h.writeEvents(w, r, r.URL.Query().Get("node_id"))
+}
+
+// handleNodeEvents는 노드의 서비스 이벤트를 최신순으로 반환합니다
+func (h *Handler) handleNodeEvents(w http.ResponseWriter, r *http.Request) {
+	h.writeEvents(w, r, r.PathValue("nodeID"))
+}
+
+func (h *Handler) writeEvents(w http.ResponseWriter, r *http.Request, nodeID string) {
+	q := r.URL.Query()
+	filter := repository.ServiceEventFilter{
+		NodeID: nodeID,
+		Unit:   q.Get("unit"),
+		Type:   q.Get("type"),
+		Limit:  defaultEventLimit,
+	}
+	if s := q.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			httpapi.WriteError(w, http.StatusBadRequest, "limit는 양의 정수여야 합니다")
+			return
+		}
+		filter.Limit = min(n, maxEventLimit)
+	}
+	if s := q.Get("since"); s != "" {
+		since, ok := httpapi.ParseSince(s)
+		if !ok {
+			httpapi.WriteError(w, http.StatusBadRequest, "since는 RFC3339 시각 또는 양의 기간이어야 합니다 (예: 24h)")
+			return
+		}
+		filter.Since = since
+	}
+
+	events, err := h.repo.GetServiceEvents(r.Context(), filter)
+	if err != nil {
+		httpapi.WriteError(w, http.StatusInternalServerError, "서비스 이벤트 조회 실패")
+		return
+	}
+	httpapi.WriteJSON(w, http.StatusOK, events)
+}
+
+// criticalResponse는 필수 서비스와 마지막으로 수신한 상태입니다.
+// 이 인스턴스가 노드의 서비스 목록을 모르면 상태가 비어 있습니다.
+type criticalResponse struct {
+	models.CriticalService
+	ActiveState string `json:"active_state,omitempty"`
+	SubState    string `json:"sub_state,omitempty"`
+	Known       bool   `json:"known"`
+}
+
+// handleCritical은 노드의 필수 서비스와 현재 상태를 반환합니다
+func (h *Handler) handleCritical(w http.ResponseWriter, r *http.Request) {
+	nodeID := r.PathValue("nodeID")
+	list, err := h.repo.GetCriticalServices(r.Context(), nodeID)
+	if err != nil {
+		httpapi.WriteError(w, http.StatusInternalServerError, "필수 서비스 조회 실패")
+		return
+	}
+
+	resp := make([]criticalResponse, 0, len(list))
+	for _, s := range list {
+		item := criticalResponse{CriticalService: s}
+		item.ActiveState, item.SubState, item.Known = h.tracker.UnitState(nodeID, s.Unit)
+		resp = append(resp, item)
+	}
+	httpapi.WriteJSON(w, http.StatusOK, resp)
+}
+
+// criticalRequest는 필수 서비스 추가 요청입니다
+type criticalRequest struct {
+	Unit    string `json:"unit"`
+	Comment string `json:"comment"`
+}
+
+func (h *Handler) handleAddCritical(w http.ResponseWriter, r *http.Request) {
+	var req criticalRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+		httpapi.WriteError(w, http.StatusBadRequest, "요청 본문이 올바른 JSON이 아닙니다")
+		return
+	}
+	req.Unit = strings.TrimSpace(req.Unit)
+	if req.Unit == "" {
+		httpapi.WriteError(w, http.StatusBadRequest, "unit이 필요합니다")
+		return
+	}
+
+	service := models.CriticalService{NodeID: r.PathValue("nodeID"), Unit: req.Unit, Comment: req.Comment}
+	if err := h.repo.AddCriticalService(r.Context(), &service); err != nil {
+		httpapi.WriteError(w, http.StatusInternalServerError, "필수 서비스 추가 실패")
+		return
+	}
+	h.tracker.InvalidateCritical(service.NodeID)
+	httpapi.WriteJSON(w, http.StatusCreated, service)
+}
+
+func (h *Handler) handleDeleteCritical(w http.ResponseWriter, r *http.Request) {
+	nodeID := r.PathValue("nodeID")
+	deleted, err := h.repo.DeleteCriticalService(r.Context(), nodeID, r.PathValue("unit"))
+	if err != nil {
+		httpapi.WriteError(w, http.StatusInternalServerError, "필수 서비스 삭제 실패")
+		return
+	}
+	if !deleted {
+		httpapi.WriteError(w, http.StatusNotFound, "필수 서비스가 없습니다")
+		return
+	}
+	h.tracker.InvalidateCritical(nodeID)
+	w.WriteHeader(http.StatusNoContent)
+}
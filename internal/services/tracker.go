@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"system-collector/internal/events"
+	"system-collector/internal/registry"
+	"system-collector/internal/repository"
+	"system-collector/pkg/logger"
+	"system-collector/pkg/models"
+)
+
+// criticalRefresh는 노드의 필수 서비스 목록을 DB에서 다시 읽는 주기입니다.
+// 다른 인스턴스의 API로 바뀐 목록도 이 주기 안에 반영됩니다.
+const criticalRefresh = time.Minute
+
+// nodeState는 노드 하나의 마지막 서비스 목록과 필수 서비스 상태입니다
+type nodeState struct {
+	units      map[string]unit // nil이면 아직 비교 기준이 없음
+	critical   map[string]bool // 필수 서비스 유닛 이름
+	criticalAt time.Time
+	down       map[string]bool // active가 아닌 필수 서비스
+}
+
+// Tracker는 수집 큐의 Sink로 동작하며 노드의 연속된 서비스 목록을 비교해
+// 실패, 상태 변경, enabled/disabled 변경, 유닛의 추가와 사라짐을 감지합니다.
+// 노드별 필수 서비스가 active가 아니게 되면 critical_service_down 이벤트를 발행합니다.
+// 감지한 이벤트는 service_events 테이블에 저장하고 이벤트 버스로 발행합니다.
+type Tracker struct {
+	repo     *repository.ServiceRepository
+	bus      *events.Bus
+	registry *registry.NodeRegistry
+
+	mu    sync.Mutex
+	nodes map[string]*nodeState
+}
+
+// NewTracker는 서비스 상태 Tracker를 생성합니다
+func NewTracker(repo *repository.ServiceRepository, bus *events.Bus, registry *registry.NodeRegistry) *Tracker {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("서비스 상태 트래커 초기화 중")
+
+	return &Tracker{
+		repo:     repo,
+		bus:      bus,
+		registry: registry,
+		nodes:    make(map[string]*nodeState),
+	}
+}
+
+// Name은 Sink 이름을 반환합니다
+func (t *Tracker) Name() string {
+	return "services"
+}
+
+// Write는 서비스 목록을 이전 목록과 비교하고 필수 서비스 상태를 확인해 이벤트를 저장하고 발행합니다.
+// 서비스 목록이 비어 있는 메트릭스(수집 실패, systemd가 없는 노드)는 건너뜁니다.
+func (t *Tracker) Write(ctx context.Context, metrics *models.SystemMetrics) error {
+	sugar := logger.FromContext(ctx)
+	if len(metrics.Services) == 0 {
+		return nil
+	}
+
+	nodeID := metrics.Key
+	st := t.state(ctx, nodeID)
+	cur := snapshot(metrics.Services)
+	now := time.Now()
+
+	t.mu.Lock()
+	var changes []models.ServiceEvent
+	if st.units != nil {
+		changes = diff(nodeID, st.units, cur, now)
+	}
+	st.units = cur
+	changes = append(changes, st.checkCritical(nodeID, now)...)
+	t.mu.Unlock()
+
+	if len(changes) == 0 {
+		return nil
+	}
+	if err := t.repo.SaveServiceEvents(ctx, changes); err != nil {
+		return fmt.Errorf("서비스 이벤트 저장 실패: %v", err)
+	}
+
+	name := t.registry.DisplayName(nodeID, metrics.System.Hostname)
+	for _, c := range changes {
+		sugar.Infow("서비스 상태 변경 감지", "unit", c.Unit, "type", c.Type, "old", c.OldValue, "new", c.NewValue)
+		t.bus.Publish(models.Event{
+			NodeID:   nodeID,
+			Type:     c.Type,
+			Severity: c.Severity,
+			Message:  c.Message,
+			Data: map[string]interface{}{
+				"service_event_id": c.ID,
+				"unit":             c.Unit,
+				"old_value":        c.OldValue,
+				"new_value":        c.NewValue,
+				"active_state":     c.ActiveState,
+				"sub_state":        c.SubState,
+				"labels":           map[string]string{"unit": c.Unit},
+				"node_name":        name,
+				"hostname":         metrics.System.Hostname,
+			},
+		})
+	}
+	return nil
+}
+
+// state는 노드의 상태를 반환합니다. 필수 서비스 목록이 없거나 오래되었으면 DB에서 다시 읽으며,
+// 읽지 못하면 이전 목록을 계속 사용하고 다음 주기에 다시 읽습니다.
+func (t *Tracker) state(ctx context.Context, nodeID string) *nodeState {
+	t.mu.Lock()
+	st, ok := t.nodes[nodeID]
+	stale := !ok || time.Since(st.criticalAt) >= criticalRefresh
+	t.mu.Unlock()
+	if ok && !stale {
+		return st
+	}
+
+	list, err := t.repo.GetCriticalServices(ctx, nodeID)
+	if err != nil {
+		sugar := logger.FromContext(ctx)
+		sugar.Errorw("필수 서비스 목록 조회 실패, 이전 목록 사용", "error", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if cur, ok := t.nodes[nodeID]; ok {
+		st = cur
+	} else {
+		st = &nodeState{critical: make(map[string]bool), down: make(map[string]bool)}
+		t.nodes[nodeID] = st
+	}
+	st.criticalAt = time.Now()
+	if err == nil {
+		st.critical = make(map[string]bool, len(list))
+		for _, s := range list {
+			st.critical[s.Unit] = true
+		}
+		// 필수 서비스에서 빠진 유닛은 복구 이벤트 없이 잊음
+		for unit := range st.down {
+			if !st.critical[unit] {
+				delete(st.down, unit)
+			}
+		}
+	}
+	return st
+}
+
+// checkCritical은 필수 서비스가 active가 아니게 되었거나 다시 active가 되었으면 이벤트를 반환합니다.
+// t.mu를 잡은 상태에서 호출해야 합니다.
+func (st *nodeState) checkCritical(nodeID string, now time.Time) []models.ServiceEvent {
+	names := make([]string, 0, len(st.critical))
+	for name := range st.critical {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var events []models.ServiceEvent
+	for _, name := range names {
+		u, ok := st.units[name]
+		down := !ok || !u.up()
+		if down == st.down[name] {
+			continue
+		}
+
+		event := models.ServiceEvent{
+			NodeID:      nodeID,
+			Unit:        name,
+			ActiveState: u.activeState,
+			SubState:    u.subState,
+			CreatedAt:   now,
+		}
+		if down {
+			st.down[name] = true
+			event.Type = models.EventCriticalServiceDown
+			event.Severity = models.SeverityCritical
+			event.NewValue = u.activeState
+			if ok {
+				event.Message = fmt.Sprintf("필수 서비스 %s가 실행 중이 아님 (%s/%s)", name, u.activeState, u.subState)
+			} else {
+				event.Message = fmt.Sprintf("필수 서비스 %s가 없음", name)
+			}
+		} else {
+			delete(st.down, name)
+			event.Type = models.EventCriticalServiceRecovered
+			event.Severity = models.SeverityInfo
+			event.NewValue = u.activeState
+			event.Message = fmt.Sprintf("필수 서비스 %s 복구됨 (%s/%s)", name, u.activeState, u.subState)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// Forget은 노드의 마지막 서비스 목록과 필수 서비스 상태를 버립니다
+func (t *Tracker) Forget(nodeID string) {
+	t.mu.Lock()
+	delete(t.nodes, nodeID)
+	t.mu.Unlock()
+}
+
+// InvalidateCritical은 다음 메트릭스에서 노드의 필수 서비스 목록을 다시 읽도록 합니다
+func (t *Tracker) InvalidateCritical(nodeID string) {
+	t.mu.Lock()
+	if st, ok := t.nodes[nodeID]; ok {
+		st.criticalAt = time.Time{}
+	}
+	t.mu.Unlock()
+}
+
+// UnitState는 노드의 마지막 서비스 목록에서 유닛의 상태를 반환합니다. 이 인스턴스가 모르는 유닛이면 false를 반환합니다.
+func (t *Tracker) UnitState(nodeID, name string) (activeState, subState string, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	st, found := t.nodes[nodeID]
+	if !found {
+		return "", "", false
+	}
+	u, found := st.units[name]
+	return u.activeState, u.subState, found
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	config "system-collector/configs"
+	"system-collector/internal/telemetry"
 	"system-collector/pkg/logger"
 	"system-collector/pkg/models"
 
@@ -52,6 +53,7 @@ func NewInfluxDBClient() (*InfluxDBClient, error) {
 	errorsCh := writeAPI.Errors()
 	go func() {
 		for err := range errorsCh {
+			telemetry.InfluxWriteErrors.Inc()
 			sugar.Errorw("InfluxDB 쓰기 오류", "error", err)
 		}
 	}()
@@ -66,13 +68,40 @@ func NewInfluxDBClient() (*InfluxDBClient, error) {
 
 func (i *InfluxDBClient) WritePoints(points []*write.Point) {
 	sugar := logger.GetCustomLogger()
-	sugar.Infow("InfluxDBClient 쓰기 시작")
+	sugar.Debugw("InfluxDBClient 쓰기 시작")
 
 	for _, p := range points {
 		i.writeAPI.WritePoint(p)
 	}
 
-	sugar.Infow("InfluxDBClient 쓰기 완료")
+	sugar.Debugw("InfluxDBClient 쓰기 완료")
+}
+
+// Ping은 InfluxDB 서버 상태를 확인합니다
+func (i *InfluxDBClient) Ping(ctx context.Context) error {
+	ok, err := i.client.Ping(ctx)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("InfluxDB 응답 없음")
+	}
+	return nil
+}
+
+// Name은 수집 큐에서 사용하는 Sink 이름을 반환합니다
+func (i *InfluxDBClient) Name() string {
+	return "influxdb"
+}
+
+// Write는 수집 큐의 Sink 인터페이스를 구현합니다
+func (i *InfluxDBClient) Write(ctx context.Context, metrics *models.SystemMetrics) error {
+	return i.StoreMetrics(ctx, metrics)
+}
+
+// Flush는 비동기 쓰기 버퍼에 남은 포인트를 즉시 전송합니다
+func (i *InfluxDBClient) Flush() {
+	i.writeAPI.Flush()
 }
 
 func (i *InfluxDBClient) Close() {
@@ -86,9 +115,9 @@ func (i *InfluxDBClient) Close() {
 	sugar.Infow("InfluxDBClient 종료 완료")
 }
 
-func (i *InfluxDBClient) StoreMetrics(metrics *models.SystemMetrics) error {
-	sugar := logger.GetCustomLogger()
-	sugar.Infow("InfluxDBClient 메트릭스 저장 시작")
+func (i *InfluxDBClient) StoreMetrics(ctx context.Context, metrics *models.SystemMetrics) error {
+	sugar := logger.FromContext(ctx)
+	sugar.Debugw("InfluxDBClient 메트릭스 저장 시작")
 
 	points := make([]*write.Point, 0, 100) // 예상 포인트 수로 초기화
 
@@ -112,6 +141,7 @@ func (i *InfluxDBClient) StoreMetrics(metrics *models.SystemMetrics) error {
 		"temperature":         metrics.CPU.Temperature,
 		"has_vmx":             metrics.CPU.HasVMX,
 		"has_svm":             metrics.CPU.HasSVM,
+		"has_hypervisor":      metrics.CPU.HasHypervisor,
 		"has_avx":             metrics.CPU.HasAVX,
 		"has_avx2":            metrics.CPU.HasAVX2,
 		"has_neon":            metrics.CPU.HasNEON,
@@ -378,6 +408,6 @@ func (i *InfluxDBClient) StoreMetrics(metrics *models.SystemMetrics) error {
 	// 모든 포인트를 한 번에 전송
 	i.WritePoints(points)
 
-	sugar.Infow("InfluxDBClient 메트릭스 저장 완료")
+	sugar.Debugw("InfluxDBClient 메트릭스 저장 완료")
 	return nil
 }
@@ -1,16 +1,25 @@
 package storage
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	config "system-collector/configs"
 	"system-collector/pkg/logger"
+	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
+// ListenerFactory는 LISTEN/NOTIFY 전용 연결을 엽니다. 비밀번호를 호출자에게 넘기지 않기 위해 사용합니다.
+type ListenerFactory func(eventCallback pq.EventCallbackType) *pq.Listener
+
 type PostgresClient struct {
 	db *sql.DB
+	// connStr은 비밀번호를 뺀 접속 문자열입니다 (로그 출력용)
+	connStr  string
+	password string
 }
 
 func NewPostgresClient() (*PostgresClient, error) {
@@ -20,20 +29,33 @@ func NewPostgresClient() (*PostgresClient, error) {
 	cfg := config.Get()
 
 	connStr := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Postgres.Host,
+		"host=%s port=%d user=%s dbname=%s sslmode=%s",
+		dsnValue(cfg.Postgres.Host),
 		cfg.Postgres.Port,
-		cfg.Postgres.User,
-		cfg.Postgres.Password,
-		cfg.Postgres.DBName,
-		cfg.Postgres.SSLMode,
+		dsnValue(cfg.Postgres.User),
+		dsnValue(cfg.Postgres.DBName),
+		dsnValue(cfg.Postgres.SSLMode),
 	)
+	client := &PostgresClient{connStr: connStr, password: cfg.Postgres.Password}
+	sugar.Infow("postgres 연결 중", "dsn", connStr)
 
-	db, err := sql.Open("postgres", connStr)
+	connector, err := pq.NewConnector(client.dsn())
 	if err != nil {
 		sugar.Errorw("postgres 연결 실패", "error", err)
 		return nil, fmt.Errorf("postgres 연결 실패: %v", err)
 	}
+	db := sql.OpenDB(connector)
+
+	pool := cfg.Postgres.Pool
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	db.SetMaxIdleConns(pool.MaxIdleConns)
+	db.SetConnMaxLifetime(time.Duration(pool.ConnMaxLifetime) * time.Second)
+	db.SetConnMaxIdleTime(time.Duration(pool.ConnMaxIdleTime) * time.Second)
+	sugar.Infow("postgres 연결 풀 설정",
+		"maxOpenConns", pool.MaxOpenConns,
+		"maxIdleConns", pool.MaxIdleConns,
+		"connMaxLifetime", pool.ConnMaxLifetime,
+		"connMaxIdleTime", pool.ConnMaxIdleTime)
 
 	if err := db.Ping(); err != nil {
 		sugar.Errorw("postgres 연결 테스트 실패", "error", err)
@@ -41,7 +63,23 @@ func NewPostgresClient() (*PostgresClient, error) {
 	}
 
 	sugar.Infow("postgres 연결 성공")
-	return &PostgresClient{db: db}, nil
+	client.db = db
+	return client, nil
+}
+
+// dsn은 비밀번호를 포함한 접속 문자열을 만듭니다. 저장하거나 출력하지 않습니다.
+func (p *PostgresClient) dsn() string {
+	if p.password == "" {
+		return p.connStr
+	}
+	return p.connStr + " password=" + dsnValue(p.password)
+}
+
+// dsnValue는 공백이나 따옴표가 있는 값도 안전하도록 접속 문자열 값을 작은따옴표로 감쌉니다
+func dsnValue(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `\'`)
+	return "'" + s + "'"
 }
 
 func (p *PostgresClient) Close() error {
@@ -58,6 +96,26 @@ func (p *PostgresClient) Close() error {
 	return nil
 }
 
+// Ping은 데이터베이스 연결 상태를 확인합니다
+func (p *PostgresClient) Ping(ctx context.Context) error {
+	return p.db.PingContext(ctx)
+}
+
+// Stats는 연결 풀 통계를 반환합니다
+func (p *PostgresClient) Stats() sql.DBStats {
+	return p.db.Stats()
+}
+
+// ConnString은 비밀번호를 뺀 접속 문자열을 반환합니다 (로그 출력용)
+func (p *PostgresClient) ConnString() string {
+	return p.connStr
+}
+
+// NewListener는 LISTEN/NOTIFY 전용 연결을 엽니다. 연결이 끊기면 1초에서 1분 간격으로 다시 연결합니다.
+func (p *PostgresClient) NewListener(eventCallback pq.EventCallbackType) *pq.Listener {
+	return pq.NewListener(p.dsn(), time.Second, time.Minute, eventCallback)
+}
+
 func (p *PostgresClient) GetDB() *sql.DB {
 	sugar := logger.GetCustomLogger()
 	sugar.Infow("postgres 데이터베이스 연결 반환")
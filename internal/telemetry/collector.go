@@ -0,0 +1,79 @@
+package telemetry
+
+// 수집기 내부 메트릭 정의
+var (
+	// MessagesReceived는 WebSocket 메시지 유형별 수신 건수입니다
+	MessagesReceived = NewCounterVec("collector_messages_received_total",
+		"WebSocket으로 수신한 메시지 수", "type")
+
+	// BytesReceived는 WebSocket 메시지 유형별 수신 바이트입니다
+	BytesReceived = NewCounterVec("collector_bytes_received_total",
+		"WebSocket으로 수신한 바이트 수", "type")
+
+	// ParseFailures는 JSON 파싱 실패 건수입니다
+	ParseFailures = NewCounterVec("collector_parse_failures_total",
+		"JSON 파싱에 실패한 메시지 수", "type")
+
+	// AuthFailures는 obscura key 인증 실패 건수입니다
+	AuthFailures = NewCounterVec("collector_auth_failures_total",
+		"인증에 실패한 메시지 수", "reason")
+
+	// MessageDuration은 메시지 처리 시간입니다
+	MessageDuration = NewHistogramVec("collector_message_duration_seconds",
+		"메시지 수신부터 응답 전송까지 걸린 시간", DefaultBuckets, "type")
+
+	// SinkWriteDuration은 Sink별 쓰기 시간입니다
+	SinkWriteDuration = NewHistogramVec("collector_sink_write_duration_seconds",
+		"Sink별 메트릭스 쓰기 시간", DefaultBuckets, "sink")
+
+	// SinkWriteErrors는 Sink별 쓰기 실패 건수입니다
+	SinkWriteErrors = NewCounterVec("collector_sink_write_errors_total",
+		"Sink별 메트릭스 쓰기 실패 수", "sink")
+
+	// InfluxWriteErrors는 InfluxDB 비동기 쓰기 API가 보고한 오류 건수입니다
+	InfluxWriteErrors = NewCounter("collector_influxdb_write_errors_total",
+		"InfluxDB writeAPI.Errors()로 보고된 오류 수")
+
+	// PostgresQueryErrors는 저장소 메서드별 PostgreSQL 쿼리 오류 건수입니다
+	PostgresQueryErrors = NewCounterVec("collector_postgres_query_errors_total",
+		"저장소 메서드별 PostgreSQL 쿼리 오류 수", "repository", "method")
+
+	// RejectedConnections는 연결 정책으로 거부하거나 끊은 연결 수입니다
+	RejectedConnections = NewCounterVec("collector_rejected_connections_total",
+		"연결 정책으로 거부된 연결 수", "reason")
+
+	// RateLimitedMessages는 속도 제한으로 버린 메시지 수입니다
+	RateLimitedMessages = NewCounter("collector_rate_limited_messages_total",
+		"속도 제한으로 버려진 메시지 수")
+
+	// ActiveConnections는 연결 유형별 현재 WebSocket 연결 수입니다
+	ActiveConnections = NewGaugeVec("collector_active_connections",
+		"현재 연결된 WebSocket 수", "type")
+
+	// EventsPublished는 유형별 발생한 노드 이벤트 수입니다
+	EventsPublished = NewCounterVec("collector_events_published_total",
+		"이벤트 버스에 발행된 노드 이벤트 수", "type")
+
+	// EventsDropped는 이벤트 버퍼가 가득 차 버려진 이벤트 수입니다
+	EventsDropped = NewCounter("collector_events_dropped_total",
+		"이벤트 버퍼가 가득 차 버려진 이벤트 수")
+
+	// StatusUpdatesCoalesced는 DB에 반영되기 전에 같은 노드의 다음 전이와 합쳐진 상태 전이 수입니다
+	StatusUpdatesCoalesced = NewCounter("collector_node_status_updates_coalesced_total",
+		"DB 반영 전에 합쳐진 노드 상태 전이 수")
+
+	// NotificationsSent는 알림 채널별 전송 결과(sent, failed, dropped) 건수입니다
+	NotificationsSent = NewCounterVec("collector_notifications_total",
+		"알림 채널별 메시지 전송 결과 수", "channel", "result")
+)
+
+// 메시지/연결 유형 레이블 값
+const (
+	TypeMetrics = "metrics"
+	TypeLogs    = "logs"
+)
+
+// PostgresError는 저장소 메서드의 쿼리 오류를 기록합니다
+func PostgresError(repository, method string) {
+	PostgresQueryErrors.WithLabelValues(repository, method).Inc()
+}
@@ -0,0 +1,18 @@
+package telemetry
+
+import (
+	"net/http"
+
+	"system-collector/pkg/logger"
+)
+
+// Handler는 기본 Registry를 Prometheus 텍스트 포맷으로 내보내는 HTTP 핸들러입니다
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := Default.WritePrometheus(w); err != nil {
+			sugar := logger.GetCustomLogger()
+			sugar.Errorw("메트릭 내보내기 실패", "error", err)
+		}
+	})
+}
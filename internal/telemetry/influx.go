@@ -0,0 +1,97 @@
+package telemetry
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"system-collector/pkg/logger"
+
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+)
+
+// SelfMeasurement는 내부 메트릭을 저장하는 InfluxDB measurement 이름입니다
+const SelfMeasurement = "collector_self"
+
+// PointWriter는 InfluxDB 포인트를 기록하는 대상입니다
+type PointWriter interface {
+	WritePoints(points []*write.Point)
+}
+
+// InfluxReporter는 기본 Registry의 값을 주기적으로 InfluxDB에 기록합니다
+type InfluxReporter struct {
+	writer   PointWriter
+	interval time.Duration
+	instance string
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewInfluxReporter는 interval 간격으로 collector_self measurement를 기록하는 리포터를 생성합니다
+func NewInfluxReporter(writer PointWriter, interval time.Duration) *InfluxReporter {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("내부 메트릭 InfluxDB 리포터 초기화 중", "interval", interval)
+
+	instance, err := os.Hostname()
+	if err != nil {
+		instance = "unknown"
+	}
+
+	return &InfluxReporter{
+		writer:   writer,
+		interval: interval,
+		instance: instance,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start는 주기적인 기록을 시작합니다
+func (r *InfluxReporter) Start() {
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.stop:
+				r.report()
+				return
+			case <-ticker.C:
+				r.report()
+			}
+		}
+	}()
+}
+
+// Stop은 마지막 값을 기록한 뒤 리포터를 종료합니다
+func (r *InfluxReporter) Stop() {
+	close(r.stop)
+	<-r.done
+}
+
+func (r *InfluxReporter) report() {
+	now := time.Now()
+	samples := Default.Gather()
+	points := make([]*write.Point, 0, len(samples))
+
+	for _, s := range samples {
+		// 히스토그램 버킷은 포인트 수가 많아 합계와 건수만 기록
+		if strings.HasSuffix(s.Name, "_bucket") {
+			continue
+		}
+		tags := map[string]string{
+			"instance": r.instance,
+			"metric":   s.Name,
+		}
+		for _, l := range s.Labels {
+			tags[l.Name] = l.Value
+		}
+		points = append(points, influxdb2.NewPoint(SelfMeasurement, tags, map[string]interface{}{"value": s.Value}, now))
+	}
+
+	r.writer.WritePoints(points)
+}
@@ -0,0 +1,460 @@
+package telemetry
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// 메트릭 타입 (Prometheus 텍스트 포맷의 TYPE 값)
+const (
+	TypeCounter   = "counter"
+	TypeGauge     = "gauge"
+	TypeHistogram = "histogram"
+)
+
+// DefaultBuckets는 지연 시간(초) 히스토그램의 기본 버킷입니다
+var DefaultBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// LabelPair는 레이블 이름과 값의 쌍입니다
+type LabelPair struct {
+	Name  string
+	Value string
+}
+
+// Sample은 수집 시점의 값 하나입니다
+type Sample struct {
+	// Name은 접미사(_bucket, _sum, _count)를 포함한 샘플 이름입니다
+	Name   string
+	Labels []LabelPair
+	Value  float64
+}
+
+// Desc는 메트릭 정의입니다
+type Desc struct {
+	Name string
+	Help string
+	Type string
+}
+
+// Collector는 Registry에 등록할 수 있는 메트릭입니다
+type Collector interface {
+	Describe() Desc
+	Collect() []Sample
+}
+
+// Registry는 등록된 메트릭을 모아 내보냅니다
+type Registry struct {
+	mu         sync.RWMutex
+	collectors []Collector
+}
+
+// Default는 수집기 내부 메트릭이 등록되는 기본 Registry입니다
+var Default = NewRegistry()
+
+// NewRegistry는 빈 Registry를 생성합니다
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register는 메트릭을 등록합니다
+func (r *Registry) Register(c Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, c)
+}
+
+// Gather는 등록된 모든 메트릭의 현재 값을 반환합니다
+func (r *Registry) Gather() []Sample {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var samples []Sample
+	for _, c := range r.collectors {
+		samples = append(samples, c.Collect()...)
+	}
+	return samples
+}
+
+// WritePrometheus는 등록된 메트릭을 Prometheus 텍스트 포맷으로 기록합니다
+func (r *Registry) WritePrometheus(w io.Writer) error {
+	r.mu.RLock()
+	collectors := append([]Collector(nil), r.collectors...)
+	r.mu.RUnlock()
+
+	sort.Slice(collectors, func(i, j int) bool {
+		return collectors[i].Describe().Name < collectors[j].Describe().Name
+	})
+
+	for _, c := range collectors {
+		desc := c.Describe()
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", desc.Name, escapeHelp(desc.Help), desc.Name, desc.Type); err != nil {
+			return err
+		}
+		for _, s := range c.Collect() {
+			if _, err := fmt.Fprintf(w, "%s%s %s\n", s.Name, formatLabels(s.Labels), formatValue(s.Value)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func escapeHelp(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
+
+func formatLabels(labels []LabelPair) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, l := range labels {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(l.Name)
+		b.WriteString(`="`)
+		v := strings.ReplaceAll(l.Value, `\`, `\\`)
+		v = strings.ReplaceAll(v, `"`, `\"`)
+		v = strings.ReplaceAll(v, "\n", `\n`)
+		b.WriteString(v)
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// atomicFloat는 float64 값을 원자적으로 더하기 위한 타입입니다
+type atomicFloat struct {
+	bits atomic.Uint64
+}
+
+func (f *atomicFloat) Add(delta float64) {
+	for {
+		old := f.bits.Load()
+		next := math.Float64bits(math.Float64frombits(old) + delta)
+		if f.bits.CompareAndSwap(old, next) {
+			return
+		}
+	}
+}
+
+func (f *atomicFloat) Set(v float64) {
+	f.bits.Store(math.Float64bits(v))
+}
+
+func (f *atomicFloat) Load() float64 {
+	return math.Float64frombits(f.bits.Load())
+}
+
+// Counter는 단조 증가하는 값입니다
+type Counter struct {
+	v atomicFloat
+}
+
+// Inc는 카운터를 1 증가시킵니다
+func (c *Counter) Inc() { c.v.Add(1) }
+
+// Add는 카운터를 delta만큼 증가시킵니다. 음수는 무시됩니다.
+func (c *Counter) Add(delta float64) {
+	if delta > 0 {
+		c.v.Add(delta)
+	}
+}
+
+// Value는 현재 값을 반환합니다
+func (c *Counter) Value() float64 { return c.v.Load() }
+
+// Gauge는 증가와 감소가 모두 가능한 값입니다
+type Gauge struct {
+	v atomicFloat
+}
+
+// Inc는 게이지를 1 증가시킵니다
+func (g *Gauge) Inc() { g.v.Add(1) }
+
+// Dec는 게이지를 1 감소시킵니다
+func (g *Gauge) Dec() { g.v.Add(-1) }
+
+// Set은 게이지 값을 설정합니다
+func (g *Gauge) Set(v float64) { g.v.Set(v) }
+
+// Value는 현재 값을 반환합니다
+func (g *Gauge) Value() float64 { return g.v.Load() }
+
+// Histogram은 관측값의 분포를 버킷별로 누적합니다
+type Histogram struct {
+	buckets []float64
+	counts  []atomic.Uint64
+	sum     atomicFloat
+	count   atomic.Uint64
+}
+
+func newHistogram(buckets []float64) *Histogram {
+	return &Histogram{
+		buckets: buckets,
+		counts:  make([]atomic.Uint64, len(buckets)),
+	}
+}
+
+// Observe는 값 하나를 기록합니다
+func (h *Histogram) Observe(v float64) {
+	for i, upper := range h.buckets {
+		if v <= upper {
+			h.counts[i].Add(1)
+		}
+	}
+	h.sum.Add(v)
+	h.count.Add(1)
+}
+
+func (h *Histogram) samples(name string, labels []LabelPair) []Sample {
+	samples := make([]Sample, 0, len(h.buckets)+3)
+	for i, upper := range h.buckets {
+		samples = append(samples, Sample{
+			Name:   name + "_bucket",
+			Labels: withLabel(labels, "le", formatValue(upper)),
+			Value:  float64(h.counts[i].Load()),
+		})
+	}
+	count := float64(h.count.Load())
+	samples = append(samples,
+		Sample{Name: name + "_bucket", Labels: withLabel(labels, "le", "+Inf"), Value: count},
+		Sample{Name: name + "_sum", Labels: labels, Value: h.sum.Load()},
+		Sample{Name: name + "_count", Labels: labels, Value: count},
+	)
+	return samples
+}
+
+func withLabel(labels []LabelPair, name, value string) []LabelPair {
+	out := make([]LabelPair, 0, len(labels)+1)
+	out = append(out, labels...)
+	return append(out, LabelPair{Name: name, Value: value})
+}
+
+// vec는 레이블 값 조합별로 자식 메트릭을 관리합니다
+type vec[T any] struct {
+	desc       Desc
+	labelNames []string
+	newChild   func() *T
+	mu         sync.RWMutex
+	children   map[string]*T
+	values     map[string][]string
+}
+
+func newVec[T any](desc Desc, labelNames []string, newChild func() *T) *vec[T] {
+	return &vec[T]{
+		desc:       desc,
+		labelNames: labelNames,
+		newChild:   newChild,
+		children:   make(map[string]*T),
+		values:     make(map[string][]string),
+	}
+}
+
+func (v *vec[T]) with(values ...string) *T {
+	if len(values) != len(v.labelNames) {
+		panic(fmt.Sprintf("%s: 레이블 값 개수 불일치 (필요: %d, 전달: %d)", v.desc.Name, len(v.labelNames), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+
+	v.mu.RLock()
+	child, ok := v.children[key]
+	v.mu.RUnlock()
+	if ok {
+		return child
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if child, ok = v.children[key]; ok {
+		return child
+	}
+	child = v.newChild()
+	v.children[key] = child
+	v.values[key] = append([]string(nil), values...)
+	return child
+}
+
+func (v *vec[T]) each(fn func(labels []LabelPair, child *T)) {
+	v.mu.RLock()
+	keys := make([]string, 0, len(v.children))
+	for key := range v.children {
+		keys = append(keys, key)
+	}
+	v.mu.RUnlock()
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		v.mu.RLock()
+		child, values := v.children[key], v.values[key]
+		v.mu.RUnlock()
+
+		labels := make([]LabelPair, len(values))
+		for i, value := range values {
+			labels[i] = LabelPair{Name: v.labelNames[i], Value: value}
+		}
+		fn(labels, child)
+	}
+}
+
+// CounterVec는 레이블별 카운터 모음입니다
+type CounterVec struct {
+	*vec[Counter]
+}
+
+// NewCounterVec는 카운터 모음을 생성하고 기본 Registry에 등록합니다
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	c := &CounterVec{newVec(Desc{Name: name, Help: help, Type: TypeCounter}, labelNames, func() *Counter { return &Counter{} })}
+	Default.Register(c)
+	return c
+}
+
+// WithLabelValues는 레이블 값에 해당하는 카운터를 반환합니다
+func (c *CounterVec) WithLabelValues(values ...string) *Counter { return c.with(values...) }
+
+// Describe는 Collector 인터페이스를 구현합니다
+func (c *CounterVec) Describe() Desc { return c.desc }
+
+// Collect는 Collector 인터페이스를 구현합니다
+func (c *CounterVec) Collect() []Sample {
+	var samples []Sample
+	c.each(func(labels []LabelPair, child *Counter) {
+		samples = append(samples, Sample{Name: c.desc.Name, Labels: labels, Value: child.Value()})
+	})
+	return samples
+}
+
+// GaugeVec는 레이블별 게이지 모음입니다
+type GaugeVec struct {
+	*vec[Gauge]
+}
+
+// NewGaugeVec는 게이지 모음을 생성하고 기본 Registry에 등록합니다
+func NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	g := &GaugeVec{newVec(Desc{Name: name, Help: help, Type: TypeGauge}, labelNames, func() *Gauge { return &Gauge{} })}
+	Default.Register(g)
+	return g
+}
+
+// WithLabelValues는 레이블 값에 해당하는 게이지를 반환합니다
+func (g *GaugeVec) WithLabelValues(values ...string) *Gauge { return g.with(values...) }
+
+// Describe는 Collector 인터페이스를 구현합니다
+func (g *GaugeVec) Describe() Desc { return g.desc }
+
+// Collect는 Collector 인터페이스를 구현합니다
+func (g *GaugeVec) Collect() []Sample {
+	var samples []Sample
+	g.each(func(labels []LabelPair, child *Gauge) {
+		samples = append(samples, Sample{Name: g.desc.Name, Labels: labels, Value: child.Value()})
+	})
+	return samples
+}
+
+// HistogramVec는 레이블별 히스토그램 모음입니다
+type HistogramVec struct {
+	*vec[Histogram]
+}
+
+// NewHistogramVec는 히스토그램 모음을 생성하고 기본 Registry에 등록합니다
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	h := &HistogramVec{newVec(Desc{Name: name, Help: help, Type: TypeHistogram}, labelNames, func() *Histogram { return newHistogram(buckets) })}
+	Default.Register(h)
+	return h
+}
+
+// WithLabelValues는 레이블 값에 해당하는 히스토그램을 반환합니다
+func (h *HistogramVec) WithLabelValues(values ...string) *Histogram { return h.with(values...) }
+
+// Describe는 Collector 인터페이스를 구현합니다
+func (h *HistogramVec) Describe() Desc { return h.desc }
+
+// Collect는 Collector 인터페이스를 구현합니다
+func (h *HistogramVec) Collect() []Sample {
+	var samples []Sample
+	h.each(func(labels []LabelPair, child *Histogram) {
+		samples = append(samples, child.samples(h.desc.Name, labels)...)
+	})
+	return samples
+}
+
+type counterCollector struct {
+	desc    Desc
+	counter *Counter
+}
+
+// NewCounter는 레이블 없는 카운터를 생성하고 기본 Registry에 등록합니다
+func NewCounter(name, help string) *Counter {
+	c := &Counter{}
+	Default.Register(&counterCollector{desc: Desc{Name: name, Help: help, Type: TypeCounter}, counter: c})
+	return c
+}
+
+func (c *counterCollector) Describe() Desc { return c.desc }
+
+func (c *counterCollector) Collect() []Sample {
+	return []Sample{{Name: c.desc.Name, Value: c.counter.Value()}}
+}
+
+// GaugeFunc는 수집 시점에 함수를 호출해 값을 얻는 게이지입니다
+type GaugeFunc struct {
+	desc Desc
+	fn   func() float64
+}
+
+// NewGaugeFunc는 함수 기반 게이지를 생성하고 기본 Registry에 등록합니다
+func NewGaugeFunc(name, help string, fn func() float64) *GaugeFunc {
+	g := &GaugeFunc{desc: Desc{Name: name, Help: help, Type: TypeGauge}, fn: fn}
+	Default.Register(g)
+	return g
+}
+
+// Describe는 Collector 인터페이스를 구현합니다
+func (g *GaugeFunc) Describe() Desc { return g.desc }
+
+// Collect는 Collector 인터페이스를 구현합니다
+func (g *GaugeFunc) Collect() []Sample {
+	return []Sample{{Name: g.desc.Name, Value: g.fn()}}
+}
+
+// CounterFunc는 수집 시점에 함수를 호출해 값을 얻는 카운터입니다
+type CounterFunc struct {
+	desc Desc
+	fn   func() float64
+}
+
+// NewCounterFunc는 함수 기반 카운터를 생성하고 기본 Registry에 등록합니다
+func NewCounterFunc(name, help string, fn func() float64) *CounterFunc {
+	c := &CounterFunc{desc: Desc{Name: name, Help: help, Type: TypeCounter}, fn: fn}
+	Default.Register(c)
+	return c
+}
+
+// Describe는 Collector 인터페이스를 구현합니다
+func (c *CounterFunc) Describe() Desc { return c.desc }
+
+// Collect는 Collector 인터페이스를 구현합니다
+func (c *CounterFunc) Collect() []Sample {
+	return []Sample{{Name: c.desc.Name, Value: c.fn()}}
+}
@@ -0,0 +1,152 @@
+package telemetry
+
+import (
+	"math"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWritePrometheus(t *testing.T) {
+	r := NewRegistry()
+
+	requests := &CounterVec{newVec(Desc{Name: "test_requests_total", Help: "요청 수 (경로는 \\로 구분)\n두 번째 줄", Type: TypeCounter},
+		[]string{"path", "code"}, func() *Counter { return &Counter{} })}
+	requests.WithLabelValues(`C:\tmp`, "200").Add(2)
+	requests.WithLabelValues(`say "hi"`+"\n", "500").Inc()
+	requests.WithLabelValues("/a", "200").Add(-5) // 음수는 무시
+	r.Register(requests)
+
+	latency := &HistogramVec{newVec(Desc{Name: "test_latency_seconds", Help: "지연 시간", Type: TypeHistogram},
+		[]string{"op"}, func() *Histogram { return newHistogram([]float64{0.25, 1}) })}
+	for _, v := range []float64{0.25, 0.5, 2} {
+		latency.WithLabelValues("read").Observe(v)
+	}
+	r.Register(latency)
+
+	up := &GaugeFunc{desc: Desc{Name: "test_up", Help: "상태", Type: TypeGauge}, fn: func() float64 { return 1 }}
+	r.Register(up)
+
+	var b strings.Builder
+	if err := r.WritePrometheus(&b); err != nil {
+		t.Fatalf("WritePrometheus: %v", err)
+	}
+
+	// 메트릭은 이름순, 레이블 조합은 값 순으로 정렬됨
+	want := `# HELP test_latency_seconds 지연 시간
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{op="read",le="0.25"} 1
+test_latency_seconds_bucket{op="read",le="1"} 2
+test_latency_seconds_bucket{op="read",le="+Inf"} 3
+test_latency_seconds_sum{op="read"} 2.75
+test_latency_seconds_count{op="read"} 3
+# HELP test_requests_total 요청 수 (경로는 \\로 구분)\n두 번째 줄
+# TYPE test_requests_total counter
+test_requests_total{path="/a",code="200"} 0
+test_requests_total{path="C:\\tmp",code="200"} 2
+test_requests_total{path="say \"hi\"\n",code="500"} 1
+# HELP test_up 상태
+# TYPE test_up gauge
+test_up 1
+`
+	if got := b.String(); got != want {
+		t.Errorf("출력 =\n%s\n기대\n%s", got, want)
+	}
+}
+
+func TestHistogramSamples(t *testing.T) {
+	h := newHistogram([]float64{0.1, 1, 10})
+	for _, v := range []float64{0.05, 0.1, 3, 3, 50} {
+		h.Observe(v)
+	}
+
+	tests := []struct {
+		name  string
+		le    string
+		value float64
+	}{
+		{name: "test_bucket", le: "0.1", value: 2}, // 경계값은 해당 버킷에 포함
+		{name: "test_bucket", le: "1", value: 2},
+		{name: "test_bucket", le: "10", value: 4},
+		{name: "test_bucket", le: "+Inf", value: 5},
+		{name: "test_sum", value: 56.15},
+		{name: "test_count", value: 5},
+	}
+	samples := h.samples("test", []LabelPair{{Name: "op", Value: "x"}})
+	if len(samples) != len(tests) {
+		t.Fatalf("샘플 %d개, 기대 %d개", len(samples), len(tests))
+	}
+	for i, tt := range tests {
+		s := samples[i]
+		le := ""
+		if last := s.Labels[len(s.Labels)-1]; last.Name == "le" {
+			le = last.Value
+		}
+		if s.Name != tt.name || le != tt.le || math.Abs(s.Value-tt.value) > 1e-9 || s.Labels[0].Value != "x" {
+			t.Errorf("%d번째 샘플 = %s{le=%q} %v, 기대 %s{le=%q} %v", i, s.Name, le, s.Value, tt.name, tt.le, tt.value)
+		}
+	}
+}
+
+func TestGauge(t *testing.T) {
+	var g Gauge
+	g.Inc()
+	g.Inc()
+	g.Dec()
+	if g.Value() != 1 {
+		t.Errorf("Inc 2번, Dec 1번 후 값 %v, 기대 1", g.Value())
+	}
+	g.Set(-3.5)
+	if g.Value() != -3.5 {
+		t.Errorf("Set 후 값 %v, 기대 -3.5", g.Value())
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		v    float64
+		want string
+	}{
+		{v: 42, want: "42"},
+		{v: 0.001, want: "0.001"},
+		{v: 1e21, want: "1e+21"},
+		{v: math.Inf(1), want: "+Inf"},
+		{v: math.Inf(-1), want: "-Inf"},
+		{v: math.NaN(), want: "NaN"},
+	}
+	for _, tt := range tests {
+		if got := formatValue(tt.v); got != tt.want {
+			t.Errorf("formatValue(%v) = %q, 기대 %q", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestWithLabelValuesCount(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("레이블 값 개수가 달라도 panic하지 않음")
+		}
+	}()
+	v := &CounterVec{newVec(Desc{Name: "test_total"}, []string{"a", "b"}, func() *Counter { return &Counter{} })}
+	v.WithLabelValues("only-one")
+}
+
+func TestHandler(t *testing.T) {
+	RateLimitedMessages.Inc()
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE collector_rate_limited_messages_total counter\n",
+		"# TYPE collector_message_duration_seconds histogram\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("/metrics 응답에 %q 없음", want)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package tlsutil
+
+import (
+	"crypto/tls"
+	"strings"
+)
+
+// NodeIdentity는 검증된 클라이언트 인증서에서 노드 ID를 추출합니다.
+// Subject CN을 우선 사용하고, 비어 있으면 첫 번째 DNS SAN, 그다음 URI SAN의 마지막 경로 요소를 사용합니다.
+// 검증된 인증서가 없으면 빈 문자열을 반환합니다.
+func NodeIdentity(state *tls.ConnectionState) string {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	leaf := state.VerifiedChains[0][0]
+
+	if cn := strings.TrimSpace(leaf.Subject.CommonName); cn != "" {
+		return cn
+	}
+	if len(leaf.DNSNames) > 0 {
+		return leaf.DNSNames[0]
+	}
+	for _, uri := range leaf.URIs {
+		id := uri.Opaque
+		if id == "" {
+			id = uri.Path
+		}
+		id = strings.TrimRight(id, "/")
+		if i := strings.LastIndexAny(id, "/:"); i >= 0 {
+			id = id[i+1:]
+		}
+		if id != "" {
+			return id
+		}
+	}
+	return ""
+}
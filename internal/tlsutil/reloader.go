@@ -0,0 +1,202 @@
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"system-collector/pkg/logger"
+)
+
+// 클라이언트 인증서 요구 수준
+const (
+	ClientAuthNone    = "none"
+	ClientAuthRequest = "request"
+	ClientAuthRequire = "require"
+)
+
+// Options는 TLS 리스너 설정입니다
+type Options struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+	ClientAuth   string
+	// ReloadInterval마다 인증서 파일의 변경 여부를 확인합니다
+	ReloadInterval time.Duration
+}
+
+// Reloader는 인증서와 클라이언트 CA를 보관하고 파일이 바뀌면 다시 읽습니다.
+// 새 인증서는 이후의 TLS 핸드셰이크부터 적용되며 기존 연결은 유지됩니다.
+type Reloader struct {
+	opts       Options
+	clientAuth tls.ClientAuthType
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	clientCA *x509.CertPool
+	modTimes map[string]time.Time
+
+	stop chan struct{}
+}
+
+// NewReloader는 인증서를 처음 로드하고 Reloader를 생성합니다
+func NewReloader(opts Options) (*Reloader, error) {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("TLS 인증서 로더 초기화 중", "certFile", opts.CertFile, "clientCAFile", opts.ClientCAFile, "clientAuth", opts.ClientAuth)
+
+	clientAuth, err := parseClientAuth(opts.ClientAuth)
+	if err != nil {
+		return nil, err
+	}
+	if clientAuth != tls.NoClientCert && opts.ClientCAFile == "" {
+		return nil, fmt.Errorf("client_auth가 %q이면 client_ca_file이 필요합니다", opts.ClientAuth)
+	}
+
+	r := &Reloader{
+		opts:       opts,
+		clientAuth: clientAuth,
+		stop:       make(chan struct{}),
+	}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func parseClientAuth(s string) (tls.ClientAuthType, error) {
+	switch s {
+	case "", ClientAuthNone:
+		return tls.NoClientCert, nil
+	case ClientAuthRequest:
+		return tls.VerifyClientCertIfGiven, nil
+	case ClientAuthRequire:
+		return tls.RequireAndVerifyClientCert, nil
+	}
+	return tls.NoClientCert, fmt.Errorf("알 수 없는 client_auth 값: %q", s)
+}
+
+// load는 인증서, 키, 클라이언트 CA 파일을 읽어 교체합니다
+func (r *Reloader) load() error {
+	modTimes, err := r.statFiles()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.opts.CertFile, r.opts.KeyFile)
+	if err != nil {
+		return fmt.Errorf("TLS 인증서 로드 실패: %v", err)
+	}
+
+	var pool *x509.CertPool
+	if r.opts.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.opts.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("클라이언트 CA 파일 읽기 실패: %v", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("클라이언트 CA 파일에 유효한 인증서가 없습니다: %s", r.opts.ClientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.clientCA = pool
+	r.modTimes = modTimes
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *Reloader) files() []string {
+	files := []string{r.opts.CertFile, r.opts.KeyFile}
+	if r.opts.ClientCAFile != "" {
+		files = append(files, r.opts.ClientCAFile)
+	}
+	return files
+}
+
+func (r *Reloader) statFiles() (map[string]time.Time, error) {
+	modTimes := make(map[string]time.Time)
+	for _, f := range r.files() {
+		info, err := os.Stat(f)
+		if err != nil {
+			return nil, fmt.Errorf("TLS 파일 확인 실패: %v", err)
+		}
+		modTimes[f] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+func (r *Reloader) changed() bool {
+	modTimes, err := r.statFiles()
+	if err != nil {
+		// 교체 도중 파일이 잠시 없을 수 있으므로 다음 주기에 다시 확인
+		return false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for f, t := range modTimes {
+		if !t.Equal(r.modTimes[f]) {
+			return true
+		}
+	}
+	return false
+}
+
+// Watch는 ReloadInterval마다 파일 변경을 확인하고 바뀌면 다시 로드합니다.
+// 로드에 실패하면 기존 인증서를 계속 사용합니다.
+func (r *Reloader) Watch() {
+	if r.opts.ReloadInterval <= 0 {
+		return
+	}
+
+	go func() {
+		sugar := logger.GetCustomLogger()
+		ticker := time.NewTicker(r.opts.ReloadInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				if !r.changed() {
+					continue
+				}
+				if err := r.load(); err != nil {
+					sugar.Errorw("TLS 인증서 다시 로드 실패, 기존 인증서 유지", "error", err)
+					continue
+				}
+				sugar.Infow("TLS 인증서 다시 로드 완료", "certFile", r.opts.CertFile)
+			}
+		}
+	}()
+}
+
+// Stop은 파일 변경 감시를 중단합니다
+func (r *Reloader) Stop() {
+	close(r.stop)
+}
+
+// TLSConfig는 핸드셰이크마다 최신 인증서와 클라이언트 CA를 사용하는 설정을 반환합니다
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				NextProtos:   []string{"http/1.1"}, // WebSocket 업그레이드는 HTTP/1.1에서만 동작
+				Certificates: []tls.Certificate{*r.cert},
+				ClientAuth:   r.clientAuth,
+				ClientCAs:    r.clientCA,
+			}, nil
+		},
+	}
+}
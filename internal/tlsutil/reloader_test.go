@@ -0,0 +1,301 @@
+package tlsutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testCA는 테스트용으로 생성한 CA입니다
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+var serialNumber int64
+
+func newSerial() *big.Int {
+	serialNumber++
+	return big.NewInt(serialNumber)
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("CA 키 생성 실패: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          newSerial(),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CA 인증서 생성 실패: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("CA 인증서 파싱 실패: %v", err)
+	}
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue는 CA로 서명한 인증서와 키를 PEM으로 반환합니다
+func (ca *testCA) issue(t *testing.T, cn string, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("키 생성 실패: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: newSerial(),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("인증서 생성 실패: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("키 직렬화 실패: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func (ca *testCA) clientCert(t *testing.T, cn string) tls.Certificate {
+	t.Helper()
+	certPEM, keyPEM := ca.issue(t, cn, x509.ExtKeyUsageClientAuth)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("클라이언트 인증서 로드 실패: %v", err)
+	}
+	return cert
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("파일 쓰기 실패: %v", err)
+	}
+}
+
+// startServer는 Reloader의 TLS 설정으로 연결한 클라이언트의 노드 ID를 응답하는 서버를 시작합니다
+func startServer(t *testing.T, r *Reloader) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		io.WriteString(w, NodeIdentity(req.TLS))
+	}))
+	srv.TLS = r.TLSConfig()
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// get은 클라이언트 인증서로 서버에 요청하고 응답 본문을 반환합니다
+func get(srv *httptest.Server, serverCA *testCA, cert *tls.Certificate) (string, error) {
+	pool := x509.NewCertPool()
+	pool.AddCert(serverCA.cert)
+	cfg := &tls.Config{RootCAs: pool}
+	if cert != nil {
+		// 서버가 허용하는 CA와 관계없이 항상 제시해야 검증 실패를 확인할 수 있음
+		cfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) { return cert, nil }
+	}
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: cfg}, Timeout: 5 * time.Second}
+	defer client.CloseIdleConnections()
+
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return string(body), err
+}
+
+// setup은 서버 인증서와 클라이언트 CA 파일을 만들고 Options를 반환합니다
+func setup(t *testing.T, clientAuth string) (Options, *testCA, *testCA) {
+	t.Helper()
+	dir := t.TempDir()
+	serverCA := newTestCA(t, "server-ca")
+	clientCA := newTestCA(t, "client-ca")
+
+	certPEM, keyPEM := serverCA.issue(t, "localhost", x509.ExtKeyUsageServerAuth)
+	opts := Options{
+		CertFile:     filepath.Join(dir, "server.crt"),
+		KeyFile:      filepath.Join(dir, "server.key"),
+		ClientCAFile: filepath.Join(dir, "client-ca.crt"),
+		ClientAuth:   clientAuth,
+	}
+	writeFile(t, opts.CertFile, certPEM)
+	writeFile(t, opts.KeyFile, keyPEM)
+	writeFile(t, opts.ClientCAFile, clientCA.pem)
+	return opts, serverCA, clientCA
+}
+
+func TestHandshake(t *testing.T) {
+	opts, serverCA, clientCA := setup(t, ClientAuthRequire)
+	otherCA := newTestCA(t, "other-ca")
+
+	r, err := NewReloader(opts)
+	if err != nil {
+		t.Fatalf("NewReloader: %v", err)
+	}
+	srv := startServer(t, r)
+
+	valid := clientCA.clientCert(t, "node-1")
+	wrongCA := otherCA.clientCert(t, "node-1")
+	tests := []struct {
+		name    string
+		cert    *tls.Certificate
+		want    string
+		wantErr bool
+	}{
+		{name: "유효한 인증서", cert: &valid, want: "node-1"},
+		{name: "다른 CA의 인증서", cert: &wrongCA, wantErr: true},
+		{name: "인증서 없음", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := get(srv, serverCA, tt.cert)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("핸드셰이크 성공 (노드 ID %q), 실패 기대", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("요청 실패: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("노드 ID = %q, 기대 %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandshakeRequest(t *testing.T) {
+	opts, serverCA, clientCA := setup(t, ClientAuthRequest)
+	r, err := NewReloader(opts)
+	if err != nil {
+		t.Fatalf("NewReloader: %v", err)
+	}
+	srv := startServer(t, r)
+
+	// request이면 인증서 없이도 연결되지만 노드 ID는 없음
+	if got, err := get(srv, serverCA, nil); err != nil || got != "" {
+		t.Errorf("인증서 없음 = %q, %v, 빈 노드 ID 기대", got, err)
+	}
+	cert := clientCA.clientCert(t, "node-2")
+	if got, err := get(srv, serverCA, &cert); err != nil || got != "node-2" {
+		t.Errorf("유효한 인증서 = %q, %v, node-2 기대", got, err)
+	}
+	// 제시한 인증서는 검증하므로 다른 CA의 인증서는 거부
+	wrong := newTestCA(t, "other-ca").clientCert(t, "node-2")
+	if got, err := get(srv, serverCA, &wrong); err == nil {
+		t.Errorf("다른 CA의 인증서로 연결됨 (노드 ID %q)", got)
+	}
+}
+
+func TestReload(t *testing.T) {
+	opts, serverCA, clientCA := setup(t, ClientAuthRequire)
+	opts.ReloadInterval = 10 * time.Millisecond
+	r, err := NewReloader(opts)
+	if err != nil {
+		t.Fatalf("NewReloader: %v", err)
+	}
+	r.Watch()
+	t.Cleanup(r.Stop)
+	srv := startServer(t, r)
+
+	oldCert := clientCA.clientCert(t, "node-1")
+	if _, err := get(srv, serverCA, &oldCert); err != nil {
+		t.Fatalf("교체 전 요청 실패: %v", err)
+	}
+
+	// 클라이언트 CA를 교체하면 재시작 없이 새 CA의 인증서만 허용
+	newCA := newTestCA(t, "client-ca-2")
+	writeFile(t, opts.ClientCAFile, newCA.pem)
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(opts.ClientCAFile, future, future); err != nil {
+		t.Fatalf("수정 시각 변경 실패: %v", err)
+	}
+
+	newCert := newCA.clientCert(t, "node-1")
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := get(srv, serverCA, &newCert); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("클라이언트 CA가 다시 로드되지 않음")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if _, err := get(srv, serverCA, &oldCert); err == nil {
+		t.Error("교체 후에도 이전 CA의 인증서로 연결됨")
+	}
+}
+
+func TestReloadKeepsCertOnError(t *testing.T) {
+	opts, serverCA, clientCA := setup(t, ClientAuthRequire)
+	opts.ReloadInterval = 10 * time.Millisecond
+	r, err := NewReloader(opts)
+	if err != nil {
+		t.Fatalf("NewReloader: %v", err)
+	}
+	r.Watch()
+	t.Cleanup(r.Stop)
+	srv := startServer(t, r)
+
+	// 잘못된 파일로 바뀌면 기존 CA를 계속 사용
+	writeFile(t, opts.ClientCAFile, []byte("not a certificate"))
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(opts.ClientCAFile, future, future); err != nil {
+		t.Fatalf("수정 시각 변경 실패: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	cert := clientCA.clientCert(t, "node-1")
+	if got, err := get(srv, serverCA, &cert); err != nil || got != "node-1" {
+		t.Errorf("다시 로드 실패 후 요청 = %q, %v, node-1 기대", got, err)
+	}
+}
+
+func TestNewReloaderRequiresClientCA(t *testing.T) {
+	opts, _, _ := setup(t, ClientAuthRequire)
+	opts.ClientCAFile = ""
+	if _, err := NewReloader(opts); err == nil {
+		t.Error("client_ca_file 없이 require 설정이 허용됨")
+	}
+	opts.ClientAuth = "always"
+	if _, err := NewReloader(opts); err == nil {
+		t.Error("알 수 없는 client_auth 값이 허용됨")
+	}
+}
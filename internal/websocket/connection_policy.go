@@ -0,0 +1,236 @@
+package websocket
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	config "system-collector/configs"
+)
+
+// 같은 노드 ID로 연결 수 상한을 넘었을 때의 처리 방식
+const (
+	DuplicateKickOld   = "kick_old"
+	DuplicateRejectNew = "reject_new"
+)
+
+var (
+	errTooManyClients       = errors.New("서버가 최대 용량에 도달했습니다")
+	errTooManyPerIP         = errors.New("IP당 최대 연결 수를 초과했습니다")
+	errTooManyPerKey        = errors.New("obscura key당 최대 연결 수를 초과했습니다")
+	errNodeAlreadyConnected = errors.New("이미 연결된 노드입니다")
+	errIdentityChanged      = errors.New("연결 중 노드 ID 또는 obscura key가 변경되었습니다")
+)
+
+// defaultMaxClients는 max_clients가 설정되지 않았을 때의 전체 연결 수 상한입니다
+const defaultMaxClients = 1000
+
+// connIdentity는 연결 하나가 차지하고 있는 슬롯 정보입니다
+type connIdentity struct {
+	ip     string
+	key    string
+	nodeID string
+}
+
+// connectionPolicy는 원격 IP, obscura key, 노드 ID별 연결 수를 관리합니다.
+// 상한 값은 매 호출마다 설정에서 읽으므로 설정이 바뀌면 이후 연결부터 적용됩니다.
+type connectionPolicy struct {
+	mu         sync.Mutex
+	total      int
+	byIP       map[string]int
+	byKey      map[string]int
+	byNode     map[string][]string // nodeID -> clientID 목록 (연결된 순서)
+	identities map[string]*connIdentity
+}
+
+func newConnectionPolicy() *connectionPolicy {
+	return &connectionPolicy{
+		byIP:       make(map[string]int),
+		byKey:      make(map[string]int),
+		byNode:     make(map[string][]string),
+		identities: make(map[string]*connIdentity),
+	}
+}
+
+// acquire는 업그레이드 전에 전체 및 IP별 상한을 확인하고 슬롯을 차지합니다
+func (p *connectionPolicy) acquire(clientID, ip string) error {
+	limits := config.Get().Connection
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	maxClients := limits.MaxClients
+	if maxClients <= 0 {
+		maxClients = defaultMaxClients
+	}
+	if p.total >= maxClients {
+		return errTooManyClients
+	}
+	if limits.MaxPerIP > 0 && p.byIP[ip] >= limits.MaxPerIP {
+		return errTooManyPerIP
+	}
+
+	p.total++
+	p.byIP[ip]++
+	p.identities[clientID] = &connIdentity{ip: ip}
+	return nil
+}
+
+// bind는 첫 메트릭스로 확인된 obscura key와 노드 ID를 연결에 연결합니다.
+// kick_old 정책에서 노드별 상한을 넘으면 끊어야 할 기존 연결의 clientID 목록을 반환합니다.
+func (p *connectionPolicy) bind(clientID, key, nodeID string) ([]string, error) {
+	limits := config.Get().Connection
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	id, ok := p.identities[clientID]
+	if !ok {
+		return nil, nil
+	}
+	if id.nodeID != "" {
+		if id.nodeID != nodeID || id.key != key {
+			return nil, errIdentityChanged
+		}
+		return nil, nil
+	}
+
+	var kicked []string
+	existing := p.byNode[nodeID]
+	if limits.MaxPerNode > 0 && len(existing) >= limits.MaxPerNode {
+		if limits.DuplicateNodePolicy == DuplicateRejectNew {
+			return nil, errNodeAlreadyConnected
+		}
+		n := len(existing) - limits.MaxPerNode + 1
+		kicked = append(kicked, existing[:n]...)
+		existing = existing[n:]
+	}
+
+	// 끊을 연결이 쓰던 key 슬롯은 반납될 것으로 보고 key별 상한을 확인
+	if limits.MaxPerKey > 0 {
+		inUse := p.byKey[key]
+		for _, kickedID := range kicked {
+			if kid, ok := p.identities[kickedID]; ok && kid.key == key {
+				inUse--
+			}
+		}
+		if inUse >= limits.MaxPerKey {
+			return nil, errTooManyPerKey
+		}
+	}
+
+	for _, kickedID := range kicked {
+		if kid, ok := p.identities[kickedID]; ok {
+			if p.byKey[kid.key]--; p.byKey[kid.key] <= 0 {
+				delete(p.byKey, kid.key)
+			}
+			kid.key, kid.nodeID = "", ""
+		}
+	}
+
+	id.key, id.nodeID = key, nodeID
+	p.byKey[key]++
+	p.byNode[nodeID] = append(existing, clientID)
+	return kicked, nil
+}
+
+// clientsFor는 nodeID에 바인딩된 연결의 clientID 목록을 연결된 순서대로 반환합니다
+func (p *connectionPolicy) clientsFor(nodeID string) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]string(nil), p.byNode[nodeID]...)
+}
+
+// release는 연결이 차지한 모든 슬롯을 반납합니다.
+// 노드에 바인딩된 연결이었고 그 노드에 남은 연결이 없으면 true를 반환합니다.
+// 중복 연결로 밀려난 연결이나 이미 반납한 연결은 false를 반환합니다.
+func (p *connectionPolicy) release(clientID string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	id, ok := p.identities[clientID]
+	if !ok {
+		return false
+	}
+	delete(p.identities, clientID)
+
+	p.total--
+	if p.byIP[id.ip]--; p.byIP[id.ip] <= 0 {
+		delete(p.byIP, id.ip)
+	}
+	if id.key != "" {
+		if p.byKey[id.key]--; p.byKey[id.key] <= 0 {
+			delete(p.byKey, id.key)
+		}
+	}
+	if id.nodeID != "" {
+		remaining := p.byNode[id.nodeID][:0]
+		for _, other := range p.byNode[id.nodeID] {
+			if other != clientID {
+				remaining = append(remaining, other)
+			}
+		}
+		if len(remaining) == 0 {
+			delete(p.byNode, id.nodeID)
+			return true
+		}
+		p.byNode[id.nodeID] = remaining
+	}
+	return false
+}
+
+// count는 현재 연결 수를 반환합니다
+func (p *connectionPolicy) count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.total
+}
+
+// rejectReason은 정책 오류를 메트릭 레이블 값으로 변환합니다
+func rejectReason(err error) string {
+	switch {
+	case errors.Is(err, errTooManyClients):
+		return "max_clients"
+	case errors.Is(err, errTooManyPerIP):
+		return "max_per_ip"
+	case errors.Is(err, errTooManyPerKey):
+		return "max_per_key"
+	case errors.Is(err, errNodeAlreadyConnected):
+		return "duplicate_node"
+	case errors.Is(err, errIdentityChanged):
+		return "identity_changed"
+	}
+	return "other"
+}
+
+// checkOrigin은 설정된 허용 Origin 목록과 요청의 Origin 헤더를 비교합니다.
+// Origin 헤더가 없는 요청(브라우저가 아닌 에이전트)은 허용합니다.
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	allowed := config.Get().Connection.AllowedOrigins
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, a := range allowed {
+		if a == "*" || strings.EqualFold(a, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteIP는 요청의 원격 주소에서 포트를 제외한 IP를 반환합니다
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
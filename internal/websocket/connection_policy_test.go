@@ -0,0 +1,261 @@
+package websocket
+
+import (
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// policyStep은 연결 정책에 대한 호출 하나와 기대 결과입니다.
+// op는 acquire, bind, release, clients 중 하나입니다.
+type policyStep struct {
+	op      string
+	client  string
+	ip      string
+	key     string
+	node    string
+	wantErr error
+	want    []string // bind가 끊은 연결, clients가 반환한 연결
+	last    bool     // release가 노드의 마지막 연결이었다고 보고해야 하는지
+}
+
+func TestConnectionPolicy(t *testing.T) {
+	acquire := func(client, ip string) policyStep { return policyStep{op: "acquire", client: client, ip: ip} }
+	bind := func(client, key, node string) policyStep {
+		return policyStep{op: "bind", client: client, key: key, node: node}
+	}
+	release := func(client string, last bool) policyStep {
+		return policyStep{op: "release", client: client, last: last}
+	}
+	clients := func(node string, want ...string) policyStep {
+		return policyStep{op: "clients", node: node, want: want}
+	}
+	fails := func(s policyStep, err error) policyStep { s.wantErr = err; return s }
+	kicks := func(s policyStep, kicked ...string) policyStep { s.want = kicked; return s }
+
+	tests := []struct {
+		name   string
+		config string
+		steps  []policyStep
+	}{
+		{
+			name:   "전체 연결 수 상한",
+			config: "connection: {max_clients: 2}",
+			steps: []policyStep{
+				acquire("a", "10.0.0.1"),
+				acquire("b", "10.0.0.2"),
+				fails(acquire("c", "10.0.0.3"), errTooManyClients),
+				release("a", false),
+				acquire("c", "10.0.0.3"),
+			},
+		},
+		{
+			name:   "IP별 상한",
+			config: "connection: {max_per_ip: 1}",
+			steps: []policyStep{
+				acquire("a", "10.0.0.1"),
+				fails(acquire("b", "10.0.0.1"), errTooManyPerIP),
+				acquire("c", "10.0.0.2"),
+				release("a", false),
+				acquire("b", "10.0.0.1"),
+			},
+		},
+		{
+			name:   "obscura key별 상한",
+			config: "connection: {max_per_key: 1}",
+			steps: []policyStep{
+				acquire("a", "10.0.0.1"),
+				acquire("b", "10.0.0.2"),
+				bind("a", "key-1", "node-1"),
+				fails(bind("b", "key-1", "node-2"), errTooManyPerKey),
+				bind("b", "key-2", "node-2"),
+				release("a", true),
+			},
+		},
+		{
+			name:   "kick_old는 가장 오래된 연결을 끊음",
+			config: "connection: {max_per_node: 1, duplicate_node_policy: kick_old}",
+			steps: []policyStep{
+				acquire("a", "10.0.0.1"),
+				acquire("b", "10.0.0.1"),
+				bind("a", "key-1", "node-1"),
+				kicks(bind("b", "key-1", "node-1"), "a"),
+				clients("node-1", "b"),
+				release("a", false), // 밀려난 연결은 노드를 오프라인으로 만들지 않음
+				release("b", true),
+				clients("node-1"),
+			},
+		},
+		{
+			name:   "kick_old는 밀려난 연결의 key 슬롯도 반납",
+			config: "connection: {max_per_key: 1, max_per_node: 1, duplicate_node_policy: kick_old}",
+			steps: []policyStep{
+				acquire("a", "10.0.0.1"),
+				acquire("b", "10.0.0.1"),
+				bind("a", "key-1", "node-1"),
+				kicks(bind("b", "key-1", "node-1"), "a"),
+			},
+		},
+		{
+			name:   "노드별 상한 2개에서 세 번째 연결",
+			config: "connection: {max_per_node: 2, duplicate_node_policy: kick_old}",
+			steps: []policyStep{
+				acquire("a", "10.0.0.1"),
+				acquire("b", "10.0.0.1"),
+				acquire("c", "10.0.0.1"),
+				bind("a", "key-1", "node-1"),
+				bind("b", "key-1", "node-1"),
+				kicks(bind("c", "key-1", "node-1"), "a"),
+				clients("node-1", "b", "c"),
+				release("b", false),
+				release("c", true),
+			},
+		},
+		{
+			name:   "reject_new는 새 연결을 거부",
+			config: "connection: {max_per_node: 1, duplicate_node_policy: reject_new}",
+			steps: []policyStep{
+				acquire("a", "10.0.0.1"),
+				acquire("b", "10.0.0.1"),
+				bind("a", "key-1", "node-1"),
+				fails(bind("b", "key-1", "node-1"), errNodeAlreadyConnected),
+				clients("node-1", "a"),
+				release("b", false),
+				release("a", true),
+			},
+		},
+		{
+			name:   "노드별 무제한에서는 마지막 연결만 오프라인 처리",
+			config: "connection: {max_per_node: 0}",
+			steps: []policyStep{
+				acquire("a", "10.0.0.1"),
+				acquire("b", "10.0.0.2"),
+				bind("a", "key-1", "node-1"),
+				bind("b", "key-1", "node-1"),
+				clients("node-1", "a", "b"),
+				release("a", false),
+				release("a", false), // 두 번 반납해도 변화 없음
+				release("b", true),
+			},
+		},
+		{
+			name:   "연결 중 노드 ID나 key 변경",
+			config: "connection: {max_per_node: 1}",
+			steps: []policyStep{
+				acquire("a", "10.0.0.1"),
+				bind("a", "key-1", "node-1"),
+				bind("a", "key-1", "node-1"),
+				fails(bind("a", "key-1", "node-2"), errIdentityChanged),
+				fails(bind("a", "key-2", "node-1"), errIdentityChanged),
+				clients("node-1", "a"),
+			},
+		},
+		{
+			name:   "반납된 연결의 bind는 무시",
+			config: "connection: {max_per_node: 1}",
+			steps: []policyStep{
+				bind("a", "key-1", "node-1"),
+				clients("node-1"),
+				release("a", false),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loadTestConfig(t, tt.config)
+			p := newConnectionPolicy()
+			for i, s := range tt.steps {
+				switch s.op {
+				case "acquire":
+					if err := p.acquire(s.client, s.ip); !errors.Is(err, s.wantErr) {
+						t.Fatalf("%d단계 acquire(%s, %s) = %v, 기대 %v", i, s.client, s.ip, err, s.wantErr)
+					}
+				case "bind":
+					kicked, err := p.bind(s.client, s.key, s.node)
+					if !errors.Is(err, s.wantErr) {
+						t.Fatalf("%d단계 bind(%s, %s, %s) = %v, 기대 %v", i, s.client, s.key, s.node, err, s.wantErr)
+					}
+					if !reflect.DeepEqual(kicked, s.want) {
+						t.Fatalf("%d단계 bind(%s) 끊을 연결 %v, 기대 %v", i, s.client, kicked, s.want)
+					}
+				case "release":
+					if last := p.release(s.client); last != s.last {
+						t.Fatalf("%d단계 release(%s) = %v, 기대 %v", i, s.client, last, s.last)
+					}
+				case "clients":
+					if got := p.clientsFor(s.node); !reflect.DeepEqual(got, s.want) {
+						t.Fatalf("%d단계 clientsFor(%s) = %v, 기대 %v", i, s.node, got, s.want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestConnectionPolicyRelease(t *testing.T) {
+	loadTestConfig(t, "connection: {max_per_node: 0}")
+	p := newConnectionPolicy()
+	for _, c := range []string{"a", "b", "c"} {
+		if err := p.acquire(c, "10.0.0.1"); err != nil {
+			t.Fatalf("acquire(%s): %v", c, err)
+		}
+	}
+	p.bind("a", "key-1", "node-1")
+	p.bind("b", "key-1", "node-1")
+	for _, c := range []string{"a", "b", "c"} {
+		p.release(c)
+	}
+
+	// 모든 연결을 반납하면 카운터가 남지 않아야 함
+	if p.count() != 0 || len(p.byIP) != 0 || len(p.byKey) != 0 || len(p.byNode) != 0 || len(p.identities) != 0 {
+		t.Errorf("반납 후 남은 상태: total %d, byIP %v, byKey %v, byNode %v, identities %d",
+			p.count(), p.byIP, p.byKey, p.byNode, len(p.identities))
+	}
+}
+
+func TestCheckOrigin(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		origin string
+		want   bool
+	}{
+		{name: "허용 목록 없음", origin: "https://evil.example", want: true},
+		{name: "Origin 헤더 없음", config: "connection: {allowed_origins: [https://app.example]}", want: true},
+		{name: "허용된 Origin (대소문자 무시)", config: "connection: {allowed_origins: [https://app.example]}", origin: "https://APP.example", want: true},
+		{name: "허용되지 않은 Origin", config: "connection: {allowed_origins: [https://app.example]}", origin: "https://evil.example"},
+		{name: "와일드카드", config: "connection: {allowed_origins: [https://app.example, \"*\"]}", origin: "https://evil.example", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loadTestConfig(t, tt.config)
+			r := httptest.NewRequest("GET", "/ws", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if got := checkOrigin(r); got != tt.want {
+				t.Errorf("checkOrigin(%q) = %v, 기대 %v", tt.origin, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRejectReason(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{err: errTooManyClients, want: "max_clients"},
+		{err: errTooManyPerIP, want: "max_per_ip"},
+		{err: errTooManyPerKey, want: "max_per_key"},
+		{err: errNodeAlreadyConnected, want: "duplicate_node"},
+		{err: errIdentityChanged, want: "identity_changed"},
+		{err: errors.New("unknown"), want: "other"},
+	}
+	for _, tt := range tests {
+		if got := rejectReason(tt.err); got != tt.want {
+			t.Errorf("rejectReason(%v) = %q, 기대 %q", tt.err, got, tt.want)
+		}
+	}
+}
@@ -4,54 +4,137 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	config "system-collector/configs"
+	"system-collector/internal/cluster"
+	"system-collector/internal/liveness"
+	"system-collector/internal/registry"
 	"system-collector/internal/repository"
+	"system-collector/internal/telemetry"
+	"system-collector/internal/tlsutil"
 	"system-collector/pkg/logger"
 	"system-collector/pkg/models"
+	"system-collector/pkg/ratelimit"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 )
 
 type Server struct {
 	upgrader   websocket.Upgrader
-	store      func(*models.SystemMetrics) error
+	store      func(context.Context, *models.SystemMetrics) error
 	cmdRepo    *repository.CommandRepository
 	userRepo   *repository.UserRepository
 	nodeRepo   *repository.NodeRepository
 	logRepo    *repository.LogRepository
-	nodeList   []*models.Node
+	registry   *registry.NodeRegistry
 	clients    sync.Map // clientID -> *ClientInfo
-	maxClients int
-	// nodeExternalIPs는 노드별 외부 IP를 관리하는 map입니다.
-	nodeExternalIPs sync.Map // nodeID -> externalIP (string)
+	connPolicy *connectionPolicy
+	logClients sync.Map // clientID -> *ClientInfo (로그 수집 연결)
+	liveness   *liveness.Tracker
+	cluster    *cluster.Coordinator
+	deliverMu  sync.Mutex // 같은 명령어를 두 번 전달하지 않도록 전달을 직렬화
+
+	mux          *http.ServeMux
+	httpServer   *http.Server
+	shuttingDown atomic.Bool
+	lifecycleMu  sync.Mutex     // shuttingDown 설정과 connWG.Add를 직렬화
+	connWG       sync.WaitGroup // 연결 처리 고루틴
+	inflightWG   sync.WaitGroup // 처리 중인 메시지 (연결 처리 고루틴 안에서만 Add)
 }
 
 // 클라이언트 정보를 저장할 구조체 추가
 type ClientInfo struct {
-	conn   *websocket.Conn
-	nodeID string // metrics.Key 저장용
+	conn    *websocket.Conn
+	nodeID  string     // metrics.Key 저장용
+	mu      sync.Mutex // nodeID 보호
+	writeMu sync.Mutex // 하나의 연결에는 동시에 하나의 writer만 허용됩니다
+	// certNodeID는 mTLS 클라이언트 인증서로 확인된 노드 ID입니다 (없으면 빈 문자열)
+	certNodeID string
+	// connID는 로그에서 연결을 구분하는 ID입니다
+	connID string
+	// connCtx는 연결 ID와 원격 주소만 담은 context이고,
+	// ctx는 여기에 바인딩된 노드 정보까지 더한 context입니다 (mu로 보호)
+	connCtx context.Context
+	ctx     context.Context
 }
 
-func NewServer(store func(*models.SystemMetrics) error, cmdRepo *repository.CommandRepository, userRepo *repository.UserRepository, nodeRepo *repository.NodeRepository, logRepo *repository.LogRepository) *Server {
-	sugar := logger.GetCustomLogger()
-	sugar.Infow("Server 초기화 중")
+// newClientInfo는 연결 ID를 발급하고 연결 ID와 원격 주소를 로그 필드로 가진 ClientInfo를 생성합니다
+func newClientInfo(conn *websocket.Conn, remoteAddr, certNodeID string) *ClientInfo {
+	connID := uuid.NewString()[:8]
+	ctx := logger.WithContext(context.Background(), "connID", connID, "remoteAddr", remoteAddr)
+	return &ClientInfo{
+		conn:       conn,
+		certNodeID: certNodeID,
+		connID:     connID,
+		connCtx:    ctx,
+		ctx:        ctx,
+	}
+}
 
-	// 사용자 목록 조회
-	nodes, err := nodeRepo.GetAllNodes()
-	if err != nil {
-		sugar.Errorw("사용자 목록 조회 실패", "error", err)
-		nodes = []*models.Node{} // 빈 배열로 초기화
+// Context는 연결의 로그 필드가 담긴 context를 반환합니다.
+// 노드가 바인딩된 뒤에는 노드 ID와 가려진 사용자 키가 포함됩니다.
+func (c *ClientInfo) Context() context.Context {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// NodeID는 연결에 바인딩된 노드 ID를 반환합니다 (아직 없으면 빈 문자열)
+func (c *ClientInfo) NodeID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.nodeID
+}
+
+// bindNode는 연결에 노드 ID를 바인딩하고 연결의 로그 필드에 노드 ID와 사용자 키를 추가합니다
+func (c *ClientInfo) bindNode(nodeID, userKey string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.nodeID == nodeID {
+		return
+	}
+	c.nodeID = nodeID
+	c.ctx = logger.WithContext(c.connCtx, "nodeID", nodeID, "userKey", logger.MaskKey(userKey))
+}
+
+// writeJSON은 쓰기 데드라인을 설정하고 JSON 메시지를 직렬화하여 전송합니다
+func (c *ClientInfo) writeJSON(v interface{}) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	return c.conn.WriteJSON(v)
+}
+
+// sendShutdownClose는 서버 재시작을 알리고 재연결 대기 시간을 담은 close frame을 보냅니다
+func (c *ClientInfo) sendShutdownClose() error {
+	reconnectDelay := config.Get().Server.ReconnectDelay
+	if reconnectDelay <= 0 {
+		reconnectDelay = 5
 	}
+	closeMessage := websocket.FormatCloseMessage(websocket.CloseServiceRestart,
+		fmt.Sprintf("server shutting down, reconnect_after=%ds", reconnectDelay))
+	return c.conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(5*time.Second))
+}
+
+func NewServer(store func(context.Context, *models.SystemMetrics) error, cmdRepo *repository.CommandRepository, userRepo *repository.UserRepository, nodeRepo *repository.NodeRepository, logRepo *repository.LogRepository, nodeRegistry *registry.NodeRegistry, livenessTracker *liveness.Tracker, coordinator *cluster.Coordinator) *Server {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("Server 초기화 중")
 
 	server := &Server{
 		upgrader: websocket.Upgrader{
-			CheckOrigin:     func(r *http.Request) bool { return true },
+			CheckOrigin:     checkOrigin,
 			ReadBufferSize:  1024,
 			WriteBufferSize: 1024,
 		},
@@ -60,180 +143,346 @@ func NewServer(store func(*models.SystemMetrics) error, cmdRepo *repository.Comm
 		userRepo:   userRepo,
 		nodeRepo:   nodeRepo,
 		logRepo:    logRepo,
-		nodeList:   nodes, // 조회한 사용자 목록 설정
-		maxClients: 1000,
+		registry:   nodeRegistry,
+		liveness:   livenessTracker,
+		cluster:    coordinator,
+		connPolicy: newConnectionPolicy(),
+		mux:        http.NewServeMux(),
 	}
 
-	// 기존 노드들의 external_ip를 map에 로드
-	for _, node := range nodes {
-		if node.ExternalIP != "" {
-			server.nodeExternalIPs.Store(node.NodeID, node.ExternalIP)
-			sugar.Infow("기존 노드 외부 IP 로드", "nodeID", node.NodeID, "externalIP", node.ExternalIP)
-		}
-	}
+	// 기존 메트릭스 웹소켓 핸들러
+	server.mux.HandleFunc("/ws", server.handleConnections)
+
+	// 로그 수집용 새로운 웹소켓 핸들러
+	server.mux.HandleFunc("/ws/logs", server.handleLogConnections)
 
 	return server
 }
 
-func (s *Server) Start() {
-	sugar := logger.GetCustomLogger()
-	sugar.Infow("WebSocket server 시작 중")
+// Mux는 WebSocket 핸들러가 등록된 HTTP 라우터를 반환합니다.
+// 헬스 체크 등 부가 엔드포인트는 Start 전에 여기에 등록합니다.
+func (s *Server) Mux() *http.ServeMux {
+	return s.mux
+}
 
-	// 기존 메트릭스 웹소켓 핸들러
-	http.HandleFunc("/ws", s.handleConnections)
+// ShuttingDown은 서버가 종료 절차에 들어갔는지 반환합니다
+func (s *Server) ShuttingDown() bool {
+	return s.shuttingDown.Load()
+}
 
-	// 로그 수집용 새로운 웹소켓 핸들러
-	http.HandleFunc("/ws/logs", s.handleLogConnections)
+// addConn은 종료 중이 아니면 연결 처리 고루틴을 connWG에 추가하고 true를 반환합니다.
+// 종료 여부 확인과 Add를 lifecycleMu로 묶어 Shutdown이 Wait를 시작한 뒤에 Add되지 않도록 합니다.
+func (s *Server) addConn() bool {
+	s.lifecycleMu.Lock()
+	defer s.lifecycleMu.Unlock()
+
+	if s.shuttingDown.Load() {
+		return false
+	}
+	s.connWG.Add(1)
+	return true
+}
+
+// ClientCount는 현재 연결된 메트릭스 클라이언트 수를 반환합니다
+func (s *Server) ClientCount() int {
+	return s.connPolicy.count()
+}
+
+// closeClient는 close code와 사유를 담은 close frame을 보내고 연결을 닫습니다
+func (s *Server) closeClient(client *ClientInfo, code int, reason string) {
+	sugar := logger.FromContext(client.Context())
+	sugar.Infow("클라이언트 연결 강제 종료", "code", code, "reason", reason)
+
+	msg := websocket.FormatCloseMessage(code, reason)
+	if err := client.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(5*time.Second)); err != nil {
+		sugar.Errorw("close frame 전송 실패", "error", err)
+	}
+	client.conn.Close()
+}
+
+// NodeLastSeen은 노드별 마지막 메트릭스 수신 시간을 반환합니다
+func (s *Server) NodeLastSeen() map[string]time.Time {
+	return s.liveness.LastSeen()
+}
+
+// Start는 HTTP 리스너를 열고 Shutdown이 호출될 때까지 블록합니다
+func (s *Server) Start() error {
+	sugar := logger.GetCustomLogger()
+	sugar.Infow("WebSocket server 시작 중")
 
 	cfg := config.Get()
-	sugar.Infow("WebSocket server starting", "port", cfg.Server.Port)
-	err := http.ListenAndServe(fmt.Sprintf(":%d", cfg.Server.Port), nil)
-	if err != nil {
+	s.httpServer = &http.Server{
+		Addr:    fmt.Sprintf(":%d", cfg.Server.Port),
+		Handler: s.mux,
+	}
+
+	var err error
+	if tlsCfg := cfg.Server.TLS; tlsCfg.Enabled {
+		reloader, rerr := tlsutil.NewReloader(tlsutil.Options{
+			CertFile:       tlsCfg.CertFile,
+			KeyFile:        tlsCfg.KeyFile,
+			ClientCAFile:   tlsCfg.ClientCAFile,
+			ClientAuth:     tlsCfg.ClientAuth,
+			ReloadInterval: time.Duration(tlsCfg.ReloadInterval) * time.Second,
+		})
+		if rerr != nil {
+			sugar.Errorw("TLS 설정 실패", "error", rerr)
+			return rerr
+		}
+		reloader.Watch()
+		defer reloader.Stop()
+
+		s.httpServer.TLSConfig = reloader.TLSConfig()
+		sugar.Infow("WebSocket server starting (TLS)", "port", cfg.Server.Port, "clientAuth", tlsCfg.ClientAuth)
+		err = s.httpServer.ListenAndServeTLS("", "")
+	} else {
+		sugar.Infow("WebSocket server starting", "port", cfg.Server.Port)
+		err = s.httpServer.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		sugar.Errorf("WebSocket server failed to start: %v", err)
+		return err
 	}
+	return nil
 }
 
-func (s *Server) handleMessage(conn *websocket.Conn, message []byte, clientID string) {
+// Shutdown은 새 연결 수락을 중단하고, 연결된 모든 클라이언트에 재접속 안내가 담긴
+// close frame을 보낸 뒤 처리 중인 메시지와 연결이 정리될 때까지 기다립니다.
+// ctx가 만료되면 남은 연결을 강제로 닫습니다.
+func (s *Server) Shutdown(ctx context.Context) error {
 	sugar := logger.GetCustomLogger()
-	sugar.Infow("handleMessage 시작")
+	sugar.Infow("WebSocket server 종료 시작")
+
+	s.lifecycleMu.Lock()
+	s.shuttingDown.Store(true)
+	s.lifecycleMu.Unlock()
+
+	// 리스너를 닫아 새 업그레이드 요청을 받지 않음 (업그레이드된 연결은 hijack 되어 여기서 기다리지 않음)
+	var shutdownErr error
+	if s.httpServer != nil {
+		if err := s.httpServer.Shutdown(ctx); err != nil {
+			sugar.Errorw("HTTP 리스너 종료 실패", "error", err)
+			shutdownErr = err
+		}
+	}
+
+	sendClose := func(key, value interface{}) bool {
+		if err := value.(*ClientInfo).sendShutdownClose(); err != nil {
+			sugar.Errorw("close frame 전송 실패", "clientID", key, "error", err)
+		}
+		return true
+	}
+	s.clients.Range(sendClose)
+	s.logClients.Range(sendClose)
+
+	// 클라이언트가 close frame에 응답하여 읽기 루프가 끝나고 처리 중인 메시지가 모두 큐에 들어갈 때까지 대기.
+	// inflightWG는 연결 처리 고루틴 안에서만 Add하므로 connWG.Wait가 끝난 뒤에는 더 늘어나지 않음
+	done := make(chan struct{})
+	go func() {
+		s.connWG.Wait()
+		s.inflightWG.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		sugar.Infow("모든 클라이언트 연결 정리 완료")
+	case <-ctx.Done():
+		sugar.Errorw("클라이언트 연결 정리 시간 초과, 남은 연결을 강제로 종료합니다")
+		forceClose := func(_, value interface{}) bool {
+			value.(*ClientInfo).conn.Close()
+			return true
+		}
+		s.clients.Range(forceClose)
+		s.logClients.Range(forceClose)
+		if shutdownErr == nil {
+			shutdownErr = ctx.Err()
+		}
+	}
+
+	sugar.Infow("WebSocket server 종료 완료")
+	return shutdownErr
+}
+
+// errCertMismatch는 클라이언트 인증서의 노드 ID와 메트릭스 키가 다를 때 반환됩니다
+var errCertMismatch = errors.New("인증서 노드 ID 불일치")
+
+// certKeyCheck는 클라이언트 인증서의 노드 ID를 메트릭스와 대조하고 obscura key 조회가 필요한지 반환합니다.
+// skipKeyCheck이면 인증서로 확인된 노드의 조회를 생략하지만, 처음 보는 노드나 저장된 소유자와 key가 다른 노드는
+// 임의의 USER_ID로 등록되지 않도록 조회합니다. lookup은 등록된 노드를 찾습니다.
+func certKeyCheck(certNodeID string, metrics *models.SystemMetrics, skipKeyCheck bool, lookup func(string) (models.Node, bool)) (bool, error) {
+	if certNodeID == "" {
+		return true, nil
+	}
+	if certNodeID != metrics.Key {
+		return false, errCertMismatch
+	}
+	if !skipKeyCheck {
+		return true, nil
+	}
+	node, ok := lookup(metrics.Key)
+	return !ok || node.ObscuraKey != metrics.USER_ID, nil
+}
+
+// handleMessage는 메트릭스 메시지 하나를 처리합니다. ctx에는 연결 ID와 메시지 ID가 들어 있습니다.
+func (s *Server) handleMessage(ctx context.Context, client *ClientInfo, message []byte, clientID string) {
+	sugar := logger.FromContext(ctx)
+	sugar.Debugw("handleMessage 시작")
 
 	start := time.Now()
 	var metrics models.SystemMetrics
 	if err := json.Unmarshal(message, &metrics); err != nil {
+		telemetry.ParseFailures.WithLabelValues(telemetry.TypeMetrics).Inc()
 		sugar.Errorw("메시지 파싱 오류", "error", err)
-		s.sendErrorResponse(conn, "메시지 파싱 오류")
+		s.sendErrorResponse(client, "메시지 파싱 오류")
 		return
 	}
 
-	exists, err := s.userRepo.ExistsUserByObscuraKey(metrics.USER_ID)
-	if err != nil || !exists {
-		sugar.Errorw("사용자 조회 실패", "error", err)
-		s.sendErrorResponse(conn, "사용자 조회 실패")
+	// mTLS로 확인된 노드는 인증서의 노드 ID와 메트릭스 키가 일치해야 함
+	keyCheck, err := certKeyCheck(client.certNodeID, &metrics, config.Get().Server.TLS.SkipKeyCheck, s.registry.Get)
+	if err != nil {
+		telemetry.AuthFailures.WithLabelValues("cert_mismatch").Inc()
+		sugar.Errorw("인증서 노드 ID 불일치", "certNodeID", client.certNodeID, "key", metrics.Key)
+		s.sendErrorResponse(client, "인증서 노드 ID 불일치")
 		return
 	}
 
+	if keyCheck {
+		exists, err := s.userRepo.ExistsUserByObscuraKey(ctx, metrics.USER_ID)
+		if err != nil || !exists {
+			if err == nil {
+				telemetry.AuthFailures.WithLabelValues("unknown_key").Inc()
+			}
+			sugar.Errorw("사용자 조회 실패", "error", err)
+			s.sendErrorResponse(client, "사용자 조회 실패")
+			return
+		}
+	}
+
 	// Key 검증
 	if metrics.Key == "" {
 		sugar.Errorw("키가 없는 메트릭스")
-		s.sendErrorResponse(conn, "키가 없는 메트릭스")
+		s.sendErrorResponse(client, "키가 없는 메트릭스")
 		return
 	}
 
-	nodeExists := false
-	for _, node := range s.nodeList {
-		if node.NodeID == metrics.Key {
-			nodeExists = true
-			break
+	// 노드 확인 및 최초 등록 (다른 사용자의 노드 ID면 거부)
+	if _, err := s.registry.Resolve(ctx, &metrics); err != nil {
+		if errors.Is(err, registry.ErrNodeOwnership) {
+			telemetry.AuthFailures.WithLabelValues("node_ownership").Inc()
+			sugar.Errorw("노드 소유자 불일치", "nodeID", metrics.Key)
+			s.sendErrorResponse(client, "다른 사용자에게 등록된 노드입니다")
+			return
 		}
+		sugar.Errorw("노드 확인 실패", "nodeID", metrics.Key, "error", err)
+		s.sendErrorResponse(client, "노드 확인 실패")
+		return
 	}
 
-	if !nodeExists {
-		node := models.Node{
-			NodeID:     metrics.Key,
-			ObscuraKey: metrics.USER_ID,
-			ServerType: "debug", // TODO: 서버 타입 추가
+	// 연결에 obscura key와 노드 ID 바인딩 (키/노드별 연결 수 제한 및 중복 연결 정책 적용)
+	kicked, err := s.connPolicy.bind(clientID, metrics.USER_ID, metrics.Key)
+	if err != nil {
+		telemetry.RejectedConnections.WithLabelValues(rejectReason(err)).Inc()
+		sugar.Errorw("연결 정책 위반", "nodeID", metrics.Key, "error", err)
+		code := websocket.ClosePolicyViolation
+		if errors.Is(err, errTooManyPerKey) {
+			code = websocket.CloseTryAgainLater
 		}
-		err := s.nodeRepo.CreateNode(&node)
-		if err != nil {
-			sugar.Errorw("노드 생성 실패", "error", err)
-		} else {
-			sugar.Infof("노드 생성 성공: %s", metrics.Key)
-			s.nodeList = append(s.nodeList, &models.Node{
-				NodeID: metrics.Key,
-			})
+		s.closeClient(client, code, err.Error())
+		return
+	}
+	firstBind := client.NodeID() == ""
+	if client.NodeID() != metrics.Key {
+		client.bindNode(metrics.Key, metrics.USER_ID)
+		ctx = logger.WithContext(ctx, "nodeID", metrics.Key, "userKey", logger.MaskKey(metrics.USER_ID))
+		sugar = logger.FromContext(ctx)
+	}
+	for _, kickedID := range kicked {
+		if value, ok := s.clients.Load(kickedID); ok {
+			sugar.Infow("중복 노드 연결 종료", "nodeID", metrics.Key, "clientID", kickedID)
+			s.closeClient(value.(*ClientInfo), websocket.ClosePolicyViolation, "replaced by a newer connection for the same node")
 		}
 	}
 
-	// External IP 관리 로직
-	if metrics.ExternalIP != "" {
-		s.handleExternalIPUpdate(metrics.Key, metrics.ExternalIP)
+	if firstBind {
+		// 다른 인스턴스에 남아 있는 이전 연결은 그 인스턴스가 끊도록 알림
+		if err := s.cluster.Acquire(metrics.Key); err != nil {
+			sugar.Errorw("노드 연결 소유권 기록 실패", "nodeID", metrics.Key, "error", err)
+		}
+		// 연결이 없는 동안 쌓인 명령어 전달
+		go s.DeliverCommands(metrics.Key)
 	}
 
-	sugar.Infof("메트릭스 키: %s", metrics.Key)
+	sugar.Debugf("메트릭스 키: %s", metrics.Key)
+	s.liveness.Seen(metrics.Key)
 
 	// 메트릭스 저장
-	if err := s.store(&metrics); err != nil {
+	if err := s.store(ctx, &metrics); err != nil {
 		sugar.Errorw("메트릭스 저장 실패", "error", err)
-		s.sendErrorResponse(conn, "메트릭스 저장 실패")
+		s.sendErrorResponse(client, "메트릭스 저장 실패")
 		return
 	}
 
-	// 응답 전송 전에 데드라인 설정
-	conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-
 	// 응답 전송
 	response := models.WSResponse{
 		Type:   "metrics_response",
 		Result: "ok",
 	}
 
-	if err := conn.WriteJSON(response); err != nil {
+	if err := client.writeJSON(response); err != nil {
 		sugar.Errorw("응답 전송 실패", "error", err)
 	}
 
 	elapsed := time.Since(start)
-	sugar.Infof("응답 전송 완료: %v ms", elapsed.Milliseconds())
+	telemetry.MessageDuration.WithLabelValues(telemetry.TypeMetrics).Observe(elapsed.Seconds())
+	sugar.Debugf("응답 전송 완료: %v ms", elapsed.Milliseconds())
+}
 
-	// 메트릭스 파싱 후 nodeID 저장
-	if value, ok := s.clients.Load(clientID); ok {
-		if clientInfo, ok := value.(*ClientInfo); ok {
-			clientInfo.nodeID = metrics.Key
-			s.clients.Store(clientID, clientInfo)
+// DeliverCommands는 노드에 대기 중인 명령어를 이 인스턴스에 연결된 노드 연결로 전달하고,
+// 전달한 명령어를 삭제합니다. 노드가 이 인스턴스에 연결되어 있지 않으면 아무것도 하지 않습니다.
+func (s *Server) DeliverCommands(nodeID string) {
+	s.deliverMu.Lock()
+	defer s.deliverMu.Unlock()
 
-			// 처음 노드 ID가 설정될 때 연결 상태 전송
-			if clientInfo.nodeID != "" {
-				// s.sendNodeStatus(clientInfo.nodeID, 1) // 1은 연결됨
-				s.updateNodeStatus(clientInfo.nodeID, 1)
-			}
+	var client *ClientInfo
+	for _, clientID := range s.connPolicy.clientsFor(nodeID) {
+		if value, ok := s.clients.Load(clientID); ok {
+			client = value.(*ClientInfo)
 		}
 	}
-}
-
-// handleExternalIPUpdate는 외부 IP 변경을 처리합니다.
-func (s *Server) handleExternalIPUpdate(nodeID, newExternalIP string) {
-	sugar := logger.GetCustomLogger()
+	if client == nil {
+		return
+	}
+	ctx := client.Context()
+	sugar := logger.FromContext(ctx)
 
-	// 기존 external IP 조회
-	storedIPInterface, exists := s.nodeExternalIPs.Load(nodeID)
-	var storedIP string
-	if exists {
-		storedIP = storedIPInterface.(string)
+	commands, err := s.cmdRepo.GetCommandsByNodeID(ctx, nodeID)
+	if err != nil || len(commands) == 0 {
+		return
 	}
 
-	// 새로운 IP가 있고, 기존 IP와 다른 경우에만 업데이트
-	if newExternalIP != "" && newExternalIP != storedIP {
-		sugar.Infow("노드 외부 IP 변경 감지",
-			"nodeID", nodeID,
-			"oldExternalIP", storedIP,
-			"newExternalIP", newExternalIP)
+	if err := client.writeJSON(models.WSCommands{Type: "commands", Commands: commands}); err != nil {
+		sugar.Errorw("명령어 전달 실패", "nodeID", nodeID, "error", err)
+		return
+	}
 
-		// map 업데이트
-		s.nodeExternalIPs.Store(nodeID, newExternalIP)
+	commandIDs := make([]int, 0, len(commands))
+	for _, cmd := range commands {
+		commandIDs = append(commandIDs, cmd.CommandID)
+	}
+	if err := s.cmdRepo.DeleteCommands(ctx, nodeID, commandIDs); err != nil {
+		sugar.Errorw("전달한 명령어 삭제 실패", "nodeID", nodeID, "error", err)
+		return
+	}
+	sugar.Infow("명령어 전달 완료", "nodeID", nodeID, "count", len(commands))
+}
 
-		// PostgreSQL DB 업데이트
-		go func() {
-			if err := s.nodeRepo.UpdateNodeExternalIP(nodeID, newExternalIP); err != nil {
-				sugar.Errorw("노드 외부 IP DB 업데이트 실패",
-					"error", err,
-					"nodeID", nodeID,
-					"externalIP", newExternalIP)
-
-				// DB 업데이트 실패 시 map에서 원래 값으로 롤백
-				if exists {
-					s.nodeExternalIPs.Store(nodeID, storedIP)
-				} else {
-					s.nodeExternalIPs.Delete(nodeID)
-				}
-			} else {
-				sugar.Infow("노드 외부 IP 업데이트 완료",
-					"nodeID", nodeID,
-					"externalIP", newExternalIP)
-			}
-		}()
-	} else if newExternalIP != "" && newExternalIP == storedIP {
-		sugar.Debugw("노드 외부 IP 변경 없음",
-			"nodeID", nodeID,
-			"externalIP", newExternalIP)
+// CloseNode는 다른 인스턴스가 노드 연결을 가져갔을 때 이 인스턴스의 연결을 끊습니다
+func (s *Server) CloseNode(nodeID string) {
+	for _, clientID := range s.connPolicy.clientsFor(nodeID) {
+		if value, ok := s.clients.Load(clientID); ok {
+			s.closeClient(value.(*ClientInfo), websocket.ClosePolicyViolation, "replaced by a newer connection for the same node")
+		}
 	}
 }
 
@@ -274,33 +523,30 @@ func (s *Server) sendCommandResults(commandResultJSON []byte, nodeID, userID str
 	}
 }
 
-func (s *Server) sendErrorResponse(conn *websocket.Conn, errMsg string) {
+func (s *Server) sendErrorResponse(client *ClientInfo, errMsg string) {
 	sugar := logger.GetCustomLogger()
-	sugar.Infow("sendErrorResponse 시작")
-
-	// 쓰기 작업 전에 데드라인 설정
-	conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	sugar.Debugw("sendErrorResponse 시작")
 
 	response := models.WSResponse{
 		Type:   "error",
 		Result: errMsg,
 	}
-	if err := conn.WriteJSON(response); err != nil {
+	if err := client.writeJSON(response); err != nil {
 		sugar.Errorw("에러 응답 전송 실패", "error", err)
 	}
 }
 
 func (s *Server) handleDisconnect(clientID string, code int, text string) {
-	sugar := logger.GetCustomLogger()
-	sugar.Infof("클라이언트 %s 연결 종료 (코드: %d, 사유: %s)", clientID, code, text)
-
 	// 클라이언트 정보와 nodeID 조회
 	if value, ok := s.clients.Load(clientID); ok {
 		if clientInfo, ok := value.(*ClientInfo); ok {
-			// nodeID가 있으면 상태 전송
-			if clientInfo.nodeID != "" {
-				// s.sendNodeStatus(clientInfo.nodeID, 0) // 0은 연결 끊김
-				s.updateNodeStatus(clientInfo.nodeID, 0)
+			sugar := logger.FromContext(clientInfo.Context())
+			sugar.Infof("클라이언트 %s 연결 종료 (코드: %d, 사유: %s)", clientID, code, text)
+
+			// 노드의 마지막 연결이 끊겼을 때만 오프라인 처리 (중복 연결로 밀려났거나 같은 노드의 다른 연결이 남은 경우 제외)
+			if nodeID := clientInfo.NodeID(); s.connPolicy.release(clientID) && nodeID != "" {
+				s.liveness.Disconnected(nodeID)
+				s.cluster.Release(nodeID)
 			}
 			clientInfo.conn.Close()
 		}
@@ -310,18 +556,26 @@ func (s *Server) handleDisconnect(clientID string, code int, text string) {
 
 func (s *Server) handleConnections(w http.ResponseWriter, r *http.Request) {
 	sugar := logger.GetCustomLogger()
-	sugar.Infow("handleConnections 시작")
+	sugar.Debugw("handleConnections 시작")
 
-	clientCount := 0
-	s.clients.Range(func(_, _ interface{}) bool {
-		clientCount++
-		return true
-	})
-	if clientCount >= s.maxClients {
-		sugar.Infof("최대 클라이언트 수 초과: %s", r.RemoteAddr)
-		http.Error(w, "서버가 최대 용량에 도달했습니다", http.StatusServiceUnavailable)
+	if !s.addConn() {
+		http.Error(w, "서버가 종료 중입니다", http.StatusServiceUnavailable)
+		return
+	}
+	defer s.connWG.Done()
+
+	clientID := r.RemoteAddr
+	if err := s.connPolicy.acquire(clientID, remoteIP(r)); err != nil {
+		telemetry.RejectedConnections.WithLabelValues(rejectReason(err)).Inc()
+		sugar.Infow("연결 거부", "remoteAddr", r.RemoteAddr, "error", err)
+		status := http.StatusServiceUnavailable
+		if errors.Is(err, errTooManyPerIP) {
+			status = http.StatusTooManyRequests
+		}
+		http.Error(w, err.Error(), status)
 		return
 	}
+	defer s.connPolicy.release(clientID)
 
 	conn, err := s.upgrader.Upgrade(w, r, nil)
 	if err != nil {
@@ -329,6 +583,16 @@ func (s *Server) handleConnections(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 메시지 크기와 속도 제한은 설정이 다시 로드되면 연결 중에도 바뀐 값으로 갱신
+	connCfg := config.Get().Connection
+	applyReadLimit(conn, connCfg.MaxMessageSize)
+	bucket := ratelimit.NewBucket(connCfg.RateLimit.MessagesPerSecond, connCfg.RateLimit.Burst)
+	violations := 0
+
+	connGauge := telemetry.ActiveConnections.WithLabelValues(telemetry.TypeMetrics)
+	connGauge.Inc()
+	defer connGauge.Dec()
+
 	// 읽기 데드라인만 설정하고 쓰기 데드라인은 각 쓰기 작업마다 설정하도록 수정
 	conn.SetReadDeadline(time.Now().Add(60 * time.Second))
 
@@ -337,13 +601,16 @@ func (s *Server) handleConnections(w http.ResponseWriter, r *http.Request) {
 		return nil
 	})
 
-	clientID := r.RemoteAddr
-	clientInfo := &ClientInfo{
-		conn:   conn,
-		nodeID: "", // 처음에는 빈 문자열로 초기화
-	}
+	clientInfo := newClientInfo(conn, r.RemoteAddr, tlsutil.NodeIdentity(r.TLS))
 	s.clients.Store(clientID, clientInfo)
+	sugar = logger.FromContext(clientInfo.Context())
+	// Shutdown이 close frame을 보낸 뒤에 등록되었으면 직접 보냄
+	if s.shuttingDown.Load() {
+		clientInfo.sendShutdownClose()
+	}
+	stopPing := make(chan struct{})
 	defer func() {
+		close(stopPing)
 		s.clients.Delete(clientID)
 		conn.Close()
 	}()
@@ -353,11 +620,15 @@ func (s *Server) handleConnections(w http.ResponseWriter, r *http.Request) {
 		ticker := time.NewTicker(30 * time.Second)
 		defer ticker.Stop()
 
-		// for range로 수정
-		for range ticker.C {
-			if err := conn.WriteControl(websocket.PingMessage, []byte{}, time.Now().Add(10*time.Second)); err != nil {
-				sugar.Errorw("Ping 실패", "error", err)
+		for {
+			select {
+			case <-stopPing:
 				return
+			case <-ticker.C:
+				if err := conn.WriteControl(websocket.PingMessage, []byte{}, time.Now().Add(10*time.Second)); err != nil {
+					sugar.Errorw("Ping 실패", "error", err)
+					return
+				}
 			}
 		}
 	}()
@@ -365,50 +636,114 @@ func (s *Server) handleConnections(w http.ResponseWriter, r *http.Request) {
 	sugar.Infof("새로운 클라이언트 연결: %s", clientID)
 	// TODO Web 서버로 Node가 접속했음을 알리는 요청 보내기
 
+	var seq uint64
 	for {
 		messageType, message, err := conn.ReadMessage()
 		if err != nil {
-			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-				s.handleDisconnect(clientID, websocket.CloseAbnormalClosure, err.Error())
+			// 정상 종료, 읽기 데드라인 초과를 포함한 모든 종료에서 노드 상태를 정리
+			code := websocket.CloseAbnormalClosure
+			var closeErr *websocket.CloseError
+			if errors.As(err, &closeErr) {
+				code = closeErr.Code
 			}
+			s.handleDisconnect(clientID, code, err.Error())
 			break
 		}
 
+		telemetry.MessagesReceived.WithLabelValues(telemetry.TypeMetrics).Inc()
+		telemetry.BytesReceived.WithLabelValues(telemetry.TypeMetrics).Add(float64(len(message)))
+
+		if current := config.Get().Connection; current.MaxMessageSize != connCfg.MaxMessageSize || current.RateLimit != connCfg.RateLimit {
+			applyReadLimit(conn, current.MaxMessageSize)
+			bucket.SetRate(current.RateLimit.MessagesPerSecond, current.RateLimit.Burst)
+			connCfg = current
+		}
+
+		// 연결당 메시지 속도 제한, 연속 위반이 한도를 넘으면 연결 종료
+		if !bucket.Allow() {
+			telemetry.RateLimitedMessages.Inc()
+			violations++
+			if maxViolations := config.Get().Connection.RateLimit.MaxViolations; maxViolations > 0 && violations >= maxViolations {
+				s.closeClient(clientInfo, websocket.ClosePolicyViolation, "rate limit exceeded")
+				s.handleDisconnect(clientID, websocket.ClosePolicyViolation, "rate limit exceeded")
+				break
+			}
+			s.sendErrorResponse(clientInfo, "요청 속도 제한 초과")
+			continue
+		}
+		violations = 0
+
 		if messageType != websocket.TextMessage {
-			s.sendErrorResponse(conn, "잘못된 메시지 타입")
+			s.sendErrorResponse(clientInfo, "잘못된 메시지 타입")
 			continue
 		}
 
+		// 메시지 ID는 연결 ID와 연결 내 순번으로 구성
+		seq++
+		msgCtx := logger.WithContext(clientInfo.Context(), "msgID", fmt.Sprintf("%s-%d", clientInfo.connID, seq))
+
+		s.inflightWG.Add(1)
 		go func() {
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
 
 			done := make(chan struct{})
 			go func() {
-				s.handleMessage(conn, message, clientID) // clientID 전달
+				defer s.inflightWG.Done()
+				s.handleMessage(msgCtx, clientInfo, message, clientID) // clientID 전달
 				close(done)
 			}()
 
 			select {
 			case <-ctx.Done():
-				sugar.Infof("메시지 처리 시간 초과: %s", clientID)
-				s.sendErrorResponse(conn, "처리 시간 초과")
+				logger.FromContext(msgCtx).Infof("메시지 처리 시간 초과: %s", clientID)
+				s.sendErrorResponse(clientInfo, "처리 시간 초과")
 			case <-done:
 			}
 		}()
 	}
 }
 
+// applyReadLimit은 메시지 크기 제한을 설정합니다. 초과 시 gorilla/websocket이 CloseMessageTooBig으로 연결을 닫습니다.
+// 0 이하이면 제한하지 않습니다.
+func applyReadLimit(conn *websocket.Conn, limit int64) {
+	if limit < 0 {
+		limit = 0
+	}
+	conn.SetReadLimit(limit)
+}
+
 func (s *Server) handleLogConnections(w http.ResponseWriter, r *http.Request) {
 	sugar := logger.GetCustomLogger()
 	sugar.Infow("로그 웹소켓 연결 시작")
 
+	if !s.addConn() {
+		http.Error(w, "서버가 종료 중입니다", http.StatusServiceUnavailable)
+		return
+	}
+	defer s.connWG.Done()
+
 	conn, err := s.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		sugar.Errorw("로그 연결 업그레이드 실패", "error", err)
 		return
 	}
-	defer conn.Close()
+
+	connGauge := telemetry.ActiveConnections.WithLabelValues(telemetry.TypeLogs)
+	connGauge.Inc()
+	defer connGauge.Dec()
+
+	clientID := r.RemoteAddr
+	clientInfo := newClientInfo(conn, r.RemoteAddr, "")
+	s.logClients.Store(clientID, clientInfo)
+	sugar = logger.FromContext(clientInfo.Context())
+	if s.shuttingDown.Load() {
+		clientInfo.sendShutdownClose()
+	}
+	defer func() {
+		s.logClients.Delete(clientID)
+		conn.Close()
+	}()
 
 	for {
 		_, message, err := conn.ReadMessage()
@@ -419,14 +754,18 @@ func (s *Server) handleLogConnections(w http.ResponseWriter, r *http.Request) {
 			break
 		}
 
+		telemetry.MessagesReceived.WithLabelValues(telemetry.TypeLogs).Inc()
+		telemetry.BytesReceived.WithLabelValues(telemetry.TypeLogs).Add(float64(len(message)))
+
 		// 클라이언트 로그 구조에 맞는 파싱
 		var payload struct {
 			Logs []models.LogMessage `json:"logs"`
 		}
 
 		if err := json.Unmarshal(message, &payload); err != nil {
+			telemetry.ParseFailures.WithLabelValues(telemetry.TypeLogs).Inc()
 			sugar.Errorw("로그 메시지 파싱 오류", "error", err)
-			s.sendErrorResponse(conn, "로그 메시지 파싱 오류")
+			s.sendErrorResponse(clientInfo, "로그 메시지 파싱 오류")
 			continue
 		}
 
@@ -434,20 +773,22 @@ func (s *Server) handleLogConnections(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
-		sugar.Infof("%d개의 로그 메시지 수신됨", len(payload.Logs))
+		sugar.Debugf("%d개의 로그 메시지 수신됨", len(payload.Logs))
 
 		// 로그 저장 처리
+		s.inflightWG.Add(1)
 		go func(logs []models.LogMessage) {
-			s.storeLogs(logs)
+			defer s.inflightWG.Done()
+			s.storeLogs(clientInfo.Context(), logs)
 		}(payload.Logs)
 	}
 }
 
-func (s *Server) storeLogs(logs []models.LogMessage) error {
+func (s *Server) storeLogs(ctx context.Context, logs []models.LogMessage) error {
 	// 여기서 로그 배열을 한 번에 DB에 저장
-	sugar := logger.GetCustomLogger()
-	sugar.Infof("%d개의 로그 저장 시작", len(logs))
-	return s.logRepo.SaveLogs(logs)
+	sugar := logger.FromContext(ctx)
+	sugar.Debugf("%d개의 로그 저장 시작", len(logs))
+	return s.logRepo.SaveLogs(ctx, logs)
 }
 
 func (s *Server) sendNodeStatus(nodeID string, status int) {
@@ -498,15 +839,3 @@ func (s *Server) sendNodeStatus(nodeID string, status int) {
 		sugar.Errorw("노드 상태 전송 실패", "status", resp.StatusCode, "response", string(bodyBytes))
 	}
 }
-
-func (s *Server) updateNodeStatus(nodeID string, status int) {
-	sugar := logger.GetCustomLogger()
-	sugar.Infof("노드 상태 업데이트: %s, %d", nodeID, status)
-
-	err := s.nodeRepo.UpdateNodeStatus(nodeID, status)
-	if err != nil {
-		sugar.Errorw("노드 상태 업데이트 실패", "error", err)
-	}
-
-	sugar.Infof("노드 상태 업데이트 성공: %s, %d", nodeID, status)
-}
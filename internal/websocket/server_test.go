@@ -0,0 +1,186 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	config "system-collector/configs"
+	"system-collector/pkg/models"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestCertKeyCheck(t *testing.T) {
+	registered := map[string]models.Node{
+		"node-1": {NodeID: "node-1", ObscuraKey: "key-a"},
+	}
+	lookup := func(nodeID string) (models.Node, bool) {
+		node, ok := registered[nodeID]
+		return node, ok
+	}
+
+	tests := []struct {
+		name         string
+		certNodeID   string
+		key          string
+		userID       string
+		skipKeyCheck bool
+		wantCheck    bool
+		wantErr      error
+	}{
+		{name: "인증서 없음", key: "node-1", userID: "key-a", skipKeyCheck: true, wantCheck: true},
+		{name: "인증서와 키 불일치", certNodeID: "node-2", key: "node-1", userID: "key-a", skipKeyCheck: true, wantErr: errCertMismatch},
+		{name: "skip_key_check 꺼짐", certNodeID: "node-1", key: "node-1", userID: "key-a", wantCheck: true},
+		{name: "등록된 노드와 같은 소유자", certNodeID: "node-1", key: "node-1", userID: "key-a", skipKeyCheck: true, wantCheck: false},
+		{name: "등록된 노드와 다른 소유자", certNodeID: "node-1", key: "node-1", userID: "key-b", skipKeyCheck: true, wantCheck: true},
+		{name: "처음 보는 노드", certNodeID: "node-3", key: "node-3", userID: "anything", skipKeyCheck: true, wantCheck: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := &models.SystemMetrics{Key: tt.key, USER_ID: tt.userID}
+			check, err := certKeyCheck(tt.certNodeID, metrics, tt.skipKeyCheck, lookup)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("오류 = %v, 기대 %v", err, tt.wantErr)
+			}
+			if err == nil && check != tt.wantCheck {
+				t.Errorf("obscura key 조회 = %v, 기대 %v", check, tt.wantCheck)
+			}
+		})
+	}
+}
+
+// loadTestConfig는 검증을 통과하는 최소 설정에 extra를 덧붙여 로드합니다
+func loadTestConfig(t *testing.T, extra ...string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	yaml := "influxdb: {token: test, org: test, bucket: test}\npostgres: {user: test, dbname: test}\n" + strings.Join(extra, "\n")
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatalf("설정 파일 쓰기 실패: %v", err)
+	}
+	if err := config.Load(path); err != nil {
+		t.Fatalf("설정 로드 실패: %v", err)
+	}
+}
+
+// startTestServer는 저장소 없이 WebSocket 핸들러만 가진 서버를 시작합니다
+func startTestServer(t *testing.T) (*Server, string) {
+	t.Helper()
+	loadTestConfig(t)
+	s := NewServer(nil, nil, nil, nil, nil, nil, nil, nil)
+	ts := httptest.NewServer(s.Mux())
+	t.Cleanup(ts.Close)
+	return s, "ws" + strings.TrimPrefix(ts.URL, "http")
+}
+
+func TestShutdown(t *testing.T) {
+	s, url := startTestServer(t)
+
+	var conns []*websocket.Conn
+	for _, path := range []string{"/ws", "/ws/logs"} {
+		conn, _, err := websocket.DefaultDialer.Dial(url+path, nil)
+		if err != nil {
+			t.Fatalf("%s 연결 실패: %v", path, err)
+		}
+		defer conn.Close()
+		conns = append(conns, conn)
+	}
+
+	// 클라이언트는 close frame을 받으면 (gorilla 기본 동작으로) 응답한 뒤 종료 순서를 기록
+	events := make(chan string, 10)
+	for i, conn := range conns {
+		go func() {
+			_, _, err := conn.ReadMessage()
+			var closeErr *websocket.CloseError
+			if !errors.As(err, &closeErr) {
+				events <- fmt.Sprintf("client%d: close frame 대신 %v", i, err)
+				return
+			}
+			if closeErr.Code != websocket.CloseServiceRestart || !strings.Contains(closeErr.Text, "reconnect_after=5s") {
+				events <- fmt.Sprintf("client%d: close = %d %q", i, closeErr.Code, closeErr.Text)
+				return
+			}
+			events <- "closed"
+		}()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	events <- "shutdown"
+
+	// 모든 클라이언트가 close frame을 받은 뒤에 Shutdown이 끝나야 함
+	for i := 0; i < len(conns); i++ {
+		if got := <-events; got != "closed" {
+			t.Fatalf("이벤트 %d = %q, close frame 수신 기대", i, got)
+		}
+	}
+	if got := <-events; got != "shutdown" {
+		t.Fatalf("마지막 이벤트 = %q, Shutdown 완료 기대", got)
+	}
+
+	// 종료 중에는 새 연결을 거부
+	_, resp, err := websocket.DefaultDialer.Dial(url+"/ws", nil)
+	if err == nil {
+		t.Fatal("종료 후 새 연결이 허용됨")
+	}
+	if resp == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("종료 후 연결 응답 = %v, 503 기대", resp)
+	}
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	s, url := startTestServer(t)
+
+	// close frame을 읽지 않는 클라이언트는 시간 초과 후 강제로 끊김
+	conn, _, err := websocket.DefaultDialer.Dial(url+"/ws", nil)
+	if err != nil {
+		t.Fatalf("연결 실패: %v", err)
+	}
+	defer conn.Close()
+	waitClients(t, s, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if err := s.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown = %v, 시간 초과 기대", err)
+	}
+
+	// close frame은 보낸 상태여야 하고, 서버 쪽 연결은 강제로 닫혀 정리되어야 함
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, _, err = conn.ReadMessage()
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) || closeErr.Code != websocket.CloseServiceRestart {
+		t.Fatalf("읽기 = %v, close frame(1012) 기대", err)
+	}
+	waitClients(t, s, 0)
+}
+
+// waitClients는 서버에 등록된 메트릭스 연결이 n개가 될 때까지 기다립니다
+func waitClients(t *testing.T, s *Server, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		count := 0
+		s.clients.Range(func(_, _ interface{}) bool {
+			count++
+			return true
+		})
+		if count == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("연결 %d개 등록 대기 시간 초과 (현재 %d개)", n, count)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,119 @@
+package geoip
+
+import (
+	"fmt"
+	"net"
+
+	"system-collector/pkg/logger"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// Info는 IP 주소의 국가와 AS(Autonomous System) 정보입니다
+type Info struct {
+	CountryCode string `json:"country_code,omitempty"`
+	CountryName string `json:"country_name,omitempty"`
+	ASN         uint   `json:"asn,omitempty"`
+	ASOrg       string `json:"as_org,omitempty"`
+}
+
+// Resolver는 국가 DB와 ASN DB를 함께 조회합니다.
+// 둘 중 하나만 설정해도 되며, nil Resolver는 빈 Info를 반환합니다.
+type Resolver struct {
+	country *maxminddb.Reader
+	asn     *maxminddb.Reader
+}
+
+// countryRecord는 Country/City DB 레코드 중 사용하는 필드입니다
+type countryRecord struct {
+	Country           countryInfo `maxminddb:"country"`
+	RegisteredCountry countryInfo `maxminddb:"registered_country"`
+}
+
+type countryInfo struct {
+	ISOCode string            `maxminddb:"iso_code"`
+	Names   map[string]string `maxminddb:"names"`
+}
+
+// asnRecord는 ASN DB 레코드입니다
+type asnRecord struct {
+	Number       uint   `maxminddb:"autonomous_system_number"`
+	Organization string `maxminddb:"autonomous_system_organization"`
+}
+
+// NewResolver는 GeoIP2/GeoLite2 Country(또는 City) DB와 ASN DB 파일로 Resolver를 생성합니다.
+// 경로가 비어 있는 DB는 사용하지 않습니다.
+func NewResolver(countryDB, asnDB string) (*Resolver, error) {
+	sugar := logger.GetCustomLogger()
+
+	r := &Resolver{}
+	if countryDB != "" {
+		reader, err := maxminddb.Open(countryDB)
+		if err != nil {
+			return nil, fmt.Errorf("GeoIP 국가 DB 열기 실패: %v", err)
+		}
+		r.country = reader
+		sugar.Infow("GeoIP 국가 DB 로드 완료", "path", countryDB, "type", reader.Metadata.DatabaseType)
+	}
+	if asnDB != "" {
+		reader, err := maxminddb.Open(asnDB)
+		if err != nil {
+			r.Close()
+			return nil, fmt.Errorf("GeoIP ASN DB 열기 실패: %v", err)
+		}
+		r.asn = reader
+		sugar.Infow("GeoIP ASN DB 로드 완료", "path", asnDB, "type", reader.Metadata.DatabaseType)
+	}
+	return r, nil
+}
+
+// Enabled는 조회할 DB가 하나라도 있는지 반환합니다
+func (r *Resolver) Enabled() bool {
+	return r != nil && (r.country != nil || r.asn != nil)
+}
+
+// Lookup은 IP 주소의 국가와 ASN을 조회합니다. 찾지 못한 값은 비워 둡니다.
+func (r *Resolver) Lookup(ipStr string) Info {
+	var info Info
+	if !r.Enabled() {
+		return info
+	}
+	ip := net.ParseIP(ipStr)
+	if ip == nil {
+		return info
+	}
+
+	// 조회 오류(IPv4 전용 DB에서 IPv6 조회, 손상된 레코드 등)는 찾지 못한 것으로 처리
+	if r.country != nil {
+		var record countryRecord
+		if err := r.country.Lookup(ip, &record); err == nil {
+			country := record.Country
+			if country.ISOCode == "" {
+				country = record.RegisteredCountry
+			}
+			info.CountryCode = country.ISOCode
+			info.CountryName = country.Names["en"]
+		}
+	}
+	if r.asn != nil {
+		var record asnRecord
+		if err := r.asn.Lookup(ip, &record); err == nil {
+			info.ASN = record.Number
+			info.ASOrg = record.Organization
+		}
+	}
+	return info
+}
+
+// Close는 열린 DB 파일을 닫습니다
+func (r *Resolver) Close() {
+	if r == nil {
+		return
+	}
+	if r.country != nil {
+		r.country.Close()
+	}
+	if r.asn != nil {
+		r.asn.Close()
+	}
+}
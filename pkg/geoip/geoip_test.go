@@ -0,0 +1,150 @@
+package geoip
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/maxmind/mmdbwriter"
+	"github.com/maxmind/mmdbwriter/mmdbtype"
+)
+
+// writeDB는 네트워크별 레코드로 MaxMind DB 파일을 만듭니다
+func writeDB(t *testing.T, dbType string, ipVersion int, records map[string]mmdbtype.Map) string {
+	t.Helper()
+	w, err := mmdbwriter.New(mmdbwriter.Options{DatabaseType: dbType, IPVersion: ipVersion, RecordSize: 24})
+	if err != nil {
+		t.Fatalf("mmdbwriter.New: %v", err)
+	}
+	for cidr, record := range records {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			t.Fatalf("ParseCIDR(%s): %v", cidr, err)
+		}
+		if err := w.Insert(network, record); err != nil {
+			t.Fatalf("Insert(%s): %v", cidr, err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), dbType+".mmdb")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("DB 파일 생성 실패: %v", err)
+	}
+	defer f.Close()
+	if _, err := w.WriteTo(f); err != nil {
+		t.Fatalf("DB 파일 쓰기 실패: %v", err)
+	}
+	return path
+}
+
+func country(code, name string) mmdbtype.Map {
+	return mmdbtype.Map{"iso_code": mmdbtype.String(code), "names": mmdbtype.Map{"en": mmdbtype.String(name)}}
+}
+
+func TestResolverLookup(t *testing.T) {
+	countryDB := writeDB(t, "GeoLite2-Country", 6, map[string]mmdbtype.Map{
+		"1.1.1.0/24":     {"country": country("AU", "Australia"), "registered_country": country("US", "United States")},
+		"8.8.8.0/24":     {"registered_country": country("US", "United States")},
+		"2001:4860::/32": {"country": country("US", "United States")},
+	})
+	asnDB := writeDB(t, "GeoLite2-ASN", 6, map[string]mmdbtype.Map{
+		"1.1.1.0/24": {"autonomous_system_number": mmdbtype.Uint32(13335), "autonomous_system_organization": mmdbtype.String("CLOUDFLARENET")},
+		"8.8.8.0/24": {"autonomous_system_number": mmdbtype.Uint32(15169), "autonomous_system_organization": mmdbtype.String("GOOGLE")},
+	})
+
+	r, err := NewResolver(countryDB, asnDB)
+	if err != nil {
+		t.Fatalf("NewResolver: %v", err)
+	}
+	defer r.Close()
+
+	tests := []struct {
+		name string
+		ip   string
+		want Info
+	}{
+		{name: "국가와 ASN", ip: "1.1.1.1", want: Info{CountryCode: "AU", CountryName: "Australia", ASN: 13335, ASOrg: "CLOUDFLARENET"}},
+		{name: "등록 국가로 대체", ip: "8.8.8.8", want: Info{CountryCode: "US", CountryName: "United States", ASN: 15169, ASOrg: "GOOGLE"}},
+		{name: "IPv6 국가만", ip: "2001:4860:4860::8888", want: Info{CountryCode: "US", CountryName: "United States"}},
+		{name: "DB에 없는 주소", ip: "9.9.9.9", want: Info{}},
+		{name: "올바르지 않은 주소", ip: "not-an-ip", want: Info{}},
+		{name: "빈 주소", ip: "", want: Info{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Lookup(tt.ip); got != tt.want {
+				t.Errorf("Lookup(%q) = %+v, 기대 %+v", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolverIPv4OnlyDB(t *testing.T) {
+	countryDB := writeDB(t, "GeoLite2-Country", 4, map[string]mmdbtype.Map{
+		"1.1.1.0/24": {"country": country("AU", "Australia")},
+	})
+	r, err := NewResolver(countryDB, "")
+	if err != nil {
+		t.Fatalf("NewResolver: %v", err)
+	}
+	defer r.Close()
+
+	if got := r.Lookup("1.1.1.1"); got.CountryCode != "AU" {
+		t.Errorf("IPv4 조회 = %+v, AU 기대", got)
+	}
+	// IPv4 전용 DB에서 IPv6 주소는 찾지 못한 것으로 처리
+	if got := r.Lookup("2001:4860:4860::8888"); got != (Info{}) {
+		t.Errorf("IPv6 조회 = %+v, 빈 값 기대", got)
+	}
+}
+
+func TestResolverDisabled(t *testing.T) {
+	var nilResolver *Resolver
+	if nilResolver.Enabled() || nilResolver.Lookup("1.1.1.1") != (Info{}) {
+		t.Error("nil Resolver가 조회됨")
+	}
+	nilResolver.Close()
+
+	r, err := NewResolver("", "")
+	if err != nil {
+		t.Fatalf("NewResolver: %v", err)
+	}
+	if r.Enabled() {
+		t.Error("DB 없이 Enabled = true")
+	}
+}
+
+func TestResolverInvalidDB(t *testing.T) {
+	valid := writeDB(t, "GeoLite2-ASN", 6, map[string]mmdbtype.Map{
+		"1.1.1.0/24": {"autonomous_system_number": mmdbtype.Uint32(13335)},
+	})
+	data, err := os.ReadFile(valid)
+	if err != nil {
+		t.Fatalf("DB 파일 읽기 실패: %v", err)
+	}
+
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"빈 파일":     nil,
+		"메타데이터 없음": []byte("this is not a maxmind database"),
+		// 메타데이터는 남기고 검색 트리 앞부분을 잘라 트리 크기가 파일보다 크게 만듦
+		"잘린 파일": data[len(data)/2:],
+	}
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name+".mmdb")
+			if err := os.WriteFile(path, content, 0o600); err != nil {
+				t.Fatalf("파일 쓰기 실패: %v", err)
+			}
+			if _, err := NewResolver("", path); err == nil {
+				t.Error("잘못된 DB가 열림")
+			}
+		})
+	}
+
+	if _, err := NewResolver(filepath.Join(dir, "missing.mmdb"), ""); err == nil {
+		t.Error("없는 파일이 열림")
+	}
+}
@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// ctxKey는 context에 로거를 저장하는 키입니다
+type ctxKey struct{}
+
+// With는 keysAndValues 필드를 모든 로그에 붙이는 로거를 반환합니다
+func (l *CustomLogger) With(keysAndValues ...interface{}) *CustomLogger {
+	if len(keysAndValues) == 0 {
+		return l
+	}
+	return &CustomLogger{base: l.base.With(toFields(keysAndValues)...)}
+}
+
+// WithContext는 ctx의 로거에 keysAndValues 필드를 더해 저장한 context를 반환합니다.
+// 연결 ID, 메시지 ID, 노드 ID처럼 한 흐름에 공통인 값을 한 번만 붙이는 데 사용합니다.
+func WithContext(ctx context.Context, keysAndValues ...interface{}) context.Context {
+	return context.WithValue(ctx, ctxKey{}, FromContext(ctx).With(keysAndValues...))
+}
+
+// FromContext는 ctx에 저장된 로거를 반환합니다. 없으면 전역 로거를 반환합니다.
+func FromContext(ctx context.Context) *CustomLogger {
+	if ctx != nil {
+		if l, ok := ctx.Value(ctxKey{}).(*CustomLogger); ok {
+			return l
+		}
+	}
+	return GetCustomLogger()
+}
+
+// toFields는 키-값 쌍을 필드로 변환합니다 (민감한 값은 가림)
+func toFields(keysAndValues []interface{}) []zap.Field {
+	fields := make([]zap.Field, 0, (len(keysAndValues)+1)/2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key := fmt.Sprint(keysAndValues[i])
+		if i+1 >= len(keysAndValues) {
+			fields = append(fields, zap.String(key, "<누락된 값>"))
+			continue
+		}
+		fields = append(fields, redactedField(key, keysAndValues[i+1]))
+	}
+	return fields
+}
+
+// MaskKey는 obscura key처럼 로그에서 식별만 가능하면 되는 값의 앞 4자리만 남깁니다
+func MaskKey(key string) string {
+	if len(key) <= 4 {
+		return redacted
+	}
+	return key[:4] + redacted
+}
@@ -4,44 +4,29 @@ import (
 	"fmt"
 	"os"
 	"runtime"
-	"time"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
-// CustomLogger는 기존 zap.SugaredLogger를 래핑하는 구조체
+// CustomLogger는 zap.Logger를 래핑하여 호출 패키지별 레벨 필터링과 비밀 값 가리기를 적용하는 구조체
 type CustomLogger struct {
-	sugar *zap.SugaredLogger
-	file  *os.File
+	base *zap.Logger
 }
 
-var customLogger *CustomLogger
+// customLogger는 InitCustomLogger 전에는 아무것도 기록하지 않습니다 (테스트, 초기화 전 호출)
+var customLogger = &CustomLogger{base: zap.NewNop()}
 
 // InitCustomLogger는 커스텀 로거를 초기화합니다
-func InitCustomLogger() error {
-	// 일반 zap 로거 초기화
-	zapLogger, err := InitLogger()
+func InitCustomLogger(opts Options) error {
+	zapLogger, err := InitLogger(opts)
 	if err != nil {
 		return err
 	}
 
-	// 로그 파일 생성/오픈
-	currentTime := time.Now()
-	logFileName := fmt.Sprintf("logs/%s.log", currentTime.Format("2006-01-02"))
-
-	if err := os.MkdirAll("logs", 0755); err != nil {
-		return fmt.Errorf("로그 디렉토리 생성 실패: %v", err)
-	}
-
-	file, err := os.OpenFile(logFileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return fmt.Errorf("로그 파일 생성 실패: %v", err)
-	}
-
-	// 커스텀 로거 생성
+	// 커스텀 로거 생성 (호출 위치는 CustomLogger 메서드를 건너뛴 실제 호출자)
 	customLogger = &CustomLogger{
-		sugar: zapLogger.Sugar(),
-		file:  file,
+		base: zapLogger.WithOptions(zap.AddCallerSkip(3)),
 	}
 
 	return nil
@@ -55,132 +40,122 @@ func GetCustomLogger() *CustomLogger {
 // 로그 레벨 메서드들 - 원래 zap.SugaredLogger와 동일한 인터페이스 제공
 
 func (l *CustomLogger) Info(args ...interface{}) {
-	l.log("INFO", args...)
+	l.log(zapcore.InfoLevel, args...)
 }
 
 func (l *CustomLogger) Infof(format string, args ...interface{}) {
-	l.logf("INFO", format, args...)
+	l.logf(zapcore.InfoLevel, format, args...)
 }
 
 func (l *CustomLogger) Error(args ...interface{}) {
-	l.log("ERROR", args...)
+	l.log(zapcore.ErrorLevel, args...)
 }
 
 func (l *CustomLogger) Errorf(format string, args ...interface{}) {
-	l.logf("ERROR", format, args...)
+	l.logf(zapcore.ErrorLevel, format, args...)
 }
 
 func (l *CustomLogger) Infow(msg string, keysAndValues ...interface{}) {
-	l.logw("INFO", msg, keysAndValues...)
+	l.logw(zapcore.InfoLevel, msg, keysAndValues...)
 }
 
 func (l *CustomLogger) Errorw(msg string, keysAndValues ...interface{}) {
-	l.logw("ERROR", msg, keysAndValues...)
+	l.logw(zapcore.ErrorLevel, msg, keysAndValues...)
 }
 
 func (l *CustomLogger) Warnw(msg string, keysAndValues ...interface{}) {
-	l.logw("WARN", msg, keysAndValues...)
+	l.logw(zapcore.WarnLevel, msg, keysAndValues...)
 }
 
 func (l *CustomLogger) Debugw(msg string, keysAndValues ...interface{}) {
-	l.logw("DEBUG", msg, keysAndValues...)
+	l.logw(zapcore.DebugLevel, msg, keysAndValues...)
 }
 
 func (l *CustomLogger) Fatalw(msg string, keysAndValues ...interface{}) {
-	l.logw("FATAL", msg, keysAndValues...)
-	os.Exit(1)
+	l.logw(zapcore.FatalLevel, msg, keysAndValues...)
 }
 
 func (l *CustomLogger) Warn(args ...interface{}) {
-	l.log("WARN", args...)
+	l.log(zapcore.WarnLevel, args...)
 }
 
 func (l *CustomLogger) Warnf(format string, args ...interface{}) {
-	l.logf("WARN", format, args...)
+	l.logf(zapcore.WarnLevel, format, args...)
 }
 
 func (l *CustomLogger) Debug(args ...interface{}) {
-	l.log("DEBUG", args...)
+	l.log(zapcore.DebugLevel, args...)
 }
 
 func (l *CustomLogger) Debugf(format string, args ...interface{}) {
-	l.logf("DEBUG", format, args...)
+	l.logf(zapcore.DebugLevel, format, args...)
 }
 
 func (l *CustomLogger) Fatal(args ...interface{}) {
-	l.log("FATAL", args...)
-	os.Exit(1)
+	l.log(zapcore.FatalLevel, args...)
 }
 
 func (l *CustomLogger) Fatalf(format string, args ...interface{}) {
-	l.logf("FATAL", format, args...)
-	os.Exit(1)
+	l.logf(zapcore.FatalLevel, format, args...)
 }
 
 // 기타 필요한 메서드 추가...
 
 // 내부 로깅 구현
-func (l *CustomLogger) log(level string, args ...interface{}) {
-	timestamp := time.Now().Format("2006-01-02 15:04:05.000")
-
-	// 호출자 정보 가져오기
-	_, file, line, _ := runtime.Caller(2) // 2단계 상위 호출자 가져오기
-
-	// 메시지 생성
-	msg := fmt.Sprint(args...)
-	logLine := fmt.Sprintf("[%s] %s:%d: %s\n", timestamp, file, line, msg)
+func (l *CustomLogger) log(level zapcore.Level, args ...interface{}) {
+	if l.skip(level) {
+		return
+	}
+	l.write(level, fmt.Sprint(args...))
+}
 
-	// 파일과 콘솔에 출력
-	fmt.Print(logLine)
-	l.file.WriteString(logLine)
+func (l *CustomLogger) logf(level zapcore.Level, format string, args ...interface{}) {
+	if l.skip(level) {
+		return
+	}
+	l.write(level, fmt.Sprintf(format, args...))
 }
 
-func (l *CustomLogger) logf(level string, format string, args ...interface{}) {
-	timestamp := time.Now().Format("2006-01-02 15:04:05.000")
+// 구조화된 로깅을 위한 logw 함수
+func (l *CustomLogger) logw(level zapcore.Level, msg string, keysAndValues ...interface{}) {
+	if l.skip(level) {
+		return
+	}
 
-	// 호출자 정보 가져오기
-	_, file, line, _ := runtime.Caller(2) // 2단계 상위 호출자 가져오기
+	l.write(level, msg, toFields(keysAndValues)...)
+}
 
-	// 메시지 생성
-	msg := fmt.Sprintf(format, args...)
-	logLine := fmt.Sprintf("[%s] %s:%d: %s\n", timestamp, file, line, msg)
+// skip은 호출 패키지의 레벨보다 낮은 로그인지 반환합니다 (Fatal은 항상 기록)
+func (l *CustomLogger) skip(level zapcore.Level) bool {
+	if level >= zapcore.FatalLevel {
+		return false
+	}
+	return skipLevel(level, callerFile)
+}
 
-	// 파일과 콘솔에 출력
-	fmt.Print(logLine)
-	l.file.WriteString(logLine)
+// callerFile은 로그를 남긴 파일을 반환합니다 (callerFile -> skipLevel -> skip -> log/logf/logw -> 공개 메서드 -> 호출자)
+func callerFile() (string, bool) {
+	_, file, _, ok := runtime.Caller(5)
+	return file, ok
 }
 
-// 구조화된 로깅을 위한 logw 함수
-func (l *CustomLogger) logw(level string, msg string, keysAndValues ...interface{}) {
-	timestamp := time.Now().Format("2006-01-02 15:04:05.000")
-
-	// 호출자 정보 가져오기
-	_, file, line, _ := runtime.Caller(2)
-
-	// 키-값 쌍 포맷팅
-	var fields string
-	for i := 0; i < len(keysAndValues); i += 2 {
-		var value interface{}
-		if i+1 < len(keysAndValues) {
-			value = keysAndValues[i+1]
-		} else {
-			value = "<누락된 값>"
-		}
-		fields += fmt.Sprintf(" %v=%v", keysAndValues[i], value)
+func (l *CustomLogger) write(level zapcore.Level, msg string, fields ...zap.Field) {
+	if ce := l.base.Check(level, redactLine(msg)); ce != nil {
+		ce.Write(fields...)
 	}
+	if level == zapcore.FatalLevel {
+		l.base.Sync()
+		os.Exit(1)
+	}
+}
 
-	// 메시지 생성
-	logLine := fmt.Sprintf("[%s] [%s] %s:%d: %s%s\n", timestamp, level, file, line, msg, fields)
-
-	// 파일과 콘솔에 출력
-	fmt.Print(logLine)
-	l.file.WriteString(logLine)
+// Sync는 버퍼에 남은 로그를 기록합니다
+func (l *CustomLogger) Sync() error {
+	return l.base.Sync()
 }
 
-// Close는 로거 리소스를 닫습니다
+// Close는 로거 리소스를 정리합니다
 func (l *CustomLogger) Close() error {
-	if l.file != nil {
-		return l.file.Close()
-	}
+	l.base.Sync()
 	return nil
 }
@@ -0,0 +1,158 @@
+package logger
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// levels는 전역 로그 레벨과 패키지별 레벨입니다. 바뀔 때마다 새 객체로 교체합니다.
+type levels struct {
+	global   zapcore.Level
+	packages map[string]zapcore.Level // 패키지 경로 (예: internal/websocket 또는 websocket) -> 레벨
+	// lowest는 전역 레벨과 패키지별 레벨 중 가장 낮은 레벨입니다. 이보다 낮은 로그는 호출 위치를 찾지 않고 버립니다.
+	lowest zapcore.Level
+
+	// cache는 호출 파일의 디렉토리별로 결정된 레벨입니다
+	cache sync.Map
+}
+
+var (
+	levelsMu sync.Mutex
+	current  atomic.Pointer[levels]
+)
+
+func init() {
+	current.Store(&levels{global: zapcore.DebugLevel, packages: map[string]zapcore.Level{}, lowest: zapcore.DebugLevel})
+}
+
+// ParseLevel은 레벨 이름(debug, info, warn, error)을 zap 레벨로 바꿉니다
+func ParseLevel(level string) (zapcore.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return zapcore.DebugLevel, nil
+	case "info", "":
+		return zapcore.InfoLevel, nil
+	case "warn", "warning":
+		return zapcore.WarnLevel, nil
+	case "error":
+		return zapcore.ErrorLevel, nil
+	}
+	return zapcore.InfoLevel, fmt.Errorf("알 수 없는 로그 레벨: %s", level)
+}
+
+// SetLevel은 출력할 최소 로그 레벨(debug, info, warn, error)을 설정합니다. 실행 중에 바꿀 수 있습니다.
+func SetLevel(level string) error {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	update(func(next *levels) { next.global = lvl })
+	return nil
+}
+
+// SetPackageLevel은 패키지의 로그 레벨을 설정합니다. level이 빈 문자열이면 전역 레벨을 따르도록 되돌립니다.
+// pkg는 모듈 기준 경로(internal/websocket) 또는 마지막 이름(websocket)입니다.
+func SetPackageLevel(pkg, level string) error {
+	pkg = strings.Trim(pkg, "/")
+	if pkg == "" {
+		return fmt.Errorf("패키지 이름이 비어 있습니다")
+	}
+	if level == "" {
+		update(func(next *levels) { delete(next.packages, pkg) })
+		return nil
+	}
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	update(func(next *levels) { next.packages[pkg] = lvl })
+	return nil
+}
+
+// SetPackageLevels는 패키지별 레벨을 모두 교체합니다
+func SetPackageLevels(packages map[string]string) error {
+	parsed := make(map[string]zapcore.Level, len(packages))
+	for pkg, level := range packages {
+		lvl, err := ParseLevel(level)
+		if err != nil {
+			return fmt.Errorf("%s: %v", pkg, err)
+		}
+		parsed[strings.Trim(pkg, "/")] = lvl
+	}
+	update(func(next *levels) { next.packages = parsed })
+	return nil
+}
+
+// Levels는 현재 전역 레벨과 패키지별 레벨을 반환합니다
+func Levels() (string, map[string]string) {
+	cur := current.Load()
+	packages := make(map[string]string, len(cur.packages))
+	for pkg, lvl := range cur.packages {
+		packages[pkg] = lvl.String()
+	}
+	return cur.global.String(), packages
+}
+
+func update(fn func(next *levels)) {
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+
+	cur := current.Load()
+	next := &levels{global: cur.global, packages: make(map[string]zapcore.Level, len(cur.packages))}
+	for pkg, lvl := range cur.packages {
+		next.packages[pkg] = lvl
+	}
+	fn(next)
+	next.lowest = next.global
+	for _, lvl := range next.packages {
+		next.lowest = min(next.lowest, lvl)
+	}
+	current.Store(next)
+}
+
+// skipLevel은 level 로그를 버려야 하는지 반환합니다. 패키지별 레벨이 설정되어 있고
+// 레벨만으로 판단할 수 없을 때만 caller로 호출 파일을 찾아 패키지 레벨을 확인합니다.
+func skipLevel(level zapcore.Level, caller func() (string, bool)) bool {
+	cur := current.Load()
+	if level < cur.lowest {
+		return true
+	}
+	if len(cur.packages) == 0 {
+		return false
+	}
+	file, _ := caller()
+	return !cur.enabled(level, file)
+}
+
+// enabled는 file에서 호출한 level 로그를 출력해야 하는지 반환합니다
+func (cur *levels) enabled(level zapcore.Level, file string) bool {
+	if len(cur.packages) == 0 {
+		return level >= cur.global
+	}
+
+	dir := filepath.ToSlash(filepath.Dir(file))
+	if cached, ok := cur.cache.Load(dir); ok {
+		return level >= cached.(zapcore.Level)
+	}
+
+	// 가장 길게 일치하는 패키지 경로의 레벨 사용
+	resolved, matched := cur.global, ""
+	keys := make([]string, 0, len(cur.packages))
+	for pkg := range cur.packages {
+		keys = append(keys, pkg)
+	}
+	sort.Strings(keys)
+	for _, pkg := range keys {
+		if (dir == pkg || strings.HasSuffix(dir, "/"+pkg)) && len(pkg) > len(matched) {
+			resolved, matched = cur.packages[pkg], pkg
+		}
+	}
+	cur.cache.Store(dir, resolved)
+	return level >= resolved
+}
@@ -0,0 +1,141 @@
+package logger
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// setLevels는 전역 레벨과 패키지별 레벨을 바꾸고 테스트가 끝나면 기본값으로 되돌립니다
+func setLevels(t *testing.T, global string, packages map[string]string) {
+	t.Helper()
+	if err := SetLevel(global); err != nil {
+		t.Fatalf("SetLevel: %v", err)
+	}
+	if err := SetPackageLevels(packages); err != nil {
+		t.Fatalf("SetPackageLevels: %v", err)
+	}
+	t.Cleanup(func() {
+		SetLevel("debug")
+		SetPackageLevels(nil)
+	})
+}
+
+func TestEnabled(t *testing.T) {
+	setLevels(t, "info", map[string]string{
+		"internal/websocket": "debug",
+		"websocket":          "error",
+		"/repository/":       "warn",
+	})
+
+	tests := []struct {
+		file  string
+		level zapcore.Level
+		want  bool
+	}{
+		// 가장 길게 일치하는 경로의 레벨 사용
+		{file: "/src/system-collector/internal/websocket/server.go", level: zapcore.DebugLevel, want: true},
+		{file: "/src/system-collector/pkg/websocket/client.go", level: zapcore.WarnLevel, want: false},
+		{file: "/src/system-collector/pkg/websocket/client.go", level: zapcore.ErrorLevel, want: true},
+		// 앞뒤 /는 무시
+		{file: "/src/system-collector/internal/repository/node.go", level: zapcore.InfoLevel, want: false},
+		{file: "/src/system-collector/internal/repository/node.go", level: zapcore.WarnLevel, want: true},
+		// 경로 일부만 같은 패키지는 전역 레벨
+		{file: "/src/system-collector/internal/mywebsocket/a.go", level: zapcore.InfoLevel, want: true},
+		{file: "/src/system-collector/cmd/server/main.go", level: zapcore.DebugLevel, want: false},
+		{file: "", level: zapcore.InfoLevel, want: true},
+	}
+	cur := current.Load()
+	for _, tt := range tests {
+		// 두 번째 호출은 캐시된 결과
+		for i := 0; i < 2; i++ {
+			if got := cur.enabled(tt.level, tt.file); got != tt.want {
+				t.Errorf("enabled(%s, %q) = %v, 기대 %v", tt.level, tt.file, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestSkipLevel(t *testing.T) {
+	tests := []struct {
+		name       string
+		global     string
+		packages   map[string]string
+		level      zapcore.Level
+		want       bool
+		wantCaller bool
+	}{
+		{name: "패키지 레벨 없음, 낮은 레벨", global: "warn", level: zapcore.InfoLevel, want: true},
+		{name: "패키지 레벨 없음, 높은 레벨", global: "warn", level: zapcore.ErrorLevel},
+		{name: "모든 레벨보다 낮음", global: "info", packages: map[string]string{"websocket": "warn"}, level: zapcore.DebugLevel, want: true},
+		{name: "패키지 레벨이 더 낮음", global: "info", packages: map[string]string{"websocket": "debug"}, level: zapcore.DebugLevel, want: true, wantCaller: true},
+		{name: "호출 패키지의 레벨", global: "info", packages: map[string]string{"logger": "debug"}, level: zapcore.DebugLevel, wantCaller: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setLevels(t, tt.global, tt.packages)
+			called := false
+			caller := func() (string, bool) {
+				called = true
+				return "/src/system-collector/pkg/logger/level_test.go", true
+			}
+			if got := skipLevel(tt.level, caller); got != tt.want {
+				t.Errorf("skipLevel(%s) = %v, 기대 %v", tt.level, got, tt.want)
+			}
+			if called != tt.wantCaller {
+				t.Errorf("호출 위치 조회 = %v, 기대 %v", called, tt.wantCaller)
+			}
+		})
+	}
+}
+
+func TestCustomLoggerPackageLevel(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	l := &CustomLogger{base: zap.New(core)}
+
+	// 호출한 파일(이 테스트)의 패키지 레벨이 적용되어야 함
+	setLevels(t, "error", map[string]string{"pkg/logger": "debug"})
+	l.Debugw("패키지 레벨로 기록")
+	l.Infof("포맷 %d", 1)
+	SetPackageLevels(map[string]string{"pkg/logger": "error", "websocket": "debug"})
+	l.Warn("패키지 레벨로 버림")
+	l.Errorw("기록")
+
+	var got []string
+	for _, e := range logs.All() {
+		got = append(got, e.Message)
+	}
+	want := []string{"패키지 레벨로 기록", "포맷 1", "기록"}
+	if len(got) != len(want) {
+		t.Fatalf("기록된 로그 %q, 기대 %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("%d번째 로그 %q, 기대 %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSampling(t *testing.T) {
+	dir := t.TempDir()
+	opts := Options{Level: "debug", Encoding: "json", Dir: dir, SampleInitial: 2, SampleThereafter: 3}
+	l, err := InitLogger(opts)
+	if err != nil {
+		t.Fatalf("InitLogger: %v", err)
+	}
+	t.Cleanup(func() { SetLevel("debug") })
+
+	// 같은 메시지는 처음 2번, 이후 3번마다 한 번 기록 (1, 2, 5, 8번째)
+	for i := 0; i < 10; i++ {
+		l.Info("반복 메시지")
+	}
+	l.Info("다른 메시지")
+	l.Sync()
+
+	counts := countMessages(t, dir)
+	if counts["반복 메시지"] != 4 || counts["다른 메시지"] != 1 {
+		t.Errorf("기록된 메시지 수 %v, 반복 메시지 4개와 다른 메시지 1개 기대", counts)
+	}
+}
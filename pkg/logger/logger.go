@@ -16,35 +16,88 @@ var (
 	globalSugar *zap.SugaredLogger
 )
 
-// InitLogger는 로그 설정을 초기화하고 로거를 생성합니다
-func InitLogger() (*zap.Logger, error) {
-	// 현재 날짜로 로그 파일명 생성
-	currentTime := time.Now()
-	logFileName := fmt.Sprintf("logs/%s.log", currentTime.Format("2006-01-02"))
-
-	// logs 디렉토리가 없으면 생성
-	if err := os.MkdirAll("logs", 0755); err != nil {
-		return nil, fmt.Errorf("로그 디렉토리 생성 실패: %v", err)
+// Options는 로거 설정입니다
+type Options struct {
+	// Level은 전역 최소 레벨, Packages는 패키지별 레벨입니다 (실행 중 변경 가능)
+	Level    string
+	Packages map[string]string
+	// Encoding은 console 또는 json입니다
+	Encoding string
+	// Dir이 비어 있으면 파일에 기록하지 않고 표준 출력에만 기록합니다
+	Dir string
+	// MaxSizeMB를 넘거나 RotateDaily이고 날짜가 바뀌면 파일을 교체합니다.
+	// 교체된 파일은 MaxBackups개, MaxAgeDays일까지 보관합니다 (0이면 무제한).
+	MaxSizeMB   int
+	MaxBackups  int
+	MaxAgeDays  int
+	RotateDaily bool
+	// SampleInitial이 0보다 크면 같은 메시지가 1초에 SampleInitial번을 넘은 뒤에는
+	// SampleThereafter번마다 한 번만 기록합니다
+	SampleInitial    int
+	SampleThereafter int
+}
+
+// DefaultOptions는 설정 없이 사용할 기본 로거 설정을 반환합니다
+func DefaultOptions() Options {
+	return Options{
+		Level:            "info",
+		Encoding:         "console",
+		Dir:              "logs",
+		MaxSizeMB:        100,
+		MaxBackups:       14,
+		MaxAgeDays:       30,
+		RotateDaily:      true,
+		SampleInitial:    100,
+		SampleThereafter: 100,
 	}
+}
 
-	// 로그 설정
-	cfg := zap.NewProductionConfig()
-	cfg.OutputPaths = []string{
-		logFileName, // 파일 출력
-		"stdout",    // 콘솔 출력
+// InitLogger는 로그 설정을 초기화하고 로거를 생성합니다.
+// 레벨 필터링은 CustomLogger가 호출 패키지 기준으로 수행하므로 코어는 모든 레벨을 받습니다.
+func InitLogger(opts Options) (*zap.Logger, error) {
+	if err := SetLevel(opts.Level); err != nil {
+		return nil, err
+	}
+	if err := SetPackageLevels(opts.Packages); err != nil {
+		return nil, err
 	}
 
+	encCfg := zap.NewProductionEncoderConfig()
 	// 사람이 읽기 쉬운 시간 형식으로 변경
-	cfg.EncoderConfig.TimeKey = "time"
-	cfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder // ISO8601 형식 사용 (2006-01-02T15:04:05.000Z)
-	// 또는 아래처럼 커스텀 형식을 사용할 수도 있습니다
-	// cfg.EncoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout("2006-01-02 15:04:05.000")
-
-	logger, err := cfg.Build()
-	if err != nil {
-		return nil, fmt.Errorf("로그 초기화 실패: %v", err)
+	encCfg.TimeKey = "time"
+	encCfg.EncodeTime = zapcore.ISO8601TimeEncoder
+	var encoder zapcore.Encoder
+	switch opts.Encoding {
+	case "json":
+		encoder = zapcore.NewJSONEncoder(encCfg)
+	case "console", "":
+		encCfg.EncodeLevel = zapcore.CapitalLevelEncoder
+		encoder = zapcore.NewConsoleEncoder(encCfg)
+	default:
+		return nil, fmt.Errorf("알 수 없는 로그 인코딩: %s", opts.Encoding)
 	}
 
+	// 모든 출력은 등록된 비밀 값을 가린 뒤 기록
+	sinks := []zapcore.WriteSyncer{redactWriter{zapcore.Lock(os.Stdout)}}
+	if opts.Dir != "" {
+		file, err := newRotatingWriter(opts.Dir, "collector", opts.MaxSizeMB, opts.MaxAgeDays, opts.MaxBackups, opts.RotateDaily)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, redactWriter{file})
+	}
+
+	var core zapcore.Core = zapcore.NewCore(encoder, zapcore.NewMultiWriteSyncer(sinks...), zapcore.DebugLevel)
+	if opts.SampleInitial > 0 {
+		thereafter := opts.SampleThereafter
+		if thereafter <= 0 {
+			thereafter = 1
+		}
+		core = zapcore.NewSamplerWithOptions(core, time.Second, opts.SampleInitial, thereafter)
+	}
+
+	logger := zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.FatalLevel))
+
 	// 글로벌 로거 설정
 	globalLogger = logger
 	globalSugar = logger.Sugar()
@@ -0,0 +1,174 @@
+package logger
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// redacted는 로그에서 비밀 값 대신 출력하는 값입니다
+const redacted = "******"
+
+// sensitiveName은 값을 가려야 하는 로그 키와 구조체 필드 이름입니다
+var sensitiveName = regexp.MustCompile(`(?i)(password|passwd|secret|token|obscura_?key|api_?key|dsn)`)
+
+var (
+	secretsMu  sync.Mutex
+	secrets    []string
+	registered = make(map[string]bool)
+	// replacer는 등록된 비밀 값을 가리는 Replacer입니다 (등록된 값이 없으면 nil)
+	replacer atomic.Pointer[strings.Replacer]
+)
+
+// RegisterSecret은 로그 출력에서 항상 가릴 값을 등록합니다 (설정의 비밀번호, 토큰 등).
+// 설정을 다시 로드할 때마다 호출해도 이미 등록된 값은 다시 추가하지 않습니다.
+func RegisterSecret(values ...string) {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+
+	for _, value := range values {
+		// 너무 짧은 값은 일반 텍스트까지 가리므로 제외
+		if len(value) < 4 || registered[value] {
+			continue
+		}
+		registered[value] = true
+		secrets = append(secrets, value, redacted)
+	}
+	if len(secrets) > 0 {
+		replacer.Store(strings.NewReplacer(secrets...))
+	}
+}
+
+// redactLine은 출력할 줄에서 등록된 비밀 값을 가립니다
+func redactLine(line string) string {
+	if r := replacer.Load(); r != nil {
+		return r.Replace(line)
+	}
+	return line
+}
+
+// redactedField는 구조화 로그의 키-값 하나를 zap 필드로 만듭니다.
+// 키 이름이 민감하면 값을 가리고, 구조체·맵·슬라이스는 민감한 필드를 가린 문자열로 기록합니다.
+func redactedField(key string, value interface{}) zap.Field {
+	if sensitiveName.MatchString(key) {
+		return zap.String(key, redacted)
+	}
+	switch v := value.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64,
+		float32, float64, time.Time, time.Duration:
+		return zap.Any(key, v)
+	case error:
+		return zap.String(key, v.Error())
+	case fmt.Stringer:
+		return zap.Stringer(key, v)
+	}
+	return zap.String(key, formatValue(reflect.ValueOf(value), 0))
+}
+
+// redactWriter는 기록하기 전에 등록된 비밀 값을 가리는 WriteSyncer입니다
+type redactWriter struct {
+	zapcore.WriteSyncer
+}
+
+func (w redactWriter) Write(p []byte) (int, error) {
+	if r := replacer.Load(); r != nil {
+		if _, err := w.WriteSyncer.Write([]byte(r.Replace(string(p)))); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	return w.WriteSyncer.Write(p)
+}
+
+// maxDepth는 중첩 구조체를 펼치는 최대 깊이입니다
+const maxDepth = 4
+
+func formatValue(v reflect.Value, depth int) string {
+	if !v.IsValid() {
+		return "<nil>"
+	}
+	// error, Stringer 등은 그대로 출력
+	if v.CanInterface() {
+		switch v.Interface().(type) {
+		case error, fmt.Stringer:
+			return fmt.Sprint(v.Interface())
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return "<nil>"
+		}
+		if v.Kind() == reflect.Pointer && v.Elem().Kind() == reflect.Struct {
+			return "&" + formatValue(v.Elem(), depth)
+		}
+		return formatValue(v.Elem(), depth)
+	case reflect.Struct:
+		if depth >= maxDepth {
+			return "{...}"
+		}
+		t := v.Type()
+		var b strings.Builder
+		b.WriteByte('{')
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+			if b.Len() > 1 {
+				b.WriteByte(' ')
+			}
+			b.WriteString(sf.Name)
+			b.WriteByte(':')
+			if sf.Tag.Get("secret") == "true" || sensitiveName.MatchString(sf.Name) {
+				b.WriteString(redacted)
+				continue
+			}
+			b.WriteString(formatValue(v.Field(i), depth+1))
+		}
+		b.WriteByte('}')
+		return b.String()
+	case reflect.Map:
+		if v.IsNil() {
+			return "map[]"
+		}
+		keys := v.MapKeys()
+		parts := make([]string, 0, len(keys))
+		for _, k := range keys {
+			name := fmt.Sprint(k.Interface())
+			value := redacted
+			if !sensitiveName.MatchString(name) {
+				value = formatValue(v.MapIndex(k), depth+1)
+			}
+			parts = append(parts, name+":"+value)
+		}
+		sort.Strings(parts)
+		return "map[" + strings.Join(parts, " ") + "]"
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return "[]"
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return fmt.Sprint(v.Interface())
+		}
+		parts := make([]string, v.Len())
+		for i := range parts {
+			parts[i] = formatValue(v.Index(i), depth+1)
+		}
+		return "[" + strings.Join(parts, " ") + "]"
+	}
+
+	if v.CanInterface() {
+		return fmt.Sprint(v.Interface())
+	}
+	return fmt.Sprint(v)
+}
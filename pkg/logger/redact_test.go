@@ -0,0 +1,31 @@
+package logger
+
+import "testing"
+
+func TestRegisterSecret(t *testing.T) {
+	RegisterSecret("old-token", "abc")
+	// 설정을 다시 로드하면 같은 값과 바뀐 값이 함께 등록됨
+	RegisterSecret("old-token", "new-token")
+	RegisterSecret("old-token", "new-token")
+
+	tests := []struct {
+		line string
+		want string
+	}{
+		{line: "token=old-token", want: "token=" + redacted},
+		{line: "token=new-token", want: "token=" + redacted},
+		{line: "짧은 값 abc는 가리지 않음", want: "짧은 값 abc는 가리지 않음"},
+		{line: "관계없는 줄", want: "관계없는 줄"},
+	}
+	for _, tt := range tests {
+		if got := redactLine(tt.line); got != tt.want {
+			t.Errorf("redactLine(%q) = %q, 기대 %q", tt.line, got, tt.want)
+		}
+	}
+
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	if len(secrets) != 4 {
+		t.Errorf("등록된 항목 %v, 중복 없이 값 2개 기대", secrets)
+	}
+}
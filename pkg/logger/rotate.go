@@ -0,0 +1,176 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rotatedTimeFormat은 교체된 로그 파일 이름에 붙는 시간 형식입니다
+const rotatedTimeFormat = "2006-01-02T15-04-05.000"
+
+// rotatingWriter는 크기 또는 날짜가 바뀌면 로그 파일을 교체하고 오래된 파일을 정리하는 io.Writer입니다.
+// 현재 파일은 <dir>/<name>.log이고, 교체된 파일은 <dir>/<name>-<시간>.log입니다.
+type rotatingWriter struct {
+	dir        string
+	name       string
+	maxSize    int64         // 바이트 (0이면 크기로 교체하지 않음)
+	maxAge     time.Duration // 교체된 파일 보관 기간 (0이면 무제한)
+	maxBackups int           // 교체된 파일 최대 개수 (0이면 무제한)
+	daily      bool          // 날짜가 바뀌면 교체
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+func newRotatingWriter(dir, name string, maxSizeMB, maxAgeDays, maxBackups int, daily bool) (*rotatingWriter, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("로그 디렉토리 생성 실패: %v", err)
+	}
+	w := &rotatingWriter{
+		dir:        dir,
+		name:       name,
+		maxSize:    int64(maxSizeMB) << 20,
+		maxAge:     time.Duration(maxAgeDays) * 24 * time.Hour,
+		maxBackups: maxBackups,
+		daily:      daily,
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *rotatingWriter) path() string {
+	return filepath.Join(w.dir, w.name+".log")
+}
+
+func (w *rotatingWriter) open() error {
+	file, err := os.OpenFile(w.path(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("로그 파일 생성 실패: %v", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("로그 파일 확인 실패: %v", err)
+	}
+	w.file = file
+	w.size = info.Size()
+	// 이전 실행에서 이어 쓰는 파일은 마지막 수정 시간을 기준으로 날짜 교체를 판단
+	w.openedAt = time.Now()
+	if w.size > 0 {
+		w.openedAt = info.ModTime()
+	}
+	return nil
+}
+
+// Write는 p를 현재 파일에 쓰고, 필요하면 먼저 파일을 교체합니다
+func (w *rotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	if w.needsRotate(now, int64(len(p))) {
+		if err := w.rotate(now); err != nil {
+			// 교체에 실패해도 기존 파일에 계속 기록
+			fmt.Fprintf(os.Stderr, "로그 파일 교체 실패: %v\n", err)
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *rotatingWriter) needsRotate(now time.Time, incoming int64) bool {
+	if w.size == 0 {
+		return false
+	}
+	if w.maxSize > 0 && w.size+incoming > w.maxSize {
+		return true
+	}
+	if w.daily {
+		y1, m1, d1 := w.openedAt.Date()
+		y2, m2, d2 := now.Date()
+		return y1 != y2 || m1 != m2 || d1 != d2
+	}
+	return false
+}
+
+func (w *rotatingWriter) rotate(now time.Time) error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	rotated := filepath.Join(w.dir, fmt.Sprintf("%s-%s.log", w.name, now.Format(rotatedTimeFormat)))
+	if err := os.Rename(w.path(), rotated); err != nil {
+		// 이름을 바꾸지 못하면 같은 파일을 다시 열어 계속 기록
+		if openErr := w.open(); openErr != nil {
+			return openErr
+		}
+		return err
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+	go w.cleanup(now)
+	return nil
+}
+
+// cleanup은 보관 개수와 기간을 넘은 교체된 파일을 삭제합니다
+func (w *rotatingWriter) cleanup(now time.Time) {
+	if w.maxAge <= 0 && w.maxBackups <= 0 {
+		return
+	}
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return
+	}
+
+	// 교체 시간 형식의 이름을 가진 파일만 대상으로 함 (같은 접두사의 다른 파일은 건드리지 않음)
+	prefix := w.name + "-"
+	rotatedAt := make(map[string]time.Time)
+	var rotated []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ".log") {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".log")
+		if t, err := time.ParseInLocation(rotatedTimeFormat, stamp, time.Local); err == nil {
+			rotated = append(rotated, name)
+			rotatedAt[name] = t
+		}
+	}
+	// 이름에 시간이 들어 있으므로 이름순이 시간순
+	sort.Sort(sort.Reverse(sort.StringSlice(rotated)))
+
+	for i, name := range rotated {
+		remove := w.maxBackups > 0 && i >= w.maxBackups
+		if w.maxAge > 0 && now.Sub(rotatedAt[name]) > w.maxAge {
+			remove = true
+		}
+		if remove {
+			os.Remove(filepath.Join(w.dir, name))
+		}
+	}
+}
+
+// Sync는 현재 파일을 디스크에 기록합니다
+func (w *rotatingWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Sync()
+}
+
+// Close는 현재 파일을 닫습니다
+func (w *rotatingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Close()
+}
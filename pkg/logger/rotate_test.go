@@ -0,0 +1,157 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNeedsRotate(t *testing.T) {
+	openedAt := time.Date(2026, 1, 2, 23, 0, 0, 0, time.Local)
+	tests := []struct {
+		name     string
+		w        *rotatingWriter
+		now      time.Time
+		incoming int64
+		want     bool
+	}{
+		{name: "빈 파일은 교체하지 않음", w: &rotatingWriter{maxSize: 10, daily: true, openedAt: openedAt}, now: openedAt.Add(48 * time.Hour), incoming: 100},
+		{name: "크기 이내", w: &rotatingWriter{maxSize: 100, size: 50, openedAt: openedAt}, now: openedAt, incoming: 50},
+		{name: "크기 초과", w: &rotatingWriter{maxSize: 100, size: 50, openedAt: openedAt}, now: openedAt, incoming: 51, want: true},
+		{name: "크기 제한 없음", w: &rotatingWriter{size: 1 << 40, openedAt: openedAt}, now: openedAt, incoming: 1},
+		{name: "같은 날", w: &rotatingWriter{size: 1, daily: true, openedAt: openedAt}, now: openedAt.Add(59 * time.Minute)},
+		{name: "날짜 바뀜", w: &rotatingWriter{size: 1, daily: true, openedAt: openedAt}, now: openedAt.Add(time.Hour), want: true},
+		{name: "날짜 교체 꺼짐", w: &rotatingWriter{size: 1, openedAt: openedAt}, now: openedAt.Add(time.Hour)},
+	}
+	for _, tt := range tests {
+		if got := tt.w.needsRotate(tt.now, tt.incoming); got != tt.want {
+			t.Errorf("%s: needsRotate = %v, 기대 %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCleanup(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.Local)
+	rotated := func(daysAgo int) string {
+		return "collector-" + now.AddDate(0, 0, -daysAgo).Format(rotatedTimeFormat) + ".log"
+	}
+	files := []string{rotated(1), rotated(2), rotated(5), rotated(40), "collector.log", "other-2026.log", "collector-broken.log"}
+
+	tests := []struct {
+		name       string
+		maxAge     time.Duration
+		maxBackups int
+		want       []string
+	}{
+		{name: "제한 없음", want: files},
+		{
+			name:       "최대 개수",
+			maxBackups: 2,
+			// 교체 시간 형식이 아닌 파일은 개수에 포함하지 않고 지우지도 않음
+			want: []string{rotated(1), rotated(2), "collector.log", "other-2026.log", "collector-broken.log"},
+		},
+		{
+			name:   "보관 기간",
+			maxAge: 3 * 24 * time.Hour,
+			want:   []string{rotated(1), rotated(2), "collector.log", "other-2026.log", "collector-broken.log"},
+		},
+		{
+			name:       "개수와 기간",
+			maxAge:     30 * 24 * time.Hour,
+			maxBackups: 4,
+			want:       []string{rotated(1), rotated(2), rotated(5), "collector.log", "other-2026.log", "collector-broken.log"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			w := &rotatingWriter{dir: dir, name: "collector", maxAge: tt.maxAge, maxBackups: tt.maxBackups}
+			w.cleanup(now)
+
+			if got, want := listDir(t, dir), sorted(tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("남은 파일 %q, 기대 %q", got, want)
+			}
+		})
+	}
+}
+
+func TestRotatingWriter(t *testing.T) {
+	dir := t.TempDir()
+	w, err := newRotatingWriter(dir, "collector", 1, 0, 0, false)
+	if err != nil {
+		t.Fatalf("newRotatingWriter: %v", err)
+	}
+	defer w.Close()
+	w.maxSize = 10
+
+	for _, line := range []string{"12345\n", "67890\n", "abc\n"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	// 두 번째 쓰기에서 10바이트를 넘으므로 첫 줄만 교체된 파일로 옮겨짐
+	files := listDir(t, dir)
+	if len(files) != 2 || files[1] != "collector.log" || !strings.HasPrefix(files[0], "collector-") {
+		t.Fatalf("파일 목록 %q, 교체된 파일 하나와 현재 파일 기대", files)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, files[0])); string(data) != "12345\n" {
+		t.Errorf("교체된 파일 내용 %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "collector.log")); string(data) != "67890\nabc\n" {
+		t.Errorf("현재 파일 내용 %q", data)
+	}
+}
+
+func listDir(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return sorted(names)
+}
+
+func sorted(names []string) []string {
+	out := append([]string(nil), names...)
+	sort.Strings(out)
+	return out
+}
+
+// countMessages는 dir/collector.log의 JSON 로그를 메시지별로 셉니다
+func countMessages(t *testing.T, dir string) map[string]int {
+	t.Helper()
+	f, err := os.Open(filepath.Join(dir, "collector.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	counts := make(map[string]int)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry struct {
+			Msg string `json:"msg"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("JSON 로그 파싱 실패: %v (%s)", err, scanner.Text())
+		}
+		counts[entry.Msg]++
+	}
+	return counts
+}
@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+// 알림 상태
+const (
+	// AlertPending은 조건을 만족했지만 아직 for 기간이 지나지 않은 상태입니다
+	AlertPending = "pending"
+	// AlertFiring은 조건이 for 기간 이상 계속되어 알림이 발생한 상태입니다
+	AlertFiring = "firing"
+	// AlertResolved는 발생한 알림의 조건이 더 이상 만족되지 않는 상태입니다
+	AlertResolved = "resolved"
+)
+
+// 알림 규칙 출처
+const (
+	RuleSourceConfig = "config"
+	RuleSourceDB     = "db"
+)
+
+// AlertRule은 수신한 메트릭스에 적용하는 임계값 규칙입니다.
+// Expr 예: "cpu.usage > 90 for 5m", "disk.usage_percent > 85 on mount /", "container.restarts increased"
+type AlertRule struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Expr     string `json:"expr"`
+	For      string `json:"for,omitempty"`
+	Severity string `json:"severity"`
+	// Match는 시리즈 레이블 조건입니다 (예: mount: "/", container: "nginx")
+	Match map[string]string `json:"match,omitempty"`
+	// UserKey, NodeID, ServerType은 규칙을 적용할 대상입니다. 비어 있으면 모든 노드에 적용합니다.
+	UserKey    string `json:"user_key,omitempty" secret:"true"`
+	NodeID     string `json:"node_id,omitempty"`
+	ServerType string `json:"server_type,omitempty"`
+	Enabled    bool   `json:"enabled"`
+	// Source는 규칙을 정의한 곳입니다 (config 또는 db)
+	Source string `json:"source"`
+}
+
+// Alert는 규칙과 노드, 시리즈 레이블 조합 하나의 알림입니다
+type Alert struct {
+	ID int64 `json:"id,omitempty"`
+	// Fingerprint는 규칙/노드/레이블로 만든 알림 식별자로, 같은 알림의 중복 발생을 막는 데 사용합니다
+	Fingerprint string            `json:"fingerprint"`
+	RuleKey     string            `json:"rule_key"`
+	RuleName    string            `json:"rule_name"`
+	NodeID      string            `json:"node_id"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Severity    string            `json:"severity"`
+	State       string            `json:"state"`
+	Value       float64           `json:"value"`
+	Message     string            `json:"message"`
+	// Silenced는 사일런스에 해당하여 알림을 보내지 않았는지 나타냅니다
+	Silenced   bool       `json:"silenced"`
+	StartedAt  time.Time  `json:"started_at"`
+	FiredAt    *time.Time `json:"fired_at,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+// Silence는 일정 기간 동안 조건에 맞는 알림을 보내지 않도록 하는 설정입니다.
+// Matchers의 키는 rule(규칙 이름), node_id 또는 시리즈 레이블 이름이며 모든 조건이 맞아야 합니다.
+type Silence struct {
+	ID        int64             `json:"id"`
+	Matchers  map[string]string `json:"matchers"`
+	Comment   string            `json:"comment"`
+	CreatedBy string            `json:"created_by"`
+	StartsAt  time.Time         `json:"starts_at"`
+	EndsAt    time.Time         `json:"ends_at"`
+}
+
+// Active는 t 시점에 사일런스가 유효한지 반환합니다
+func (s Silence) Active(t time.Time) bool {
+	return !t.Before(s.StartsAt) && t.Before(s.EndsAt)
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// BaselineStats는 지수 가중 이동 평균으로 학습한 평균과 분산입니다
+type BaselineStats struct {
+	Mean     float64 `json:"mean"`
+	Variance float64 `json:"variance"`
+	Count    int64   `json:"count"`
+}
+
+// MetricBaseline은 노드의 지표 시리즈 하나의 기준값입니다.
+// Series는 지표 이름과 레이블입니다 (예: cpu.usage, network.rx_bytes_per_sec{interface=eth0}).
+type MetricBaseline struct {
+	NodeID string `json:"node_id"`
+	Series string `json:"series"`
+	BaselineStats
+	// Hourly는 시간대(0~23시)별 기준값입니다. 하루 주기 패턴이 있는 지표의 이상 판단에 사용합니다.
+	Hourly    []BaselineStats `json:"hourly,omitempty"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// NodeLease는 노드 연결을 소유한 수집기 인스턴스 정보입니다
+type NodeLease struct {
+	NodeID     string    `json:"node_id"`
+	InstanceID string    `json:"instance_id"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// ClusterInstance는 클러스터에 참여한 수집기 인스턴스입니다
+type ClusterInstance struct {
+	InstanceID  string    `json:"instance_id"`
+	StartedAt   time.Time `json:"started_at"`
+	HeartbeatAt time.Time `json:"heartbeat_at"`
+	LeaseCount  int       `json:"lease_count"`
+}
@@ -1,9 +1,9 @@
 package models
 
 type Command struct {
-	CommandID     int    `db:"command_id"`
-	NodeID        string `db:"node_id"`
-	CommandType   string `db:"command_type"`
-	CommandStatus int16  `db:"command_status"`
-	Target        string `db:"target"`
+	CommandID     int    `db:"command_id" json:"command_id"`
+	NodeID        string `db:"node_id" json:"node_id"`
+	CommandType   string `db:"command_type" json:"command_type"`
+	CommandStatus int16  `db:"command_status" json:"command_status"`
+	Target        string `db:"target" json:"target"`
 }
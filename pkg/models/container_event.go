@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// ContainerEvent는 연속된 컨테이너 목록을 비교해 감지한 컨테이너 생명주기 변화입니다.
+// Type은 container_created, container_started 등 이벤트 유형(EventContainer*)입니다.
+type ContainerEvent struct {
+	ID            int64  `json:"id"`
+	NodeID        string `json:"node_id"`
+	ContainerName string `json:"container_name"`
+	ContainerID   string `json:"container_id"`
+	Image         string `json:"image"`
+	Type          string `json:"type"`
+	Severity      string `json:"severity"`
+	// OldValue, NewValue는 바뀐 값입니다 (상태, 이미지, 재시작 횟수, 헬스 상태)
+	OldValue string `json:"old_value,omitempty"`
+	NewValue string `json:"new_value,omitempty"`
+	// RestartDelta는 container_restarted 이벤트의 재시작 횟수 증가량입니다
+	RestartDelta int       `json:"restart_delta,omitempty"`
+	Message      string    `json:"message"`
+	CreatedAt    time.Time `json:"created_at"`
+}
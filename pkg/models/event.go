@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// 이벤트 심각도
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// 이벤트 유형
+const (
+	// EventExternalIPChanged는 노드의 외부 IP가 바뀌었을 때 발생합니다
+	EventExternalIPChanged = "external_ip_changed"
+	// EventExternalIPRelocated는 외부 IP가 다른 국가나 ASN으로 바뀌었을 때 발생합니다
+	EventExternalIPRelocated = "external_ip_relocated"
+	// EventNodeOnline, EventNodeStale, EventNodeOffline은 노드 생존 상태가 바뀌었을 때 발생합니다
+	EventNodeOnline  = "node_online"
+	EventNodeStale   = "node_stale"
+	EventNodeOffline = "node_offline"
+	// EventAlertFiring, EventAlertResolved는 알림 규칙의 알림이 발생하거나 해소되었을 때 발생합니다
+	EventAlertFiring   = "alert_firing"
+	EventAlertResolved = "alert_resolved"
+	// EventMetricAnomaly는 지표 값이 노드의 학습된 기준값에서 크게 벗어났을 때 발생합니다
+	EventMetricAnomaly = "metric_anomaly"
+	// EventDiskFullPredicted는 디스크(용량 또는 아이노드)가 가득 찰 때까지 남은 예상 시간이 기준보다 짧아졌을 때 발생합니다
+	EventDiskFullPredicted = "disk_full_predicted"
+	// EventContainer*는 연속된 컨테이너 목록의 차이로 감지한 컨테이너 생명주기 변화입니다
+	EventContainerCreated      = "container_created"
+	EventContainerStarted      = "container_started"
+	EventContainerStopped      = "container_stopped"
+	EventContainerDisappeared  = "container_disappeared"
+	EventContainerRestarted    = "container_restarted"
+	EventContainerUnhealthy    = "container_unhealthy"
+	EventContainerImageChanged = "container_image_changed"
+	// EventService*는 연속된 서비스 목록의 차이로 감지한 systemd 유닛의 상태 변화입니다
+	EventServiceFailed       = "service_failed"
+	EventServiceStateChanged = "service_state_changed"
+	EventServiceEnabled      = "service_enabled"
+	EventServiceDisabled     = "service_disabled"
+	EventServiceAppeared     = "service_appeared"
+	EventServiceDisappeared  = "service_disappeared"
+	// EventCriticalServiceDown, EventCriticalServiceRecovered는 노드의 필수 서비스가 active가 아니게 되거나 다시 active가 되었을 때 발생합니다
+	EventCriticalServiceDown      = "critical_service_down"
+	EventCriticalServiceRecovered = "critical_service_recovered"
+	// EventSecurity*는 프로세스 목록에서 감지한 보안 이벤트입니다
+	EventSecurityNewProcess     = "security_new_process"
+	EventSecuritySuspiciousPath = "security_suspicious_path"
+	EventSecurityKnownMiner     = "security_known_miner"
+	EventSecurityProcessSpike   = "security_process_spike"
+	EventSecurityThreadSpike    = "security_thread_spike"
+	// EventPortExposed는 컨테이너 포트가 공인 IP 인터페이스에 새로 공개되었을 때 발생합니다
+	EventPortExposed = "port_exposed"
+)
+
+// Event는 노드에서 감지된 상태 변화입니다.
+// 이벤트 버스를 통해 저장되고 구독자(알림 등)에게 전달됩니다.
+type Event struct {
+	ID        int64                  `json:"id"`
+	NodeID    string                 `json:"node_id"`
+	Type      string                 `json:"type"`
+	Severity  string                 `json:"severity"`
+	Message   string                 `json:"message"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// ExposedPort는 컨테이너가 호스트에 공개한 포트 하나와 그 포트가 열린 인터페이스 주소입니다
+type ExposedPort struct {
+	// Interface, Address는 포트가 열린 인터페이스와 주소입니다.
+	// 바인딩 주소가 어느 인터페이스에도 없으면 Interface가 비어 있습니다.
+	Interface string `json:"interface,omitempty"`
+	Address   string `json:"address"`
+	// BindAddress는 도커가 보고한 바인딩 주소입니다 (비어 있거나 0.0.0.0, ::이면 모든 인터페이스)
+	BindAddress   string `json:"bind_address,omitempty"`
+	HostPort      string `json:"host_port"`
+	Protocol      string `json:"protocol"`
+	ContainerPort string `json:"container_port"`
+	ContainerName string `json:"container_name"`
+	ContainerID   string `json:"container_id"`
+	Image         string `json:"image"`
+	// Public은 주소가 공인 IP이거나 노드의 외부 IP와 같은지 나타냅니다
+	Public    bool      `json:"public"`
+	FirstSeen time.Time `json:"first_seen"`
+}
+
+// NodeExposure는 노드에서 마지막으로 수신한 컨테이너 포트 공개 현황입니다
+type NodeExposure struct {
+	NodeID     string `json:"node_id"`
+	Hostname   string `json:"hostname"`
+	ExternalIP string `json:"external_ip,omitempty"`
+	// BehindNAT는 외부 IP가 어느 인터페이스에도 없는지 나타냅니다. 이 경우 공개 포트는 포트 포워딩이 있을 때만 외부에서 접근할 수 있습니다.
+	BehindNAT bool          `json:"behind_nat"`
+	Ports     []ExposedPort `json:"ports"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
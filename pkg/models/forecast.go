@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Trend는 사용량 하나(용량 또는 아이노드)의 증가 추세와 가득 찰 때까지의 예상 시간입니다
+type Trend struct {
+	Used  int64 `json:"used"`
+	Total int64 `json:"total"`
+	// RatePerHour는 최근 기간의 선형 회귀로 구한 시간당 증가량입니다 (감소하면 음수)
+	RatePerHour float64 `json:"rate_per_hour"`
+	// HoursToFull, FullAt은 지금 추세가 계속될 때 가득 찰 때까지의 시간과 예상 시각입니다.
+	// 사용량이 늘지 않으면 비어 있습니다.
+	HoursToFull *float64   `json:"hours_to_full,omitempty"`
+	FullAt      *time.Time `json:"full_at,omitempty"`
+}
+
+// DiskForecast는 노드의 마운트 하나의 디스크 사용량 예측입니다
+type DiskForecast struct {
+	NodeID     string `json:"node_id"`
+	MountPoint string `json:"mount_point"`
+	Device     string `json:"device"`
+	Bytes      Trend  `json:"bytes"`
+	// Inodes는 파일시스템이 아이노드 수를 보고하지 않으면 비어 있습니다
+	Inodes *Trend `json:"inodes,omitempty"`
+	// Samples는 예측에 사용한 샘플 수, Since는 가장 오래된 샘플의 시각입니다
+	Samples   int       `json:"samples"`
+	Since     time.Time `json:"since"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
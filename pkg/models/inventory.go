@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+// NodeInventory는 노드의 하드웨어/소프트웨어 구성 정보입니다.
+// 메트릭스 중 자주 바뀌지 않는 값만 모아 변경 이력 추적에 사용합니다.
+type NodeInventory struct {
+	NodeID    string          `json:"node_id"`
+	OS        InventoryOS     `json:"os"`
+	CPU       InventoryCPU    `json:"cpu"`
+	Memory    InventoryMemory `json:"memory"`
+	Disks     []InventoryDisk `json:"disks"`
+	NICs      []InventoryNIC  `json:"nics"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// InventoryOS는 운영체제 구성 정보입니다
+type InventoryOS struct {
+	Hostname       string `json:"hostname"`
+	Name           string `json:"name"`
+	Platform       string `json:"platform"`
+	PlatformFamily string `json:"platform_family"`
+	Version        string `json:"version"`
+	Architecture   string `json:"architecture"`
+	KernelVersion  string `json:"kernel_version"`
+}
+
+// InventoryCPU는 CPU 구성 정보입니다
+type InventoryCPU struct {
+	Model         string  `json:"model"`
+	Vendor        string  `json:"vendor"`
+	Architecture  string  `json:"architecture"`
+	Cores         int     `json:"cores"`
+	LogicalCores  int     `json:"logical_cores"`
+	L3CacheSize   int     `json:"l3_cache_size"`
+	MaxClockSpeed float64 `json:"max_clock_speed"`
+}
+
+// InventoryMemory는 메모리 구성 정보입니다
+type InventoryMemory struct {
+	Total          int64  `json:"total"`
+	SwapTotal      int64  `json:"swap_total"`
+	DataRate       uint64 `json:"data_rate"`
+	UsingSlotCount uint16 `json:"using_slot_count"`
+	TotalSlotCount uint16 `json:"total_slot_count"`
+	FormFactor     string `json:"form_factor"`
+}
+
+// InventoryDisk는 디스크 구성 정보입니다
+type InventoryDisk struct {
+	Device         string `json:"device"`
+	MountPoint     string `json:"mount_point"`
+	FilesystemType string `json:"filesystem_type"`
+	ModelName      string `json:"model_name"`
+	Type           string `json:"type"`
+	Total          int64  `json:"total"`
+}
+
+// InventoryNIC는 네트워크 인터페이스 구성 정보입니다
+type InventoryNIC struct {
+	Interface string `json:"interface"`
+	MAC       string `json:"mac"`
+	MTU       int    `json:"mtu"`
+	Speed     uint64 `json:"speed"`
+}
+
+// InventoryChange는 인벤토리 항목 하나의 변경 이력입니다.
+// 디스크/NIC가 추가되거나 제거되면 Field가 비어 있고 OldValue 또는 NewValue에 요약이 들어갑니다.
+type InventoryChange struct {
+	ID        int64     `json:"id"`
+	NodeID    string    `json:"node_id"`
+	Component string    `json:"component"` // os, cpu, memory, disk, nic
+	Item      string    `json:"item"`      // 디스크 장치명 또는 인터페이스명 (단일 항목이면 빈 문자열)
+	Field     string    `json:"field"`
+	OldValue  string    `json:"old_value"`
+	NewValue  string    `json:"new_value"`
+	ChangedAt time.Time `json:"changed_at"`
+}
@@ -3,12 +3,13 @@ package models
 import (
 	"encoding/json"
 	"log"
+	"strings"
 	"time"
 )
 
 // SystemMetrics는 시스템의 전반적인 상태 정보를 포함하는 구조체입니다.
 type SystemMetrics struct {
-	USER_ID string `json:"user_id"`
+	USER_ID string `json:"user_id" secret:"true"`
 	// Key는 메트릭스의 고유 식별자입니다
 	Key string `json:"key"`
 	// ExternalIP는 외부 IP 주소를 나타냅니다
@@ -59,6 +60,14 @@ type SystemInfo struct {
 	TotalThreads uint64 `json:"total_threads"`
 	// TotalFileDescriptors는 시스템에 열린 파일 디스크립터 수를 나타냅니다
 	TotalFileDescriptors uint64 `json:"total_file_descriptors"`
+	// VirtualizationSystem은 감지된 가상화 종류를 나타냅니다 (kvm, vmware, xen 등)
+	VirtualizationSystem string `json:"virtualization_system,omitempty"`
+	// VirtualizationRole은 가상화에서의 역할을 나타냅니다 (guest 또는 host)
+	VirtualizationRole string `json:"virtualization_role,omitempty"`
+	// SysVendor는 DMI의 시스템 제조사를 나타냅니다
+	SysVendor string `json:"sys_vendor,omitempty"`
+	// ProductName은 DMI의 제품명을 나타냅니다
+	ProductName string `json:"product_name,omitempty"`
 }
 
 // CPUMetrics는 CPU 관련 메트릭스를 포함하는 구조체입니다.
@@ -85,6 +94,8 @@ type CPUMetrics struct {
 	HasVMX bool `json:"has_vmx"`
 	// HasSVM은 AMD-V 지원 여부를 나타냅니다
 	HasSVM bool `json:"has_svm"`
+	// HasHypervisor는 CPUID의 hypervisor 비트가 켜져 있는지 나타냅니다 (게스트에서만 켜짐)
+	HasHypervisor bool `json:"has_hypervisor"`
 	// HasAVX는 AVX 지원 여부를 나타냅니다
 	HasAVX bool `json:"has_avx"`
 	// HasAVX2는 AVX2 지원 여부를 나타냅니다
@@ -347,6 +358,17 @@ type DockerContainer struct {
 	Env []ContainerEnv `json:"container_env"`
 }
 
+// Running은 컨테이너가 실행 중인지 반환합니다 (Status가 "running" 또는 docker ps 형식의 "Up ...")
+func (c DockerContainer) Running() bool {
+	s := strings.ToLower(strings.TrimSpace(c.Status))
+	return s == "running" || strings.HasPrefix(s, "up")
+}
+
+// Unhealthy는 컨테이너의 헬스 체크가 실패한 상태인지 반환합니다
+func (c DockerContainer) Unhealthy() bool {
+	return strings.EqualFold(c.Health.Status, "unhealthy")
+}
+
 // ContainerLabel은 컨테이너 레이블 정보를 포함하는 구조체입니다.
 type ContainerLabel struct {
 	Key   string `json:"label_key"`
@@ -1,9 +1,52 @@
 package models
 
+import "time"
+
+// 서버 유형
+const (
+	ServerTypeBareMetal     = "bare-metal"
+	ServerTypeVM            = "vm"
+	ServerTypeContainerHost = "container-host"
+)
+
+// 노드 상태 (nodes.status)
+const (
+	NodeStatusOffline = 0
+	NodeStatusOnline  = 1
+	// NodeStatusStale은 연결은 남아 있지만 메트릭스가 기대 주기만큼 오지 않는 상태입니다
+	NodeStatusStale = 2
+)
+
+// NodeStatusName은 노드 상태 값을 이름으로 변환합니다
+func NodeStatusName(status int) string {
+	switch status {
+	case NodeStatusOnline:
+		return "online"
+	case NodeStatusStale:
+		return "stale"
+	}
+	return "offline"
+}
+
 type Node struct {
 	NodeID     string `json:"node_id"`
-	ObscuraKey string `json:"obscura_key"`
+	ObscuraKey string `json:"obscura_key" secret:"true"`
 	ServerType string `json:"server_type"`
 	// ExternalIP는 노드의 외부 IP 주소를 나타냅니다
 	ExternalIP string `json:"external_ip"`
+	// Name은 노드 표시 이름입니다 (처음 등록할 때 에이전트의 호스트명)
+	Name string `json:"node_name"`
+}
+
+// IPHistoryEntry는 노드가 사용한 외부 IP 하나의 사용 기간과 위치 정보입니다
+type IPHistoryEntry struct {
+	NodeID      string    `json:"node_id"`
+	IP          string    `json:"ip"`
+	FirstSeen   time.Time `json:"first_seen"`
+	LastSeen    time.Time `json:"last_seen"`
+	SampleCount int64     `json:"sample_count"`
+	CountryCode string    `json:"country_code"`
+	CountryName string    `json:"country_name"`
+	ASN         uint      `json:"asn"`
+	ASOrg       string    `json:"as_org"`
 }
@@ -0,0 +1,24 @@
+package models
+
+// ProcessNode는 프로세스 트리의 노드 하나입니다
+type ProcessNode struct {
+	ProcessInfo
+	// Matched는 프로세스가 조회 조건에 맞는지 나타냅니다. 조건에 맞는 프로세스의 상위 프로세스는 트리를 잇기 위해 함께 포함됩니다.
+	Matched bool `json:"matched"`
+	// TreeCPUUsage, TreeMemoryRSS는 자신과 모든 하위 프로세스의 CPU 사용률과 메모리 사용량 합계입니다
+	TreeCPUUsage  float64        `json:"tree_cpu_usage"`
+	TreeMemoryRSS int64          `json:"tree_memory_rss"`
+	Children      []*ProcessNode `json:"children,omitempty"`
+}
+
+// ProcessGroup은 프로세스 이름 또는 사용자별 합계입니다
+type ProcessGroup struct {
+	Key          string  `json:"key"`
+	Count        int     `json:"count"`
+	Threads      int     `json:"threads"`
+	CPUUsage     float64 `json:"cpu_usage"`
+	MemoryRSS    int64   `json:"memory_rss"`
+	OpenFiles    int     `json:"open_files"`
+	IOReadBytes  int64   `json:"io_read_bytes"`
+	IOWriteBytes int64   `json:"io_write_bytes"`
+}
@@ -5,3 +5,9 @@ type WSResponse struct {
 	Type   string `json:"type"`
 	Result string `json:"result"`
 }
+
+// WSCommands는 노드에 전달하는 명령어 목록 메시지입니다
+type WSCommands struct {
+	Type     string    `json:"type"`
+	Commands []Command `json:"commands"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// SecurityEvent는 프로세스 목록에서 감지한 보안 이벤트입니다.
+// Type은 security_new_process, security_suspicious_path 등 이벤트 유형(EventSecurity*)입니다.
+type SecurityEvent struct {
+	ID       int64  `json:"id"`
+	NodeID   string `json:"node_id"`
+	Type     string `json:"type"`
+	Severity string `json:"severity"`
+	// ProcessName, PID, User, Command는 이벤트를 일으킨 프로세스입니다 (프로세스/스레드 수 급증은 비어 있음)
+	ProcessName string `json:"process_name,omitempty"`
+	PID         int    `json:"pid,omitempty"`
+	User        string `json:"user,omitempty"`
+	Command     string `json:"command,omitempty"`
+	// OldValue, NewValue는 급증 이벤트의 평소 값과 현재 값입니다
+	OldValue  string    `json:"old_value,omitempty"`
+	NewValue  string    `json:"new_value,omitempty"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SecurityAllowlistEntry는 노드에서 보안 이벤트를 발행하지 않을 프로세스 이름입니다
+type SecurityAllowlistEntry struct {
+	NodeID      string    `json:"node_id"`
+	ProcessName string    `json:"process_name"`
+	Comment     string    `json:"comment,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
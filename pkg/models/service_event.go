@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// ServiceEvent는 연속된 서비스 목록을 비교해 감지한 systemd 유닛의 상태 변화입니다.
+// Type은 service_failed, service_disabled 등 이벤트 유형(EventService*, EventCriticalService*)입니다.
+type ServiceEvent struct {
+	ID       int64  `json:"id"`
+	NodeID   string `json:"node_id"`
+	Unit     string `json:"unit"`
+	Type     string `json:"type"`
+	Severity string `json:"severity"`
+	// OldValue, NewValue는 바뀐 값입니다 (ActiveState 또는 enabled/disabled)
+	OldValue string `json:"old_value,omitempty"`
+	NewValue string `json:"new_value,omitempty"`
+	// ActiveState, SubState는 이벤트 시점의 유닛 상태입니다 (사라진 유닛은 비어 있음)
+	ActiveState string    `json:"active_state,omitempty"`
+	SubState    string    `json:"sub_state,omitempty"`
+	Message     string    `json:"message"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// CriticalService는 노드에서 항상 active 상태여야 하는 서비스입니다
+type CriticalService struct {
+	NodeID    string    `json:"node_id"`
+	Unit      string    `json:"unit"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Bucket은 토큰 버킷 방식의 속도 제한기입니다.
+// rate가 0 이하이면 제한하지 않습니다.
+type Bucket struct {
+	mu     sync.Mutex
+	rate   float64 // 초당 채워지는 토큰 수
+	burst  float64 // 최대 토큰 수
+	tokens float64
+	last   time.Time
+}
+
+// NewBucket은 초당 rate개, 최대 burst개의 토큰을 가진 버킷을 생성합니다
+func NewBucket(rate float64, burst int) *Bucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Bucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Allow는 토큰 하나를 사용할 수 있으면 소비하고 true를 반환합니다
+func (b *Bucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.rate <= 0 {
+		return true
+	}
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// SetRate는 속도와 최대 토큰 수를 변경합니다. 현재 토큰 수는 새 최대값을 넘지 않게 조정됩니다.
+func (b *Bucket) SetRate(rate float64, burst int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if burst < 1 {
+		burst = 1
+	}
+	b.rate = rate
+	b.burst = float64(burst)
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
@@ -0,0 +1,108 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+// step은 경과 시간 뒤 Allow를 호출한 기대 결과입니다
+type step struct {
+	elapsed time.Duration
+	want    bool
+}
+
+func TestBucketAllow(t *testing.T) {
+	tests := []struct {
+		name  string
+		rate  float64
+		burst int
+		steps []step
+	}{
+		{
+			name:  "무제한",
+			rate:  0,
+			burst: 1,
+			steps: []step{{want: true}, {want: true}, {want: true}},
+		},
+		{
+			name:  "burst만큼 바로 허용",
+			rate:  1,
+			burst: 3,
+			steps: []step{{want: true}, {want: true}, {want: true}, {want: false}},
+		},
+		{
+			name:  "초당 2개씩 채워짐",
+			rate:  2,
+			burst: 1,
+			steps: []step{
+				{want: true},
+				{elapsed: 100 * time.Millisecond, want: false},
+				{elapsed: 400 * time.Millisecond, want: true},
+				{want: false},
+			},
+		},
+		{
+			name:  "오래 쉬어도 burst를 넘지 않음",
+			rate:  10,
+			burst: 2,
+			steps: []step{
+				{want: true},
+				{want: true},
+				{elapsed: time.Hour, want: true},
+				{want: true},
+				{want: false},
+			},
+		},
+		{
+			name:  "burst가 1보다 작으면 1",
+			rate:  1,
+			burst: 0,
+			steps: []step{{want: true}, {want: false}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBucket(tt.rate, tt.burst)
+			for i, s := range tt.steps {
+				advance(b, s.elapsed)
+				if got := b.Allow(); got != s.want {
+					t.Fatalf("%d단계 (%v 후) Allow = %v, 기대 %v", i, s.elapsed, got, s.want)
+				}
+			}
+		})
+	}
+}
+
+func TestBucketSetRate(t *testing.T) {
+	b := NewBucket(1, 5)
+
+	// 최대 토큰 수를 줄이면 남은 토큰도 줄어듦
+	b.SetRate(1, 2)
+	for i, want := range []bool{true, true, false} {
+		if got := b.Allow(); got != want {
+			t.Fatalf("burst 축소 후 %d번째 Allow = %v, 기대 %v", i, got, want)
+		}
+	}
+
+	// 속도를 높이면 바로 빨리 채워짐
+	b.SetRate(10, 2)
+	advance(b, 100*time.Millisecond)
+	if !b.Allow() {
+		t.Error("속도 증가 후 0.1초 뒤 Allow = false")
+	}
+
+	// 0으로 바꾸면 제한 해제
+	b.SetRate(0, 2)
+	for i := 0; i < 10; i++ {
+		if !b.Allow() {
+			t.Fatalf("제한 해제 후 %d번째 Allow = false", i)
+		}
+	}
+}
+
+// advance는 마지막으로 토큰을 채운 시각을 d만큼 앞당겨 시간이 흐른 것처럼 만듭니다
+func advance(b *Bucket, d time.Duration) {
+	b.mu.Lock()
+	b.last = b.last.Add(-d)
+	b.mu.Unlock()
+}
@@ -0,0 +1,11 @@
+package version
+
+// 빌드 시 -ldflags "-X system-collector/pkg/version.Version=..." 로 주입됩니다
+var (
+	// Version은 릴리스 버전입니다
+	Version = "dev"
+	// Commit은 빌드한 git 커밋 해시입니다
+	Commit = "unknown"
+	// BuildTime은 빌드 시각입니다
+	BuildTime = "unknown"
+)